@@ -2,6 +2,7 @@ package platform
 
 import (
 	"context"
+	"time"
 )
 
 var (
@@ -12,6 +13,10 @@ var (
 	}
 )
 
+// DefaultTokenGracePeriod is the duration the previous token of a rotated authorization
+// remains valid for, unless another grace period is given.
+const DefaultTokenGracePeriod = time.Hour
+
 // Authorization is an authorization. 🎉
 type Authorization struct {
 	ID          ID           `json:"id"`
@@ -21,6 +26,22 @@ type Authorization struct {
 	OrgID       ID           `json:"orgID"`
 	UserID      ID           `json:"userID"`
 	Permissions []Permission `json:"permissions"`
+	// RoleIDs are roles of the organization of the authorization, whose permissions
+	// are granted in addition to Permissions.
+	RoleIDs   []ID      `json:"roleIDs,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	// ExpiresAt is the time the authorization expires at; it never expires if nil.
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+
+	// TokenHash is the hash of Token. Services store the hash rather than the token, which
+	// is only set on the authorization when it is created or rotated.
+	TokenHash string `json:"tokenHash,omitempty"`
+
+	// PreviousTokenHash is the hash of the token replaced by the last rotation of the
+	// authorization, which remains valid until PreviousTokenExpiresAt.
+	PreviousTokenHash      string     `json:"previousTokenHash,omitempty"`
+	PreviousTokenExpiresAt *time.Time `json:"previousTokenExpiresAt,omitempty"`
 }
 
 // Allowed returns true if the authorization is active and request permission
@@ -38,9 +59,24 @@ func IsActive(a *Authorization) bool {
 	return a.IsActive()
 }
 
-// IsActive returns true if the authorization active.
+// IsActive returns true if the authorization is active and unexpired.
 func (a *Authorization) IsActive() bool {
-	return a.Status == Active
+	return a.Status == Active && !a.ExpiredAt(time.Now())
+}
+
+// ExpiredAt returns true if the authorization is expired at time t.
+func (a *Authorization) ExpiredAt(t time.Time) bool {
+	return a.ExpiresAt != nil && !t.Before(*a.ExpiresAt)
+}
+
+// HasTokenHash returns true if h is the hash of the token of the authorization at time now,
+// or of its previous token within the grace period of its last rotation.
+func (a *Authorization) HasTokenHash(h string, now time.Time) bool {
+	if h == a.TokenHash {
+		return true
+	}
+	return a.PreviousTokenHash != "" && h == a.PreviousTokenHash &&
+		a.PreviousTokenExpiresAt != nil && now.Before(*a.PreviousTokenExpiresAt)
 }
 
 // GetUserID returns the user id.
@@ -61,6 +97,8 @@ const (
 	OpFindAuthorizations       = "FindAuthorizations"
 	OpCreateAuthorization      = "CreateAuthorization"
 	OpSetAuthorizationStatus   = "SetAuthorizationStatus"
+	OpRotateAuthorization      = "RotateAuthorization"
+	OpSetAuthorizationLastUsed = "SetAuthorizationLastUsed"
 	OpDeleteAuthorization      = "DeleteAuthorization"
 )
 
@@ -83,10 +121,20 @@ type AuthorizationService interface {
 	// for setting an authorization to inactive or active.
 	SetAuthorizationStatus(ctx context.Context, id ID, status Status) error
 
+	// RotateAuthorization replaces the token of the authorization with a new token.
+	// The previous token remains valid for gracePeriod.
+	RotateAuthorization(ctx context.Context, id ID, gracePeriod time.Duration) (*Authorization, error)
+
 	// Removes a authorization by token.
 	DeleteAuthorization(ctx context.Context, id ID) error
 }
 
+// AuthorizationUsageService records the use of authorizations.
+type AuthorizationUsageService interface {
+	// SetAuthorizationLastUsed records that the authorization was last used at t.
+	SetAuthorizationLastUsed(ctx context.Context, id ID, t time.Time) error
+}
+
 // AuthorizationFilter represents a set of filter that restrict the returned results.
 type AuthorizationFilter struct {
 	Token *string
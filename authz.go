@@ -29,31 +29,43 @@ type Authorizer interface {
 	Kind() string
 }
 
-// PermissionAllowed
+// PermissionAllowed returns true if any of ps grants the permission p.
+// A permission without an ID grants the action on every resource of its type,
+// and a permission without an OrgID grants it in every organization.
 func PermissionAllowed(p Permission, ps []Permission) bool {
-	pID := ID(0)
-	if p.ID != nil {
-		pID = *p.ID
-		if !pID.Valid() {
-			return false
-		}
+	if !validIDPtr(p.ID) || !validIDPtr(p.OrgID) {
+		return false
 	}
 
 	for _, perm := range ps {
-		permID := ID(0)
-		if perm.ID != nil {
-			permID = *perm.ID
-			if !permID.Valid() {
-				return false
-			}
+		if !validIDPtr(perm.ID) || !validIDPtr(perm.OrgID) {
+			return false
 		}
-		if perm.Action == p.Action && perm.Resource == p.Resource && permID == pID {
+		if perm.grants(p) {
 			return true
 		}
 	}
 	return false
 }
 
+// grants returns true if p is a wildcard for, or equal to, the requested permission r.
+func (p Permission) grants(r Permission) bool {
+	if p.Action != r.Action || p.Resource != r.Resource {
+		return false
+	}
+	if p.OrgID != nil && (r.OrgID == nil || *p.OrgID != *r.OrgID) {
+		return false
+	}
+	if p.ID != nil && (r.ID == nil || *p.ID != *r.ID) {
+		return false
+	}
+	return true
+}
+
+func validIDPtr(id *ID) bool {
+	return id == nil || id.Valid()
+}
+
 // Action is an enum defining all possible resource operations
 type Action string
 
@@ -62,11 +74,14 @@ const (
 	ReadAction Action = "read" // 1
 	// WriteAction is the action for writing.
 	WriteAction Action = "write" // 2
+	// DeleteAction is the action for deleting.
+	DeleteAction Action = "delete" // 3
 )
 
 var actions = []Action{
-	ReadAction,  // 1
-	WriteAction, // 2
+	ReadAction,   // 1
+	WriteAction,  // 2
+	DeleteAction, // 3
 }
 
 // Valid checks if the action is a member of the Action enum
@@ -74,6 +89,7 @@ func (a Action) Valid() (err error) {
 	switch a {
 	case ReadAction: // 1
 	case WriteAction: // 2
+	case DeleteAction: // 3
 	default:
 		err = ErrInvalidAction
 	}
@@ -101,6 +117,20 @@ const (
 	TelegrafsResource = Resource("telegrafs") // 6
 	// UsersResource gives permissions to one or more users.
 	UsersResource = Resource("users") // 7
+	// MacrosResource gives permissions to one or more macros.
+	MacrosResource = Resource("macros") // 8
+	// ViewsResource gives permissions to one or more views.
+	ViewsResource = Resource("views") // 9
+	// ScrapersResource gives permissions to one or more scraper targets.
+	ScrapersResource = Resource("scrapers") // 10
+	// SecretsResource gives permissions to one or more secrets.
+	SecretsResource = Resource("secrets") // 11
+	// LabelsResource gives permissions to the labels of one or more resources.
+	// The ID of a labels permission is the ID of the labeled resource, and its OrgID the
+	// organization of the labeled resource.
+	LabelsResource = Resource("labels") // 12
+	// RolesResource gives permissions to one or more roles.
+	RolesResource = Resource("roles") // 13
 )
 
 // AllResources is the list of all known resource types.
@@ -113,6 +143,12 @@ var AllResources = []Resource{
 	TasksResource,          // 5
 	TelegrafsResource,      // 6
 	UsersResource,          // 7
+	MacrosResource,         // 8
+	ViewsResource,          // 9
+	ScrapersResource,       // 10
+	SecretsResource,        // 11
+	LabelsResource,         // 12
+	RolesResource,          // 13
 }
 
 // OrgResources is the list of all known resource types that belong to an organization.
@@ -123,6 +159,18 @@ var OrgResources = []Resource{
 	TasksResource,      // 5
 	TelegrafsResource,  // 6
 	UsersResource,      // 7
+	SecretsResource,    // 11
+	LabelsResource,     // 12
+	RolesResource,      // 13
+}
+
+// SharedResources is the list of all known resource types that do not belong to an organization,
+// and are shared by the users of every organization. Users may read and create shared resources,
+// but only write and delete those they own, unless they are explicitly granted more.
+var SharedResources = []Resource{
+	MacrosResource,   // 8
+	ViewsResource,    // 9
+	ScrapersResource, // 10
 }
 
 // Valid checks if the resource is a member of the Resource enum.
@@ -136,6 +184,12 @@ func (r Resource) Valid() (err error) {
 	case TelegrafsResource: // 5
 	case SourcesResource: // 6
 	case UsersResource: //7
+	case MacrosResource: // 8
+	case ViewsResource: // 9
+	case ScrapersResource: // 10
+	case SecretsResource: // 11
+	case LabelsResource: // 12
+	case RolesResource: // 13
 	default:
 		err = ErrInvalidResource
 	}
@@ -143,15 +197,30 @@ func (r Resource) Valid() (err error) {
 	return err
 }
 
+// InOrg returns true if the resource belongs to an organization.
+func (r Resource) InOrg() bool {
+	for _, or := range OrgResources {
+		if r == or {
+			return true
+		}
+	}
+	return false
+}
+
 // Permission defines an action and a resource.
+// A nil OrgID or ID matches every organization or resource respectively.
 type Permission struct {
 	Action   Action   `json:"action"`
 	Resource Resource `json:"resource"`
+	OrgID    *ID      `json:"orgID,omitempty"`
 	ID       *ID      `json:"id,omitempty"`
 }
 
 func (p Permission) String() string {
 	str := fmt.Sprintf("%s:%s", p.Action, p.Resource)
+	if p.OrgID != nil {
+		str = fmt.Sprintf("%s:orgs/%s/%s", p.Action, (*p.OrgID).String(), p.Resource)
+	}
 	if p.ID != nil {
 		str += fmt.Sprintf(":%s", (*p.ID).String())
 	}
@@ -185,6 +254,22 @@ func (p *Permission) Valid() error {
 		}
 	}
 
+	if p.OrgID != nil {
+		if !(*p.OrgID).Valid() {
+			return &Error{
+				Code: EInvalid,
+				Err:  ErrInvalidID,
+				Msg:  "invalid org id for permission",
+			}
+		}
+		if !p.Resource.InOrg() {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("%s do not belong to an organization", p.Resource),
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -209,6 +294,29 @@ func NewPermissionAtID(id ID, a Action, r Resource) (*Permission, error) {
 	return p, p.Valid()
 }
 
+// NewPermissionInOrg returns a permission for every resource of the type in the organization.
+func NewPermissionInOrg(orgID ID, a Action, r Resource) (*Permission, error) {
+	p := &Permission{
+		Action:   a,
+		Resource: r,
+		OrgID:    &orgID,
+	}
+
+	return p, p.Valid()
+}
+
+// NewPermissionAtIDInOrg returns a permission for the resource id in the organization.
+func NewPermissionAtIDInOrg(orgID, id ID, a Action, r Resource) (*Permission, error) {
+	p := &Permission{
+		Action:   a,
+		Resource: r,
+		OrgID:    &orgID,
+		ID:       &id,
+	}
+
+	return p, p.Valid()
+}
+
 // OperPermissions are the default permissions for those who setup the application.
 func OperPermissions() []Permission {
 	ps := []Permission{}
@@ -226,18 +334,28 @@ func OrgAdminPermissions(orgID ID) []Permission {
 	ps := []Permission{}
 	for _, r := range OrgResources {
 		for _, a := range actions {
-			ps = append(ps, Permission{ID: &orgID, Action: a, Resource: r})
+			ps = append(ps, Permission{OrgID: &orgID, Action: a, Resource: r})
 		}
 	}
 
 	return ps
 }
 
+// SharedMemberPermissions are the default permissions of org admins and members for the shared resources.
+func SharedMemberPermissions() []Permission {
+	ps := []Permission{}
+	for _, r := range SharedResources {
+		ps = append(ps, Permission{Action: ReadAction, Resource: r})
+	}
+
+	return ps
+}
+
 // OrgMemberPermissions are the default permissions for org members.
 func OrgMemberPermissions(orgID ID) []Permission {
 	ps := []Permission{}
 	for _, r := range OrgResources {
-		ps = append(ps, Permission{ID: &orgID, Action: ReadAction, Resource: r})
+		ps = append(ps, Permission{OrgID: &orgID, Action: ReadAction, Resource: r})
 	}
 
 	return ps
@@ -123,6 +123,137 @@ func TestAuthorizer_PermissionAllowed(t *testing.T) {
 			},
 			allowed: false,
 		},
+		{
+			name: "permission without ID allows every ID",
+			permission: platform.Permission{
+				Action:   platform.WriteAction,
+				Resource: platform.BucketsResource,
+				OrgID:    IDPtr(1),
+				ID:       IDPtr(2),
+			},
+			permissions: []platform.Permission{
+				{
+					Action:   platform.WriteAction,
+					Resource: platform.BucketsResource,
+				},
+			},
+			allowed: true,
+		},
+		{
+			name: "permission in organization allows every ID in organization",
+			permission: platform.Permission{
+				Action:   platform.WriteAction,
+				Resource: platform.BucketsResource,
+				OrgID:    IDPtr(1),
+				ID:       IDPtr(2),
+			},
+			permissions: []platform.Permission{
+				{
+					Action:   platform.WriteAction,
+					Resource: platform.BucketsResource,
+					OrgID:    IDPtr(1),
+				},
+			},
+			allowed: true,
+		},
+		{
+			name: "permission in organization with ID",
+			permission: platform.Permission{
+				Action:   platform.WriteAction,
+				Resource: platform.BucketsResource,
+				OrgID:    IDPtr(1),
+				ID:       IDPtr(2),
+			},
+			permissions: []platform.Permission{
+				{
+					Action:   platform.WriteAction,
+					Resource: platform.BucketsResource,
+					OrgID:    IDPtr(1),
+					ID:       IDPtr(2),
+				},
+			},
+			allowed: true,
+		},
+		{
+			name: "permission in differing organization",
+			permission: platform.Permission{
+				Action:   platform.WriteAction,
+				Resource: platform.BucketsResource,
+				OrgID:    IDPtr(1),
+				ID:       IDPtr(2),
+			},
+			permissions: []platform.Permission{
+				{
+					Action:   platform.WriteAction,
+					Resource: platform.BucketsResource,
+					OrgID:    IDPtr(3),
+				},
+			},
+			allowed: false,
+		},
+		{
+			name: "permission in organization does not allow every organization",
+			permission: platform.Permission{
+				Action:   platform.WriteAction,
+				Resource: platform.BucketsResource,
+			},
+			permissions: []platform.Permission{
+				{
+					Action:   platform.WriteAction,
+					Resource: platform.BucketsResource,
+					OrgID:    IDPtr(1),
+				},
+			},
+			allowed: false,
+		},
+		{
+			name: "permission with ID does not allow every ID",
+			permission: platform.Permission{
+				Action:   platform.WriteAction,
+				Resource: platform.BucketsResource,
+				OrgID:    IDPtr(1),
+			},
+			permissions: []platform.Permission{
+				{
+					Action:   platform.WriteAction,
+					Resource: platform.BucketsResource,
+					ID:       IDPtr(2),
+				},
+			},
+			allowed: false,
+		},
+		{
+			name: "write does not allow delete",
+			permission: platform.Permission{
+				Action:   platform.DeleteAction,
+				Resource: platform.BucketsResource,
+				OrgID:    IDPtr(1),
+				ID:       IDPtr(2),
+			},
+			permissions: []platform.Permission{
+				{
+					Action:   platform.WriteAction,
+					Resource: platform.BucketsResource,
+					OrgID:    IDPtr(1),
+				},
+			},
+			allowed: false,
+		},
+		{
+			name: "bad organization id in permission",
+			permission: platform.Permission{
+				Action:   platform.WriteAction,
+				Resource: platform.BucketsResource,
+				OrgID:    IDPtr(0),
+			},
+			permissions: []platform.Permission{
+				{
+					Action:   platform.WriteAction,
+					Resource: platform.BucketsResource,
+				},
+			},
+			allowed: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -139,6 +270,7 @@ func TestPermission_Valid(t *testing.T) {
 	type fields struct {
 		Action   platform.Action
 		Resource platform.Resource
+		OrgID    *platform.ID
 		ID       *platform.ID
 	}
 	tests := []struct {
@@ -171,6 +303,32 @@ func TestPermission_Valid(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "valid bucket permission in an organization",
+			fields: fields{
+				Action:   platform.DeleteAction,
+				Resource: platform.BucketsResource,
+				OrgID:    validID(),
+			},
+		},
+		{
+			name: "invalid bucket permission with an invalid organization ID",
+			fields: fields{
+				Action:   platform.WriteAction,
+				Resource: platform.BucketsResource,
+				OrgID:    func() *platform.ID { id := platform.InvalidID(); return &id }(),
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid organization permission in an organization",
+			fields: fields{
+				Action:   platform.WriteAction,
+				Resource: platform.OrgsResource,
+				OrgID:    validID(),
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid permission without an action",
 			fields: fields{
@@ -191,6 +349,7 @@ func TestPermission_Valid(t *testing.T) {
 			p := &platform.Permission{
 				Action:   tt.fields.Action,
 				Resource: tt.fields.Resource,
+				OrgID:    tt.fields.OrgID,
 				ID:       tt.fields.ID,
 			}
 			if err := p.Valid(); (err != nil) != tt.wantErr {
@@ -208,6 +367,11 @@ func TestPermissionAllResources_Valid(t *testing.T) {
 		platform.BucketsResource,
 		platform.DashboardsResource,
 		platform.SourcesResource,
+		platform.MacrosResource,
+		platform.ViewsResource,
+		platform.ScrapersResource,
+		platform.SecretsResource,
+		platform.LabelsResource,
 	}
 
 	for _, r := range resources {
@@ -226,6 +390,7 @@ func TestPermissionAllActions(t *testing.T) {
 	var actions = []platform.Action{
 		platform.ReadAction,
 		platform.WriteAction,
+		platform.DeleteAction,
 	}
 
 	for _, a := range actions {
@@ -244,6 +409,7 @@ func TestPermission_String(t *testing.T) {
 	type fields struct {
 		Action   platform.Action
 		Resource platform.Resource
+		OrgID    *platform.ID
 		ID       *platform.ID
 		Name     *string
 	}
@@ -269,12 +435,23 @@ func TestPermission_String(t *testing.T) {
 			},
 			want: `write:buckets:0000000000000064`,
 		},
+		{
+			name: "valid permission in an organization",
+			fields: fields{
+				Action:   platform.DeleteAction,
+				Resource: platform.BucketsResource,
+				OrgID:    IDPtr(1),
+				ID:       validID(),
+			},
+			want: `delete:orgs/0000000000000001/buckets:0000000000000064`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			p := platform.Permission{
 				Action:   tt.fields.Action,
 				Resource: tt.fields.Resource,
+				OrgID:    tt.fields.OrgID,
 				ID:       tt.fields.ID,
 			}
 			if got := p.String(); got != tt.want {
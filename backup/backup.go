@@ -0,0 +1,265 @@
+// Package backup writes and restores archives of the metadata and time series data of a running influxd.
+//
+// An archive is a gzipped tar file containing a manifest, a copy of the bolt metadata database,
+// and the TSM and tombstone files of the storage engine. The series file and index are not archived;
+// they are rebuilt from the TSM files when the archive is restored.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/storage"
+	"go.uber.org/zap"
+)
+
+// Version is the version of the archive format written by Snapshot.Write.
+const Version = 1
+
+const (
+	manifestName = "manifest.json"
+	boltName     = "influxd.bolt"
+	engineDir    = "engine"
+)
+
+// Manifest describes the contents of an archive.
+type Manifest struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	// The organization and bucket the time series data was limited to, if any.
+	// The metadata is always complete.
+	OrganizationID *platform.ID `json:"orgID,omitempty"`
+	BucketID       *platform.ID `json:"bucketID,omitempty"`
+}
+
+// A MetadataBackuper writes a consistent copy of a metadata store, such as a bolt.Client.
+type MetadataBackuper interface {
+	Backup(ctx context.Context, w io.Writer) error
+}
+
+// An EngineBackuper creates a directory of the files of a storage engine, such as a storage.Engine.
+type EngineBackuper interface {
+	CreateBackup(orgID, bucketID *platform.ID) (string, error)
+}
+
+// Filter limits the time series data in an archive to an organization, or to a bucket within it.
+type Filter struct {
+	OrganizationID *platform.ID
+	BucketID       *platform.ID
+}
+
+// A Snapshot is a consistent copy of the metadata and of the time series data, which can be written as an archive.
+type Snapshot struct {
+	dir      string
+	manifest Manifest
+}
+
+// Create creates a snapshot of the metadata of meta and of the time series data of engine selected by filter.
+// The snapshot must be closed to remove its files.
+func Create(ctx context.Context, meta MetadataBackuper, engine EngineBackuper, filter Filter) (*Snapshot, error) {
+	dir, err := engine.CreateBackup(filter.OrganizationID, filter.BucketID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The metadata is copied after the engine files, so that it describes every bucket they contain.
+	if err := backupMetadata(ctx, meta, filepath.Join(dir, boltName)); err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+
+	return &Snapshot{
+		dir: dir,
+		manifest: Manifest{
+			Version:        Version,
+			CreatedAt:      time.Now().UTC(),
+			OrganizationID: filter.OrganizationID,
+			BucketID:       filter.BucketID,
+		},
+	}, nil
+}
+
+// Close removes the files of the snapshot.
+func (s *Snapshot) Close() error {
+	return os.RemoveAll(s.dir)
+}
+
+// Write writes the snapshot to w as an archive.
+func (s *Snapshot) Write(w io.Writer) error {
+	manifest, err := json.Marshal(s.manifest)
+	if err != nil {
+		return err
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    manifestName,
+		Mode:    0644,
+		Size:    int64(len(manifest)),
+		ModTime: s.manifest.CreatedAt,
+	}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(manifest); err != nil {
+		return err
+	}
+
+	if err := writeFile(tw, filepath.Join(s.dir, boltName), boltName); err != nil {
+		return err
+	}
+
+	fis, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	for _, fi := range fis {
+		if fi.IsDir() || fi.Name() == boltName {
+			continue
+		}
+		if err := writeFile(tw, filepath.Join(s.dir, fi.Name()), path.Join(engineDir, fi.Name())); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func backupMetadata(ctx context.Context, meta MetadataBackuper, path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := meta.Backup(ctx, f); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+// writeFile writes the file at path to tw as name.
+func writeFile(tw *tar.Writer, path, name string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	hdr, err := tar.FileInfoHeader(fi, "")
+	if err != nil {
+		return err
+	}
+	hdr.Name = name
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// Restore extracts the archive read from r, creating the bolt database at boltPath and the storage engine at
+// enginePath, configured by c. Neither may already exist, except as an empty directory. The index of the engine
+// is rebuilt from the restored TSM files.
+func Restore(r io.Reader, boltPath, enginePath string, c storage.Config, log *zap.Logger) (*Manifest, error) {
+	if _, err := os.Stat(boltPath); err == nil {
+		return nil, fmt.Errorf("bolt database already exists: %s", boltPath)
+	}
+	if fis, err := ioutil.ReadDir(enginePath); err == nil && len(fis) > 0 {
+		return nil, fmt.Errorf("engine directory is not empty: %s", enginePath)
+	}
+
+	dataPath := c.GetEnginePath(enginePath)
+	if err := os.MkdirAll(dataPath, 0777); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(boltPath), 0700); err != nil {
+		return nil, err
+	}
+
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+
+	var manifest *Manifest
+	hasBolt := false
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch dir, name := path.Split(hdr.Name); {
+		case hdr.Name == manifestName:
+			manifest = &Manifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("invalid manifest: %v", err)
+			}
+			if manifest.Version != Version {
+				return nil, fmt.Errorf("unsupported archive version %d; expected %d", manifest.Version, Version)
+			}
+		case hdr.Name == boltName:
+			if err := extractFile(tr, boltPath, 0600); err != nil {
+				return nil, err
+			}
+			hasBolt = true
+		case dir == engineDir+"/" && name != "" && name != "." && name != "..":
+			if err := extractFile(tr, filepath.Join(dataPath, name), 0666); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unexpected file in archive: %s", hdr.Name)
+		}
+	}
+	if manifest == nil || !hasBolt {
+		return nil, fmt.Errorf("archive is incomplete: missing %s or %s", manifestName, boltName)
+	}
+
+	log.Info("Rebuilding index", zap.String("path", enginePath))
+	if err := storage.BuildIndex(enginePath, c, log); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func extractFile(r io.Reader, path string, perm os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if _, err := io.Copy(f, r); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,251 @@
+package backup_test
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/influxql"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/backup"
+	"github.com/influxdata/platform/bolt"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+	"go.uber.org/zap"
+)
+
+func TestWriteRestore(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "backup_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	client := bolt.NewClient()
+	client.Path = filepath.Join(dir, "src", "influxd.bolt")
+	if err := client.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	org := &platform.Organization{Name: "org"}
+	if err := client.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	bucketA := &platform.Bucket{Name: "a", OrganizationID: org.ID}
+	bucketB := &platform.Bucket{Name: "b", OrganizationID: org.ID}
+	for _, b := range []*platform.Bucket{bucketA, bucketB} {
+		if err := client.CreateBucket(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	engine := storage.NewEngine(filepath.Join(dir, "src", "engine"), storage.NewConfig())
+	if err := engine.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Close()
+
+	write := func(bucket platform.ID, lines string) {
+		pts, err := models.ParsePointsString(lines)
+		if err != nil {
+			t.Fatal(err)
+		}
+		points, err := tsdb.ExplodePoints(org.ID, bucket, pts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := engine.WritePoints(points); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(bucketA.ID, "cpu,host=a v=1 1\ncpu,host=b v=1 1")
+	write(bucketB.ID, "cpu,host=a v=1 1")
+
+	// A series with all of its data deleted after it was written to a TSM file is not restored.
+	write(bucketA.ID, "cpu,host=c v=1 1\ncpu,host=c v=1 10")
+	dir2, err := engine.CreateBackup(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.RemoveAll(dir2)
+	for _, r := range [][2]int64{{0, 5}, {6, 10}} {
+		if err := engine.DeleteBucketRangePredicate(org.ID, bucketA.ID, r[0], r[1], influxql.MustParseExpr("host = 'c'")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		filter backup.Filter
+		seriesA,
+		seriesB float64
+	}{
+		{name: "all", seriesA: 2, seriesB: 1},
+		{name: "org", filter: backup.Filter{OrganizationID: &org.ID}, seriesA: 2, seriesB: 1},
+		{name: "bucket", filter: backup.Filter{OrganizationID: &org.ID, BucketID: &bucketA.ID}, seriesA: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot, err := backup.Create(ctx, client, engine, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := snapshot.Write(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if err := snapshot.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			boltPath := filepath.Join(dir, tt.name, "influxd.bolt")
+			enginePath := filepath.Join(dir, tt.name, "engine")
+			manifest, err := backup.Restore(&buf, boltPath, enginePath, storage.NewConfig(), zap.NewNop())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if manifest.Version != backup.Version || (manifest.BucketID == nil) != (tt.filter.BucketID == nil) {
+				t.Fatalf("unexpected manifest: %+v", manifest)
+			}
+
+			restored := bolt.NewClient()
+			restored.Path = boltPath
+			if err := restored.Open(ctx); err != nil {
+				t.Fatal(err)
+			}
+			defer restored.Close()
+			if _, err := restored.FindBucketByID(ctx, bucketB.ID); err != nil {
+				t.Fatalf("bucket missing from restored metadata: %v", err)
+			}
+
+			e := storage.NewEngine(enginePath, storage.NewConfig())
+			if err := e.Open(); err != nil {
+				t.Fatal(err)
+			}
+			defer e.Close()
+
+			for _, c := range []struct {
+				bucket platform.ID
+				exp    float64
+			}{{bucketA.ID, tt.seriesA}, {bucketB.ID, tt.seriesB}} {
+				u, err := e.GetUsage(ctx, platform.UsageFilter{BucketID: &c.bucket})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got := u[platform.UsageSeries].Value; got != c.exp {
+					t.Fatalf("got %v series in bucket %s, exp %v", got, c.bucket, c.exp)
+				}
+			}
+		})
+	}
+
+	if _, err := backup.Restore(bytes.NewReader(nil), filepath.Join(dir, "all", "influxd.bolt"), filepath.Join(dir, "new"), storage.NewConfig(), zap.NewNop()); err == nil {
+		t.Fatal("expected error restoring over an existing bolt database")
+	}
+}
+
+func TestWriteRestore_ColdFiles(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "backup_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	client := bolt.NewClient()
+	client.Path = filepath.Join(dir, "src", "influxd.bolt")
+	if err := client.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	org := &platform.Organization{Name: "org"}
+	if err := client.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	bucket := &platform.Bucket{Name: "a", OrganizationID: org.ID}
+	if err := client.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	// The retention enforcer partitions the TSM files by bucket, so that they can be offloaded.
+	c := storage.NewConfig()
+	c.ColdStoragePath = filepath.Join(dir, "cold")
+	engine := storage.NewEngine(filepath.Join(dir, "src", "engine"), c, storage.WithRetentionEnforcer(client))
+	if err := engine.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer engine.Close()
+
+	pts, err := models.ParsePointsString("cpu,host=a v=1 1\ncpu,host=b v=1 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	points, err := tsdb.ExplodePoints(org.ID, bucket.ID, pts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.WritePoints(points); err != nil {
+		t.Fatal(err)
+	}
+
+	// Creating a backup writes the cached points to a TSM file, which is then offloaded.
+	snapshot, err := engine.CreateBackup(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.RemoveAll(snapshot)
+	if n, err := engine.OffloadColdFiles(func([]byte, int64) bool { return true }); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Fatalf("expected 1 file offloaded, got %d", n)
+	}
+
+	tests := []struct {
+		name   string
+		filter backup.Filter
+	}{
+		{name: "all"},
+		{name: "bucket", filter: backup.Filter{OrganizationID: &org.ID, BucketID: &bucket.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snapshot, err := backup.Create(ctx, client, engine, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if err := snapshot.Write(&buf); err != nil {
+				t.Fatal(err)
+			}
+			if err := snapshot.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			// The restored engine has no cold storage, so it only opens if the backup holds the whole files.
+			enginePath := filepath.Join(dir, tt.name, "engine")
+			if _, err := backup.Restore(&buf, filepath.Join(dir, tt.name, "influxd.bolt"), enginePath, storage.NewConfig(), zap.NewNop()); err != nil {
+				t.Fatal(err)
+			}
+			e := storage.NewEngine(enginePath, storage.NewConfig())
+			if err := e.Open(); err != nil {
+				t.Fatal(err)
+			}
+			defer e.Close()
+
+			u, err := e.GetUsage(ctx, platform.UsageFilter{BucketID: &bucket.ID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := u[platform.UsageSeries].Value; got != 2 {
+				t.Fatalf("got %v series in bucket, exp 2", got)
+			}
+		})
+	}
+}
@@ -3,16 +3,33 @@ package bolt
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"time"
 
 	bolt "github.com/coreos/bbolt"
 	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/rand"
+	"go.uber.org/zap"
 )
 
 var (
 	authorizationBucket = []byte("authorizationsv1")
 	authorizationIndex  = []byte("authorizationindexv1")
+
+	// tokenHashBucket holds the key tokens are hashed with when the client is not given a
+	// TokenHasher, and a check that tokens are hashed with the same key every time.
+	tokenHashBucket   = []byte("tokenhashv1")
+	tokenHashKeyKey   = []byte("key")
+	tokenHashCheckKey = []byte("check")
+
+	// authorizationPermissionsBucket exists once the permissions of the stored authorizations
+	// have been migrated to organization permissions and the delete action.
+	authorizationPermissionsBucket = []byte("authorizationpermissionsv2")
 )
 
+// tokenHashCheck is the value hashed to check the key tokens are hashed with.
+const tokenHashCheck = "influxdb token hash check"
+
 var _ platform.AuthorizationService = (*Client)(nil)
 
 func (c *Client) initializeAuthorizations(ctx context.Context, tx *bolt.Tx) error {
@@ -22,6 +39,132 @@ func (c *Client) initializeAuthorizations(ctx context.Context, tx *bolt.Tx) erro
 	if _, err := tx.CreateBucketIfNotExists([]byte(authorizationIndex)); err != nil {
 		return err
 	}
+	if err := c.initializeTokenHasher(ctx, tx); err != nil {
+		return err
+	}
+	if err := c.migrateAuthorizationTokens(ctx, tx); err != nil {
+		return err
+	}
+	return c.migrateAuthorizationPermissions(ctx, tx)
+}
+
+// initializeTokenHasher checks that the TokenHasher of the client hashes tokens the same way
+// as when the database was last opened. Without a TokenHasher, tokens are hashed with a key
+// stored in the database.
+func (c *Client) initializeTokenHasher(ctx context.Context, tx *bolt.Tx) error {
+	b, err := tx.CreateBucketIfNotExists(tokenHashBucket)
+	if err != nil {
+		return err
+	}
+	check := b.Get(tokenHashCheckKey)
+
+	if c.TokenHasher == nil {
+		key := b.Get(tokenHashKeyKey)
+		if key == nil {
+			if check != nil {
+				return fmt.Errorf("the tokens of %s are hashed with a key that is not stored in it: the token hash key is required", c.Path)
+			}
+			if key, err = rand.NewTokenHashKey(); err != nil {
+				return err
+			}
+			if err := b.Put(tokenHashKeyKey, key); err != nil {
+				return err
+			}
+		}
+		// Values of a bucket are only valid for the life of the transaction.
+		c.TokenHasher = rand.NewTokenHasher(append([]byte(nil), key...))
+	}
+
+	h := c.TokenHasher.HashToken(tokenHashCheck)
+	if check == nil {
+		return b.Put(tokenHashCheckKey, []byte(h))
+	}
+	if string(check) != h {
+		return fmt.Errorf("the tokens of %s are hashed with another token hash key", c.Path)
+	}
+	return nil
+}
+
+// migrateAuthorizationTokens replaces the tokens of authorizations stored before tokens were
+// hashed with their hashes, and rebuilds the token index without the tokens.
+func (c *Client) migrateAuthorizationTokens(ctx context.Context, tx *bolt.Tx) error {
+	var as []*platform.Authorization
+	migrate := false
+	err := c.forEachAuthorization(ctx, tx, func(a *platform.Authorization) bool {
+		migrate = migrate || a.Token != ""
+		as = append(as, a)
+		return true
+	})
+	if err != nil || !migrate {
+		return err
+	}
+
+	if err := tx.DeleteBucket(authorizationIndex); err != nil {
+		return err
+	}
+	if _, err := tx.CreateBucket(authorizationIndex); err != nil {
+		return err
+	}
+	for _, a := range as {
+		if pe := c.putAuthorization(ctx, tx, a); pe != nil {
+			return pe
+		}
+	}
+
+	c.Logger.Info("Replaced authorization tokens with their hashes", zap.Int("authorizations", len(as)))
+	return nil
+}
+
+// migrateAuthorizationPermissions migrates the permissions of the authorizations stored before
+// permissions could be restricted to an organization, and before deleting required the delete action.
+// Permissions on the resources of an organization that were granted at the ID of the organization
+// are granted in the organization instead, and the delete action is granted along the write action.
+func (c *Client) migrateAuthorizationPermissions(ctx context.Context, tx *bolt.Tx) error {
+	if tx.Bucket(authorizationPermissionsBucket) != nil {
+		return nil
+	}
+
+	var as []*platform.Authorization
+	err := c.forEachAuthorization(ctx, tx, func(a *platform.Authorization) bool {
+		as = append(as, a)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, a := range as {
+		ps := make([]platform.Permission, 0, len(a.Permissions))
+		for _, p := range a.Permissions {
+			if p.OrgID == nil && p.ID != nil && p.Resource.InOrg() {
+				if _, pe := c.findOrganizationByID(ctx, tx, *p.ID); pe == nil {
+					p.OrgID, p.ID = p.ID, nil
+				}
+			}
+			ps = append(ps, p)
+		}
+		for _, p := range ps {
+			if p.Action != platform.WriteAction {
+				continue
+			}
+			p.Action = platform.DeleteAction
+			if !platform.PermissionAllowed(p, ps) {
+				ps = append(ps, p)
+			}
+		}
+		a.Permissions = ps
+
+		if pe := c.putAuthorization(ctx, tx, a); pe != nil {
+			return pe
+		}
+	}
+
+	if _, err := tx.CreateBucket(authorizationPermissionsBucket); err != nil {
+		return err
+	}
+	if len(as) > 0 {
+		c.Logger.Info("Migrated the permissions of authorizations", zap.Int("authorizations", len(as)))
+	}
 	return nil
 }
 
@@ -89,7 +232,7 @@ func (c *Client) FindAuthorizationByToken(ctx context.Context, n string) (*platf
 }
 
 func (c *Client) findAuthorizationByToken(ctx context.Context, tx *bolt.Tx, n string) (*platform.Authorization, *platform.Error) {
-	a := tx.Bucket(authorizationIndex).Get(authorizationIndexKey(n))
+	a := tx.Bucket(authorizationIndex).Get(authorizationIndexKey(c.TokenHasher.HashToken(n)))
 	if a == nil {
 		return nil, &platform.Error{
 			Code: platform.ENotFound,
@@ -103,10 +246,21 @@ func (c *Client) findAuthorizationByToken(ctx context.Context, tx *bolt.Tx, n st
 			Err:  err,
 		}
 	}
-	return c.findAuthorizationByID(ctx, tx, id)
+	auth, pe := c.findAuthorizationByID(ctx, tx, id)
+	if pe != nil {
+		return nil, pe
+	}
+	// The index keeps the previous token of a rotated authorization after its grace period.
+	if !auth.HasTokenHash(c.TokenHasher.HashToken(n), c.time()) {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  "authorization not found",
+		}
+	}
+	return auth, nil
 }
 
-func filterAuthorizationsFn(filter platform.AuthorizationFilter) func(a *platform.Authorization) bool {
+func filterAuthorizationsFn(filter platform.AuthorizationFilter, h platform.TokenHasher) func(a *platform.Authorization) bool {
 	if filter.ID != nil {
 		return func(a *platform.Authorization) bool {
 			return a.ID == *filter.ID
@@ -114,8 +268,9 @@ func filterAuthorizationsFn(filter platform.AuthorizationFilter) func(a *platfor
 	}
 
 	if filter.Token != nil {
+		tokenHash := h.HashToken(*filter.Token)
 		return func(a *platform.Authorization) bool {
-			return a.Token == *filter.Token
+			return a.TokenHash == tokenHash
 		}
 	}
 
@@ -187,7 +342,7 @@ func (c *Client) findAuthorizations(ctx context.Context, tx *bolt.Tx, f platform
 	}
 
 	as := []*platform.Authorization{}
-	filterFn := filterAuthorizationsFn(f)
+	filterFn := filterAuthorizationsFn(f, c.TokenHasher)
 	err := c.forEachAuthorization(ctx, tx, func(a *platform.Authorization) bool {
 		if filterFn(a) {
 			as = append(as, a)
@@ -215,10 +370,6 @@ func (c *Client) CreateAuthorization(ctx context.Context, a *platform.Authorizat
 			return platform.ErrUnableToCreateToken
 		}
 
-		if unique := c.uniqueAuthorizationToken(ctx, tx, a); !unique {
-			return platform.ErrUnableToCreateToken
-		}
-
 		token, err := c.TokenGenerator.Token()
 		if err != nil {
 			return &platform.Error{
@@ -228,7 +379,12 @@ func (c *Client) CreateAuthorization(ctx context.Context, a *platform.Authorizat
 		}
 		a.Token = token
 
+		if unique := c.uniqueAuthorizationToken(ctx, tx, a); !unique {
+			return platform.ErrUnableToCreateToken
+		}
+
 		a.ID = c.IDGenerator.ID()
+		a.CreatedAt = c.time()
 
 		pe := c.putAuthorization(ctx, tx, a)
 		if pe != nil {
@@ -262,10 +418,18 @@ func encodeAuthorization(a *platform.Authorization) ([]byte, error) {
 		}
 	}
 
-	return json.Marshal(a)
+	// Tokens are only stored as their hashes.
+	stored := *a
+	stored.Token = ""
+	return json.Marshal(&stored)
 }
 
+// putAuthorization stores a and indexes it by the hashes of its tokens. The token of a is only
+// stored as its hash.
 func (c *Client) putAuthorization(ctx context.Context, tx *bolt.Tx, a *platform.Authorization) *platform.Error {
+	if a.Token != "" {
+		a.TokenHash = c.TokenHasher.HashToken(a.Token)
+	}
 	v, err := encodeAuthorization(a)
 	if err != nil {
 		return &platform.Error{
@@ -282,12 +446,20 @@ func (c *Client) putAuthorization(ctx context.Context, tx *bolt.Tx, a *platform.
 		}
 	}
 
-	if err := tx.Bucket(authorizationIndex).Put(authorizationIndexKey(a.Token), encodedID); err != nil {
+	if err := tx.Bucket(authorizationIndex).Put(authorizationIndexKey(a.TokenHash), encodedID); err != nil {
 		return &platform.Error{
 			Code: platform.EInternal,
 			Err:  err,
 		}
 	}
+	if a.PreviousTokenHash != "" {
+		if err := tx.Bucket(authorizationIndex).Put(authorizationIndexKey(a.PreviousTokenHash), encodedID); err != nil {
+			return &platform.Error{
+				Code: platform.EInternal,
+				Err:  err,
+			}
+		}
+	}
 
 	if err := tx.Bucket(authorizationBucket).Put(encodedID, v); err != nil {
 		return &platform.Error{
@@ -330,7 +502,7 @@ func (c *Client) forEachAuthorization(ctx context.Context, tx *bolt.Tx, fn func(
 }
 
 func (c *Client) uniqueAuthorizationToken(ctx context.Context, tx *bolt.Tx, a *platform.Authorization) bool {
-	v := tx.Bucket(authorizationIndex).Get(authorizationIndexKey(a.Token))
+	v := tx.Bucket(authorizationIndex).Get(authorizationIndexKey(c.TokenHasher.HashToken(a.Token)))
 	return len(v) == 0
 }
 
@@ -352,11 +524,18 @@ func (c *Client) deleteAuthorization(ctx context.Context, tx *bolt.Tx, id platfo
 	if pe != nil {
 		return pe
 	}
-	if err := tx.Bucket(authorizationIndex).Delete(authorizationIndexKey(a.Token)); err != nil {
+	if err := tx.Bucket(authorizationIndex).Delete(authorizationIndexKey(a.TokenHash)); err != nil {
 		return &platform.Error{
 			Err: err,
 		}
 	}
+	if a.PreviousTokenHash != "" {
+		if err := tx.Bucket(authorizationIndex).Delete(authorizationIndexKey(a.PreviousTokenHash)); err != nil {
+			return &platform.Error{
+				Err: err,
+			}
+		}
+	}
 	encodedID, err := id.Encode()
 	if err != nil {
 		return &platform.Error{
@@ -414,3 +593,82 @@ func (c *Client) updateAuthorization(ctx context.Context, tx *bolt.Tx, id platfo
 	}
 	return nil
 }
+
+// RotateAuthorization replaces the token of the authorization with a new token.
+// The previous token remains valid for gracePeriod.
+func (c *Client) RotateAuthorization(ctx context.Context, id platform.ID, gracePeriod time.Duration) (*platform.Authorization, error) {
+	var a *platform.Authorization
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		var pe *platform.Error
+		a, pe = c.rotateAuthorization(ctx, tx, id, gracePeriod)
+		if pe != nil {
+			pe.Op = getOp(platform.OpRotateAuthorization)
+			return pe
+		}
+		return nil
+	})
+	return a, err
+}
+
+func (c *Client) rotateAuthorization(ctx context.Context, tx *bolt.Tx, id platform.ID, gracePeriod time.Duration) (*platform.Authorization, *platform.Error) {
+	a, pe := c.findAuthorizationByID(ctx, tx, id)
+	if pe != nil {
+		return nil, pe
+	}
+
+	token, err := c.TokenGenerator.Token()
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+	if v := tx.Bucket(authorizationIndex).Get(authorizationIndexKey(c.TokenHasher.HashToken(token))); len(v) != 0 {
+		return nil, &platform.Error{
+			Code: platform.EConflict,
+			Msg:  "generated token is not unique",
+		}
+	}
+
+	// Only the token replaced now remains valid beside the new token.
+	idx := tx.Bucket(authorizationIndex)
+	if a.PreviousTokenHash != "" {
+		if err := idx.Delete(authorizationIndexKey(a.PreviousTokenHash)); err != nil {
+			return nil, &platform.Error{
+				Err: err,
+			}
+		}
+	}
+	a.PreviousTokenHash, a.PreviousTokenExpiresAt = "", nil
+	if gracePeriod > 0 {
+		expiresAt := c.time().Add(gracePeriod)
+		a.PreviousTokenHash, a.PreviousTokenExpiresAt = a.TokenHash, &expiresAt
+	} else if err := idx.Delete(authorizationIndexKey(a.TokenHash)); err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+	a.Token = token
+
+	if pe := c.putAuthorization(ctx, tx, a); pe != nil {
+		return nil, pe
+	}
+	return a, nil
+}
+
+var _ platform.AuthorizationUsageService = (*Client)(nil)
+
+// SetAuthorizationLastUsed records that the authorization was last used at t.
+func (c *Client) SetAuthorizationLastUsed(ctx context.Context, id platform.ID, t time.Time) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		a, pe := c.findAuthorizationByID(ctx, tx, id)
+		if pe == nil {
+			a.LastUsedAt = &t
+			pe = c.putAuthorization(ctx, tx, a)
+		}
+		if pe != nil {
+			pe.Op = getOp(platform.OpSetAuthorizationLastUsed)
+			return pe
+		}
+		return nil
+	})
+}
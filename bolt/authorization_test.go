@@ -1,11 +1,14 @@
 package bolt_test
 
 import (
+	"bytes"
 	"context"
 	"testing"
 
+	bbolt "github.com/coreos/bbolt"
 	"github.com/influxdata/platform"
 	"github.com/influxdata/platform/bolt"
+	"github.com/influxdata/platform/rand"
 	platformtesting "github.com/influxdata/platform/testing"
 )
 
@@ -16,6 +19,12 @@ func initAuthorizationService(f platformtesting.AuthorizationFields, t *testing.
 	}
 	c.IDGenerator = f.IDGenerator
 	c.TokenGenerator = f.TokenGenerator
+	if f.TokenHasher != nil {
+		c.TokenHasher = f.TokenHasher
+	}
+	if f.NowFn != nil {
+		c.WithTime(f.NowFn)
+	}
 	ctx := context.Background()
 
 	for _, u := range f.Users {
@@ -61,3 +70,160 @@ func initAuthorizationService(f platformtesting.AuthorizationFields, t *testing.
 func TestAuthorizationService(t *testing.T) {
 	platformtesting.AuthorizationService(initAuthorizationService, t)
 }
+
+func TestClient_MigrateAuthorizationTokens(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+	ctx := context.Background()
+
+	// Store an authorization the way it was stored before tokens were hashed.
+	id := platformtesting.MustIDBase16("020f755c3c082000")
+	encodedID, err := id.Encode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.DB().Update(func(tx *bbolt.Tx) error {
+		if err := tx.Bucket([]byte("authorizationsv1")).Put(encodedID, []byte(`{"id":"020f755c3c082000","orgID":"020f755c3c083000","userID":"020f755c3c084000","token":"plaintext","status":"active"}`)); err != nil {
+			return err
+		}
+		return tx.Bucket([]byte("authorizationindexv1")).Put([]byte("plaintext"), encodedID)
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	a, err := c.FindAuthorizationByToken(ctx, "plaintext")
+	if err != nil {
+		t.Fatalf("failed to find authorization by token: %v", err)
+	}
+	if a.ID != id || a.Token != "" {
+		t.Fatalf("unexpected authorization: %+v", a)
+	}
+
+	if err := c.DB().View(func(tx *bbolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bbolt.Bucket) error {
+			return b.ForEach(func(k, v []byte) error {
+				if bytes.Contains(k, []byte("plaintext")) || bytes.Contains(v, []byte("plaintext")) {
+					t.Errorf("token stored in bucket %s", name)
+				}
+				return nil
+			})
+		})
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_MigrateAuthorizationPermissions(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+	c.TokenGenerator = rand.NewTokenGenerator(64)
+	ctx := context.Background()
+
+	org := &platform.Organization{Name: "o"}
+	if err := c.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	user := &platform.User{Name: "u"}
+	if err := c.CreateUser(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	bucketID := platformtesting.MustIDBase16("020f755c3c082001")
+	a := &platform.Authorization{
+		OrgID:  org.ID,
+		UserID: user.ID,
+		Permissions: []platform.Permission{
+			{Action: platform.WriteAction, Resource: platform.BucketsResource, ID: &bucketID},
+			{Action: platform.ReadAction, Resource: platform.TasksResource, ID: &org.ID},
+			{Action: platform.WriteAction, Resource: platform.TasksResource, ID: &org.ID},
+			{Action: platform.WriteAction, Resource: platform.UsersResource},
+		},
+	}
+	if err := c.CreateAuthorization(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+
+	// Store the authorization the way it was stored before permissions were migrated.
+	if err := c.DB().Update(func(tx *bbolt.Tx) error {
+		return tx.DeleteBucket([]byte("authorizationpermissionsv2"))
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := c.FindAuthorizationByToken(ctx, a.Token)
+	if err != nil {
+		t.Fatalf("failed to find authorization by token: %v", err)
+	}
+	for _, p := range []platform.Permission{
+		{Action: platform.WriteAction, Resource: platform.BucketsResource, ID: &bucketID},
+		{Action: platform.DeleteAction, Resource: platform.BucketsResource, ID: &bucketID},
+		{Action: platform.ReadAction, Resource: platform.TasksResource, OrgID: &org.ID},
+		{Action: platform.WriteAction, Resource: platform.TasksResource, OrgID: &org.ID},
+		{Action: platform.DeleteAction, Resource: platform.TasksResource, OrgID: &org.ID},
+		{Action: platform.DeleteAction, Resource: platform.UsersResource},
+	} {
+		if !got.Allowed(p) {
+			t.Errorf("expected migrated authorization to allow %s, got %v", p, got.Permissions)
+		}
+	}
+
+	// Permissions are only migrated once, so that authorizations can be created without delete.
+	writeOnly := &platform.Authorization{
+		OrgID:       org.ID,
+		UserID:      user.ID,
+		Permissions: []platform.Permission{{Action: platform.WriteAction, Resource: platform.BucketsResource, ID: &bucketID}},
+	}
+	if err := c.CreateAuthorization(ctx, writeOnly); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	got, err = c.FindAuthorizationByToken(ctx, writeOnly.Token)
+	if err != nil {
+		t.Fatalf("failed to find authorization by token: %v", err)
+	}
+	if len(got.Permissions) != 1 {
+		t.Fatalf("unexpected permissions migrated again: %v", got.Permissions)
+	}
+}
+
+func TestClient_TokenHasher(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+	ctx := context.Background()
+
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Tokens are hashed with the key stored in the database, unless another key is given.
+	c.TokenHasher = rand.NewTokenHasher([]byte("another key"))
+	if err := c.Open(ctx); err == nil {
+		t.Fatal("expected opening the database with another token hash key to fail")
+	}
+}
@@ -3,6 +3,7 @@ package bolt
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
@@ -29,7 +30,11 @@ type Client struct {
 
 	IDGenerator    platform.IDGenerator
 	TokenGenerator platform.TokenGenerator
-	time           func() time.Time
+	// TokenHasher hashes the tokens of authorizations for storage. If nil when the client is
+	// opened, tokens are hashed with a key stored in the database. Copies of the database,
+	// such as backups, then contain the key.
+	TokenHasher platform.TokenHasher
+	time        func() time.Time
 }
 
 // NewClient returns an instance of a Client.
@@ -168,6 +173,21 @@ func (c *Client) initialize(ctx context.Context) error {
 			return err
 		}
 
+		// Always create Replications bucket.
+		if err := c.initializeReplications(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create Roles bucket.
+		if err := c.initializeRoles(ctx, tx); err != nil {
+			return err
+		}
+
+		// Always create SSO identities bucket.
+		if err := c.initializeSSOIdentities(ctx, tx); err != nil {
+			return err
+		}
+
 		return nil
 	}); err != nil {
 		return err
@@ -183,3 +203,11 @@ func (c *Client) Close() error {
 	}
 	return nil
 }
+
+// Backup writes a consistent copy of the bolt database to w while the database remains open.
+func (c *Client) Backup(ctx context.Context, w io.Writer) error {
+	return c.db.View(func(tx *bolt.Tx) error {
+		_, err := tx.WriteTo(w)
+		return err
+	})
+}
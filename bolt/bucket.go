@@ -497,16 +497,50 @@ func (c *Client) updateBucket(ctx context.Context, tx *bolt.Tx, id platform.ID,
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
-	if upd.Name != nil {
+	if upd.ShardGroupDuration != nil {
+		b.ShardGroupDuration = *upd.ShardGroupDuration
+	}
+
+	if upd.ColdStorageAfter != nil {
+		b.ColdStorageAfter = *upd.ColdStorageAfter
+	}
+
+	if upd.SchemaType != nil {
+		b.SchemaType = *upd.SchemaType
+	}
+
+	if upd.MeasurementSchemas != nil {
+		b.MeasurementSchemas = upd.MeasurementSchemas
+	}
+
+	if upd.DownsampleTiers != nil {
+		b.DownsampleTiers = upd.DownsampleTiers
+	}
+
+	if upd.Name != nil || upd.OrganizationID != nil {
 		key, err := bucketIndexKey(b)
 		if err != nil {
 			return nil, err
 		}
-		// Buckets are indexed by name and so the bucket index must be pruned when name is modified.
+		// Buckets are indexed by organization and name and so the bucket index must be pruned when either is modified.
 		if err := tx.Bucket(bucketIndex).Delete(key); err != nil {
 			return nil, err
 		}
-		b.Name = *upd.Name
+		if upd.Name != nil {
+			b.Name = *upd.Name
+		}
+		if upd.OrganizationID != nil {
+			if _, err := c.findOrganizationByID(ctx, tx, *upd.OrganizationID); err != nil {
+				return nil, err
+			}
+			b.OrganizationID = *upd.OrganizationID
+		}
+		if !c.uniqueBucketName(ctx, tx, b) {
+			return nil, &platform.Error{
+				Code: platform.EConflict,
+				Msg:  fmt.Sprintf("bucket with name %s already exists", b.Name),
+			}
+		}
 	}
 
 	if err := c.appendBucketEventToLog(ctx, tx, b.ID, bucketUpdatedEvent); err != nil {
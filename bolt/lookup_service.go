@@ -57,6 +57,26 @@ func (c *Client) Name(ctx context.Context, resource platform.Resource, id platfo
 			return "", err
 		}
 		return r.Name, nil
+	case platform.MacrosResource: // 8
+		r, err := c.FindMacroByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return r.Name, nil
+	case platform.ViewsResource: // 9
+		r, err := c.FindViewByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return r.Name, nil
+	case platform.ScrapersResource: // 10
+		r, err := c.GetTargetByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return r.Name, nil
+	case platform.SecretsResource: // 11 secrets are identified by key, not ID
+	case platform.LabelsResource: // 12 labels are identified by name
 	}
 
 	return "", nil
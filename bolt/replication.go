@@ -0,0 +1,174 @@
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/influxdata/platform"
+)
+
+var (
+	replicationBucket = []byte("replicationsv1")
+)
+
+var _ platform.ReplicationService = (*Client)(nil)
+
+func (c *Client) initializeReplications(ctx context.Context, tx *bolt.Tx) error {
+	if _, err := tx.CreateBucketIfNotExists(replicationBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FindReplicationByID returns a single replication by ID.
+func (c *Client) FindReplicationByID(ctx context.Context, id platform.ID) (*platform.Replication, error) {
+	var r *platform.Replication
+	err := c.db.View(func(tx *bolt.Tx) error {
+		rep, pe := c.findReplicationByID(ctx, tx, id)
+		if pe != nil {
+			pe.Op = getOp(platform.OpFindReplicationByID)
+			return pe
+		}
+		r = rep
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (c *Client) findReplicationByID(ctx context.Context, tx *bolt.Tx, id platform.ID) (*platform.Replication, *platform.Error) {
+	encID, err := id.Encode()
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	v := tx.Bucket(replicationBucket).Get(encID)
+	if v == nil {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  platform.ErrReplicationNotFound,
+		}
+	}
+
+	r := &platform.Replication{}
+	if err := json.Unmarshal(v, r); err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+	return r, nil
+}
+
+// FindReplications returns a list of replications that match filter and the total count of matching replications.
+func (c *Client) FindReplications(ctx context.Context, filter platform.ReplicationFilter) ([]*platform.Replication, int, error) {
+	op := getOp(platform.OpFindReplications)
+	if filter.ID != nil {
+		r, err := c.FindReplicationByID(ctx, *filter.ID)
+		if err != nil {
+			return nil, 0, &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		return []*platform.Replication{r}, 1, nil
+	}
+
+	rs := []*platform.Replication{}
+	err := c.db.View(func(tx *bolt.Tx) error {
+		cur := tx.Bucket(replicationBucket).Cursor()
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			r := &platform.Replication{}
+			if err := json.Unmarshal(v, r); err != nil {
+				return err
+			}
+			if filter.OrganizationID != nil && r.OrganizationID != *filter.OrganizationID {
+				continue
+			}
+			if filter.LocalBucketID != nil && r.LocalBucketID != *filter.LocalBucketID {
+				continue
+			}
+			rs = append(rs, r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	return rs, len(rs), nil
+}
+
+// CreateReplication creates a new replication and sets r.ID with the new identifier.
+func (c *Client) CreateReplication(ctx context.Context, r *platform.Replication) error {
+	op := getOp(platform.OpCreateReplication)
+	if err := r.Valid(); err != nil {
+		return &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		r.ID = c.IDGenerator.ID()
+		encID, err := r.ID.Encode()
+		if err != nil {
+			return &platform.Error{
+				Code: platform.EInvalid,
+				Op:   op,
+				Err:  err,
+			}
+		}
+
+		v, err := json.Marshal(r)
+		if err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+
+		if err := tx.Bucket(replicationBucket).Put(encID, v); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteReplication removes a replication by ID.
+func (c *Client) DeleteReplication(ctx context.Context, id platform.ID) error {
+	op := getOp(platform.OpDeleteReplication)
+	return c.db.Update(func(tx *bolt.Tx) error {
+		if _, pe := c.findReplicationByID(ctx, tx, id); pe != nil {
+			pe.Op = op
+			return pe
+		}
+
+		encID, err := id.Encode()
+		if err != nil {
+			return &platform.Error{
+				Code: platform.EInvalid,
+				Op:   op,
+				Err:  err,
+			}
+		}
+
+		if err := tx.Bucket(replicationBucket).Delete(encID); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		return nil
+	})
+}
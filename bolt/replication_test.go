@@ -0,0 +1,61 @@
+package bolt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/platform"
+)
+
+func TestReplicationService(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+	ctx := context.Background()
+
+	if err := c.CreateReplication(ctx, &platform.Replication{Name: "r"}); platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("expected invalid replication error, got %v", err)
+	}
+
+	rs := []*platform.Replication{
+		{OrganizationID: 1, Name: "a", LocalBucketID: 10},
+		{OrganizationID: 1, Name: "b", LocalBucketID: 11},
+		{OrganizationID: 2, Name: "c", LocalBucketID: 12},
+	}
+	for _, r := range rs {
+		r.RemoteURL = "http://localhost:9999"
+		r.RemoteOrganizationID, r.RemoteBucketID = 100, 101
+		r.TokenSecretKey = "token"
+		if err := c.CreateReplication(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if r, err := c.FindReplicationByID(ctx, rs[1].ID); err != nil {
+		t.Fatal(err)
+	} else if *r != *rs[1] {
+		t.Fatalf("unexpected replication %+v", r)
+	}
+
+	orgID, bucketID := platform.ID(1), platform.ID(11)
+	if found, n, err := c.FindReplications(ctx, platform.ReplicationFilter{OrganizationID: &orgID}); err != nil || n != 2 {
+		t.Fatalf("expected 2 replications of the organization, got %d: %v", n, err)
+	} else if found[0].Name != "a" || found[1].Name != "b" {
+		t.Fatalf("unexpected replications %+v", found)
+	}
+	if found, n, err := c.FindReplications(ctx, platform.ReplicationFilter{LocalBucketID: &bucketID}); err != nil || n != 1 || found[0].ID != rs[1].ID {
+		t.Fatalf("expected the replication of the bucket, got %d: %v", n, err)
+	}
+
+	if err := c.DeleteReplication(ctx, rs[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.FindReplicationByID(ctx, rs[0].ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected deleted replication not to be found, got %v", err)
+	}
+	if err := c.DeleteReplication(ctx, rs[0].ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected not found deleting a deleted replication, got %v", err)
+	}
+}
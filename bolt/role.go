@@ -0,0 +1,296 @@
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/influxdata/platform"
+)
+
+var (
+	roleBucket = []byte("rolesv1")
+	roleIndex  = []byte("rolesindexv1")
+)
+
+var _ platform.RoleService = (*Client)(nil)
+
+func (c *Client) initializeRoles(ctx context.Context, tx *bolt.Tx) error {
+	if _, err := tx.CreateBucketIfNotExists(roleBucket); err != nil {
+		return err
+	}
+	if _, err := tx.CreateBucketIfNotExists(roleIndex); err != nil {
+		return err
+	}
+	return nil
+}
+
+// roleIndexKey is the key of the role in the index of role names, which are unique within an organization.
+func roleIndexKey(r *platform.Role) ([]byte, *platform.Error) {
+	orgID, err := r.OrganizationID.Encode()
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	k := make([]byte, platform.IDLength+len(r.Name))
+	copy(k, orgID)
+	copy(k[platform.IDLength:], []byte(r.Name))
+	return k, nil
+}
+
+// FindRoleByID returns a single role by ID.
+func (c *Client) FindRoleByID(ctx context.Context, id platform.ID) (*platform.Role, error) {
+	var r *platform.Role
+	err := c.db.View(func(tx *bolt.Tx) error {
+		role, pe := c.findRoleByID(ctx, tx, id)
+		if pe != nil {
+			pe.Op = getOp(platform.OpFindRoleByID)
+			return pe
+		}
+		r = role
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (c *Client) findRoleByID(ctx context.Context, tx *bolt.Tx, id platform.ID) (*platform.Role, *platform.Error) {
+	encID, err := id.Encode()
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	v := tx.Bucket(roleBucket).Get(encID)
+	if v == nil {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  platform.ErrRoleNotFound,
+		}
+	}
+
+	r := &platform.Role{}
+	if err := json.Unmarshal(v, r); err != nil {
+		return nil, &platform.Error{
+			Err: err,
+		}
+	}
+	return r, nil
+}
+
+// FindRoles returns a list of roles that match filter and the total count of matching roles.
+func (c *Client) FindRoles(ctx context.Context, filter platform.RoleFilter) ([]*platform.Role, int, error) {
+	op := getOp(platform.OpFindRoles)
+	if filter.ID != nil {
+		r, err := c.FindRoleByID(ctx, *filter.ID)
+		if err != nil {
+			return nil, 0, &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		return []*platform.Role{r}, 1, nil
+	}
+
+	rs := []*platform.Role{}
+	err := c.db.View(func(tx *bolt.Tx) error {
+		cur := tx.Bucket(roleBucket).Cursor()
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			r := &platform.Role{}
+			if err := json.Unmarshal(v, r); err != nil {
+				return err
+			}
+			if filter.OrganizationID != nil && r.OrganizationID != *filter.OrganizationID {
+				continue
+			}
+			if filter.Name != nil && r.Name != *filter.Name {
+				continue
+			}
+			rs = append(rs, r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	return rs, len(rs), nil
+}
+
+// CreateRole creates a new role and sets r.ID with the new identifier.
+func (c *Client) CreateRole(ctx context.Context, r *platform.Role) error {
+	op := getOp(platform.OpCreateRole)
+	if err := r.Valid(); err != nil {
+		return &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		if _, pe := c.findOrganizationByID(ctx, tx, r.OrganizationID); pe != nil {
+			pe.Op = op
+			return pe
+		}
+
+		r.ID = c.IDGenerator.ID()
+		if pe := c.putRole(ctx, tx, r); pe != nil {
+			pe.Op = op
+			return pe
+		}
+		return nil
+	})
+}
+
+// PutRole puts a role in the store.
+func (c *Client) PutRole(ctx context.Context, r *platform.Role) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		if pe := c.putRole(ctx, tx, r); pe != nil {
+			return pe
+		}
+		return nil
+	})
+}
+
+// putRole stores r, and its name in the index unless another role of the organization has it.
+func (c *Client) putRole(ctx context.Context, tx *bolt.Tx, r *platform.Role) *platform.Error {
+	encID, err := r.ID.Encode()
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+
+	key, pe := roleIndexKey(r)
+	if pe != nil {
+		return pe
+	}
+	if id := tx.Bucket(roleIndex).Get(key); id != nil && string(id) != string(encID) {
+		return &platform.Error{
+			Code: platform.EConflict,
+			Msg:  fmt.Sprintf("role with name %s already exists", r.Name),
+		}
+	}
+
+	v, err := json.Marshal(r)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+	if err := tx.Bucket(roleIndex).Put(key, encID); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+	if err := tx.Bucket(roleBucket).Put(encID, v); err != nil {
+		return &platform.Error{
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// UpdateRole updates a single role with changeset.
+func (c *Client) UpdateRole(ctx context.Context, id platform.ID, upd platform.RoleUpdate) (*platform.Role, error) {
+	op := getOp(platform.OpUpdateRole)
+	var r *platform.Role
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		role, pe := c.findRoleByID(ctx, tx, id)
+		if pe != nil {
+			pe.Op = op
+			return pe
+		}
+
+		key, pe := roleIndexKey(role)
+		if pe != nil {
+			pe.Op = op
+			return pe
+		}
+
+		upd.Apply(role)
+		if err := role.Valid(); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+
+		if err := tx.Bucket(roleIndex).Delete(key); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		if pe := c.putRole(ctx, tx, role); pe != nil {
+			pe.Op = op
+			return pe
+		}
+		r = role
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// DeleteRole removes a role by ID, and its assignments to users.
+func (c *Client) DeleteRole(ctx context.Context, id platform.ID) error {
+	op := getOp(platform.OpDeleteRole)
+	return c.db.Update(func(tx *bolt.Tx) error {
+		r, pe := c.findRoleByID(ctx, tx, id)
+		if pe != nil {
+			pe.Op = op
+			return pe
+		}
+
+		key, pe := roleIndexKey(r)
+		if pe != nil {
+			pe.Op = op
+			return pe
+		}
+		if err := tx.Bucket(roleIndex).Delete(key); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+
+		encID, err := id.Encode()
+		if err != nil {
+			return &platform.Error{
+				Code: platform.EInvalid,
+				Op:   op,
+				Err:  err,
+			}
+		}
+		if err := tx.Bucket(roleBucket).Delete(encID); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+
+		if err := c.deleteUserResourceMappings(ctx, tx, platform.UserResourceMappingFilter{
+			ResourceID: id,
+			Resource:   platform.RolesResource,
+		}); err != nil {
+			return &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		return nil
+	})
+}
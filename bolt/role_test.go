@@ -0,0 +1,108 @@
+package bolt_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bolt"
+	platformtesting "github.com/influxdata/platform/testing"
+)
+
+func initRoleService(f platformtesting.RoleFields, t *testing.T) (platform.RoleService, string, func()) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	c.IDGenerator = f.IDGenerator
+	ctx := context.Background()
+	for _, o := range f.Organizations {
+		if err := c.PutOrganization(ctx, o); err != nil {
+			t.Fatalf("failed to populate organizations: %v", err)
+		}
+	}
+	for _, r := range f.Roles {
+		if err := c.PutRole(ctx, r); err != nil {
+			t.Fatalf("failed to populate roles: %v", err)
+		}
+	}
+	return c, bolt.OpPrefix, closeFn
+}
+
+func TestRoleService_CreateRole(t *testing.T) {
+	platformtesting.CreateRole(initRoleService, t)
+}
+
+func TestRoleService_FindRoleByID(t *testing.T) {
+	platformtesting.FindRoleByID(initRoleService, t)
+}
+
+func TestRoleService_FindRoles(t *testing.T) {
+	platformtesting.FindRoles(initRoleService, t)
+}
+
+func TestRoleService_UpdateRole(t *testing.T) {
+	platformtesting.UpdateRole(initRoleService, t)
+}
+
+func TestRoleService_DeleteRole(t *testing.T) {
+	platformtesting.DeleteRole(initRoleService, t)
+}
+
+func TestClient_FindSession_Roles(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+
+	ctx := context.Background()
+	orgID, userID := platformtesting.MustIDBase16("020f755c3c083000"), platformtesting.MustIDBase16("020f755c3c082000")
+	roles := []*platform.Role{
+		{ID: platformtesting.MustIDBase16("020f755c3c085000"), OrganizationID: orgID, Name: "viewer", Permissions: platform.ViewerPermissions(orgID)},
+		{ID: platformtesting.MustIDBase16("020f755c3c085001"), OrganizationID: orgID, Name: "editor", Permissions: platform.EditorPermissions(orgID)},
+	}
+	for _, r := range roles {
+		if err := c.PutRole(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.CreateUserResourceMapping(ctx, &platform.UserResourceMapping{
+			ResourceID: r.ID,
+			Resource:   platform.RolesResource,
+			UserID:     userID,
+			UserType:   platform.Member,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := c.PutSession(ctx, &platform.Session{ID: platformtesting.MustIDBase16("020f755c3c086000"), Key: "abc123", UserID: userID, ExpiresAt: time.Now().Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	readBuckets := platform.Permission{Action: platform.ReadAction, Resource: platform.BucketsResource, OrgID: &orgID}
+	writeBuckets := platform.Permission{Action: platform.WriteAction, Resource: platform.BucketsResource, OrgID: &orgID}
+
+	s, err := c.FindSession(ctx, "abc123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !platform.PermissionAllowed(readBuckets, s.Permissions) || !platform.PermissionAllowed(writeBuckets, s.Permissions) {
+		t.Fatalf("expected the session to be granted the permissions of its roles, got %v", s.Permissions)
+	}
+
+	// Deleting a role removes its assignments, and so its permissions from sessions.
+	if err := c.DeleteRole(ctx, roles[1].ID); err != nil {
+		t.Fatal(err)
+	}
+	s, err = c.FindSession(ctx, "abc123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !platform.PermissionAllowed(readBuckets, s.Permissions) {
+		t.Errorf("expected the session to be granted the permissions of the remaining role, got %v", s.Permissions)
+	}
+	if platform.PermissionAllowed(writeBuckets, s.Permissions) {
+		t.Errorf("expected the session not to be granted the permissions of the deleted role, got %v", s.Permissions)
+	}
+}
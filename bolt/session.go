@@ -88,6 +88,17 @@ func (c *Client) findSession(ctx context.Context, tx *bolt.Tx, key string) (*pla
 		}
 
 		ps = append(ps, p...)
+
+		// A user is granted the permissions of the roles assigned to the user.
+		if m.Resource == platform.RolesResource {
+			r, pe := c.findRoleByID(ctx, tx, m.ResourceID)
+			if pe != nil && pe.Code != platform.ENotFound {
+				return nil, pe
+			}
+			if pe == nil {
+				ps = append(ps, r.Permissions...)
+			}
+		}
 	}
 	s.Permissions = ps
 	return s, nil
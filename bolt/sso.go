@@ -0,0 +1,74 @@
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+
+	bolt "github.com/coreos/bbolt"
+	"github.com/influxdata/platform"
+)
+
+var ssoIdentityBucket = []byte("ssoidentitiesv1")
+
+var _ platform.SSOIdentityService = (*Client)(nil)
+
+func (c *Client) initializeSSOIdentities(ctx context.Context, tx *bolt.Tx) error {
+	if _, err := tx.CreateBucketIfNotExists(ssoIdentityBucket); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ssoIdentityKey is the key of the identity of the subject of the provider. Provider
+// names do not contain NUL, so keys of different providers do not collide.
+func ssoIdentityKey(provider, subject string) []byte {
+	return []byte(provider + "\x00" + subject)
+}
+
+// FindSSOIdentity returns the identity of the subject of the provider.
+func (c *Client) FindSSOIdentity(ctx context.Context, provider, subject string) (*platform.SSOIdentity, error) {
+	var i *platform.SSOIdentity
+	err := c.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(ssoIdentityBucket).Get(ssoIdentityKey(provider, subject))
+		if v == nil {
+			return &platform.Error{
+				Code: platform.ENotFound,
+				Op:   getOp(platform.OpFindSSOIdentity),
+				Msg:  platform.ErrSSOIdentityNotFound,
+			}
+		}
+
+		i = &platform.SSOIdentity{}
+		if err := json.Unmarshal(v, i); err != nil {
+			return &platform.Error{
+				Op:  getOp(platform.OpFindSSOIdentity),
+				Err: err,
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// PutSSOIdentity links the subject of the provider to the user of the identity.
+func (c *Client) PutSSOIdentity(ctx context.Context, i *platform.SSOIdentity) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		v, err := json.Marshal(i)
+		if err != nil {
+			return &platform.Error{
+				Op:  getOp(platform.OpPutSSOIdentity),
+				Err: err,
+			}
+		}
+		if err := tx.Bucket(ssoIdentityBucket).Put(ssoIdentityKey(i.Provider, i.Subject), v); err != nil {
+			return &platform.Error{
+				Op:  getOp(platform.OpPutSSOIdentity),
+				Err: err,
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,38 @@
+package bolt_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/platform"
+)
+
+func TestClient_SSOIdentity(t *testing.T) {
+	c, closeFn, err := NewTestClient()
+	if err != nil {
+		t.Fatalf("failed to create new bolt client: %v", err)
+	}
+	defer closeFn()
+
+	ctx := context.Background()
+	if _, err := c.FindSSOIdentity(ctx, "github", "alice"); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected identity not to be found, got %v", err)
+	}
+
+	want := &platform.SSOIdentity{Provider: "github", Subject: "alice", UserID: platform.ID(1)}
+	if err := c.PutSSOIdentity(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := c.FindSSOIdentity(ctx, "github", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != *want {
+		t.Fatalf("unexpected identity: got %+v, want %+v", got, want)
+	}
+
+	// Subjects are linked per provider.
+	if _, err := c.FindSSOIdentity(ctx, "google", "alice"); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected identity of another provider not to be found, got %v", err)
+	}
+}
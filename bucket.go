@@ -25,6 +25,247 @@ type Bucket struct {
 	Name                string        `json:"name"`
 	RetentionPolicyName string        `json:"rp,omitempty"` // This to support v1 sources
 	RetentionPeriod     time.Duration `json:"retentionPeriod"`
+
+	// ShardGroupDuration is the duration of the time partitions of the stored data of the bucket,
+	// which expires a partition at a time. The default depends on the retention period.
+	ShardGroupDuration time.Duration `json:"shardGroupDuration,omitempty"`
+
+	// ColdStorageAfter is the age of the data of the bucket after which it is offloaded to the
+	// cold storage of the engine, if it has one. Zero keeps all data in local storage.
+	ColdStorageAfter time.Duration `json:"coldStorageAfter,omitempty"`
+
+	// SchemaType determines whether writes must conform to MeasurementSchemas.
+	SchemaType         SchemaType          `json:"schemaType,omitempty"`
+	MeasurementSchemas []MeasurementSchema `json:"measurementSchemas,omitempty"`
+
+	// DownsampleTiers are the rollups of the bucket's data, from the finest to the coarsest.
+	DownsampleTiers []DownsampleTier `json:"downsampleTiers,omitempty"`
+}
+
+// MinShardGroupDuration is the shortest shard group duration of a bucket.
+const MinShardGroupDuration = time.Hour
+
+// DefaultShardGroupDuration returns the shard group duration of a bucket with the
+// retention period rp that does not set its own.
+func DefaultShardGroupDuration(rp time.Duration) time.Duration {
+	switch {
+	case rp == InfiniteRetention || rp >= 180*24*time.Hour:
+		return 7 * 24 * time.Hour
+	case rp >= 2*24*time.Hour:
+		return 24 * time.Hour
+	default:
+		return time.Hour
+	}
+}
+
+// ValidateShardGroupDuration returns an error if d is neither zero, for the default, nor at least MinShardGroupDuration.
+func ValidateShardGroupDuration(d time.Duration) error {
+	if d != 0 && d < MinShardGroupDuration {
+		return &Error{Code: EInvalid, Msg: fmt.Sprintf("shard group duration must be at least %s", MinShardGroupDuration)}
+	}
+	return nil
+}
+
+// ValidateColdStorageAfter returns an error if d is negative.
+func ValidateColdStorageAfter(d time.Duration) error {
+	if d < 0 {
+		return &Error{Code: EInvalid, Msg: "cold storage threshold must not be negative"}
+	}
+	return nil
+}
+
+// SchemaType determines how the shape of the data in a bucket is defined.
+type SchemaType string
+
+const (
+	// SchemaTypeImplicit buckets accept any measurement, tags, and fields; this is the default.
+	SchemaTypeImplicit SchemaType = "implicit"
+	// SchemaTypeExplicit buckets only accept points matching one of the bucket's measurement schemas.
+	SchemaTypeExplicit SchemaType = "explicit"
+)
+
+// Valid returns an error if t is not a known schema type. The empty schema type is implicit.
+func (t SchemaType) Valid() error {
+	switch t {
+	case "", SchemaTypeImplicit, SchemaTypeExplicit:
+		return nil
+	default:
+		return &Error{
+			Code: EInvalid,
+			Msg:  fmt.Sprintf("unknown schema type %q; valid types are implicit and explicit", string(t)),
+		}
+	}
+}
+
+// SchemaFieldType is the type of the values of a field in a measurement schema.
+type SchemaFieldType string
+
+// Field types allowed in a measurement schema.
+const (
+	SchemaFieldTypeFloat    SchemaFieldType = "float"
+	SchemaFieldTypeInteger  SchemaFieldType = "integer"
+	SchemaFieldTypeUnsigned SchemaFieldType = "unsigned"
+	SchemaFieldTypeString   SchemaFieldType = "string"
+	SchemaFieldTypeBoolean  SchemaFieldType = "boolean"
+)
+
+// MeasurementSchema is the shape of a measurement in a bucket with an explicit schema.
+// Points of the measurement must have every tag key in Tags and no others,
+// and may only have the fields listed in Fields, with values of the listed types.
+type MeasurementSchema struct {
+	Name   string        `json:"name"`
+	Tags   []string      `json:"tags,omitempty"`
+	Fields []FieldSchema `json:"fields"`
+}
+
+// FieldSchema is the name and value type of a field in a measurement schema.
+type FieldSchema struct {
+	Name string          `json:"name"`
+	Type SchemaFieldType `json:"type"`
+}
+
+// ValidateSchema returns an error if the schema type or measurement schemas of b are malformed.
+func (b *Bucket) ValidateSchema() error {
+	if err := b.SchemaType.Valid(); err != nil {
+		return err
+	}
+	return ValidateMeasurementSchemas(b.MeasurementSchemas)
+}
+
+// ValidateMeasurementSchemas returns an error if any measurement schema is malformed
+// or more than one schema has the same measurement name.
+func ValidateMeasurementSchemas(ms []MeasurementSchema) error {
+	names := make(map[string]bool, len(ms))
+	for _, m := range ms {
+		if m.Name == "" {
+			return &Error{Code: EInvalid, Msg: "measurement schema requires a name"}
+		}
+		if names[m.Name] {
+			return &Error{Code: EInvalid, Msg: fmt.Sprintf("duplicate schema for measurement %q", m.Name)}
+		}
+		names[m.Name] = true
+
+		if len(m.Fields) == 0 {
+			return &Error{Code: EInvalid, Msg: fmt.Sprintf("schema for measurement %q requires at least one field", m.Name)}
+		}
+		fields := make(map[string]bool, len(m.Fields))
+		for _, f := range m.Fields {
+			if f.Name == "" {
+				return &Error{Code: EInvalid, Msg: fmt.Sprintf("field in schema for measurement %q requires a name", m.Name)}
+			}
+			if fields[f.Name] {
+				return &Error{Code: EInvalid, Msg: fmt.Sprintf("duplicate field %q in schema for measurement %q", f.Name, m.Name)}
+			}
+			fields[f.Name] = true
+
+			switch f.Type {
+			case SchemaFieldTypeFloat, SchemaFieldTypeInteger, SchemaFieldTypeUnsigned, SchemaFieldTypeString, SchemaFieldTypeBoolean:
+			default:
+				return &Error{
+					Code: EInvalid,
+					Msg:  fmt.Sprintf("field %q in schema for measurement %q has unknown type %q", f.Name, m.Name, string(f.Type)),
+				}
+			}
+		}
+
+		tags := make(map[string]bool, len(m.Tags))
+		for _, k := range m.Tags {
+			if k == "" {
+				return &Error{Code: EInvalid, Msg: fmt.Sprintf("tag in schema for measurement %q requires a name", m.Name)}
+			}
+			if tags[k] || fields[k] {
+				return &Error{Code: EInvalid, Msg: fmt.Sprintf("duplicate tag %q in schema for measurement %q", k, m.Name)}
+			}
+			tags[k] = true
+		}
+	}
+	return nil
+}
+
+// DownsampleTier is a rollup of the data of a bucket into windows of Every,
+// kept for RetentionPeriod in a bucket that is managed along with the source bucket.
+// Each aggregate is written to the target bucket with an "aggregate" tag naming it.
+// The aggregates a tier shares with the previous tier are rolled up from the previous
+// tier's bucket, and the others from the source bucket.
+type DownsampleTier struct {
+	Every           time.Duration `json:"every"`
+	Aggregates      []string      `json:"aggregates"`
+	RetentionPeriod time.Duration `json:"retentionPeriod"`
+	// Offset delays the rollup of each window, so that points written late are included.
+	Offset time.Duration `json:"offset,omitempty"`
+	// Fields names the numeric fields of the source bucket rolled up by the mean, median,
+	// and sum aggregates. It defaults to the numeric fields of a bucket with an explicit schema.
+	Fields []string `json:"fields,omitempty"`
+
+	// BucketID and TaskID identify the target bucket and the rollup task of the tier.
+	// They are set by the service managing the tiers and ignored on input.
+	BucketID ID `json:"bucketID,omitempty"`
+	TaskID   ID `json:"taskID,omitempty"`
+}
+
+// Downsample aggregates, which can be applied to the windows of a tier.
+const (
+	DownsampleMean   = "mean"
+	DownsampleMedian = "median"
+	DownsampleSum    = "sum"
+	DownsampleCount  = "count"
+	DownsampleMin    = "min"
+	DownsampleMax    = "max"
+	DownsampleFirst  = "first"
+	DownsampleLast   = "last"
+)
+
+// ValidateDownsampleTiers returns an error if any tier is malformed, or the tiers are not
+// ordered from the finest to the coarsest window with non-decreasing retention periods.
+// The window of each tier must be a multiple of the window of the previous tier, which must
+// be retained for at least the window of the tier that rolls it up.
+func ValidateDownsampleTiers(tiers []DownsampleTier) error {
+	for i, t := range tiers {
+		if t.Every < time.Second || t.Every%time.Second != 0 {
+			return &Error{Code: EInvalid, Msg: "downsample tier window must be a whole number of seconds, of at least one second"}
+		}
+		if t.RetentionPeriod != InfiniteRetention && t.RetentionPeriod < t.Every {
+			return &Error{Code: EInvalid, Msg: fmt.Sprintf("retention period of downsample tier %s must be at least its window", t.Every)}
+		}
+		if t.Offset < 0 || t.Offset%time.Second != 0 {
+			return &Error{Code: EInvalid, Msg: fmt.Sprintf("offset of downsample tier %s must be a non-negative whole number of seconds", t.Every)}
+		}
+		if len(t.Aggregates) == 0 {
+			return &Error{Code: EInvalid, Msg: fmt.Sprintf("downsample tier %s requires at least one aggregate", t.Every)}
+		}
+		seen := make(map[string]bool, len(t.Aggregates))
+		for _, a := range t.Aggregates {
+			switch a {
+			case DownsampleMean, DownsampleMedian, DownsampleSum, DownsampleCount,
+				DownsampleMin, DownsampleMax, DownsampleFirst, DownsampleLast:
+			default:
+				return &Error{Code: EInvalid, Msg: fmt.Sprintf("unknown aggregate %q in downsample tier %s", a, t.Every)}
+			}
+			if seen[a] {
+				return &Error{Code: EInvalid, Msg: fmt.Sprintf("duplicate aggregate %q in downsample tier %s", a, t.Every)}
+			}
+			seen[a] = true
+		}
+
+		if i == 0 {
+			continue
+		}
+		prev := tiers[i-1]
+		if t.Every <= prev.Every {
+			return &Error{Code: EInvalid, Msg: "downsample tiers must be ordered by increasing window"}
+		}
+		if t.Every%prev.Every != 0 {
+			return &Error{Code: EInvalid, Msg: fmt.Sprintf("window of downsample tier %s must be a multiple of the window of tier %s", t.Every, prev.Every)}
+		}
+		if prev.RetentionPeriod != InfiniteRetention && prev.RetentionPeriod < t.Every {
+			return &Error{Code: EInvalid, Msg: fmt.Sprintf("downsample tier %s must be retained for at least the window of tier %s", prev.Every, t.Every)}
+		}
+		if prev.RetentionPeriod == InfiniteRetention && t.RetentionPeriod != InfiniteRetention ||
+			t.RetentionPeriod != InfiniteRetention && t.RetentionPeriod < prev.RetentionPeriod {
+			return &Error{Code: EInvalid, Msg: fmt.Sprintf("downsample tier %s must be retained at least as long as tier %s", t.Every, prev.Every)}
+		}
+	}
+	return nil
 }
 
 // ops for buckets error and buckets op logs.
@@ -63,8 +304,19 @@ type BucketService interface {
 // BucketUpdate represents updates to a bucket.
 // Only fields which are set are updated.
 type BucketUpdate struct {
-	Name            *string        `json:"name,omitempty"`
-	RetentionPeriod *time.Duration `json:"retentionPeriod,omitempty"`
+	Name               *string        `json:"name,omitempty"`
+	RetentionPeriod    *time.Duration `json:"retentionPeriod,omitempty"`
+	ShardGroupDuration *time.Duration `json:"shardGroupDuration,omitempty"`
+	ColdStorageAfter   *time.Duration `json:"coldStorageAfter,omitempty"`
+	SchemaType         *SchemaType    `json:"schemaType,omitempty"`
+	// MeasurementSchemas replaces the bucket's measurement schemas when it is not nil.
+	MeasurementSchemas []MeasurementSchema `json:"measurementSchemas,omitempty"`
+	// DownsampleTiers replaces the bucket's downsample tiers when it is not nil.
+	DownsampleTiers []DownsampleTier `json:"downsampleTiers,omitempty"`
+	// OrganizationID moves the bucket to another organization, without moving its stored data.
+	// It is set by BucketTransferService.MoveBucket once the data has been copied, and is not
+	// part of the bucket updates of the HTTP API, which moves buckets with the transfer endpoint.
+	OrganizationID *ID `json:"orgID,omitempty"`
 }
 
 // BucketFilter represents a set of filter that restrict the returned results.
@@ -99,5 +351,5 @@ func (f BucketFilter) QueryParams() map[string][]string {
 
 // InternalBucketID returns the ID for an organization's specified internal bucket
 func InternalBucketID(t BucketType) (*ID, error) {
-	return IDFromString(fmt.Sprintf("%d", t))
+	return IDFromString(fmt.Sprintf("%016x", int(t)))
 }
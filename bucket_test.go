@@ -0,0 +1,202 @@
+package platform_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform"
+)
+
+func TestBucketValidateSchema(t *testing.T) {
+	usage := []platform.FieldSchema{{Name: "usage", Type: platform.SchemaFieldTypeFloat}}
+
+	tests := []struct {
+		name    string
+		bucket  platform.Bucket
+		wantErr bool
+	}{
+		{
+			name:   "no schema",
+			bucket: platform.Bucket{},
+		},
+		{
+			name: "explicit",
+			bucket: platform.Bucket{
+				SchemaType: platform.SchemaTypeExplicit,
+				MeasurementSchemas: []platform.MeasurementSchema{
+					{Name: "cpu", Tags: []string{"host"}, Fields: usage},
+					{Name: "mem", Fields: []platform.FieldSchema{{Name: "used", Type: platform.SchemaFieldTypeUnsigned}}},
+				},
+			},
+		},
+		{
+			name:    "unknown schema type",
+			bucket:  platform.Bucket{SchemaType: "strict"},
+			wantErr: true,
+		},
+		{
+			name: "duplicate measurement",
+			bucket: platform.Bucket{
+				MeasurementSchemas: []platform.MeasurementSchema{
+					{Name: "cpu", Fields: usage},
+					{Name: "cpu", Fields: usage},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "no fields",
+			bucket: platform.Bucket{
+				MeasurementSchemas: []platform.MeasurementSchema{{Name: "cpu"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown field type",
+			bucket: platform.Bucket{
+				MeasurementSchemas: []platform.MeasurementSchema{
+					{Name: "cpu", Fields: []platform.FieldSchema{{Name: "usage", Type: "double"}}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "tag and field with the same name",
+			bucket: platform.Bucket{
+				MeasurementSchemas: []platform.MeasurementSchema{
+					{Name: "cpu", Tags: []string{"usage"}, Fields: usage},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.bucket.ValidateSchema()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateSchema() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && platform.ErrorCode(err) != platform.EInvalid {
+				t.Fatalf("expected invalid error code, got %q", platform.ErrorCode(err))
+			}
+		})
+	}
+}
+
+func TestValidateDownsampleTiers(t *testing.T) {
+	day := 24 * time.Hour
+	meanMinMax := []string{platform.DownsampleMean, platform.DownsampleMin, platform.DownsampleMax}
+
+	tests := []struct {
+		name    string
+		tiers   []platform.DownsampleTier
+		wantErr bool
+	}{
+		{
+			name: "no tiers",
+		},
+		{
+			name: "tiers",
+			tiers: []platform.DownsampleTier{
+				{Every: time.Minute, Aggregates: meanMinMax, RetentionPeriod: 90 * day},
+				{Every: time.Hour, Aggregates: meanMinMax, RetentionPeriod: 2 * 365 * day},
+				{Every: day, Aggregates: []string{platform.DownsampleCount}, RetentionPeriod: platform.InfiniteRetention},
+			},
+		},
+		{
+			name:    "sub-second window",
+			tiers:   []platform.DownsampleTier{{Every: time.Millisecond, Aggregates: meanMinMax}},
+			wantErr: true,
+		},
+		{
+			name:    "retention shorter than window",
+			tiers:   []platform.DownsampleTier{{Every: time.Hour, Aggregates: meanMinMax, RetentionPeriod: time.Minute}},
+			wantErr: true,
+		},
+		{
+			name:    "no aggregates",
+			tiers:   []platform.DownsampleTier{{Every: time.Minute}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown aggregate",
+			tiers:   []platform.DownsampleTier{{Every: time.Minute, Aggregates: []string{"mode"}}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate aggregate",
+			tiers:   []platform.DownsampleTier{{Every: time.Minute, Aggregates: []string{"mean", "mean"}}},
+			wantErr: true,
+		},
+		{
+			name: "windows out of order",
+			tiers: []platform.DownsampleTier{
+				{Every: time.Hour, Aggregates: meanMinMax},
+				{Every: time.Minute, Aggregates: meanMinMax},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "negative offset",
+			tiers:   []platform.DownsampleTier{{Every: time.Minute, Aggregates: meanMinMax, Offset: -time.Second}},
+			wantErr: true,
+		},
+		{
+			name: "window not a multiple of previous window",
+			tiers: []platform.DownsampleTier{
+				{Every: time.Hour, Aggregates: meanMinMax},
+				{Every: 90 * time.Minute, Aggregates: meanMinMax},
+			},
+			wantErr: true,
+		},
+		{
+			name: "previous tier expires within window",
+			tiers: []platform.DownsampleTier{
+				{Every: time.Minute, Aggregates: meanMinMax, RetentionPeriod: 30 * time.Minute},
+				{Every: time.Hour, Aggregates: meanMinMax, RetentionPeriod: time.Hour},
+			},
+			wantErr: true,
+		},
+		{
+			name: "coarser tier expires first",
+			tiers: []platform.DownsampleTier{
+				{Every: time.Minute, Aggregates: meanMinMax, RetentionPeriod: platform.InfiniteRetention},
+				{Every: time.Hour, Aggregates: meanMinMax, RetentionPeriod: 365 * day},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := platform.ValidateDownsampleTiers(tt.tiers)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateDownsampleTiers() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && platform.ErrorCode(err) != platform.EInvalid {
+				t.Fatalf("expected invalid error code, got %q", platform.ErrorCode(err))
+			}
+		})
+	}
+}
+
+func TestDefaultShardGroupDuration(t *testing.T) {
+	day := 24 * time.Hour
+	tests := []struct {
+		rp, want time.Duration
+	}{
+		{rp: platform.InfiniteRetention, want: 7 * day},
+		{rp: 365 * day, want: 7 * day},
+		{rp: 30 * day, want: day},
+		{rp: 2 * day, want: day},
+		{rp: 12 * time.Hour, want: time.Hour},
+	}
+	for _, tt := range tests {
+		if got := platform.DefaultShardGroupDuration(tt.rp); got != tt.want {
+			t.Errorf("DefaultShardGroupDuration(%s) = %s, want %s", tt.rp, got, tt.want)
+		}
+	}
+
+	if err := platform.ValidateShardGroupDuration(time.Minute); platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("expected invalid error for a shard group duration of a minute, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package platform
+
+import (
+	"context"
+	"time"
+)
+
+// ErrBucketTransferNotFound is the error message for a missing bucket transfer.
+const ErrBucketTransferNotFound = "bucket transfer not found"
+
+// ops for bucket transfers.
+const (
+	OpCloneBucket            = "CloneBucket"
+	OpMoveBucket             = "MoveBucket"
+	OpFindBucketTransferByID = "FindBucketTransferByID"
+)
+
+// BucketTransferType is the kind of a bucket transfer.
+type BucketTransferType string
+
+const (
+	// BucketTransferClone copies the data of a bucket into a new bucket.
+	BucketTransferClone BucketTransferType = "clone"
+	// BucketTransferMove moves a bucket and its data to another organization.
+	BucketTransferMove BucketTransferType = "move"
+)
+
+// BucketTransferStatus is the status of a bucket transfer.
+type BucketTransferStatus string
+
+const (
+	BucketTransferRunning   BucketTransferStatus = "running"
+	BucketTransferSucceeded BucketTransferStatus = "succeeded"
+	BucketTransferFailed    BucketTransferStatus = "failed"
+)
+
+// BucketTransfer is the copy of the stored data of a bucket into another bucket or organization,
+// which runs in the background as the series keys of all of the data are rewritten.
+type BucketTransfer struct {
+	ID     ID                   `json:"id,omitempty"`
+	Type   BucketTransferType   `json:"type"`
+	Status BucketTransferStatus `json:"status"`
+
+	// The bucket and organization the data is copied from, and the bucket and organization
+	// it is copied to. A moved bucket keeps its ID.
+	BucketID                  ID `json:"bucketID"`
+	OrganizationID            ID `json:"orgID"`
+	DestinationBucketID       ID `json:"destinationBucketID,omitempty"`
+	DestinationOrganizationID ID `json:"destinationOrgID"`
+	// DestinationName is the new name of a moved bucket, if it is renamed.
+	DestinationName string `json:"destinationName,omitempty"`
+
+	// SeriesCopied out of SeriesTotal is the progress of the transfer. SeriesTotal is
+	// zero until the series of the bucket have been counted.
+	SeriesCopied int64 `json:"seriesCopied"`
+	SeriesTotal  int64 `json:"seriesTotal"`
+	PointsCopied int64 `json:"pointsCopied"`
+
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"startedAt"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+// BucketClone describes the bucket created by cloning a bucket.
+type BucketClone struct {
+	// Name of the new bucket, which defaults to the name of the cloned bucket.
+	Name string `json:"name,omitempty"`
+	// OrganizationID of the new bucket, which defaults to the organization of the cloned bucket.
+	OrganizationID ID `json:"orgID,omitempty"`
+}
+
+// BucketMove describes where a bucket is moved to.
+type BucketMove struct {
+	// OrganizationID is the organization the bucket is moved to.
+	OrganizationID ID `json:"orgID"`
+	// Name optionally renames the bucket, such as when the organization already has a bucket of the same name.
+	Name string `json:"name,omitempty"`
+}
+
+// BucketTransferService clones buckets and moves them across organizations.
+//
+// Cloning a bucket returns once the new bucket has been created, and moving a bucket once the
+// move has been checked, while the stored data is copied in the background by the returned transfer.
+type BucketTransferService interface {
+	// CloneBucket creates a copy of the bucket id, and starts copying its data into the copy.
+	CloneBucket(ctx context.Context, id ID, clone BucketClone) (*BucketTransfer, error)
+
+	// MoveBucket starts moving the bucket id and its data into another organization. The bucket
+	// stays in its organization with all of its data until the data has been copied.
+	MoveBucket(ctx context.Context, id ID, move BucketMove) (*BucketTransfer, error)
+
+	// FindBucketTransferByID returns the progress of the transfer id of the bucket bucketID,
+	// which is either the source or the destination of the transfer.
+	FindBucketTransferByID(ctx context.Context, bucketID, id ID) (*BucketTransfer, error)
+}
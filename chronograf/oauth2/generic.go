@@ -37,6 +37,7 @@ type Generic struct {
 	TokenURL       string
 	APIURL         string // APIURL returns OpenID Userinfo
 	APIKey         string // APIKey is the JSON key to lookup email address in APIURL response
+	GroupsKey      string // GroupsKey is the optional JSON key to lookup the groups of the user in APIURL response or id_token
 	Logger         chronograf.Logger
 }
 
@@ -120,7 +121,7 @@ func (g *Generic) PrincipalID(provider *http.Client) (string, error) {
 }
 
 // Group returns the domain that a user belongs to in the
-// the generic OAuth.
+// the generic OAuth, or the groups of the user if GroupsKey is set.
 func (g *Generic) Group(provider *http.Client) (string, error) {
 	res := map[string]interface{}{}
 
@@ -134,6 +135,10 @@ func (g *Generic) Group(provider *http.Client) (string, error) {
 		return "", err
 	}
 
+	if g.GroupsKey != "" {
+		return groups(res[g.GroupsKey])
+	}
+
 	email := ""
 	value := res[g.APIKey]
 	if e, ok := value.(string); ok {
@@ -214,8 +219,13 @@ func (g *Generic) PrincipalIDFromClaims(claims gojwt.MapClaims) (string, error)
 	return "", fmt.Errorf("no claim for %s", g.APIKey)
 }
 
-// GroupFromClaims verifies an optional id_token, extracts the email address of the user and splits off the domain part
+// GroupFromClaims verifies an optional id_token, extracts the email address of the user and splits off the domain part.
+// If GroupsKey is set, the groups claim of the id_token is returned instead.
 func (g *Generic) GroupFromClaims(claims gojwt.MapClaims) (string, error) {
+	if g.GroupsKey != "" {
+		return groups(claims[g.GroupsKey])
+	}
+
 	if id, ok := claims[g.APIKey].(string); ok {
 		email := strings.Split(id, "@")
 		if len(email) != 2 {
@@ -228,3 +238,25 @@ func (g *Generic) GroupFromClaims(claims gojwt.MapClaims) (string, error) {
 
 	return "", fmt.Errorf("no claim for %s", g.APIKey)
 }
+
+// groups returns the comma delimited groups of a groups claim, which is either a list of
+// group names or a comma delimited string. A user without groups has no claim.
+func groups(claim interface{}) (string, error) {
+	switch v := claim.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []interface{}:
+		names := make([]string, 0, len(v))
+		for _, n := range v {
+			name, ok := n.(string)
+			if !ok {
+				return "", fmt.Errorf("malformed groups claim, expected group names but got %v", n)
+			}
+			names = append(names, name)
+		}
+		return strings.Join(names, ","), nil
+	}
+	return "", fmt.Errorf("malformed groups claim, expected a list of group names but got %v", claim)
+}
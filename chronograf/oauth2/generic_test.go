@@ -102,6 +102,63 @@ func TestGenericGroup_withEmail(t *testing.T) {
 	}
 }
 
+func TestGenericGroup_withGroupsKey(t *testing.T) {
+	t.Parallel()
+
+	response := struct {
+		Email  string   `json:"email"`
+		Groups []string `json:"groups"`
+	}{
+		"martymcfly@pinheads.rok",
+		[]string{"time-travelers", "hill-valley"},
+	}
+	mockAPI := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		enc := json.NewEncoder(rw)
+
+		rw.WriteHeader(http.StatusOK)
+		_ = enc.Encode(response)
+	}))
+	defer mockAPI.Close()
+
+	logger := &chronograf.NoopLogger{}
+	prov := oauth2.Generic{
+		Logger:    logger,
+		APIURL:    mockAPI.URL,
+		APIKey:    "email",
+		GroupsKey: "groups",
+	}
+	tt, err := oauth2.NewTestTripper(logger, mockAPI, http.DefaultTransport)
+	if err != nil {
+		t.Fatal("Error initializing TestTripper: err:", err)
+	}
+
+	tc := &http.Client{
+		Transport: tt,
+	}
+
+	got, err := prov.Group(tc)
+	if err != nil {
+		t.Fatal("Unexpected error while retrieiving Group: err:", err)
+	}
+
+	want := "time-travelers,hill-valley"
+	if got != want {
+		t.Fatal("Retrieved group was not as expected. Want:", want, "Got:", got)
+	}
+
+	got, err = prov.GroupFromClaims(map[string]interface{}{"groups": []interface{}{"hill-valley"}})
+	if err != nil {
+		t.Fatal("Unexpected error while retrieiving Group from claims: err:", err)
+	}
+	if want := "hill-valley"; got != want {
+		t.Fatal("Retrieved group was not as expected. Want:", want, "Got:", got)
+	}
+}
+
 func TestGenericPrincipalID(t *testing.T) {
 	t.Parallel()
 
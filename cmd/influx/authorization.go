@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/influxdata/platform"
 	"github.com/influxdata/platform/bolt"
@@ -28,8 +30,18 @@ type AuthorizationCreateFlags struct {
 	createUserPermission bool
 	deleteUserPermission bool
 
-	readBucketPermissions  []string
-	writeBucketPermissions []string
+	orgID string
+
+	readBucketPermissions   []string
+	writeBucketPermissions  []string
+	deleteBucketPermissions []string
+
+	readBucketsPermission  bool
+	writeBucketsPermission bool
+
+	roles []string
+
+	expiresIn time.Duration
 }
 
 var authorizationCreateFlags AuthorizationCreateFlags
@@ -49,6 +61,15 @@ func init() {
 
 	authorizationCreateCmd.Flags().StringArrayVarP(&authorizationCreateFlags.readBucketPermissions, "read-bucket", "", []string{}, "bucket id")
 	authorizationCreateCmd.Flags().StringArrayVarP(&authorizationCreateFlags.writeBucketPermissions, "write-bucket", "", []string{}, "bucket id")
+	authorizationCreateCmd.Flags().StringArrayVarP(&authorizationCreateFlags.deleteBucketPermissions, "delete-bucket", "", []string{}, "bucket id")
+
+	authorizationCreateCmd.Flags().StringVarP(&authorizationCreateFlags.orgID, "org-id", "o", "", "organization id of the authorization, which the bucket permissions are restricted to")
+	authorizationCreateCmd.Flags().BoolVarP(&authorizationCreateFlags.readBucketsPermission, "read-buckets", "", false, "grants the permission to read every bucket")
+	authorizationCreateCmd.Flags().BoolVarP(&authorizationCreateFlags.writeBucketsPermission, "write-buckets", "", false, "grants the permission to write every bucket")
+
+	authorizationCreateCmd.Flags().StringArrayVarP(&authorizationCreateFlags.roles, "role", "", []string{}, "role id in the organization whose permissions are granted")
+
+	authorizationCreateCmd.Flags().DurationVarP(&authorizationCreateFlags.expiresIn, "expires-in", "", 0, "duration after which the authorization expires (never expires if 0)")
 
 	authorizationCmd.AddCommand(authorizationCreateCmd)
 }
@@ -64,42 +85,78 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 	}
 
 	if authorizationCreateFlags.deleteUserPermission {
-		p, err := platform.NewPermission(platform.WriteAction, platform.UsersResource)
+		p, err := platform.NewPermission(platform.DeleteAction, platform.UsersResource)
 		if err != nil {
 			return err
 		}
 		permissions = append(permissions, *p)
 	}
 
-	for _, p := range authorizationCreateFlags.writeBucketPermissions {
-		var id platform.ID
-		if err := id.DecodeFromString(p); err != nil {
-			return err
-		}
-
-		p, err := platform.NewPermissionAtID(id, platform.WriteAction, platform.BucketsResource)
+	var orgID *platform.ID
+	if authorizationCreateFlags.orgID != "" {
+		id, err := platform.IDFromString(authorizationCreateFlags.orgID)
 		if err != nil {
 			return err
 		}
+		orgID = id
+	}
+
+	bucketPermissions := []struct {
+		action platform.Action
+		all    bool
+		ids    []string
+	}{
+		{action: platform.ReadAction, all: authorizationCreateFlags.readBucketsPermission, ids: authorizationCreateFlags.readBucketPermissions},
+		{action: platform.WriteAction, all: authorizationCreateFlags.writeBucketsPermission, ids: authorizationCreateFlags.writeBucketPermissions},
+		{action: platform.DeleteAction, ids: authorizationCreateFlags.deleteBucketPermissions},
+	}
+	for _, bp := range bucketPermissions {
+		if bp.all {
+			p := platform.Permission{Action: bp.action, Resource: platform.BucketsResource, OrgID: orgID}
+			if err := p.Valid(); err != nil {
+				return err
+			}
+			permissions = append(permissions, p)
+		}
 
-		permissions = append(permissions, *p)
+		for _, s := range bp.ids {
+			id, err := platform.IDFromString(s)
+			if err != nil {
+				return err
+			}
+
+			p := platform.Permission{Action: bp.action, Resource: platform.BucketsResource, OrgID: orgID, ID: id}
+			if err := p.Valid(); err != nil {
+				return err
+			}
+			permissions = append(permissions, p)
+		}
 	}
 
-	for _, p := range authorizationCreateFlags.readBucketPermissions {
-		var id platform.ID
-		if err := id.DecodeFromString(p); err != nil {
-			return err
-		}
+	authorization := &platform.Authorization{
+		Permissions: permissions,
+	}
+	if orgID != nil {
+		authorization.OrgID = *orgID
+	}
 
-		p, err := platform.NewPermissionAtID(id, platform.ReadAction, platform.BucketsResource)
+	if len(authorizationCreateFlags.roles) > 0 && orgID == nil {
+		return fmt.Errorf("org-id is required to grant roles")
+	}
+	for _, s := range authorizationCreateFlags.roles {
+		id, err := platform.IDFromString(s)
 		if err != nil {
 			return err
 		}
-		permissions = append(permissions, *p)
+		authorization.RoleIDs = append(authorization.RoleIDs, *id)
 	}
 
-	authorization := &platform.Authorization{
-		Permissions: permissions,
+	if authorizationCreateFlags.expiresIn < 0 {
+		return fmt.Errorf("expires-in must not be negative")
+	}
+	if authorizationCreateFlags.expiresIn > 0 {
+		expiresAt := time.Now().Add(authorizationCreateFlags.expiresIn)
+		authorization.ExpiresAt = &expiresAt
 	}
 
 	s, err := newAuthorizationService(flags)
@@ -119,6 +176,8 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		"User",
 		"UserID",
 		"Permissions",
+		"Roles",
+		"Expires",
 	)
 
 	ps := []string{}
@@ -126,12 +185,19 @@ func authorizationCreateF(cmd *cobra.Command, args []string) error {
 		ps = append(ps, p.String())
 	}
 
+	rs := []string{}
+	for _, id := range authorization.RoleIDs {
+		rs = append(rs, id.String())
+	}
+
 	w.Write(map[string]interface{}{
 		"ID":          authorization.ID.String(),
 		"Token":       authorization.Token,
 		"Status":      authorization.Status,
 		"UserID":      authorization.UserID.String(),
 		"Permissions": ps,
+		"Roles":       rs,
+		"Expires":     formatAuthorizationTime(authorization.ExpiresAt),
 	})
 
 	w.Flush()
@@ -215,11 +281,12 @@ func authorizationFindF(cmd *cobra.Command, args []string) error {
 	w := internal.NewTabWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
-		"Token",
 		"Status",
 		"User",
 		"UserID",
 		"Permissions",
+		"Expires",
+		"LastUsed",
 	)
 
 	for _, a := range authorizations {
@@ -230,10 +297,11 @@ func authorizationFindF(cmd *cobra.Command, args []string) error {
 
 		w.Write(map[string]interface{}{
 			"ID":          a.ID,
-			"Token":       a.Token,
 			"Status":      a.Status,
 			"UserID":      a.UserID.String(),
 			"Permissions": permissions,
+			"Expires":     formatAuthorizationTime(a.ExpiresAt),
+			"LastUsed":    formatAuthorizationTime(a.LastUsedAt),
 		})
 	}
 
@@ -242,6 +310,14 @@ func authorizationFindF(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// formatAuthorizationTime formats an optional time of an authorization, which is empty if unset.
+func formatAuthorizationTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
 // AuthorizationDeleteFlags are command line args used when deleting a authorization
 type AuthorizationDeleteFlags struct {
 	id string
@@ -286,7 +362,6 @@ func authorizationDeleteF(cmd *cobra.Command, args []string) error {
 	w := internal.NewTabWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
-		"Token",
 		"User",
 		"UserID",
 		"Permissions",
@@ -300,7 +375,6 @@ func authorizationDeleteF(cmd *cobra.Command, args []string) error {
 
 	w.Write(map[string]interface{}{
 		"ID":          a.ID.String(),
-		"Token":       a.Token,
 		"UserID":      a.UserID.String(),
 		"Permissions": ps,
 		"Deleted":     true,
@@ -355,7 +429,6 @@ func authorizationActiveF(cmd *cobra.Command, args []string) error {
 	w := internal.NewTabWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
-		"Token",
 		"Status",
 		"User",
 		"UserID",
@@ -369,7 +442,6 @@ func authorizationActiveF(cmd *cobra.Command, args []string) error {
 
 	w.Write(map[string]interface{}{
 		"ID":          a.ID.String(),
-		"Token":       a.Token,
 		"Status":      a.Status,
 		"UserID":      a.UserID.String(),
 		"Permissions": ps,
@@ -424,7 +496,6 @@ func authorizationInactiveF(cmd *cobra.Command, args []string) error {
 	w := internal.NewTabWriter(os.Stdout)
 	w.WriteHeaders(
 		"ID",
-		"Token",
 		"Status",
 		"User",
 		"UserID",
@@ -438,7 +509,6 @@ func authorizationInactiveF(cmd *cobra.Command, args []string) error {
 
 	w.Write(map[string]interface{}{
 		"ID":          a.ID.String(),
-		"Token":       a.Token,
 		"Status":      a.Status,
 		"UserID":      a.UserID.String(),
 		"Permissions": ps,
@@ -448,3 +518,67 @@ func authorizationInactiveF(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// AuthorizationRotateFlags are command line args used when rotating the token of an authorization
+type AuthorizationRotateFlags struct {
+	id          string
+	gracePeriod time.Duration
+}
+
+var authorizationRotateFlags AuthorizationRotateFlags
+
+func init() {
+	authorizationRotateCmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Replace the token of an authorization",
+		RunE:  authorizationRotateF,
+	}
+
+	authorizationRotateCmd.Flags().StringVarP(&authorizationRotateFlags.id, "id", "i", "", "authorization id (required)")
+	authorizationRotateCmd.MarkFlagRequired("id")
+	authorizationRotateCmd.Flags().DurationVarP(&authorizationRotateFlags.gracePeriod, "grace-period", "", platform.DefaultTokenGracePeriod, "duration the replaced token remains valid for")
+
+	authorizationCmd.AddCommand(authorizationRotateCmd)
+}
+
+func authorizationRotateF(cmd *cobra.Command, args []string) error {
+	s, err := newAuthorizationService(flags)
+	if err != nil {
+		return err
+	}
+
+	var id platform.ID
+	if err := id.DecodeFromString(authorizationRotateFlags.id); err != nil {
+		return err
+	}
+
+	if authorizationRotateFlags.gracePeriod < 0 {
+		return fmt.Errorf("grace-period must not be negative")
+	}
+
+	a, err := s.RotateAuthorization(context.Background(), id, authorizationRotateFlags.gracePeriod)
+	if err != nil {
+		return err
+	}
+
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Token",
+		"Status",
+		"UserID",
+		"Expires",
+	)
+
+	w.Write(map[string]interface{}{
+		"ID":      a.ID.String(),
+		"Token":   a.Token,
+		"Status":  a.Status,
+		"UserID":  a.UserID.String(),
+		"Expires": formatAuthorizationTime(a.ExpiresAt),
+	})
+
+	w.Flush()
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/backup"
+	"github.com/influxdata/platform/http"
+	"github.com/influxdata/platform/internal/fs"
+	"github.com/influxdata/platform/kit/signals"
+	"github.com/influxdata/platform/logger"
+	"github.com/influxdata/platform/storage"
+	"github.com/spf13/cobra"
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up the metadata and data of a running influxd",
+	Long: `Download an archive of the metadata and time series data of a running influxd.
+		The data may be limited to one organization or bucket; the metadata is always complete.
+		The archive is restored with the restore command.`,
+	Args: cobra.NoArgs,
+	RunE: backupF,
+}
+
+var backupFlags struct {
+	OrgID    string
+	BucketID string
+	Output   string
+}
+
+func init() {
+	backupCmd.Flags().StringVar(&backupFlags.OrgID, "org-id", "", "only back up the data of this organization")
+	backupCmd.Flags().StringVar(&backupFlags.BucketID, "bucket-id", "", "only back up the data of this bucket")
+	backupCmd.Flags().StringVarP(&backupFlags.Output, "output", "o", "", "path of the archive to create (required)")
+	backupCmd.MarkFlagRequired("output")
+}
+
+func backupF(cmd *cobra.Command, args []string) error {
+	ctx := signals.WithStandardSignals(context.Background())
+
+	var filter backup.Filter
+	var err error
+	if backupFlags.OrgID != "" {
+		if filter.OrganizationID, err = platform.IDFromString(backupFlags.OrgID); err != nil {
+			return err
+		}
+	}
+	if backupFlags.BucketID != "" {
+		if filter.BucketID, err = platform.IDFromString(backupFlags.BucketID); err != nil {
+			return err
+		}
+	}
+
+	// Write to a temporary file so that a failed backup does not leave a truncated archive behind.
+	tmp := backupFlags.Output + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	defer f.Close()
+
+	s := &http.BackupService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+	if err := s.Backup(ctx, f, filter); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, backupFlags.Output); err != nil {
+		return err
+	}
+
+	fmt.Printf("Backup written to %s\n", backupFlags.Output)
+	return nil
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore a backup into a new data directory",
+	Long: `Restore an archive created by the backup command into a new bolt database and engine directory.
+		influxd must not be running against them. The index is rebuilt from the restored data.`,
+	Args: cobra.NoArgs,
+	RunE: restoreF,
+}
+
+var restoreFlags struct {
+	Input      string
+	BoltPath   string
+	EnginePath string
+}
+
+func init() {
+	var boltPath, enginePath string
+	if dir, err := fs.InfluxDir(); err == nil {
+		boltPath = filepath.Join(dir, "influxd.bolt")
+		enginePath = filepath.Join(dir, "engine")
+	}
+
+	restoreCmd.Flags().StringVarP(&restoreFlags.Input, "input", "i", "", "path of the archive to restore (required)")
+	restoreCmd.Flags().StringVar(&restoreFlags.BoltPath, "bolt-path", boltPath, "path of the bolt database to create")
+	restoreCmd.Flags().StringVar(&restoreFlags.EnginePath, "engine-path", enginePath, "path of the engine directory to create")
+	restoreCmd.MarkFlagRequired("input")
+}
+
+func restoreF(cmd *cobra.Command, args []string) error {
+	f, err := os.Open(restoreFlags.Input)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	manifest, err := backup.Restore(f, restoreFlags.BoltPath, restoreFlags.EnginePath, storage.NewConfig(), logger.New(os.Stderr))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restored backup from %s\n", manifest.CreatedAt)
+	return nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/http"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete points from influxdb",
+	Long: `Delete the points of a bucket between start and stop.
+		When a predicate is given, such as 'r._measurement == "cpu" and r.customer == "acme"',
+		only the series matching it are deleted.`,
+	Args: cobra.NoArgs,
+	RunE: fluxDeleteF,
+}
+
+var deleteFlags struct {
+	OrgID     string
+	Org       string
+	BucketID  string
+	Bucket    string
+	Start     string
+	Stop      string
+	Predicate string
+}
+
+func init() {
+	deleteCmd.PersistentFlags().StringVar(&deleteFlags.OrgID, "org-id", "", "id of the organization that owns the bucket")
+	viper.BindEnv("ORG_ID")
+	if h := viper.GetString("ORG_ID"); h != "" {
+		deleteFlags.OrgID = h
+	}
+
+	deleteCmd.PersistentFlags().StringVarP(&deleteFlags.Org, "org", "o", "", "name of the organization that owns the bucket")
+	viper.BindEnv("ORG")
+	if h := viper.GetString("ORG"); h != "" {
+		deleteFlags.Org = h
+	}
+
+	deleteCmd.PersistentFlags().StringVar(&deleteFlags.BucketID, "bucket-id", "", "ID of the bucket to delete from")
+	viper.BindEnv("BUCKET_ID")
+	if h := viper.GetString("BUCKET_ID"); h != "" {
+		deleteFlags.BucketID = h
+	}
+
+	deleteCmd.PersistentFlags().StringVarP(&deleteFlags.Bucket, "bucket", "b", "", "name of the bucket to delete from")
+	viper.BindEnv("BUCKET_NAME")
+	if h := viper.GetString("BUCKET_NAME"); h != "" {
+		deleteFlags.Bucket = h
+	}
+
+	deleteCmd.PersistentFlags().StringVar(&deleteFlags.Start, "start", "", "start of the time range to delete, inclusive, in RFC3339 format (required)")
+	deleteCmd.PersistentFlags().StringVar(&deleteFlags.Stop, "stop", "", "end of the time range to delete, exclusive, in RFC3339 format (required)")
+	deleteCmd.PersistentFlags().StringVarP(&deleteFlags.Predicate, "predicate", "p", "", "Flux expression over the record r selecting the series to delete; only tags, _measurement and _field may be compared")
+}
+
+func fluxDeleteF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if deleteFlags.Org != "" && deleteFlags.OrgID != "" {
+		cmd.Usage()
+		return fmt.Errorf("please specify one of org or org-id")
+	}
+
+	if deleteFlags.Bucket != "" && deleteFlags.BucketID != "" {
+		cmd.Usage()
+		return fmt.Errorf("please specify one of bucket or bucket-id")
+	}
+
+	if deleteFlags.Start == "" || deleteFlags.Stop == "" {
+		cmd.Usage()
+		return fmt.Errorf("please specify both start and stop")
+	}
+
+	start, err := time.Parse(time.RFC3339Nano, deleteFlags.Start)
+	if err != nil {
+		return fmt.Errorf("invalid start: %v", err)
+	}
+	stop, err := time.Parse(time.RFC3339Nano, deleteFlags.Stop)
+	if err != nil {
+		return fmt.Errorf("invalid stop: %v", err)
+	}
+
+	bs := &http.BucketService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	filter := platform.BucketFilter{}
+
+	if deleteFlags.BucketID != "" {
+		filter.ID, err = platform.IDFromString(deleteFlags.BucketID)
+		if err != nil {
+			return err
+		}
+	}
+	if deleteFlags.Bucket != "" {
+		filter.Name = &deleteFlags.Bucket
+	}
+
+	if deleteFlags.OrgID != "" {
+		filter.OrganizationID, err = platform.IDFromString(deleteFlags.OrgID)
+		if err != nil {
+			return err
+		}
+	}
+	if deleteFlags.Org != "" {
+		filter.Organization = &deleteFlags.Org
+	}
+
+	buckets, n, err := bs.FindBuckets(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return fmt.Errorf("bucket does not exist")
+	}
+
+	s := &http.DeleteService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+	return s.DeleteBucketRangePredicate(ctx, buckets[0].OrganizationID, buckets[0].ID, start, stop, deleteFlags.Predicate)
+}
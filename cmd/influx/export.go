@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/http"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/query"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export points from influxdb as line protocol",
+	Long: `Export the points of a bucket between start and stop as line protocol,
+		which can be written to another bucket or server with the write command.
+		The points are read with a Flux query and streamed to the output.`,
+	Args: cobra.NoArgs,
+	RunE: fluxExportF,
+}
+
+var exportFlags struct {
+	OrgID    string
+	Org      string
+	BucketID string
+	Bucket   string
+	Start    string
+	Stop     string
+	Output   string
+	Compress bool
+}
+
+func init() {
+	exportCmd.PersistentFlags().StringVar(&exportFlags.OrgID, "org-id", "", "id of the organization that owns the bucket")
+	viper.BindEnv("ORG_ID")
+	if h := viper.GetString("ORG_ID"); h != "" {
+		exportFlags.OrgID = h
+	}
+
+	exportCmd.PersistentFlags().StringVarP(&exportFlags.Org, "org", "o", "", "name of the organization that owns the bucket")
+	viper.BindEnv("ORG")
+	if h := viper.GetString("ORG"); h != "" {
+		exportFlags.Org = h
+	}
+
+	exportCmd.PersistentFlags().StringVar(&exportFlags.BucketID, "bucket-id", "", "ID of the bucket to export")
+	viper.BindEnv("BUCKET_ID")
+	if h := viper.GetString("BUCKET_ID"); h != "" {
+		exportFlags.BucketID = h
+	}
+
+	exportCmd.PersistentFlags().StringVarP(&exportFlags.Bucket, "bucket", "b", "", "name of the bucket to export")
+	viper.BindEnv("BUCKET_NAME")
+	if h := viper.GetString("BUCKET_NAME"); h != "" {
+		exportFlags.Bucket = h
+	}
+
+	exportCmd.PersistentFlags().StringVar(&exportFlags.Start, "start", "1970-01-01T00:00:00Z", "start of the time range to export, inclusive, in RFC3339 format")
+	exportCmd.PersistentFlags().StringVar(&exportFlags.Stop, "stop", "", "end of the time range to export, exclusive, in RFC3339 format; defaults to now")
+	exportCmd.PersistentFlags().StringVar(&exportFlags.Output, "output", "", "path of the file to write; defaults to standard output")
+	exportCmd.PersistentFlags().BoolVar(&exportFlags.Compress, "compress", false, "compress the output with gzip")
+}
+
+func fluxExportF(cmd *cobra.Command, args []string) error {
+	ctx := context.Background()
+
+	if flags.local {
+		return fmt.Errorf("local flag not supported for export command")
+	}
+
+	if exportFlags.Org != "" && exportFlags.OrgID != "" {
+		cmd.Usage()
+		return fmt.Errorf("please specify one of org or org-id")
+	}
+
+	if exportFlags.Bucket != "" && exportFlags.BucketID != "" {
+		cmd.Usage()
+		return fmt.Errorf("please specify one of bucket or bucket-id")
+	}
+
+	start, err := time.Parse(time.RFC3339Nano, exportFlags.Start)
+	if err != nil {
+		return fmt.Errorf("invalid start: %v", err)
+	}
+	stop := time.Now().UTC()
+	if exportFlags.Stop != "" {
+		stop, err = time.Parse(time.RFC3339Nano, exportFlags.Stop)
+		if err != nil {
+			return fmt.Errorf("invalid stop: %v", err)
+		}
+	}
+
+	bs := &http.BucketService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	filter := platform.BucketFilter{}
+
+	if exportFlags.BucketID != "" {
+		filter.ID, err = platform.IDFromString(exportFlags.BucketID)
+		if err != nil {
+			return err
+		}
+	}
+	if exportFlags.Bucket != "" {
+		filter.Name = &exportFlags.Bucket
+	}
+
+	if exportFlags.OrgID != "" {
+		filter.OrganizationID, err = platform.IDFromString(exportFlags.OrgID)
+		if err != nil {
+			return err
+		}
+	}
+	if exportFlags.Org != "" {
+		filter.Organization = &exportFlags.Org
+	}
+
+	buckets, n, err := bs.FindBuckets(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		return fmt.Errorf("bucket does not exist")
+	}
+
+	qs := &http.FluxQueryService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+	results, err := qs.Query(ctx, &query.Request{
+		OrganizationID: buckets[0].OrganizationID,
+		Compiler: lang.FluxCompiler{
+			Query: fmt.Sprintf(`from(bucketID: %q) |> range(start: %s, stop: %s)`,
+				buckets[0].ID.String(), start.Format(time.RFC3339Nano), stop.Format(time.RFC3339Nano)),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	defer results.Release()
+
+	var w io.Writer = os.Stdout
+	if exportFlags.Output != "" {
+		f, err := os.Create(exportFlags.Output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	bw := bufio.NewWriter(w)
+	w = bw
+	var gw *gzip.Writer
+	if exportFlags.Compress {
+		gw = gzip.NewWriter(bw)
+		w = gw
+	}
+
+	for results.More() {
+		if err := results.Next().Tables().Do(func(tbl flux.Table) error {
+			return exportTable(w, tbl)
+		}); err != nil {
+			return err
+		}
+	}
+	if err := results.Err(); err != nil {
+		return err
+	}
+
+	if gw != nil {
+		if err := gw.Close(); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// exportTable writes the rows of a table returned by from() as line protocol, one field per line.
+// Every string column other than the measurement, field, and time bounds is written as a tag.
+func exportTable(w io.Writer, tbl flux.Table) error {
+	measurementIdx := execute.ColIdx("_measurement", tbl.Cols())
+	fieldIdx := execute.ColIdx("_field", tbl.Cols())
+	valueIdx := execute.ColIdx("_value", tbl.Cols())
+	timeIdx := execute.ColIdx(execute.DefaultTimeColLabel, tbl.Cols())
+	if measurementIdx < 0 || fieldIdx < 0 || valueIdx < 0 || timeIdx < 0 {
+		return fmt.Errorf("table is missing one of the _measurement, _field, _value, or _time columns")
+	}
+
+	var tagIdxs []int
+	for j, c := range tbl.Cols() {
+		switch c.Label {
+		case "_measurement", "_field", "_value", execute.DefaultTimeColLabel, execute.DefaultStartColLabel, execute.DefaultStopColLabel:
+		default:
+			if c.Type == flux.TString {
+				tagIdxs = append(tagIdxs, j)
+			}
+		}
+	}
+
+	buf := make([]byte, 0, 256)
+	return tbl.Do(func(cr flux.ColReader) error {
+		cols := cr.Cols()
+		for i := 0; i < cr.Len(); i++ {
+			tags := make(models.Tags, 0, len(tagIdxs))
+			for _, j := range tagIdxs {
+				if v := cr.Strings(j)[i]; v != "" {
+					tags = append(tags, models.NewTag([]byte(cols[j].Label), []byte(v)))
+				}
+			}
+			sort.Sort(tags)
+
+			var value interface{}
+			switch cols[valueIdx].Type {
+			case flux.TFloat:
+				value = cr.Floats(valueIdx)[i]
+			case flux.TInt:
+				value = cr.Ints(valueIdx)[i]
+			case flux.TUInt:
+				value = cr.UInts(valueIdx)[i]
+			case flux.TString:
+				value = cr.Strings(valueIdx)[i]
+			case flux.TBool:
+				value = cr.Bools(valueIdx)[i]
+			default:
+				return fmt.Errorf("unsupported value type %v", cols[valueIdx].Type)
+			}
+
+			pt, err := models.NewPoint(
+				cr.Strings(measurementIdx)[i],
+				tags,
+				models.Fields{cr.Strings(fieldIdx)[i]: value},
+				cr.Times(timeIdx)[i].Time(),
+			)
+			if err != nil {
+				return err
+			}
+
+			buf = pt.AppendString(buf[:0])
+			buf = append(buf, '\n')
+			if _, err := w.Write(buf); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
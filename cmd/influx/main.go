@@ -23,10 +23,15 @@ var influxCmd = &cobra.Command{
 
 func init() {
 	influxCmd.AddCommand(authorizationCmd)
+	influxCmd.AddCommand(backupCmd)
 	influxCmd.AddCommand(bucketCmd)
+	influxCmd.AddCommand(deleteCmd)
+	influxCmd.AddCommand(exportCmd)
 	influxCmd.AddCommand(organizationCmd)
 	influxCmd.AddCommand(queryCmd)
 	influxCmd.AddCommand(replCmd)
+	influxCmd.AddCommand(restoreCmd)
+	influxCmd.AddCommand(roleCmd)
 	influxCmd.AddCommand(setupCmd)
 	influxCmd.AddCommand(taskCmd)
 	influxCmd.AddCommand(userCmd)
@@ -0,0 +1,440 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bolt"
+	"github.com/influxdata/platform/cmd/influx/internal"
+	"github.com/influxdata/platform/http"
+	"github.com/influxdata/platform/internal/fs"
+	"github.com/spf13/cobra"
+)
+
+var roleCmd = &cobra.Command{
+	Use:   "role",
+	Short: "Organization role management commands",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Usage()
+	},
+}
+
+func newRoleService(f Flags, orgID platform.ID) (platform.RoleService, error) {
+	if flags.local {
+		boltFile, err := fs.BoltFile()
+		if err != nil {
+			return nil, err
+		}
+		c := bolt.NewClient()
+		c.Path = boltFile
+		if err := c.Open(context.Background()); err != nil {
+			return nil, err
+		}
+
+		return c, nil
+	}
+	return &http.RoleService{
+		Addr:           flags.host,
+		Token:          flags.token,
+		OrganizationID: orgID,
+	}, nil
+}
+
+// rolePresetNames returns the names of the preset roles, for flag usage.
+func rolePresetNames() string {
+	names := make([]string, 0, len(platform.RolePresets))
+	for name := range platform.RolePresets {
+		names = append(names, fmt.Sprintf("%q", name))
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// rolePermissions returns the permissions of the preset, if any, and the permissions
+// formatted as action:resource, which are restricted to the organization.
+func rolePermissions(orgID platform.ID, preset string, permissions []string) ([]platform.Permission, error) {
+	var ps []platform.Permission
+	if preset != "" {
+		fn, ok := platform.RolePresets[preset]
+		if !ok {
+			return nil, fmt.Errorf("unknown role preset %q; presets are %s", preset, rolePresetNames())
+		}
+		ps = append(ps, fn(orgID)...)
+	}
+
+	for _, s := range permissions {
+		parts := strings.SplitN(s, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("permission %q is not formatted as action:resource", s)
+		}
+		a, r := platform.Action(parts[0]), platform.Resource(parts[1])
+
+		var p *platform.Permission
+		var err error
+		if r == platform.OrgsResource {
+			p, err = platform.NewPermissionAtID(orgID, a, r)
+		} else {
+			p, err = platform.NewPermissionInOrg(orgID, a, r)
+		}
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, *p)
+	}
+	return ps, nil
+}
+
+func writeRoles(rs ...*platform.Role) {
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"Name",
+		"OrganizationID",
+		"Permissions",
+	)
+	for _, r := range rs {
+		ps := []string{}
+		for _, p := range r.Permissions {
+			ps = append(ps, p.String())
+		}
+		w.Write(map[string]interface{}{
+			"ID":             r.ID.String(),
+			"Name":           r.Name,
+			"OrganizationID": r.OrganizationID.String(),
+			"Permissions":    ps,
+		})
+	}
+	w.Flush()
+}
+
+// RoleCreateFlags are command line args used when creating a role
+type RoleCreateFlags struct {
+	orgID       string
+	name        string
+	description string
+	preset      string
+	permissions []string
+}
+
+var roleCreateFlags RoleCreateFlags
+
+func init() {
+	roleCreateCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create role",
+		RunE:  roleCreateF,
+	}
+
+	roleCreateCmd.Flags().StringVarP(&roleCreateFlags.orgID, "org-id", "o", "", "organization id (required)")
+	roleCreateCmd.MarkFlagRequired("org-id")
+	roleCreateCmd.Flags().StringVarP(&roleCreateFlags.name, "name", "n", "", "role name (required)")
+	roleCreateCmd.MarkFlagRequired("name")
+	roleCreateCmd.Flags().StringVarP(&roleCreateFlags.description, "description", "d", "", "role description")
+	roleCreateCmd.Flags().StringVarP(&roleCreateFlags.preset, "preset", "", "", "grants the permissions of a preset role: "+rolePresetNames())
+	roleCreateCmd.Flags().StringArrayVarP(&roleCreateFlags.permissions, "permission", "p", []string{}, "grants a permission in the organization, formatted as action:resource")
+
+	roleCmd.AddCommand(roleCreateCmd)
+}
+
+func roleCreateF(cmd *cobra.Command, args []string) error {
+	orgID, err := platform.IDFromString(roleCreateFlags.orgID)
+	if err != nil {
+		return err
+	}
+
+	ps, err := rolePermissions(*orgID, roleCreateFlags.preset, roleCreateFlags.permissions)
+	if err != nil {
+		return err
+	}
+
+	s, err := newRoleService(flags, *orgID)
+	if err != nil {
+		return err
+	}
+
+	r := &platform.Role{
+		OrganizationID: *orgID,
+		Name:           roleCreateFlags.name,
+		Description:    roleCreateFlags.description,
+		Permissions:    ps,
+	}
+	if err := s.CreateRole(context.Background(), r); err != nil {
+		return err
+	}
+
+	writeRoles(r)
+	return nil
+}
+
+// RoleFindFlags are command line args used when finding roles
+type RoleFindFlags struct {
+	orgID string
+	id    string
+	name  string
+}
+
+var roleFindFlags RoleFindFlags
+
+func init() {
+	roleFindCmd := &cobra.Command{
+		Use:   "find",
+		Short: "Find roles",
+		RunE:  roleFindF,
+	}
+
+	roleFindCmd.Flags().StringVarP(&roleFindFlags.orgID, "org-id", "o", "", "organization id (required)")
+	roleFindCmd.MarkFlagRequired("org-id")
+	roleFindCmd.Flags().StringVarP(&roleFindFlags.id, "id", "i", "", "role id")
+	roleFindCmd.Flags().StringVarP(&roleFindFlags.name, "name", "n", "", "role name")
+
+	roleCmd.AddCommand(roleFindCmd)
+}
+
+func roleFindF(cmd *cobra.Command, args []string) error {
+	orgID, err := platform.IDFromString(roleFindFlags.orgID)
+	if err != nil {
+		return err
+	}
+
+	s, err := newRoleService(flags, *orgID)
+	if err != nil {
+		return err
+	}
+
+	filter := platform.RoleFilter{OrganizationID: orgID}
+	if roleFindFlags.id != "" {
+		id, err := platform.IDFromString(roleFindFlags.id)
+		if err != nil {
+			return err
+		}
+		filter.ID = id
+	}
+	if roleFindFlags.name != "" {
+		filter.Name = &roleFindFlags.name
+	}
+
+	rs, _, err := s.FindRoles(context.Background(), filter)
+	if err != nil {
+		return err
+	}
+
+	writeRoles(rs...)
+	return nil
+}
+
+// RoleUpdateFlags are command line args used when updating a role
+type RoleUpdateFlags struct {
+	orgID       string
+	id          string
+	name        string
+	description string
+	preset      string
+	permissions []string
+}
+
+var roleUpdateFlags RoleUpdateFlags
+
+func init() {
+	roleUpdateCmd := &cobra.Command{
+		Use:   "update",
+		Short: "Update role",
+		RunE:  roleUpdateF,
+	}
+
+	roleUpdateCmd.Flags().StringVarP(&roleUpdateFlags.orgID, "org-id", "o", "", "organization id (required)")
+	roleUpdateCmd.MarkFlagRequired("org-id")
+	roleUpdateCmd.Flags().StringVarP(&roleUpdateFlags.id, "id", "i", "", "role id (required)")
+	roleUpdateCmd.MarkFlagRequired("id")
+	roleUpdateCmd.Flags().StringVarP(&roleUpdateFlags.name, "name", "n", "", "role name")
+	roleUpdateCmd.Flags().StringVarP(&roleUpdateFlags.description, "description", "d", "", "role description")
+	roleUpdateCmd.Flags().StringVarP(&roleUpdateFlags.preset, "preset", "", "", "replaces the permissions with those of a preset role: "+rolePresetNames())
+	roleUpdateCmd.Flags().StringArrayVarP(&roleUpdateFlags.permissions, "permission", "p", []string{}, "replaces the permissions, formatted as action:resource")
+
+	roleCmd.AddCommand(roleUpdateCmd)
+}
+
+func roleUpdateF(cmd *cobra.Command, args []string) error {
+	orgID, err := platform.IDFromString(roleUpdateFlags.orgID)
+	if err != nil {
+		return err
+	}
+	id, err := platform.IDFromString(roleUpdateFlags.id)
+	if err != nil {
+		return err
+	}
+
+	s, err := newRoleService(flags, *orgID)
+	if err != nil {
+		return err
+	}
+
+	upd := platform.RoleUpdate{}
+	if roleUpdateFlags.name != "" {
+		upd.Name = &roleUpdateFlags.name
+	}
+	if cmd.Flags().Changed("description") {
+		upd.Description = &roleUpdateFlags.description
+	}
+	if roleUpdateFlags.preset != "" || len(roleUpdateFlags.permissions) > 0 {
+		ps, err := rolePermissions(*orgID, roleUpdateFlags.preset, roleUpdateFlags.permissions)
+		if err != nil {
+			return err
+		}
+		upd.Permissions = &ps
+	}
+
+	r, err := s.UpdateRole(context.Background(), *id, upd)
+	if err != nil {
+		return err
+	}
+
+	writeRoles(r)
+	return nil
+}
+
+// RoleDeleteFlags are command line args used when deleting a role
+type RoleDeleteFlags struct {
+	orgID string
+	id    string
+}
+
+var roleDeleteFlags RoleDeleteFlags
+
+func init() {
+	roleDeleteCmd := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete role",
+		RunE:  roleDeleteF,
+	}
+
+	roleDeleteCmd.Flags().StringVarP(&roleDeleteFlags.orgID, "org-id", "o", "", "organization id (required)")
+	roleDeleteCmd.MarkFlagRequired("org-id")
+	roleDeleteCmd.Flags().StringVarP(&roleDeleteFlags.id, "id", "i", "", "role id (required)")
+	roleDeleteCmd.MarkFlagRequired("id")
+
+	roleCmd.AddCommand(roleDeleteCmd)
+}
+
+func roleDeleteF(cmd *cobra.Command, args []string) error {
+	orgID, err := platform.IDFromString(roleDeleteFlags.orgID)
+	if err != nil {
+		return err
+	}
+	id, err := platform.IDFromString(roleDeleteFlags.id)
+	if err != nil {
+		return err
+	}
+
+	s, err := newRoleService(flags, *orgID)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	r, err := s.FindRoleByID(ctx, *id)
+	if err != nil {
+		return err
+	}
+	if err := s.DeleteRole(ctx, *id); err != nil {
+		return err
+	}
+
+	writeRoles(r)
+	return nil
+}
+
+// Member management
+var roleMembersCmd = &cobra.Command{
+	Use:   "members",
+	Short: "Role assignment commands",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Usage()
+	},
+}
+
+// RoleMembersFlags are command line args used when assigning a role to users
+type RoleMembersFlags struct {
+	orgID    string
+	id       string
+	memberID string
+}
+
+var roleMembersFlags RoleMembersFlags
+
+func init() {
+	roleMembersAddCmd := &cobra.Command{
+		Use:   "add",
+		Short: "Assign role to user",
+		RunE:  roleMembersAddF,
+	}
+	roleMembersRemoveCmd := &cobra.Command{
+		Use:   "remove",
+		Short: "Remove role from user",
+		RunE:  roleMembersRemoveF,
+	}
+
+	for _, cmd := range []*cobra.Command{roleMembersAddCmd, roleMembersRemoveCmd} {
+		cmd.Flags().StringVarP(&roleMembersFlags.orgID, "org-id", "o", "", "organization id (required)")
+		cmd.MarkFlagRequired("org-id")
+		cmd.Flags().StringVarP(&roleMembersFlags.id, "id", "i", "", "role id (required)")
+		cmd.MarkFlagRequired("id")
+		cmd.Flags().StringVarP(&roleMembersFlags.memberID, "member", "m", "", "member id (required)")
+		cmd.MarkFlagRequired("member")
+		roleMembersCmd.AddCommand(cmd)
+	}
+
+	roleCmd.AddCommand(roleMembersCmd)
+}
+
+// roleMembersArgs returns the role service of the organization, and the ids of the role and member.
+func roleMembersArgs() (*http.RoleService, platform.ID, platform.ID, error) {
+	orgID, err := platform.IDFromString(roleMembersFlags.orgID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	id, err := platform.IDFromString(roleMembersFlags.id)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	memberID, err := platform.IDFromString(roleMembersFlags.memberID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	s := &http.RoleService{
+		Addr:           flags.host,
+		Token:          flags.token,
+		OrganizationID: *orgID,
+	}
+	return s, *id, *memberID, nil
+}
+
+func roleMembersAddF(cmd *cobra.Command, args []string) error {
+	s, id, memberID, err := roleMembersArgs()
+	if err != nil {
+		return err
+	}
+	if err := s.AddRoleMember(context.Background(), id, memberID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Role %s assigned to member %s\n", id, memberID)
+	return nil
+}
+
+func roleMembersRemoveF(cmd *cobra.Command, args []string) error {
+	s, id, memberID, err := roleMembersArgs()
+	if err != nil {
+		return err
+	}
+	if err := s.RemoveRoleMember(context.Background(), id, memberID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Role %s removed from member %s\n", id, memberID)
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/influxdata/flux/repl"
 	"github.com/influxdata/platform"
@@ -411,9 +412,13 @@ func taskDeleteF(cmd *cobra.Command, args []string) {
 
 // taskLogFindFlags define the Delete command
 type TaskLogFindFlags struct {
-	taskID string
-	runID  string
-	orgID  string
+	taskID     string
+	runID      string
+	orgID      string
+	afterTime  string
+	beforeTime string
+	status     string
+	match      string
 }
 
 var taskLogFindFlags TaskLogFindFlags
@@ -428,6 +433,10 @@ func init() {
 	taskLogFindCmd.Flags().StringVarP(&taskLogFindFlags.taskID, "task-id", "", "", "task id (required)")
 	taskLogFindCmd.Flags().StringVarP(&taskLogFindFlags.runID, "run-id", "", "", "run id")
 	taskLogFindCmd.Flags().StringVarP(&taskLogFindFlags.orgID, "org-id", "", "", "organization id")
+	taskLogFindCmd.Flags().StringVarP(&taskLogFindFlags.afterTime, "after", "", "", "only show log lines written after this time")
+	taskLogFindCmd.Flags().StringVarP(&taskLogFindFlags.beforeTime, "before", "", "", "only show log lines written before this time")
+	taskLogFindCmd.Flags().StringVarP(&taskLogFindFlags.status, "status", "", "", "only show logs of runs with this status")
+	taskLogFindCmd.Flags().StringVarP(&taskLogFindFlags.match, "match", "", "", "only show log lines containing this text, ignoring case")
 	taskLogFindCmd.MarkFlagRequired("task-id")
 
 	logCmd.AddCommand(taskLogFindCmd)
@@ -465,6 +474,11 @@ func taskLogFindF(cmd *cobra.Command, args []string) {
 		filter.Org = id
 	}
 
+	filter.AfterTime = taskLogFindFlags.afterTime
+	filter.BeforeTime = taskLogFindFlags.beforeTime
+	filter.Status = taskLogFindFlags.status
+	filter.Match = taskLogFindFlags.match
+
 	ctx := context.TODO()
 	logs, _, err := s.FindLogs(ctx, filter)
 	if err != nil {
@@ -492,6 +506,8 @@ type TaskRunFindFlags struct {
 	afterTime  string
 	beforeTime string
 	limit      int
+	status     string
+	match      string
 }
 
 var taskRunFindFlags TaskRunFindFlags
@@ -509,6 +525,8 @@ func init() {
 	taskRunFindCmd.Flags().StringVarP(&taskRunFindFlags.afterTime, "after", "", "", "after time for filtering")
 	taskRunFindCmd.Flags().StringVarP(&taskRunFindFlags.beforeTime, "before", "", "", "before time for filtering")
 	taskRunFindCmd.Flags().IntVarP(&taskRunFindFlags.limit, "limit", "", 0, "limit the results")
+	taskRunFindCmd.Flags().StringVarP(&taskRunFindFlags.status, "status", "", "", "status for filtering")
+	taskRunFindCmd.Flags().StringVarP(&taskRunFindFlags.match, "match", "", "", "only find runs with a log line containing this text, ignoring case")
 
 	taskRunFindCmd.MarkFlagRequired("task-id")
 	taskRunFindCmd.MarkFlagRequired("org-id")
@@ -526,6 +544,8 @@ func taskRunFindF(cmd *cobra.Command, args []string) {
 		Limit:      taskRunFindFlags.limit,
 		AfterTime:  taskRunFindFlags.afterTime,
 		BeforeTime: taskRunFindFlags.beforeTime,
+		Status:     taskRunFindFlags.status,
+		Match:      taskRunFindFlags.match,
 	}
 	taskID, err := platform.IDFromString(taskRunFindFlags.taskID)
 	if err != nil {
@@ -632,3 +652,170 @@ func runRetryF(cmd *cobra.Command, args []string) {
 
 	fmt.Printf("Retry for task %s's run %s queued as run %s.\n", taskID, runID, newRun.ID)
 }
+
+type TaskBackfillFlags struct {
+	taskID string
+	start  string
+	stop   string
+}
+
+var taskBackfillFlags TaskBackfillFlags
+
+var taskBackfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "run a task for every schedule in a historical time range",
+	Run:   taskBackfillF,
+}
+
+func init() {
+	taskBackfillCmd.Flags().StringVarP(&taskBackfillFlags.taskID, "task-id", "i", "", "task id (required)")
+	taskBackfillCmd.Flags().StringVarP(&taskBackfillFlags.start, "start", "", "", "start of the time range, in RFC3339 format (required)")
+	taskBackfillCmd.Flags().StringVarP(&taskBackfillFlags.stop, "stop", "", "", "end of the time range, in RFC3339 format (required)")
+	taskBackfillCmd.MarkFlagRequired("task-id")
+	taskBackfillCmd.MarkFlagRequired("start")
+	taskBackfillCmd.MarkFlagRequired("stop")
+
+	taskCmd.AddCommand(taskBackfillCmd)
+}
+
+func taskBackfillF(cmd *cobra.Command, args []string) {
+	s := &http.TaskService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	var taskID platform.ID
+	if err := taskID.DecodeFromString(taskBackfillFlags.taskID); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	start, err := time.Parse(time.RFC3339, taskBackfillFlags.start)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	stop, err := time.Parse(time.RFC3339, taskBackfillFlags.stop)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	b, err := s.BackfillTask(context.Background(), taskID, start.Unix(), stop.Unix())
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	printBackfills(b)
+}
+
+type TaskBackfillFindFlags struct {
+	taskID string
+}
+
+var taskBackfillFindFlags TaskBackfillFindFlags
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "find",
+		Short: "find backfills for a task",
+		Run:   taskBackfillFindF,
+	}
+
+	cmd.Flags().StringVarP(&taskBackfillFindFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.MarkFlagRequired("task-id")
+
+	taskBackfillCmd.AddCommand(cmd)
+}
+
+func taskBackfillFindF(cmd *cobra.Command, args []string) {
+	s := &http.TaskService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	var taskID platform.ID
+	if err := taskID.DecodeFromString(taskBackfillFindFlags.taskID); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	backfills, err := s.FindBackfills(context.Background(), taskID)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	printBackfills(backfills...)
+}
+
+type TaskBackfillCancelFlags struct {
+	taskID, backfillID string
+}
+
+var taskBackfillCancelFlags TaskBackfillCancelFlags
+
+func init() {
+	cmd := &cobra.Command{
+		Use:   "cancel",
+		Short: "cancel a backfill and its runs in progress",
+		Run:   taskBackfillCancelF,
+	}
+
+	cmd.Flags().StringVarP(&taskBackfillCancelFlags.taskID, "task-id", "i", "", "task id (required)")
+	cmd.Flags().StringVarP(&taskBackfillCancelFlags.backfillID, "backfill-id", "b", "", "backfill id (required)")
+	cmd.MarkFlagRequired("task-id")
+	cmd.MarkFlagRequired("backfill-id")
+
+	taskBackfillCmd.AddCommand(cmd)
+}
+
+func taskBackfillCancelF(cmd *cobra.Command, args []string) {
+	s := &http.TaskService{
+		Addr:  flags.host,
+		Token: flags.token,
+	}
+
+	var taskID, backfillID platform.ID
+	if err := taskID.DecodeFromString(taskBackfillCancelFlags.taskID); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := backfillID.DecodeFromString(taskBackfillCancelFlags.backfillID); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if err := s.CancelBackfill(context.Background(), taskID, backfillID); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Backfill %s of task %s canceled.\n", backfillID, taskID)
+}
+
+func printBackfills(backfills ...*platform.Backfill) {
+	w := internal.NewTabWriter(os.Stdout)
+	w.WriteHeaders(
+		"ID",
+		"TaskID",
+		"Start",
+		"Stop",
+		"RequestedAt",
+		"Completed",
+		"Remaining",
+	)
+	for _, b := range backfills {
+		w.Write(map[string]interface{}{
+			"ID":          b.ID,
+			"TaskID":      b.TaskID,
+			"Start":       b.Start,
+			"Stop":        b.Stop,
+			"RequestedAt": b.RequestedAt,
+			"Completed":   b.Completed,
+			"Remaining":   b.Remaining,
+		})
+	}
+	w.Flush()
+}
@@ -20,7 +20,8 @@ var writeCmd = &cobra.Command{
 	Use:   "write line protocol or @/path/to/points.txt",
 	Short: "Write points to influxdb",
 	Long: `Write a single line of line protocol to influx db,
-		or add an entire file specified with an @ prefix`,
+		or add an entire file specified with an @ prefix.
+		Annotated CSV and JSON points may be written with --format`,
 	Args: cobra.ExactArgs(1),
 	RunE: fluxWriteF,
 }
@@ -31,6 +32,7 @@ var writeFlags struct {
 	BucketID  string
 	Bucket    string
 	Precision string
+	Format    string
 }
 
 func init() {
@@ -63,6 +65,15 @@ func init() {
 	if p := viper.GetString("PRECISION"); p != "" {
 		writeFlags.Precision = p
 	}
+
+	writeCmd.PersistentFlags().StringVar(&writeFlags.Format, "format", "lp", "format of the data: lp (line protocol), csv (annotated CSV, as output by query), or json")
+}
+
+// writeContentTypes maps the formats accepted by the write command to the content types sent to the server.
+var writeContentTypes = map[string]string{
+	"lp":   "text/plain; charset=utf-8",
+	"csv":  "text/csv; charset=utf-8",
+	"json": "application/json",
 }
 
 func fluxWriteF(cmd *cobra.Command, args []string) error {
@@ -83,6 +94,12 @@ func fluxWriteF(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("invalid precision")
 	}
 
+	contentType, ok := writeContentTypes[writeFlags.Format]
+	if !ok {
+		cmd.Usage()
+		return fmt.Errorf("invalid format; valid formats are lp, csv, and json")
+	}
+
 	bs := &http.BucketService{
 		Addr:  flags.host,
 		Token: flags.token,
@@ -136,12 +153,16 @@ func fluxWriteF(cmd *cobra.Command, args []string) error {
 		r = strings.NewReader(args[0])
 	}
 
-	s := write.Batcher{
-		Service: &http.WriteService{
-			Addr:      flags.host,
-			Token:     flags.token,
-			Precision: writeFlags.Precision,
-		},
+	var s platform.WriteService = &http.WriteService{
+		Addr:        flags.host,
+		Token:       flags.token,
+		Precision:   writeFlags.Precision,
+		ContentType: contentType,
+	}
+	// Only line protocol can be split into batches at arbitrary lines;
+	// CSV annotations and JSON documents must be sent whole.
+	if writeFlags.Format == "lp" {
+		s = &write.Batcher{Service: s}
 	}
 
 	ctx = signals.WithStandardSignals(ctx)
@@ -0,0 +1,395 @@
+// Package export exports the TSM and WAL files of an engine as line protocol.
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bolt"
+	"github.com/influxdata/platform/internal/fs"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+	"github.com/influxdata/platform/tsdb/tsm1"
+)
+
+// Command represents the program execution for "influx_inspect export".
+type Command struct {
+	Stderr io.Writer
+	Stdout io.Writer
+
+	enginePath string
+	boltPath   string
+	out        string
+	compress   bool
+
+	orgID, bucketID *platform.ID
+	prefix          []byte // escaped measurement prefix of the selected keys
+
+	startTime int64
+	endTime   int64
+
+	context [16]byte // organization and bucket of the last exported point
+
+	// orgs and buckets resolve the names written in the context comments, if the bolt database is available.
+	orgs        platform.OrganizationService
+	buckets     platform.BucketService
+	orgNames    map[platform.ID]string
+	bucketNames map[platform.ID]string
+}
+
+// NewCommand returns a new instance of Command.
+func NewCommand() *Command {
+	return &Command{
+		Stderr: os.Stderr,
+		Stdout: os.Stdout,
+	}
+}
+
+// Run executes the command.
+func (cmd *Command) Run(args ...string) error {
+	var orgID, bucketID, start, end string
+
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.StringVar(&cmd.enginePath, "engine-path", defaultPath("engine"), "Path to the engine")
+	fs.StringVar(&cmd.boltPath, "bolt-path", defaultPath("influxd.bolt"), "Path to the bolt database used to resolve organization and bucket names. IDs are written if it cannot be opened")
+	fs.StringVar(&cmd.out, "out", "-", "Destination file, or - for standard output")
+	fs.StringVar(&orgID, "org-id", "", "Optional: the organization to export")
+	fs.StringVar(&bucketID, "bucket-id", "", "Optional: the bucket to export. Requires -org-id")
+	fs.StringVar(&start, "start", "", "Optional: the start time of the export (RFC3339 format)")
+	fs.StringVar(&end, "end", "", "Optional: the end time of the export (RFC3339 format)")
+	fs.BoolVar(&cmd.compress, "compress", false, "Compress the output with gzip")
+	fs.SetOutput(cmd.Stdout)
+	fs.Usage = func() {
+		fmt.Fprintf(cmd.Stdout, "Exports TSM and WAL files as line protocol.\n\n")
+		fmt.Fprintf(cmd.Stdout, "Usage: %s export [flags]\n\n", filepath.Base(os.Args[0]))
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if fs.NArg() > 0 {
+		fs.Usage()
+		return nil
+	}
+
+	if orgID != "" {
+		id, err := platform.IDFromString(orgID)
+		if err != nil {
+			return fmt.Errorf("invalid -org-id: %v", err)
+		}
+		cmd.orgID = id
+	}
+	if bucketID != "" {
+		if cmd.orgID == nil {
+			return fmt.Errorf("-bucket-id requires -org-id")
+		}
+		id, err := platform.IDFromString(bucketID)
+		if err != nil {
+			return fmt.Errorf("invalid -bucket-id: %v", err)
+		}
+		cmd.bucketID = id
+	}
+
+	cmd.startTime, cmd.endTime = math.MinInt64, math.MaxInt64
+	if start != "" {
+		t, err := time.Parse(time.RFC3339, start)
+		if err != nil {
+			return fmt.Errorf("invalid -start: %v", err)
+		}
+		cmd.startTime = t.UnixNano()
+	}
+	if end != "" {
+		t, err := time.Parse(time.RFC3339, end)
+		if err != nil {
+			return fmt.Errorf("invalid -end: %v", err)
+		}
+		cmd.endTime = t.UnixNano()
+	}
+	if cmd.startTime > cmd.endTime {
+		return fmt.Errorf("-end must be after -start")
+	}
+
+	return cmd.export()
+}
+
+func defaultPath(name string) string {
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, name)
+}
+
+func (cmd *Command) export() error {
+	// Measurement names begin with the organization ID, followed by the bucket ID.
+	if cmd.orgID != nil {
+		name := tsdb.EncodeName(*cmd.orgID, 0)
+		prefix := name[:8]
+		if cmd.bucketID != nil {
+			name = tsdb.EncodeName(*cmd.orgID, *cmd.bucketID)
+			prefix = name[:]
+		}
+		cmd.prefix = models.EscapeMeasurement(prefix)
+	}
+
+	c := storage.NewConfig()
+	tsmFiles, err := filepath.Glob(filepath.Join(c.GetEnginePath(cmd.enginePath), "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		return err
+	}
+	walFiles, err := filepath.Glob(filepath.Join(c.GetWALPath(cmd.enginePath), "*."+tsm1.WALFileExtension))
+	if err != nil {
+		return err
+	}
+	if len(tsmFiles) == 0 && len(walFiles) == 0 {
+		return fmt.Errorf("no TSM or WAL files found in %s", cmd.enginePath)
+	}
+	sort.Strings(tsmFiles)
+	sort.Strings(walFiles)
+
+	cmd.orgNames, cmd.bucketNames = make(map[platform.ID]string), make(map[platform.ID]string)
+	if client, err := cmd.openBolt(); err != nil {
+		fmt.Fprintf(cmd.Stderr, "writing organization and bucket IDs instead of names: %v\n", err)
+	} else {
+		defer client.Close()
+		cmd.orgs, cmd.buckets = client, client
+	}
+
+	var w io.Writer = cmd.Stdout
+	if cmd.out != "-" {
+		f, err := os.Create(cmd.out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	bw := bufio.NewWriterSize(w, 1024*1024)
+	w = bw
+	var gw *gzip.Writer
+	if cmd.compress {
+		gw = gzip.NewWriter(bw)
+		w = gw
+	}
+
+	if err := cmd.write(w, tsmFiles, walFiles); err != nil {
+		return err
+	}
+
+	if gw != nil {
+		if err := gw.Close(); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// openBolt opens the bolt database at boltPath. It fails if influxd has the database open.
+func (cmd *Command) openBolt() (*bolt.Client, error) {
+	if cmd.boltPath == "" {
+		return nil, fmt.Errorf("no bolt database")
+	}
+	if _, err := os.Stat(cmd.boltPath); err != nil {
+		return nil, err
+	}
+	client := bolt.NewClient()
+	client.Path = cmd.boltPath
+	if err := client.Open(context.Background()); err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// orgName returns the name of the organization with the id, or the id if the name can't be found.
+func (cmd *Command) orgName(id platform.ID) string {
+	name, ok := cmd.orgNames[id]
+	if !ok {
+		name = id.String()
+		if cmd.orgs != nil {
+			if o, err := cmd.orgs.FindOrganizationByID(context.Background(), id); err == nil {
+				name = o.Name
+			} else {
+				fmt.Fprintf(cmd.Stderr, "unable to find organization %s: %v\n", id, err)
+			}
+		}
+		cmd.orgNames[id] = name
+	}
+	return name
+}
+
+// bucketName returns the name of the bucket with the id, or the id if the name can't be found.
+func (cmd *Command) bucketName(id platform.ID) string {
+	name, ok := cmd.bucketNames[id]
+	if !ok {
+		name = id.String()
+		if cmd.buckets != nil {
+			if b, err := cmd.buckets.FindBucketByID(context.Background(), id); err == nil {
+				name = b.Name
+			} else {
+				fmt.Fprintf(cmd.Stderr, "unable to find bucket %s: %v\n", id, err)
+			}
+		}
+		cmd.bucketNames[id] = name
+	}
+	return name
+}
+
+func (cmd *Command) write(w io.Writer, tsmFiles, walFiles []string) error {
+	for _, path := range tsmFiles {
+		if err := cmd.writeTSMFile(w, path); err != nil {
+			return err
+		}
+	}
+	for _, path := range walFiles {
+		if err := cmd.writeWALFile(w, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (cmd *Command) writeTSMFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		f.Close()
+		fmt.Fprintf(cmd.Stderr, "skipping unreadable TSM file %s: %v\n", path, err)
+		return nil
+	}
+	defer r.Close()
+
+	if min, max := r.TimeRange(); max < cmd.startTime || min > cmd.endTime {
+		return nil
+	}
+
+	iter := r.Iterator(cmd.prefix)
+	for iter.Next() {
+		key := iter.Key()
+		if !bytes.HasPrefix(key, cmd.prefix) {
+			break // Keys are sorted, so no later key can match.
+		}
+
+		values, err := r.ReadAll(key)
+		if err != nil {
+			fmt.Fprintf(cmd.Stderr, "unable to read key %q in %s: %v\n", key, path, err)
+			continue
+		}
+		if err := cmd.writeValues(w, key, values); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
+
+func (cmd *Command) writeWALFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	r := tsm1.NewWALSegmentReader(f)
+	defer r.Close()
+
+	for r.Next() {
+		entry, err := r.Read()
+		if err != nil {
+			// The tail of the last segment may be incomplete if influxd did not shut down cleanly.
+			fmt.Fprintf(cmd.Stderr, "stopped reading corrupt WAL file %s at entry %d: %v\n", path, r.Count(), err)
+			return nil
+		}
+
+		// Deletes are not replayed; exported data may include points deleted after they were written to the WAL.
+		e, ok := entry.(*tsm1.WriteWALEntry)
+		if !ok {
+			continue
+		}
+
+		keys := make([]string, 0, len(e.Values))
+		for key := range e.Values {
+			if bytes.HasPrefix([]byte(key), cmd.prefix) {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if err := cmd.writeValues(w, []byte(key), e.Values[key]); err != nil {
+				return err
+			}
+		}
+	}
+	return r.Error()
+}
+
+// writeValues writes the values of the TSM key as line protocol. The names of the encoded organization
+// and bucket of the key are written as comments whenever they change, and the measurement and
+// field names are restored from the tags they are stored in.
+func (cmd *Command) writeValues(w io.Writer, key []byte, values []tsm1.Value) error {
+	seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey(key)
+	name, tags := models.ParseKeyBytes(seriesKey)
+	if len(name) != len(cmd.context) {
+		fmt.Fprintf(cmd.Stderr, "skipping key with invalid organization and bucket %q\n", key)
+		return nil
+	}
+
+	var measurement []byte
+	pointTags := make(models.Tags, 0, len(tags))
+	for _, t := range tags {
+		switch string(t.Key) {
+		case tsdb.MeasurementTagKey:
+			measurement = t.Value
+		case tsdb.FieldKeyTagKey:
+		default:
+			pointTags = append(pointTags, t)
+		}
+	}
+	if len(measurement) == 0 {
+		fmt.Fprintf(cmd.Stderr, "skipping key without a measurement %q\n", key)
+		return nil
+	}
+
+	var encoded [16]byte
+	copy(encoded[:], name)
+	if encoded != cmd.context {
+		cmd.context = encoded
+		org, bucket := tsdb.DecodeName(encoded)
+		if _, err := fmt.Fprintf(w, "# CONTEXT-ORGANIZATION: %s\n# CONTEXT-BUCKET: %s\n", cmd.orgName(org), cmd.bucketName(bucket)); err != nil {
+			return err
+		}
+	}
+
+	buf := make([]byte, 0, 256)
+	for _, v := range values {
+		ts := v.UnixNano()
+		if ts < cmd.startTime || ts > cmd.endTime {
+			continue
+		}
+
+		pt, err := models.NewPoint(string(measurement), pointTags, models.Fields{string(field): v.Value()}, time.Unix(0, ts))
+		if err != nil {
+			fmt.Fprintf(cmd.Stderr, "skipping invalid point for key %q: %v\n", key, err)
+			continue
+		}
+
+		buf = pt.AppendString(buf[:0])
+		buf = append(buf, '\n')
+		if _, err := w.Write(buf); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bolt"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/tsdb"
+	"github.com/influxdata/platform/tsdb/tsm1"
+)
+
+// explode returns the TSM values of the line protocol lines written to org and bucket, by key.
+func explode(t *testing.T, org, bucket platform.ID, lines string) map[string][]tsm1.Value {
+	t.Helper()
+	pts, err := models.ParsePointsString(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pts, err = tsdb.ExplodePoints(org, bucket, pts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := make(map[string][]tsm1.Value)
+	for _, pt := range pts {
+		iter := pt.FieldIterator()
+		for iter.Next() {
+			key := string(tsm1.SeriesFieldKeyBytes(string(pt.Key()), string(iter.FieldKey())))
+			var v interface{}
+			switch iter.Type() {
+			case models.Float:
+				v, _ = iter.FloatValue()
+			case models.Integer:
+				v, _ = iter.IntegerValue()
+			case models.String:
+				v = iter.StringValue()
+			}
+			values[key] = append(values[key], tsm1.NewValue(pt.Time().UnixNano(), v))
+		}
+	}
+	return values
+}
+
+func writeTSMFile(t *testing.T, path string, values map[string][]tsm1.Value) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := tsm1.NewTSMWriter(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := w.Write([]byte(k), values[k]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.WriteIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCommand_Run(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	org, bucketA, bucketB := platform.ID(1), platform.ID(2), platform.ID(3)
+
+	if err := os.MkdirAll(filepath.Join(dir, "data"), 0777); err != nil {
+		t.Fatal(err)
+	}
+	values := explode(t, org, bucketA, "cpu,host=a value=1 10\ncpu,host=b value=2i 20\nmem,host=a free=\"lots\" 30")
+	for k, v := range explode(t, org, bucketB, "cpu,host=c value=3 10") {
+		values[k] = v
+	}
+	writeTSMFile(t, filepath.Join(dir, "data", "000000001-000000001.tsm"), values)
+
+	wal := tsm1.NewWAL(filepath.Join(dir, "wal"))
+	if err := wal.Open(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wal.WriteMulti(explode(t, org, bucketA, "cpu,host=a value=4 40")); err != nil {
+		t.Fatal(err)
+	}
+	if err := wal.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		exp  string
+	}{
+		{
+			name: "all",
+			exp: `# CONTEXT-ORGANIZATION: 0000000000000001
+# CONTEXT-BUCKET: 0000000000000002
+mem,host=a free="lots" 30
+cpu,host=a value=1 10
+cpu,host=b value=2i 20
+# CONTEXT-ORGANIZATION: 0000000000000001
+# CONTEXT-BUCKET: 0000000000000003
+cpu,host=c value=3 10
+# CONTEXT-ORGANIZATION: 0000000000000001
+# CONTEXT-BUCKET: 0000000000000002
+cpu,host=a value=4 40
+`,
+		},
+		{
+			name: "bucket and time range",
+			args: []string{"-org-id", org.String(), "-bucket-id", bucketA.String(), "-start", "1970-01-01T00:00:00.00000002Z", "-end", "1970-01-01T00:00:00.00000004Z"},
+			exp: `# CONTEXT-ORGANIZATION: 0000000000000001
+# CONTEXT-BUCKET: 0000000000000002
+mem,host=a free="lots" 30
+cpu,host=b value=2i 20
+cpu,host=a value=4 40
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			cmd := NewCommand()
+			cmd.Stdout, cmd.Stderr = &stdout, &stderr
+			if err := cmd.Run(append([]string{"-engine-path", dir, "-bolt-path", filepath.Join(dir, "influxd.bolt")}, tt.args...)...); err != nil {
+				t.Fatal(err)
+			}
+			if got := stdout.String(); got != tt.exp {
+				t.Fatalf("unexpected output:\n%s\nexp:\n%s\nstderr: %s", got, tt.exp, stderr.String())
+			}
+		})
+	}
+
+	if err := NewCommand().Run("-engine-path", dir, "-bucket-id", bucketA.String()); err == nil || !strings.Contains(err.Error(), "requires -org-id") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCommand_Run_Names(t *testing.T) {
+	dir, err := ioutil.TempDir("", "export_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	client := bolt.NewClient()
+	client.Path = filepath.Join(dir, "influxd.bolt")
+	if err := client.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	org := &platform.Organization{Name: "org"}
+	if err := client.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	bucket := &platform.Bucket{Name: "bucket", OrganizationID: org.ID}
+	if err := client.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The ID of a bucket missing from the bolt database is written instead of its name.
+	deleted := platform.ID(1)
+	if err := os.MkdirAll(filepath.Join(dir, "data"), 0777); err != nil {
+		t.Fatal(err)
+	}
+	values := explode(t, org.ID, bucket.ID, "cpu,host=a value=1 10")
+	for k, v := range explode(t, org.ID, deleted, "cpu,host=b value=2 10") {
+		values[k] = v
+	}
+	writeTSMFile(t, filepath.Join(dir, "data", "000000001-000000001.tsm"), values)
+
+	var stdout, stderr bytes.Buffer
+	cmd := NewCommand()
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run("-engine-path", dir, "-bolt-path", client.Path); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := `# CONTEXT-ORGANIZATION: org
+# CONTEXT-BUCKET: 0000000000000001
+cpu,host=b value=2 10
+# CONTEXT-ORGANIZATION: org
+# CONTEXT-BUCKET: bucket
+cpu,host=a value=1 10
+`
+	if got := stdout.String(); got != exp {
+		t.Fatalf("unexpected output:\n%s\nexp:\n%s\nstderr: %s", got, exp, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "unable to find bucket 0000000000000001") {
+		t.Fatalf("unexpected stderr: %s", stderr.String())
+	}
+}
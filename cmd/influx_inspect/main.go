@@ -0,0 +1,91 @@
+// The influx_inspect command displays detailed information about InfluxDB data files.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/influxdata/platform/cmd/influx_inspect/buildtsi"
+	"github.com/influxdata/platform/cmd/influx_inspect/export"
+	"github.com/influxdata/platform/cmd/influx_inspect/report"
+	"github.com/influxdata/platform/cmd/influx_inspect/verify"
+)
+
+func main() {
+	m := NewMain()
+	if err := m.Run(os.Args[1:]...); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Main represents the program execution.
+type Main struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// NewMain returns a new instance of Main.
+func NewMain() *Main {
+	return &Main{
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+}
+
+// Run determines and runs the command specified by the CLI args.
+func (m *Main) Run(args ...string) error {
+	name, args := "", args
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	switch name {
+	case "", "help":
+		fmt.Fprintln(m.Stdout, usage)
+	case "buildtsi":
+		cmd := buildtsi.NewCommand()
+		cmd.Stdout, cmd.Stderr = m.Stdout, m.Stderr
+		if err := cmd.Run(args...); err != nil {
+			return fmt.Errorf("buildtsi: %s", err)
+		}
+	case "export":
+		cmd := export.NewCommand()
+		cmd.Stdout, cmd.Stderr = m.Stdout, m.Stderr
+		if err := cmd.Run(args...); err != nil {
+			return fmt.Errorf("export: %s", err)
+		}
+	case "report":
+		cmd := report.NewCommand()
+		cmd.Stdout, cmd.Stderr = m.Stdout, m.Stderr
+		if err := cmd.Run(args...); err != nil {
+			return fmt.Errorf("report: %s", err)
+		}
+	case "verify":
+		cmd := verify.NewCommand()
+		cmd.Stdout, cmd.Stderr = m.Stdout, m.Stderr
+		if err := cmd.Run(args...); err != nil {
+			return fmt.Errorf("verify: %s", err)
+		}
+	default:
+		return fmt.Errorf(`unknown command "%s"`+"\n"+`Run 'influx_inspect help' for usage`+"\n\n", name)
+	}
+
+	return nil
+}
+
+const usage = `Usage: influx_inspect [command] [arguments]
+
+The commands are:
+
+    buildtsi             generates tsi1 indexes from tsm1 data
+    export               exports raw data from TSM and WAL files as line protocol
+    help                 display this help message
+    report               displays TSM file sizes and series cardinality by bucket
+    verify               verifies the integrity of TSM files, tombstones, and series files
+
+Use "influx_inspect [command] -help" for more information about a command.`
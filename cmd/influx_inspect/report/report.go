@@ -0,0 +1,236 @@
+// Package report reports the sizes of the TSM files and the series cardinality of an engine.
+package report
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/influxdata/platform/internal/fs"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+	"github.com/influxdata/platform/tsdb/tsi1"
+	"github.com/influxdata/platform/tsdb/tsm1"
+)
+
+// Command represents the program execution for "influx_inspect report".
+type Command struct {
+	Stderr io.Writer
+	Stdout io.Writer
+
+	enginePath string
+	detailed   bool
+}
+
+// NewCommand returns a new instance of Command.
+func NewCommand() *Command {
+	return &Command{
+		Stderr: os.Stderr,
+		Stdout: os.Stdout,
+	}
+}
+
+// Run executes the command.
+func (cmd *Command) Run(args ...string) error {
+	fs := flag.NewFlagSet("report", flag.ExitOnError)
+	fs.StringVar(&cmd.enginePath, "engine-path", defaultEnginePath(), "Path to the engine")
+	fs.BoolVar(&cmd.detailed, "detailed", false, "Report the series cardinality of each measurement in each bucket")
+	fs.SetOutput(cmd.Stdout)
+	fs.Usage = func() {
+		fmt.Fprintf(cmd.Stdout, "Reports TSM file sizes and series cardinality by bucket.\n\n")
+		fmt.Fprintf(cmd.Stdout, "Usage: %s report [flags]\n\n", filepath.Base(os.Args[0]))
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if fs.NArg() > 0 {
+		fs.Usage()
+		return nil
+	}
+	if _, err := os.Stat(cmd.enginePath); err != nil {
+		return err
+	}
+
+	c := storage.NewConfig()
+	if err := cmd.reportTSMFiles(c); err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.Stdout)
+	return cmd.reportCardinality(c)
+}
+
+func defaultEnginePath() string {
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "engine")
+}
+
+// reportTSMFiles writes the size, block and point counts, and time range of each TSM file.
+func (cmd *Command) reportTSMFiles(c storage.Config) error {
+	paths, err := filepath.Glob(filepath.Join(c.GetEnginePath(cmd.enginePath), "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+
+	tw := tabwriter.NewWriter(cmd.Stdout, 8, 8, 1, '\t', 0)
+	fmt.Fprintln(tw, "File\tSize\tKeys\tBlocks\tPoints\tPoints/Block\tMin Time\tMax Time")
+
+	var totalSize, totalBlocks, totalPoints int64
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		r, err := tsm1.NewTSMReader(f)
+		if err != nil {
+			f.Close()
+			fmt.Fprintf(cmd.Stderr, "unable to read %s: %v\n", path, err)
+			continue
+		}
+
+		var blocks, points int64
+		iter := r.BlockIterator()
+		for iter.Next() {
+			_, _, _, _, _, buf, err := iter.Read()
+			if err != nil {
+				break
+			}
+			blocks++
+			points += int64(tsm1.BlockCount(buf))
+		}
+		if err := iter.Err(); err != nil {
+			fmt.Fprintf(cmd.Stderr, "unable to read blocks of %s: %v\n", path, err)
+		}
+
+		var pointsPerBlock float64
+		if blocks > 0 {
+			pointsPerBlock = float64(points) / float64(blocks)
+		}
+		min, max := r.TimeRange()
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%.1f\t%s\t%s\n",
+			filepath.Base(path), r.Size(), r.KeyCount(), blocks, points, pointsPerBlock,
+			time.Unix(0, min).UTC().Format(time.RFC3339Nano), time.Unix(0, max).UTC().Format(time.RFC3339Nano))
+
+		totalSize += int64(r.Size())
+		totalBlocks += blocks
+		totalPoints += points
+		r.Close()
+	}
+	fmt.Fprintf(tw, "Total (%d files)\t%d\t\t%d\t%d\t\t\t\n", len(paths), totalSize, totalBlocks, totalPoints)
+	return tw.Flush()
+}
+
+// reportCardinality writes the number of series in each bucket, as tracked by the index,
+// and the number of series of each measurement if the report is detailed.
+func (cmd *Command) reportCardinality(c storage.Config) error {
+	sfile := tsdb.NewSeriesFile(c.GetSeriesFilePath(cmd.enginePath))
+	if err := sfile.Open(); err != nil {
+		return err
+	}
+	defer sfile.Close()
+
+	index := tsi1.NewIndex(sfile, c.Index, tsi1.WithPath(c.GetIndexPath(cmd.enginePath)), tsi1.DisableMetrics())
+	if err := index.Open(); err != nil {
+		return err
+	}
+	defer index.Close()
+
+	// Each index measurement is a bucket, named by its encoded organization and bucket IDs.
+	stats := index.MeasurementCardinalityStats()
+
+	tw := tabwriter.NewWriter(cmd.Stdout, 8, 8, 1, '\t', 0)
+	if cmd.detailed {
+		fmt.Fprintln(tw, "Organization\tBucket\tMeasurement\tSeries")
+	} else {
+		fmt.Fprintln(tw, "Organization\tBucket\tSeries")
+	}
+
+	var total int
+	for _, name := range stats.MeasurementNames() {
+		n := stats[name]
+		total += n
+		if len(name) != 16 {
+			fmt.Fprintf(cmd.Stderr, "skipping measurement with invalid organization and bucket %q\n", name)
+			continue
+		}
+
+		var encoded [16]byte
+		copy(encoded[:], name)
+		org, bucket := tsdb.DecodeName(encoded)
+		if !cmd.detailed {
+			fmt.Fprintf(tw, "%s\t%s\t%d\n", org, bucket, n)
+			continue
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t\t%d\n", org, bucket, n)
+		counts, err := measurementCardinality(index, []byte(name))
+		if err != nil {
+			return err
+		}
+		for _, m := range counts {
+			fmt.Fprintf(tw, "\t\t%s\t%d\n", m.name, m.n)
+		}
+	}
+	if cmd.detailed {
+		fmt.Fprintf(tw, "Total\t\t\t%d\n", total)
+	} else {
+		fmt.Fprintf(tw, "Total\t\t%d\n", total)
+	}
+	return tw.Flush()
+}
+
+type measurementCount struct {
+	name string
+	n    int
+}
+
+// measurementCardinality returns the number of series of each measurement of the bucket named name in index.
+// Measurements are stored as the values of a tag, so each count is that of the series with the tag value.
+func measurementCardinality(index *tsi1.Index, name []byte) ([]measurementCount, error) {
+	vitr, err := index.TagValueIterator(name, tsdb.MeasurementTagKeyBytes)
+	if err != nil {
+		return nil, err
+	} else if vitr == nil {
+		return nil, nil
+	}
+	defer vitr.Close()
+
+	var counts []measurementCount
+	for {
+		value, err := vitr.Next()
+		if err != nil {
+			return nil, err
+		} else if value == nil {
+			break
+		}
+
+		sitr, err := index.TagValueSeriesIDIterator(name, tsdb.MeasurementTagKeyBytes, value)
+		if err != nil {
+			return nil, err
+		}
+		n := 0
+		if sitr != nil {
+			for {
+				elem, err := sitr.Next()
+				if err != nil {
+					sitr.Close()
+					return nil, err
+				} else if elem.SeriesID.IsZero() {
+					break
+				}
+				n++
+			}
+			sitr.Close()
+		}
+		counts = append(counts, measurementCount{name: string(value), n: n})
+	}
+	return counts, nil
+}
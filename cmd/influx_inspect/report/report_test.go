@@ -0,0 +1,73 @@
+package report
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+)
+
+func TestCommand_Run(t *testing.T) {
+	dir, err := ioutil.TempDir("", "report_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	engine := storage.NewEngine(dir, storage.NewConfig())
+	if err := engine.Open(); err != nil {
+		t.Fatal(err)
+	}
+	pts, err := models.ParsePointsString("cpu,host=a value=1 1\ncpu,host=b value=2 2\nmem,host=a free=3 3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	points, err := tsdb.ExplodePoints(platform.ID(1), platform.ID(2), pts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.WritePoints(points); err != nil {
+		t.Fatal(err)
+	}
+	// Creating a backup writes the cache to a TSM file.
+	backup, err := engine.CreateBackup(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.RemoveAll(backup)
+	if err := engine.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout bytes.Buffer
+	cmd := NewCommand()
+	cmd.Stdout, cmd.Stderr = &stdout, &stdout
+	if err := cmd.Run("-engine-path", dir, "-detailed"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Compare the output with the columns separated by single spaces, regardless of their alignment.
+	var lines []string
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		lines = append(lines, strings.Join(strings.Fields(line), " "))
+	}
+	out := strings.Join(lines, "\n")
+
+	for _, exp := range []string{
+		"000000001-000000001.tsm 356 3 3 3 1.0",
+		"0000000000000001 0000000000000002 3",
+		"\ncpu 2\n",
+		"\nmem 1\n",
+		"Total 3",
+	} {
+		if !strings.Contains(out, exp) {
+			t.Fatalf("output does not contain %q:\n%s", exp, stdout.String())
+		}
+	}
+}
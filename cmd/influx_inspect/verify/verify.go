@@ -0,0 +1,195 @@
+// Package verify verifies the integrity of the TSM files, tombstones, and series file of an engine.
+package verify
+
+import (
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/influxdata/platform/internal/fs"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+	"github.com/influxdata/platform/tsdb/tsm1"
+)
+
+// Command represents the program execution for "influx_inspect verify".
+type Command struct {
+	Stderr io.Writer
+	Stdout io.Writer
+
+	enginePath string
+	verbose    bool
+}
+
+// NewCommand returns a new instance of Command.
+func NewCommand() *Command {
+	return &Command{
+		Stderr: os.Stderr,
+		Stdout: os.Stdout,
+	}
+}
+
+// Run executes the command. It returns an error if any file is corrupt.
+func (cmd *Command) Run(args ...string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	fs.StringVar(&cmd.enginePath, "engine-path", defaultEnginePath(), "Path to the engine")
+	fs.BoolVar(&cmd.verbose, "v", false, "Print the status of every file, not only corrupt ones")
+	fs.SetOutput(cmd.Stdout)
+	fs.Usage = func() {
+		fmt.Fprintf(cmd.Stdout, "Verifies the integrity of TSM files, tombstones, and series file segments.\n\n")
+		fmt.Fprintf(cmd.Stdout, "Usage: %s verify [flags]\n\n", filepath.Base(os.Args[0]))
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	} else if fs.NArg() > 0 {
+		fs.Usage()
+		return nil
+	}
+	if _, err := os.Stat(cmd.enginePath); err != nil {
+		return err
+	}
+
+	return cmd.run()
+}
+
+func defaultEnginePath() string {
+	dir, err := fs.InfluxDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "engine")
+}
+
+func (cmd *Command) run() error {
+	start := time.Now()
+	c := storage.NewConfig()
+
+	tsmFiles, err := filepath.Glob(filepath.Join(c.GetEnginePath(cmd.enginePath), "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		return err
+	}
+	segmentFiles, err := seriesSegmentFiles(c.GetSeriesFilePath(cmd.enginePath))
+	if err != nil {
+		return err
+	}
+	sort.Strings(tsmFiles)
+
+	tw := tabwriter.NewWriter(cmd.Stdout, 8, 8, 1, '\t', 0)
+
+	var corrupt int
+	var totalBlocks, brokenBlocks int
+	for _, path := range tsmFiles {
+		blocks, broken, err := verifyTSMFile(path)
+		totalBlocks += blocks
+		brokenBlocks += broken
+		if err == nil && broken > 0 {
+			err = fmt.Errorf("%d/%d blocks have invalid checksums", broken, blocks)
+		}
+		cmd.report(tw, path, err)
+		if err != nil {
+			corrupt++
+		}
+
+		// Walking the tombstones decodes every entry, checking the header and gzip checksums.
+		ts := tsm1.NewTombstoner(path, nil)
+		if !ts.HasTombstones() {
+			continue
+		}
+		err = ts.Walk(func(tsm1.Tombstone) error { return nil })
+		cmd.report(tw, ts.TombstoneFiles()[0].Path, err)
+		if err != nil {
+			corrupt++
+		}
+	}
+
+	for _, path := range segmentFiles {
+		data, err := ioutil.ReadFile(path)
+		if err == nil {
+			err = tsdb.VerifySeriesSegment(data)
+		}
+		cmd.report(tw, path, err)
+		if err != nil {
+			corrupt++
+		}
+	}
+
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(cmd.Stdout, "Verified %d TSM files with %d blocks (%d broken) and %d series segments in %s\n",
+		len(tsmFiles), totalBlocks, brokenBlocks, len(segmentFiles), time.Since(start))
+	if corrupt > 0 {
+		return fmt.Errorf("%d corrupt files", corrupt)
+	}
+	return nil
+}
+
+// report writes the status of the file at path, if it is corrupt or the command is verbose.
+func (cmd *Command) report(w io.Writer, path string, err error) {
+	if err != nil {
+		fmt.Fprintf(w, "%s\tcorrupt\t%v\n", path, err)
+	} else if cmd.verbose {
+		fmt.Fprintf(w, "%s\thealthy\t\n", path)
+	}
+}
+
+// verifyTSMFile returns the number of blocks in the TSM file at path, and the number of those blocks
+// whose checksum does not match their data. An error is returned if the file cannot be read at all.
+func verifyTSMFile(path string) (blocks, broken int, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	r, err := tsm1.NewTSMReader(f)
+	if err != nil {
+		f.Close()
+		return 0, 0, err
+	}
+	defer r.Close()
+
+	iter := r.BlockIterator()
+	for iter.Next() {
+		blocks++
+		_, _, _, _, checksum, buf, err := iter.Read()
+		if err != nil || crc32.ChecksumIEEE(buf) != checksum {
+			broken++
+		}
+	}
+	return blocks, broken, iter.Err()
+}
+
+// seriesSegmentFiles returns the paths of the segment files in each partition of the series file at path.
+func seriesSegmentFiles(path string) ([]string, error) {
+	partitions, err := ioutil.ReadDir(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, p := range partitions {
+		if !p.IsDir() {
+			continue
+		}
+		fis, err := ioutil.ReadDir(filepath.Join(path, p.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, fi := range fis {
+			if tsdb.IsValidSeriesSegmentFilename(fi.Name()) {
+				paths = append(paths, filepath.Join(path, p.Name(), fi.Name()))
+			}
+		}
+	}
+	return paths, nil
+}
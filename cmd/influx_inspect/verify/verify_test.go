@@ -0,0 +1,83 @@
+package verify
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+	"github.com/influxdata/platform/tsdb/tsm1"
+)
+
+func TestCommand_Run(t *testing.T) {
+	dir, err := ioutil.TempDir("", "verify_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	engine := storage.NewEngine(dir, storage.NewConfig())
+	if err := engine.Open(); err != nil {
+		t.Fatal(err)
+	}
+	pts, err := models.ParsePointsString("cpu,host=a value=1 1\ncpu,host=b value=2 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	points, err := tsdb.ExplodePoints(platform.ID(1), platform.ID(2), pts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.WritePoints(points); err != nil {
+		t.Fatal(err)
+	}
+	// Creating a backup writes the cache to a TSM file.
+	backup, err := engine.CreateBackup(nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.RemoveAll(backup)
+	if err := engine.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func() (string, error) {
+		var stdout bytes.Buffer
+		cmd := NewCommand()
+		cmd.Stdout, cmd.Stderr = &stdout, &stdout
+		err := cmd.Run("-engine-path", dir, "-v")
+		return stdout.String(), err
+	}
+
+	if out, err := run(); err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	} else if !strings.Contains(out, "healthy") || strings.Contains(out, "corrupt") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+
+	// Flip a byte in the data of the first block, after the TSM header and the block checksum.
+	paths, err := filepath.Glob(filepath.Join(dir, "data", "*."+tsm1.TSMFileExtension))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no TSM files: %v", err)
+	}
+	data, err := ioutil.ReadFile(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	data[5+4+1] ^= 0xff
+	if err := ioutil.WriteFile(paths[0], data, 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	if out, err := run(); err == nil {
+		t.Fatalf("expected error\n%s", out)
+	} else if !strings.Contains(out, "1/2 blocks have invalid checksums") {
+		t.Fatalf("unexpected output:\n%s", out)
+	}
+}
@@ -16,7 +16,9 @@ import (
 	"github.com/influxdata/flux/execute"
 	"github.com/influxdata/platform"
 	"github.com/influxdata/platform/bolt"
+	"github.com/influxdata/platform/chronograf/oauth2"
 	"github.com/influxdata/platform/chronograf/server"
+	"github.com/influxdata/platform/downsample"
 	protofs "github.com/influxdata/platform/fs"
 	"github.com/influxdata/platform/gather"
 	"github.com/influxdata/platform/http"
@@ -27,6 +29,8 @@ import (
 	"github.com/influxdata/platform/nats"
 	"github.com/influxdata/platform/query"
 	pcontrol "github.com/influxdata/platform/query/control"
+	"github.com/influxdata/platform/rand"
+	"github.com/influxdata/platform/replication"
 	"github.com/influxdata/platform/snowflake"
 	"github.com/influxdata/platform/source"
 	"github.com/influxdata/platform/storage"
@@ -55,16 +59,35 @@ type Launcher struct {
 	logLevel        string
 	httpBindAddress string
 	boltPath        string
+	tokenHashKey    string
 	natsPath        string
 	developerMode   bool
 	enginePath      string
+	coldStoragePath string
 	protosPath      string
 
+	replicationQueuePath string
+
 	secretStore string
 
+	taskLogRetention time.Duration
+
+	taskOrgConcurrency    int
+	taskGlobalConcurrency int
+
+	maxSeriesPerBucket int
+	maxSeriesPerOrg    int
+	storageMode        string
+
+	sso ssoConfig
+
 	boltClient *bolt.Client
 	engine     *storage.Engine
 
+	replicationService *replication.Service
+
+	bucketTransferService *storage.BucketTransferService
+
 	queryController *pcontrol.Controller
 
 	httpPort   int
@@ -72,7 +95,8 @@ type Launcher struct {
 
 	natsServer *nats.Server
 
-	scheduler *taskbackend.TickScheduler
+	scheduler       *taskbackend.TickScheduler
+	taskCoordinator *coordinator.Coordinator
 
 	logger *zap.Logger
 
@@ -110,11 +134,17 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 	m.httpServer.Shutdown(ctx)
 
 	m.logger.Info("Stopping", zap.String("service", "task"))
+	m.taskCoordinator.Stop()
 	m.scheduler.Stop()
 
 	m.logger.Info("Stopping", zap.String("service", "nats"))
 	m.natsServer.Close()
 
+	m.logger.Info("Stopping", zap.String("service", "bucket-transfer"))
+	if err := m.bucketTransferService.Close(); err != nil {
+		m.logger.Info("Failed closing bucket transfer service", zap.Error(err))
+	}
+
 	m.logger.Info("Stopping", zap.String("service", "bolt"))
 	if err := m.boltClient.Close(); err != nil {
 		m.logger.Info("failed closing bolt", zap.Error(err))
@@ -125,6 +155,11 @@ func (m *Launcher) Shutdown(ctx context.Context) {
 		m.logger.Info("Failed closing query service", zap.Error(err))
 	}
 
+	m.logger.Info("Stopping", zap.String("service", "replication"))
+	if err := m.replicationService.Close(); err != nil {
+		m.logger.Info("Failed closing replication service", zap.Error(err))
+	}
+
 	m.logger.Info("Stopping", zap.String("service", "storage-engine"))
 	if err := m.engine.Close(); err != nil {
 		m.logger.Error("failed to close engine", zap.Error(err))
@@ -167,6 +202,12 @@ func (m *Launcher) Run(ctx context.Context, args ...string) error {
 				Default: filepath.Join(dir, "influxd.bolt"),
 				Desc:    "path to boltdb database",
 			},
+			{
+				DestP:   &m.tokenHashKey,
+				Flag:    "token-hash-key-path",
+				Default: "",
+				Desc:    "path to the key the tokens stored in boltdb are hashed with, created if it does not exist; if empty, the key is stored in boltdb, and so in its backups",
+			},
 			{
 				DestP:   &m.developerMode,
 				Flag:    "developer-mode",
@@ -185,12 +226,147 @@ func (m *Launcher) Run(ctx context.Context, args ...string) error {
 				Default: filepath.Join(dir, "engine"),
 				Desc:    "path to persistent engine files",
 			},
+			{
+				DestP: &m.coldStoragePath,
+				Flag:  "cold-storage-path",
+				Desc:  "path to the directory cold TSM files are offloaded to; cold storage is disabled if not set",
+			},
+			{
+				DestP:   &m.replicationQueuePath,
+				Flag:    "replication-queue-path",
+				Default: filepath.Join(dir, "replicationq"),
+				Desc:    "path to the queues of points waiting to be forwarded by replications",
+			},
 			{
 				DestP:   &m.secretStore,
 				Flag:    "secret-store",
 				Default: "bolt",
 				Desc:    "data store for secrets (bolt or vault)",
 			},
+			{
+				DestP:   &m.taskLogRetention,
+				Flag:    "task-log-retention",
+				Default: taskbackend.DefaultRunLogRetention,
+				Desc:    "how long to keep task run records and logs",
+			},
+			{
+				DestP:   &m.taskOrgConcurrency,
+				Flag:    "task-org-concurrency",
+				Default: 0,
+				Desc:    "maximum number of task runs executing at once for a single organization; 0 means no limit",
+			},
+			{
+				DestP:   &m.taskGlobalConcurrency,
+				Flag:    "task-global-concurrency",
+				Default: 0,
+				Desc:    "maximum number of task runs executing at once across all organizations; 0 means no limit",
+			},
+			{
+				DestP:   &m.maxSeriesPerBucket,
+				Flag:    "storage-max-series-per-bucket",
+				Default: 0,
+				Desc:    "maximum number of series in a bucket; writes of new series beyond the limit are rejected; 0 means no limit",
+			},
+			{
+				DestP:   &m.maxSeriesPerOrg,
+				Flag:    "storage-max-series-per-org",
+				Default: 0,
+				Desc:    "maximum number of series across the buckets of an organization; writes of new series beyond the limit are rejected; 0 means no limit",
+			},
+			{
+				DestP:   &m.storageMode,
+				Flag:    "storage-mode",
+				Default: string(storage.ModeReadWrite),
+				Desc:    "mode of the storage engine at startup: read-write, read-only to reject writes and pause compactions and retention, or maintenance to reject queries as well",
+			},
+			{
+				DestP: &m.sso.provider,
+				Flag:  "sso-provider",
+				Desc:  "identity provider users sign in with through OAuth2: github, google, heroku, auth0 or generic; single sign-on is disabled if not set",
+			},
+			{
+				DestP: &m.sso.publicURL,
+				Flag:  "sso-public-url",
+				Desc:  "URL at which users reach the server, to which the identity provider redirects after signing in",
+			},
+			{
+				DestP: &m.sso.clientID,
+				Flag:  "sso-client-id",
+				Desc:  "OAuth2 client ID registered with the identity provider",
+			},
+			{
+				DestP: &m.sso.clientSecret,
+				Flag:  "sso-client-secret",
+				Desc:  "OAuth2 client secret registered with the identity provider",
+			},
+			{
+				DestP: &m.sso.tokenSecret,
+				Flag:  "sso-token-secret",
+				Desc:  "secret signing the state of sign ins, and validating HS256 id_tokens",
+			},
+			{
+				DestP: &m.sso.jwksURL,
+				Flag:  "sso-jwks-url",
+				Desc:  "URL of the JSON web key set validating RS256 id_tokens",
+			},
+			{
+				DestP:   &m.sso.useIDToken,
+				Flag:    "sso-use-id-token",
+				Default: false,
+				Desc:    "read the user and groups from the id_token returned by the identity provider instead of requesting them",
+			},
+			{
+				DestP:   &m.sso.scopes,
+				Flag:    "sso-scopes",
+				Default: []string{"openid", "email"},
+				Desc:    "scopes requested from a generic identity provider",
+			},
+			{
+				DestP: &m.sso.organizations,
+				Flag:  "sso-organizations",
+				Desc:  "GitHub, Heroku or Auth0 organizations users are required to belong to (required with these providers)",
+			},
+			{
+				DestP: &m.sso.domains,
+				Flag:  "sso-domains",
+				Desc:  "email domains users of Google or a generic identity provider are required to belong to (required with these providers)",
+			},
+			{
+				DestP: &m.sso.auth0Domain,
+				Flag:  "sso-auth0-domain",
+				Desc:  "URL of the Auth0 domain",
+			},
+			{
+				DestP: &m.sso.authURL,
+				Flag:  "sso-auth-url",
+				Desc:  "authorization endpoint of a generic identity provider",
+			},
+			{
+				DestP: &m.sso.tokenURL,
+				Flag:  "sso-token-url",
+				Desc:  "token endpoint of a generic identity provider",
+			},
+			{
+				DestP: &m.sso.apiURL,
+				Flag:  "sso-api-url",
+				Desc:  "userinfo endpoint of a generic identity provider",
+			},
+			{
+				DestP:   &m.sso.apiKey,
+				Flag:    "sso-api-key",
+				Default: "email",
+				Desc:    "claim of a generic identity provider naming the user",
+			},
+			{
+				DestP: &m.sso.groupsKey,
+				Flag:  "sso-groups-key",
+				Desc:  "claim of a generic identity provider listing the groups of the user; the email domain of the user is its group if not set",
+			},
+			{
+				DestP: &m.sso.groupMappings,
+				Flag:  "sso-group-mapping",
+				Desc:  "grants the users of a group of the identity provider the membership of an organization, as group=orgID or group=orgID:owner; memberships are updated at every sign in",
+			},
 			{
 				DestP:   &m.protosPath,
 				Flag:    "protos-path",
@@ -238,6 +414,15 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 	m.boltClient.Path = m.boltPath
 	m.boltClient.WithLogger(m.logger.With(zap.String("service", "bolt")))
 
+	if m.tokenHashKey != "" {
+		key, err := loadTokenHashKey(m.tokenHashKey)
+		if err != nil {
+			m.logger.Error("failed loading token hash key", zap.Error(err))
+			return err
+		}
+		m.boltClient.TokenHasher = rand.NewTokenHasher(key)
+	}
+
 	if err := m.boltClient.Open(ctx); err != nil {
 		m.logger.Error("failed opening bolt", zap.Error(err))
 		return err
@@ -264,6 +449,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		labelSvc         platform.LabelService                    = m.boltClient
 		secretSvc        platform.SecretService                   = m.boltClient
 		lookupSvc        platform.LookupService                   = m.boltClient
+		roleSvc          platform.RoleService                     = m.boltClient
 	)
 
 	switch m.secretStore {
@@ -298,9 +484,32 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 	var pointsWriter storage.PointsWriter
 	{
-		m.engine = storage.NewEngine(m.enginePath, storage.NewConfig(), storage.WithRetentionEnforcer(bucketSvc))
+		taskLogsBucketID, err := platform.InternalBucketID(platform.BucketTypeLogs)
+		if err != nil {
+			m.logger.Error("failed to determine task logs bucket", zap.Error(err))
+			return err
+		}
+		config := storage.NewConfig()
+		config.MaxSeriesPerBucket = m.maxSeriesPerBucket
+		config.MaxSeriesPerOrg = m.maxSeriesPerOrg
+		config.ColdStoragePath = m.coldStoragePath
+		m.engine = storage.NewEngine(m.enginePath, config,
+			storage.WithSystemBucketRetention(*taskLogsBucketID, m.taskLogRetention),
+			storage.WithBucketSchemas(bucketSvc),
+			storage.WithRetentionEnforcer(bucketSvc),
+		)
 		m.engine.WithLogger(m.logger)
 
+		mode, err := storage.ParseMode(m.storageMode)
+		if err != nil {
+			m.logger.Error("invalid storage mode", zap.Error(err))
+			return err
+		}
+		if err := m.engine.SetMode(mode); err != nil {
+			m.logger.Error("failed to set storage mode", zap.Error(err))
+			return err
+		}
+
 		if err := m.engine.Open(); err != nil {
 			m.logger.Error("failed to open engine", zap.Error(err))
 			return err
@@ -308,7 +517,16 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		// The Engine's metrics must be registered after it opens.
 		reg.MustRegister(m.engine.PrometheusCollectors()...)
 
-		pointsWriter = m.engine
+		// Points written to buckets with replications are queued to be forwarded once written to the engine.
+		m.replicationService = replication.NewService(m.replicationQueuePath, m.engine, m.boltClient, secretSvc)
+		m.replicationService.Logger = m.logger.With(zap.String("service", "replication"))
+		if err := m.replicationService.Open(ctx); err != nil {
+			m.logger.Error("failed to open replication service", zap.Error(err))
+			return err
+		}
+		reg.MustRegister(m.replicationService.PrometheusCollectors()...)
+
+		pointsWriter = m.replicationService
 
 		const (
 			concurrencyQuota = 10
@@ -335,6 +553,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 	var storageQueryService query.ProxyQueryService = readservice.NewProxyQueryService(m.queryController)
 	var taskSvc platform.TaskService
+	var tieredBucketSvc platform.BucketService
 	{
 		boltStore, err := taskbolt.New(m.boltClient.DB(), "tasks")
 		if err != nil {
@@ -345,16 +564,34 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		executor := taskexecutor.NewAsyncQueryServiceExecutor(m.logger.With(zap.String("service", "task-executor")), m.queryController, boltStore)
 
 		lw := taskbackend.NewPointLogWriter(pointsWriter)
-		m.scheduler = taskbackend.NewScheduler(boltStore, executor, lw, time.Now().UTC().Unix(), taskbackend.WithTicker(ctx, 100*time.Millisecond), taskbackend.WithLogger(m.logger))
+		m.scheduler = taskbackend.NewScheduler(boltStore, executor, lw, time.Now().UTC().Unix(), taskbackend.WithTicker(ctx, 100*time.Millisecond), taskbackend.WithLogger(m.logger),
+			taskbackend.WithConcurrencyLimits(m.taskOrgConcurrency, m.taskGlobalConcurrency))
 		m.scheduler.Start(ctx)
 		reg.MustRegister(m.scheduler.PrometheusCollectors()...)
 
 		queryService := query.QueryServiceBridge{AsyncQueryService: m.queryController}
-		lr := taskbackend.NewQueryLogReader(queryService)
-		taskSvc = task.PlatformAdapter(coordinator.New(m.logger.With(zap.String("service", "task-coordinator")), m.scheduler, boltStore), lr, m.scheduler)
+		lr := taskbackend.NewQueryLogReader(queryService, taskbackend.WithLookback(m.taskLogRetention))
+		// Tasks are not shared with other nodes, as both the tasks and their leases would have to be
+		// kept in a store shared by all nodes, rather than in the local bolt file.
+		m.taskCoordinator = coordinator.New(m.logger.With(zap.String("service", "task-coordinator")), m.scheduler, boltStore)
+		taskSvc = task.PlatformAdapter(m.taskCoordinator, lr, m.scheduler)
+		// The tasks of downsample tiers are created with the unvalidated task service,
+		// as the change to their bucket has already been authorized.
+		tieredBucketSvc = downsample.NewBucketService(storage.NewBucketService(bucketSvc, m.engine), taskSvc)
 		taskSvc = task.NewValidator(taskSvc, bucketSvc)
 	}
 
+	// Clones are created with the same bucket service as other buckets, and transfers are
+	// saved in the bolt database so that they are resumed on restart.
+	transferStore := bolt.NewKVStore(m.boltPath)
+	transferStore.WithDB(m.boltClient.DB())
+	m.bucketTransferService = storage.NewBucketTransferService(m.engine, tieredBucketSvc, snowflake.NewIDGenerator(), transferStore)
+	m.bucketTransferService.Logger = m.logger.With(zap.String("service", "bucket-transfer"))
+	if err := m.bucketTransferService.Open(ctx); err != nil {
+		m.logger.Error("failed to open bucket transfer service", zap.Error(err))
+		return err
+	}
+
 	// NATS streaming server
 	m.natsServer = nats.NewServer(nats.Config{FilestoreDir: m.natsPath})
 	if err := m.natsServer.Open(); err != nil {
@@ -395,16 +632,49 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		Addr: m.httpBindAddress,
 	}
 
+	var ssoProviders []*oauth2.AuthMux
+	if m.sso.enabled() {
+		ssoLogger := m.logger.With(zap.String("service", "sso"))
+		provider, err := m.sso.newProvider(http.NewOAuth2Logger(ssoLogger))
+		if err != nil {
+			ssoLogger.Error("failed to configure identity provider", zap.Error(err))
+			return err
+		}
+		mappings, err := m.sso.newGroupMappings()
+		if err != nil {
+			ssoLogger.Error("failed to configure group mappings", zap.Error(err))
+			return err
+		}
+		auth := &http.SSOAuthenticator{
+			Logger:                     ssoLogger,
+			UserService:                userSvc,
+			UserResourceMappingService: userResourceSvc,
+			SessionService:             sessionSvc,
+			SSOIdentityService:         m.boltClient,
+			GroupMappings:              mappings,
+		}
+		tokens := oauth2.NewJWT(m.sso.tokenSecret, m.sso.jwksURL)
+		ssoProviders = append(ssoProviders, http.NewSSOAuthMux(provider, auth, tokens, m.sso.useIDToken, ssoLogger))
+	}
+
 	handlerConfig := &http.APIBackend{
 		DeveloperMode:        m.developerMode,
 		Logger:               m.logger,
 		NewBucketService:     source.NewBucketService,
 		NewQueryService:      source.NewQueryService,
 		PointsWriter:         pointsWriter,
+		PredicateDeleter:     m.engine,
+		EngineMode:           m.engine,
+		UsageService:         m.engine,
+		MetadataBackuper:     m.boltClient,
+		EngineBackuper:       m.engine,
 		AuthorizationService: authSvc,
-		// Wrap the BucketService in a storage backed one that will ensure deleted buckets are removed from the storage engine.
-		BucketService:                   storage.NewBucketService(bucketSvc, m.engine),
+		// The bolt client records the last use of the authorizations of tokens.
+		AuthorizationUsageService: m.boltClient,
+		// The BucketService removes deleted buckets from the storage engine and manages the downsample tiers of buckets.
+		BucketService:                   tieredBucketSvc,
 		SessionService:                  sessionSvc,
+		SSOProviders:                    ssoProviders,
 		UserService:                     userSvc,
 		OrganizationService:             orgSvc,
 		UserResourceMappingService:      userResourceSvc,
@@ -412,6 +682,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		DashboardService:                dashboardSvc,
 		DashboardOperationLogService:    dashboardLogSvc,
 		BucketOperationLogService:       bucketLogSvc,
+		BucketTransferService:           m.bucketTransferService,
 		UserOperationLogService:         userLogSvc,
 		OrganizationOperationLogService: orgLogSvc,
 		SourceService:                   sourceSvc,
@@ -424,6 +695,8 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 		ScraperTargetStoreService:       scraperTargetSvc,
 		ChronografService:               chronografSvc,
 		SecretService:                   secretSvc,
+		ReplicationService:              m.replicationService,
+		RoleService:                     roleSvc,
 		LookupService:                   lookupSvc,
 		ProtoService:                    protoSvc,
 	}
@@ -435,6 +708,7 @@ func (m *Launcher) run(ctx context.Context) (err error) {
 
 	h := http.NewHandlerFromRegistry("platform", reg)
 	h.Handler = platformHandler
+	h.ReadyHandler = http.NewReadyHandler(m.engine)
 	h.Logger = httpLogger
 	h.Tracer = opentracing.GlobalTracer()
 
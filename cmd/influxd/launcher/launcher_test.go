@@ -2,15 +2,18 @@ package launcher_test
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
 	nethttp "net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/influxdata/platform/cmd/influxd/launcher"
 
@@ -156,6 +159,87 @@ func TestLauncher_BucketDelete(t *testing.T) {
 	}
 }
 
+func TestLauncher_Replication(t *testing.T) {
+	l := RunLauncherOrFail(t, ctx)
+	l.SetupOrFail(t)
+	defer l.ShutdownOrFail(t, ctx)
+
+	// The remote influxd records the line protocol written to it.
+	var remoteOrgID, remoteBucketID platform.ID = 100, 101
+	writes := make(chan string, 10)
+	remote := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.URL.Path != "/api/v2/write" || r.Header.Get("Authorization") != "Token remote-token" ||
+			r.URL.Query().Get("org") != remoteOrgID.String() || r.URL.Query().Get("bucket") != remoteBucketID.String() {
+			w.WriteHeader(nethttp.StatusBadRequest)
+			return
+		}
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(nethttp.StatusBadRequest)
+			return
+		}
+		body, _ := ioutil.ReadAll(zr)
+		writes <- string(body)
+		w.WriteHeader(nethttp.StatusNoContent)
+	}))
+	defer remote.Close()
+
+	// Store the token of the remote in a secret of the organization and replicate the bucket.
+	resp, err := nethttp.DefaultClient.Do(l.MustNewHTTPRequest("PATCH", fmt.Sprintf("/api/v2/orgs/%s/secrets", l.Org.ID), `{"remote":"remote-token"}`))
+	if err != nil {
+		t.Fatal(err)
+	} else if err := resp.Body.Close(); err != nil {
+		t.Fatal(err)
+	} else if resp.StatusCode != nethttp.StatusNoContent {
+		t.Fatalf("unexpected status code patching secrets: %d", resp.StatusCode)
+	}
+
+	rs := &http.ReplicationService{Addr: l.URL(), Token: l.Auth.Token}
+	r := &platform.Replication{
+		OrganizationID:       l.Org.ID,
+		Name:                 "remote",
+		LocalBucketID:        l.Bucket.ID,
+		RemoteURL:            remote.URL,
+		RemoteOrganizationID: remoteOrgID,
+		RemoteBucketID:       remoteBucketID,
+		TokenSecretKey:       "remote",
+	}
+	if err := rs.CreateReplication(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if found, n, err := rs.FindReplications(ctx, platform.ReplicationFilter{LocalBucketID: &l.Bucket.ID}); err != nil {
+		t.Fatal(err)
+	} else if n != 1 || found[0].ID != r.ID {
+		t.Fatalf("unexpected replications %+v", found)
+	}
+
+	// Points written locally are forwarded to the remote.
+	resp, err = nethttp.DefaultClient.Do(l.MustNewHTTPRequest("POST", fmt.Sprintf("/api/v2/write?org=%s&bucket=%s", l.Org.ID, l.Bucket.ID), `m,k=v f=100i 946684800000000000`))
+	if err != nil {
+		t.Fatal(err)
+	} else if err := resp.Body.Close(); err != nil {
+		t.Fatal(err)
+	} else if resp.StatusCode != nethttp.StatusNoContent {
+		t.Fatalf("unexpected status code writing: %d", resp.StatusCode)
+	}
+
+	select {
+	case body := <-writes:
+		if exp := "m,k=v f=100i 946684800000000000\n"; body != exp {
+			t.Fatalf("unexpected points forwarded: got %q, exp %q", body, exp)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for points to be forwarded")
+	}
+
+	if err := rs.DeleteReplication(ctx, r.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rs.FindReplicationByID(ctx, r.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected deleted replication not to be found, got %v", err)
+	}
+}
+
 // Launcher is a test wrapper for launcher.Launcher.
 type Launcher struct {
 	*launcher.Launcher
@@ -210,6 +294,7 @@ func (l *Launcher) Run(ctx context.Context, args ...string) error {
 	args = append(args, "--protos-path", filepath.Join(l.Path, "protos"))
 	args = append(args, "--engine-path", filepath.Join(l.Path, "engine"))
 	args = append(args, "--nats-path", filepath.Join(l.Path, "nats"))
+	args = append(args, "--replication-queue-path", filepath.Join(l.Path, "replicationq"))
 	args = append(args, "--http-bind-address", "127.0.0.1:0")
 	args = append(args, "--log-level", "debug")
 	return l.Launcher.Run(ctx, args...)
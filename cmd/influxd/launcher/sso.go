@@ -0,0 +1,125 @@
+package launcher
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/influxdata/platform/chronograf"
+	"github.com/influxdata/platform/chronograf/oauth2"
+	"github.com/influxdata/platform/http"
+)
+
+// ssoConfig is the configuration of single sign-on with an OAuth2 identity provider.
+type ssoConfig struct {
+	provider      string
+	publicURL     string
+	clientID      string
+	clientSecret  string
+	tokenSecret   string
+	jwksURL       string
+	useIDToken    bool
+	scopes        []string
+	organizations []string
+	domains       []string
+	auth0Domain   string
+	authURL       string
+	tokenURL      string
+	apiURL        string
+	apiKey        string
+	groupsKey     string
+	groupMappings []string
+}
+
+// enabled returns true if users can sign in with an identity provider.
+func (c *ssoConfig) enabled() bool {
+	return c.provider != ""
+}
+
+// newProvider returns the identity provider of the configuration.
+func (c *ssoConfig) newProvider(logger chronograf.Logger) (oauth2.Provider, error) {
+	if c.clientID == "" || c.clientSecret == "" || c.tokenSecret == "" {
+		return nil, fmt.Errorf("sso-client-id, sso-client-secret and sso-token-secret are required to sign in with %s", c.provider)
+	}
+
+	// Users are provisioned on first sign in, so every account of the provider could sign in
+	// without a restriction to organizations or domains.
+	switch c.provider {
+	case "github", "heroku", "auth0":
+		if len(c.organizations) == 0 {
+			return nil, fmt.Errorf("sso-organizations is required to restrict the users who can sign in with %s", c.provider)
+		}
+	case "google", "generic":
+		if len(c.domains) == 0 {
+			return nil, fmt.Errorf("sso-domains is required to restrict the users who can sign in with %s", c.provider)
+		}
+	}
+
+	redirectURL := strings.TrimSuffix(c.publicURL, "/") + http.SSOCallbackPath(c.provider)
+	switch c.provider {
+	case "github":
+		return &oauth2.Github{
+			ClientID:     c.clientID,
+			ClientSecret: c.clientSecret,
+			Orgs:         c.organizations,
+			Logger:       logger,
+		}, nil
+	case "google":
+		if c.publicURL == "" {
+			return nil, fmt.Errorf("sso-public-url is required to sign in with google")
+		}
+		return &oauth2.Google{
+			ClientID:     c.clientID,
+			ClientSecret: c.clientSecret,
+			Domains:      c.domains,
+			RedirectURL:  redirectURL,
+			Logger:       logger,
+		}, nil
+	case "heroku":
+		return &oauth2.Heroku{
+			ClientID:      c.clientID,
+			ClientSecret:  c.clientSecret,
+			Organizations: c.organizations,
+			Logger:        logger,
+		}, nil
+	case "auth0":
+		if c.publicURL == "" || c.auth0Domain == "" {
+			return nil, fmt.Errorf("sso-public-url and sso-auth0-domain are required to sign in with auth0")
+		}
+		p, err := oauth2.NewAuth0(c.auth0Domain, c.clientID, c.clientSecret, redirectURL, c.organizations, logger)
+		if err != nil {
+			return nil, err
+		}
+		return &p, nil
+	case "generic":
+		if c.publicURL == "" || c.authURL == "" || c.tokenURL == "" {
+			return nil, fmt.Errorf("sso-public-url, sso-auth-url and sso-token-url are required to sign in with a generic provider")
+		}
+		return &oauth2.Generic{
+			ClientID:       c.clientID,
+			ClientSecret:   c.clientSecret,
+			RequiredScopes: c.scopes,
+			Domains:        c.domains,
+			RedirectURL:    redirectURL,
+			AuthURL:        c.authURL,
+			TokenURL:       c.tokenURL,
+			APIURL:         c.apiURL,
+			APIKey:         c.apiKey,
+			GroupsKey:      c.groupsKey,
+			Logger:         logger,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown sso provider %q: must be one of github, google, heroku, auth0 or generic", c.provider)
+}
+
+// newGroupMappings returns the group mappings of the configuration.
+func (c *ssoConfig) newGroupMappings() ([]http.SSOGroupMapping, error) {
+	ms := make([]http.SSOGroupMapping, 0, len(c.groupMappings))
+	for _, s := range c.groupMappings {
+		m, err := http.ParseSSOGroupMapping(s)
+		if err != nil {
+			return nil, err
+		}
+		ms = append(ms, m)
+	}
+	return ms, nil
+}
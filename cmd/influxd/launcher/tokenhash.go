@@ -0,0 +1,48 @@
+package launcher
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/influxdata/platform/rand"
+)
+
+// loadTokenHashKey returns the hex encoded key in the file at path, which is created with a
+// new key if it does not exist.
+func loadTokenHashKey(path string) ([]byte, error) {
+	b, err := ioutil.ReadFile(path)
+	if err == nil {
+		key, err := hex.DecodeString(strings.TrimSpace(string(b)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid token hash key in %s: %v", path, err)
+		}
+		if len(key) == 0 {
+			return nil, fmt.Errorf("empty token hash key in %s", path)
+		}
+		return key, nil
+	}
+	if !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	key, err := rand.NewTokenHashKey()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.WriteString(hex.EncodeToString(key) + "\n"); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return key, f.Close()
+}
@@ -0,0 +1,414 @@
+// Package downsample manages the target buckets and rollup tasks of the downsample tiers of buckets.
+package downsample
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/influxdata/platform"
+	platcontext "github.com/influxdata/platform/context"
+)
+
+// BucketService wraps an existing platform.BucketService implementation.
+//
+// BucketService creates a target bucket and a rollup task for each downsample tier of
+// a bucket, keeps them in step with the tiers as the bucket is updated, and removes
+// them when the tier or the bucket is deleted. Tasks are owned by the user in the context.
+type BucketService struct {
+	platform.BucketService
+	tasks platform.TaskService
+}
+
+// NewBucketService returns a new BucketService managing the tiers of the buckets of s with ts.
+func NewBucketService(s platform.BucketService, ts platform.TaskService) *BucketService {
+	return &BucketService{
+		BucketService: s,
+		tasks:         ts,
+	}
+}
+
+// CreateBucket creates a new bucket and sets b.ID with the new identifier,
+// along with the target bucket and task of each of its downsample tiers.
+func (s *BucketService) CreateBucket(ctx context.Context, b *platform.Bucket) error {
+	if err := platform.ValidateDownsampleTiers(b.DownsampleTiers); err != nil {
+		return err
+	}
+	if err := validateFields(b, b.DownsampleTiers); err != nil {
+		return err
+	}
+
+	tiers := b.DownsampleTiers
+	b.DownsampleTiers = nil
+	if err := s.BucketService.CreateBucket(ctx, b); err != nil {
+		return err
+	}
+	if len(tiers) == 0 {
+		return nil
+	}
+
+	managed, err := s.reconcile(ctx, b, nil, tiers)
+	if err != nil {
+		s.BucketService.DeleteBucket(ctx, b.ID)
+		return err
+	}
+	upd, err := s.BucketService.UpdateBucket(ctx, b.ID, platform.BucketUpdate{DownsampleTiers: managed})
+	if err != nil {
+		return err
+	}
+	*b = *upd
+	return nil
+}
+
+// UpdateBucket updates a single bucket with changeset.
+// Tiers are matched to the bucket's current tiers by window: matching tiers keep their
+// target bucket and task, new tiers are created, and tiers no longer present are deleted.
+// The tasks of the tiers are also updated when the schema of the bucket changes.
+func (s *BucketService) UpdateBucket(ctx context.Context, id platform.ID, upd platform.BucketUpdate) (*platform.Bucket, error) {
+	if upd.DownsampleTiers == nil && upd.SchemaType == nil && upd.MeasurementSchemas == nil {
+		return s.BucketService.UpdateBucket(ctx, id, upd)
+	}
+
+	b, err := s.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if upd.DownsampleTiers == nil {
+		if len(b.DownsampleTiers) == 0 {
+			return s.BucketService.UpdateBucket(ctx, id, upd)
+		}
+		upd.DownsampleTiers = b.DownsampleTiers
+	}
+	if err := platform.ValidateDownsampleTiers(upd.DownsampleTiers); err != nil {
+		return nil, err
+	}
+
+	src := *b
+	if upd.SchemaType != nil {
+		src.SchemaType = *upd.SchemaType
+	}
+	if upd.MeasurementSchemas != nil {
+		src.MeasurementSchemas = upd.MeasurementSchemas
+	}
+	if err := validateFields(&src, upd.DownsampleTiers); err != nil {
+		return nil, err
+	}
+	tiers, err := s.reconcile(ctx, &src, b, upd.DownsampleTiers)
+	if err != nil {
+		return nil, err
+	}
+	upd.DownsampleTiers = tiers
+	return s.BucketService.UpdateBucket(ctx, id, upd)
+}
+
+// DeleteBucket removes a bucket by ID, and the target buckets and tasks of its tiers.
+func (s *BucketService) DeleteBucket(ctx context.Context, id platform.ID) error {
+	b, err := s.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	for _, t := range b.DownsampleTiers {
+		if err := s.deleteTier(ctx, t); err != nil {
+			return err
+		}
+	}
+	return s.BucketService.DeleteBucket(ctx, id)
+}
+
+// reconcile brings the target buckets and tasks of the tiers of orig, the bucket b before it is updated
+// or nil if it is being created, in line with the tiers upd, and returns upd with the IDs of the target
+// bucket and task of each tier set.
+func (s *BucketService) reconcile(ctx context.Context, b, orig *platform.Bucket, upd []platform.DownsampleTier) ([]platform.DownsampleTier, error) {
+	var old []platform.DownsampleTier
+	if orig != nil {
+		old = orig.DownsampleTiers
+	}
+	existing := make(map[time.Duration]int, len(old))
+	for i, t := range old {
+		existing[t.Every] = i
+	}
+
+	tiers := make([]platform.DownsampleTier, 0, len(upd))
+	var created []platform.DownsampleTier
+	fail := func(err error) ([]platform.DownsampleTier, error) {
+		for _, t := range created {
+			s.deleteTier(ctx, t)
+		}
+		return nil, err
+	}
+
+	for _, t := range upd {
+		oi, ok := existing[t.Every]
+		if !ok {
+			t, err := s.createTier(ctx, b, append(tiers, t))
+			if err != nil {
+				return fail(err)
+			}
+			created = append(created, t)
+			tiers = append(tiers, t)
+			continue
+		}
+		delete(existing, t.Every)
+
+		o := old[oi]
+		t.BucketID, t.TaskID = o.BucketID, o.TaskID
+		if t.RetentionPeriod != o.RetentionPeriod {
+			if _, err := s.BucketService.UpdateBucket(ctx, t.BucketID, platform.BucketUpdate{RetentionPeriod: &t.RetentionPeriod}); err != nil {
+				return fail(err)
+			}
+		}
+		tiers = append(tiers, t)
+
+		// The script also depends on the previous tier and the schema of b, so it is compared as a whole.
+		script, err := Script(b, tiers, len(tiers)-1)
+		if err != nil {
+			return fail(err)
+		}
+		if prev, err := Script(orig, old, oi); err != nil || script != prev {
+			if _, err := s.tasks.UpdateTask(ctx, t.TaskID, platform.TaskUpdate{Flux: &script}); err != nil {
+				return fail(err)
+			}
+		}
+	}
+
+	for _, i := range existing {
+		if err := s.deleteTier(ctx, old[i]); err != nil {
+			return nil, err
+		}
+	}
+	return tiers, nil
+}
+
+// createTier creates the target bucket and task of the last of the tiers of b, and returns it with their IDs set.
+// The previous tiers must already have their target buckets.
+func (s *BucketService) createTier(ctx context.Context, b *platform.Bucket, tiers []platform.DownsampleTier) (platform.DownsampleTier, error) {
+	i := len(tiers) - 1
+	t := tiers[i]
+	auth, err := platcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return t, err
+	}
+
+	target := &platform.Bucket{
+		OrganizationID:  b.OrganizationID,
+		Name:            TierBucketName(b.Name, t.Every),
+		RetentionPeriod: t.RetentionPeriod,
+	}
+	if err := s.BucketService.CreateBucket(ctx, target); err != nil {
+		return t, err
+	}
+	t.BucketID = target.ID
+	tiers[i] = t
+
+	script, err := Script(b, tiers, i)
+	if err != nil {
+		s.BucketService.DeleteBucket(ctx, target.ID)
+		return t, err
+	}
+	task := &platform.Task{
+		Organization: b.OrganizationID,
+		Owner:        platform.User{ID: auth.GetUserID()},
+		Flux:         script,
+	}
+	if err := s.tasks.CreateTask(ctx, task); err != nil {
+		s.BucketService.DeleteBucket(ctx, target.ID)
+		return t, err
+	}
+	t.TaskID = task.ID
+	return t, nil
+}
+
+// deleteTier deletes the task and target bucket of t. Either may already have been deleted.
+func (s *BucketService) deleteTier(ctx context.Context, t platform.DownsampleTier) error {
+	if t.TaskID.Valid() {
+		if err := s.tasks.DeleteTask(ctx, t.TaskID); err != nil && platform.ErrorCode(err) != platform.ENotFound {
+			return err
+		}
+	}
+	if t.BucketID.Valid() {
+		if err := s.BucketService.DeleteBucket(ctx, t.BucketID); err != nil && platform.ErrorCode(err) != platform.ENotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+// TierBucketName returns the name of the target bucket of the tier of the bucket name with window every.
+func TierBucketName(name string, every time.Duration) string {
+	return name + "_" + formatDuration(every)
+}
+
+// selectors are the aggregates that select a point of each window, keeping its time.
+var selectors = map[string]bool{
+	platform.DownsampleMin:   true,
+	platform.DownsampleMax:   true,
+	platform.DownsampleFirst: true,
+	platform.DownsampleLast:  true,
+}
+
+// numeric are the aggregates that only apply to numeric fields.
+var numeric = map[string]bool{
+	platform.DownsampleMean:   true,
+	platform.DownsampleMedian: true,
+	platform.DownsampleSum:    true,
+}
+
+// RollupDelay is the time allowed for the rollup of a tier to be written before the next tier reads it.
+const RollupDelay = time.Minute
+
+// Script returns the Flux script of the task of the tier i of the tiers of the bucket src.
+//
+// Every run aggregates the window ending at the scheduled time of the run, writing each aggregate with
+// an "aggregate" tag to the tier's bucket. Aggregates other than selectors are timestamped with the end
+// of their window. Aggregates kept by the previous tier are rolled up from its bucket: counts are summed,
+// and the other aggregates are applied again, so that means and medians of a tier are approximations.
+// The other aggregates are computed from the source bucket, with the numeric aggregates limited to the
+// numeric fields of the tier. Runs are delayed by the offset of the tier, and by the offset of the
+// previous tier and RollupDelay when reading from it.
+func Script(src *platform.Bucket, tiers []platform.DownsampleTier, i int) (string, error) {
+	t := tiers[i]
+	every := formatDuration(t.Every)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "option task = {name: %q, every: %s", "downsample "+TierBucketName(src.Name, t.Every), every)
+	if offset := taskOffset(tiers, i); offset > 0 {
+		fmt.Fprintf(&sb, ", offset: %s", formatDuration(offset))
+	}
+	sb.WriteString("}\n")
+
+	for _, agg := range t.Aggregates {
+		fn := agg
+		if rollup, ok := previousAggregate(tiers, i, agg); ok {
+			prev := tiers[i-1]
+			fn = rollup
+			fmt.Fprintf(&sb, "\nfrom(bucketID: %q)\n", prev.BucketID.String())
+			if selectors[agg] {
+				fmt.Fprintf(&sb, "\t|> range(start: -%s)\n", every)
+				fmt.Fprintf(&sb, "\t|> filter(fn: (r) => r.aggregate == %q)\n", agg)
+			} else {
+				// The aggregates of the previous tier are timestamped with the end of their window,
+				// so they are read up to the end of the window and shifted back to its start.
+				fmt.Fprintf(&sb, "\t|> range(start: -%s, stop: %s)\n", formatDuration(t.Every-prev.Every), formatDuration(prev.Every))
+				fmt.Fprintf(&sb, "\t|> filter(fn: (r) => r.aggregate == %q)\n", agg)
+				fmt.Fprintf(&sb, "\t|> shift(shift: -%s)\n", formatDuration(prev.Every))
+			}
+		} else {
+			fmt.Fprintf(&sb, "\nfrom(bucketID: %q)\n", src.ID.String())
+			fmt.Fprintf(&sb, "\t|> range(start: -%s)\n", every)
+			if numeric[agg] {
+				pred, err := numericPredicate(src, t)
+				if err != nil {
+					return "", err
+				}
+				fmt.Fprintf(&sb, "\t|> filter(fn: (r) => %s)\n", pred)
+			}
+		}
+
+		fmt.Fprintf(&sb, "\t|> window(every: %s)\n\t|> %s()\n", every, fn)
+		if !selectors[agg] {
+			sb.WriteString("\t|> duplicate(column: \"_stop\", as: \"_time\")\n")
+		}
+		sb.WriteString("\t|> window(every: inf)\n")
+		fmt.Fprintf(&sb, "\t|> set(key: \"aggregate\", value: %q)\n", agg)
+		fmt.Fprintf(&sb, "\t|> to(bucketID: %q, orgID: %q)\n", t.BucketID.String(), src.OrganizationID.String())
+		fmt.Fprintf(&sb, "\t|> yield(name: %q)\n", agg)
+	}
+	return sb.String(), nil
+}
+
+// previousAggregate returns the aggregate rolling up the aggregate agg of the tier before the tier i,
+// and whether that tier keeps agg.
+func previousAggregate(tiers []platform.DownsampleTier, i int, agg string) (string, bool) {
+	if i == 0 {
+		return "", false
+	}
+	for _, a := range tiers[i-1].Aggregates {
+		if a != agg {
+			continue
+		}
+		if agg == platform.DownsampleCount {
+			return platform.DownsampleSum, true
+		}
+		return agg, true
+	}
+	return "", false
+}
+
+// taskOffset returns the offset of the task of the tier i, which also waits for the task
+// of the previous tier if any of its aggregates are rolled up from that tier.
+func taskOffset(tiers []platform.DownsampleTier, i int) time.Duration {
+	t := tiers[i]
+	for _, agg := range t.Aggregates {
+		if _, ok := previousAggregate(tiers, i, agg); ok {
+			return t.Offset + taskOffset(tiers, i-1) + RollupDelay
+		}
+	}
+	return t.Offset
+}
+
+// numericPredicate returns the body of a Flux filter function matching the numeric fields of the tier t
+// of b: the fields of t, or else the numeric fields of the explicit schema of b.
+func numericPredicate(b *platform.Bucket, t platform.DownsampleTier) (string, error) {
+	var preds []string
+	if len(t.Fields) > 0 {
+		for _, f := range t.Fields {
+			preds = append(preds, fmt.Sprintf("r._field == %q", f))
+		}
+	} else if b.SchemaType == platform.SchemaTypeExplicit {
+		for _, m := range b.MeasurementSchemas {
+			var fields []string
+			for _, f := range m.Fields {
+				switch f.Type {
+				case platform.SchemaFieldTypeFloat, platform.SchemaFieldTypeInteger, platform.SchemaFieldTypeUnsigned:
+					fields = append(fields, fmt.Sprintf("r._field == %q", f.Name))
+				}
+			}
+			if len(fields) > 0 {
+				preds = append(preds, fmt.Sprintf("(r._measurement == %q and (%s))", m.Name, strings.Join(fields, " or ")))
+			}
+		}
+	}
+	if len(preds) == 0 {
+		return "", &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  fmt.Sprintf("downsample tier %s of bucket %s requires numeric fields for its mean, median, and sum aggregates", t.Every, b.Name),
+		}
+	}
+	return strings.Join(preds, " or "), nil
+}
+
+// validateFields returns an error if a tier of b computes a numeric aggregate from the source bucket
+// without any numeric fields to compute it from.
+func validateFields(b *platform.Bucket, tiers []platform.DownsampleTier) error {
+	for i, t := range tiers {
+		for _, agg := range t.Aggregates {
+			if _, ok := previousAggregate(tiers, i, agg); ok || !numeric[agg] {
+				continue
+			}
+			if _, err := numericPredicate(b, t); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// formatDuration formats d in the largest of days, hours, minutes, or seconds that divides it,
+// which is valid both as a Flux duration literal and in a bucket name.
+func formatDuration(d time.Duration) string {
+	for _, u := range []struct {
+		d    time.Duration
+		unit string
+	}{
+		{24 * time.Hour, "d"},
+		{time.Hour, "h"},
+		{time.Minute, "m"},
+	} {
+		if d%u.d == 0 {
+			return fmt.Sprintf("%d%s", d/u.d, u.unit)
+		}
+	}
+	return fmt.Sprintf("%ds", d/time.Second)
+}
@@ -0,0 +1,199 @@
+package downsample_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/downsample"
+	"github.com/influxdata/platform/inmem"
+	"github.com/influxdata/platform/mock"
+	_ "github.com/influxdata/platform/query/builtin"
+	"github.com/influxdata/platform/task/options"
+)
+
+// taskService is a TaskService keeping the tasks created with it in memory.
+func taskService(tasks map[platform.ID]*platform.Task) *mock.TaskService {
+	var next platform.ID = 100
+	return &mock.TaskService{
+		CreateTaskFn: func(_ context.Context, t *platform.Task) error {
+			next++
+			t.ID = next
+			tasks[t.ID] = t
+			return nil
+		},
+		UpdateTaskFn: func(_ context.Context, id platform.ID, upd platform.TaskUpdate) (*platform.Task, error) {
+			tasks[id].Flux = *upd.Flux
+			return tasks[id], nil
+		},
+		DeleteTaskFn: func(_ context.Context, id platform.ID) error {
+			delete(tasks, id)
+			return nil
+		},
+	}
+}
+
+func TestBucketService(t *testing.T) {
+	ctx := pcontext.SetAuthorizer(context.Background(), &platform.Authorization{UserID: 7})
+
+	buckets := inmem.NewService()
+	org := &platform.Organization{Name: "org"}
+	if err := buckets.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	tasks := make(map[platform.ID]*platform.Task)
+	s := downsample.NewBucketService(buckets, taskService(tasks))
+
+	b := &platform.Bucket{
+		OrganizationID:  org.ID,
+		Name:            "cpu",
+		RetentionPeriod: 7 * 24 * time.Hour,
+		DownsampleTiers: []platform.DownsampleTier{
+			{Every: time.Minute, Aggregates: []string{"mean", "min", "max"}, RetentionPeriod: 90 * 24 * time.Hour, Offset: 30 * time.Second, Fields: []string{"usage"}},
+			{Every: time.Hour, Aggregates: []string{"mean"}, RetentionPeriod: platform.InfiniteRetention},
+		},
+	}
+
+	// Numeric aggregates of an implicit bucket require the fields to roll up.
+	noFields := *b
+	noFields.DownsampleTiers = []platform.DownsampleTier{{Every: time.Minute, Aggregates: []string{"mean"}, RetentionPeriod: time.Hour}}
+	if err := s.CreateBucket(ctx, &noFields); platform.ErrorCode(err) != platform.EInvalid {
+		t.Fatalf("expected invalid tiers without fields, got %v", err)
+	}
+
+	if err := s.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(b.DownsampleTiers) != 2 || len(tasks) != 2 {
+		t.Fatalf("expected 2 tiers and tasks, got %d and %d", len(b.DownsampleTiers), len(tasks))
+	}
+	offsets := []time.Duration{30 * time.Second, 30*time.Second + downsample.RollupDelay}
+	for i, tier := range b.DownsampleTiers {
+		target, err := buckets.FindBucketByID(ctx, tier.BucketID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := downsample.TierBucketName("cpu", tier.Every); target.Name != exp || target.RetentionPeriod != tier.RetentionPeriod {
+			t.Fatalf("unexpected target bucket %s with retention %s", target.Name, target.RetentionPeriod)
+		}
+
+		task := tasks[tier.TaskID]
+		if task == nil || task.Owner.ID != 7 || task.Organization != org.ID {
+			t.Fatalf("unexpected task %+v", task)
+		}
+		opts, err := options.FromScript(task.Flux)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if opts.Every != tier.Every || opts.Offset != offsets[i] {
+			t.Fatalf("task runs every %s with offset %s, expected %s and %s", opts.Every, opts.Offset, tier.Every, offsets[i])
+		}
+		if _, err := flux.Compile(ctx, task.Flux, time.Now()); err != nil {
+			t.Fatalf("invalid script: %v\n%s", err, task.Flux)
+		}
+	}
+	// The first tier rolls up the numeric fields of the source, and the second tier the first tier.
+	if script := tasks[b.DownsampleTiers[0].TaskID].Flux; !strings.Contains(script, `from(bucketID: "`+b.ID.String()+`")`) ||
+		!strings.Contains(script, `r._field == "usage"`) {
+		t.Fatalf("expected first tier to roll up usage of the source bucket:\n%s", script)
+	}
+	if script := tasks[b.DownsampleTiers[1].TaskID].Flux; !strings.Contains(script, `from(bucketID: "`+b.DownsampleTiers[0].BucketID.String()+`")`) ||
+		!strings.Contains(script, `r.aggregate == "mean"`) || strings.Contains(script, b.ID.String()) {
+		t.Fatalf("expected second tier to roll up the first tier:\n%s", script)
+	}
+	if got := downsample.TierBucketName("cpu", 90*time.Minute); got != "cpu_90m" {
+		t.Fatalf("unexpected tier bucket name %s", got)
+	}
+
+	// Changing the aggregates of the first tier updates its task, and removing the second deletes it.
+	first, second := b.DownsampleTiers[0], b.DownsampleTiers[1]
+	upd, err := s.UpdateBucket(ctx, b.ID, platform.BucketUpdate{
+		DownsampleTiers: []platform.DownsampleTier{
+			{Every: time.Minute, Aggregates: []string{"count"}, RetentionPeriod: 30 * 24 * time.Hour},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(upd.DownsampleTiers) != 1 || upd.DownsampleTiers[0].BucketID != first.BucketID || upd.DownsampleTiers[0].TaskID != first.TaskID {
+		t.Fatalf("unexpected tiers %+v", upd.DownsampleTiers)
+	}
+	if target, err := buckets.FindBucketByID(ctx, first.BucketID); err != nil || target.RetentionPeriod != 30*24*time.Hour {
+		t.Fatalf("expected target bucket retention to be updated: %v", err)
+	}
+	if _, err := buckets.FindBucketByID(ctx, second.BucketID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected removed tier bucket to be deleted, got %v", err)
+	}
+	if len(tasks) != 1 {
+		t.Fatalf("expected removed tier task to be deleted, got %d tasks", len(tasks))
+	}
+	if _, err := flux.Compile(ctx, tasks[first.TaskID].Flux, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Deleting the bucket deletes its tiers.
+	if err := s.DeleteBucket(ctx, b.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, n, err := buckets.FindBuckets(ctx, platform.BucketFilter{}); err != nil || n != 0 {
+		t.Fatalf("expected no buckets, got %d: %v", n, err)
+	}
+	if len(tasks) != 0 {
+		t.Fatalf("expected no tasks, got %d", len(tasks))
+	}
+}
+
+func TestBucketService_ExplicitSchema(t *testing.T) {
+	ctx := pcontext.SetAuthorizer(context.Background(), &platform.Authorization{UserID: 7})
+
+	buckets := inmem.NewService()
+	org := &platform.Organization{Name: "org"}
+	if err := buckets.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	tasks := make(map[platform.ID]*platform.Task)
+	s := downsample.NewBucketService(buckets, taskService(tasks))
+
+	b := &platform.Bucket{
+		OrganizationID: org.ID,
+		Name:           "cpu",
+		SchemaType:     platform.SchemaTypeExplicit,
+		MeasurementSchemas: []platform.MeasurementSchema{{
+			Name: "cpu",
+			Fields: []platform.FieldSchema{
+				{Name: "usage", Type: platform.SchemaFieldTypeFloat},
+				{Name: "state", Type: platform.SchemaFieldTypeString},
+			},
+		}},
+		DownsampleTiers: []platform.DownsampleTier{
+			{Every: time.Minute, Aggregates: []string{"mean"}, RetentionPeriod: platform.InfiniteRetention},
+		},
+	}
+	if err := s.CreateBucket(ctx, b); err != nil {
+		t.Fatal(err)
+	}
+	script := tasks[b.DownsampleTiers[0].TaskID].Flux
+	if !strings.Contains(script, `(r._measurement == "cpu" and (r._field == "usage"))`) || strings.Contains(script, "state") {
+		t.Fatalf("expected mean of the numeric fields of the schema:\n%s", script)
+	}
+	if _, err := flux.Compile(ctx, script, time.Now()); err != nil {
+		t.Fatalf("invalid script: %v\n%s", err, script)
+	}
+
+	// Changing the schema updates the task of the tier.
+	schemas := append(b.MeasurementSchemas, platform.MeasurementSchema{
+		Name:   "mem",
+		Fields: []platform.FieldSchema{{Name: "used", Type: platform.SchemaFieldTypeInteger}},
+	})
+	if _, err := s.UpdateBucket(ctx, b.ID, platform.BucketUpdate{MeasurementSchemas: schemas}); err != nil {
+		t.Fatal(err)
+	}
+	if script := tasks[b.DownsampleTiers[0].TaskID].Flux; !strings.Contains(script, `(r._measurement == "mem" and (r._field == "used"))`) {
+		t.Fatalf("expected task to be updated with the schema:\n%s", script)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/backup"
+	"github.com/influxdata/platform/chronograf/oauth2"
 	"github.com/influxdata/platform/chronograf/server"
 	"github.com/influxdata/platform/query"
 	"github.com/influxdata/platform/storage"
@@ -27,6 +29,11 @@ type APIHandler struct {
 	QueryHandler         *FluxHandler
 	ProtoHandler         *ProtoHandler
 	WriteHandler         *WriteHandler
+	DeleteHandler        *DeleteHandler
+	UsageHandler         *UsageHandler
+	ConfigHandler        *ConfigHandler
+	BackupHandler        *BackupHandler
+	ReplicationHandler   *ReplicationHandler
 	SetupHandler         *SetupHandler
 	SessionHandler       *SessionHandler
 }
@@ -41,9 +48,16 @@ type APIBackend struct {
 	NewQueryService  func(*platform.Source) (query.ProxyQueryService, error)
 
 	PointsWriter                    storage.PointsWriter
+	PredicateDeleter                storage.PredicateDeleter
+	EngineMode                      storage.ModeSetter
+	UsageService                    platform.UsageService
+	MetadataBackuper                backup.MetadataBackuper
+	EngineBackuper                  backup.EngineBackuper
 	AuthorizationService            platform.AuthorizationService
+	AuthorizationUsageService       platform.AuthorizationUsageService
 	BucketService                   platform.BucketService
 	SessionService                  platform.SessionService
+	SSOProviders                    []*oauth2.AuthMux
 	UserService                     platform.UserService
 	OrganizationService             platform.OrganizationService
 	UserResourceMappingService      platform.UserResourceMappingService
@@ -51,6 +65,7 @@ type APIBackend struct {
 	DashboardService                platform.DashboardService
 	DashboardOperationLogService    platform.DashboardOperationLogService
 	BucketOperationLogService       platform.BucketOperationLogService
+	BucketTransferService           platform.BucketTransferService
 	UserOperationLogService         platform.UserOperationLogService
 	OrganizationOperationLogService platform.OrganizationOperationLogService
 	SourceService                   platform.SourceService
@@ -62,6 +77,8 @@ type APIBackend struct {
 	TelegrafService                 platform.TelegrafConfigStore
 	ScraperTargetStoreService       platform.ScraperTargetStoreService
 	SecretService                   platform.SecretService
+	ReplicationService              platform.ReplicationService
+	RoleService                     platform.RoleService
 	LookupService                   platform.LookupService
 	ChronografService               *server.Service
 	ProtoService                    platform.ProtoService
@@ -76,12 +93,14 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	h.BucketHandler = NewBucketHandler(b.UserResourceMappingService, b.LabelService, b.UserService)
 	h.BucketHandler.BucketService = b.BucketService
 	h.BucketHandler.BucketOperationLogService = b.BucketOperationLogService
+	h.BucketHandler.BucketTransferService = b.BucketTransferService
 
 	h.OrgHandler = NewOrgHandler(b.UserResourceMappingService, b.LabelService, b.UserService)
 	h.OrgHandler.OrganizationService = b.OrganizationService
 	h.OrgHandler.BucketService = b.BucketService
 	h.OrgHandler.OrganizationOperationLogService = b.OrganizationOperationLogService
 	h.OrgHandler.SecretService = b.SecretService
+	h.OrgHandler.RoleService = b.RoleService
 
 	h.UserHandler = NewUserHandler()
 	h.UserHandler.UserService = b.UserService
@@ -94,11 +113,13 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 
 	h.MacroHandler = NewMacroHandler()
 	h.MacroHandler.MacroService = b.MacroService
+	h.MacroHandler.UserResourceMappingService = b.UserResourceMappingService
 
 	h.AuthorizationHandler = NewAuthorizationHandler(b.UserService)
 	h.AuthorizationHandler.OrganizationService = b.OrganizationService
 	h.AuthorizationHandler.AuthorizationService = b.AuthorizationService
 	h.AuthorizationHandler.LookupService = b.LookupService
+	h.AuthorizationHandler.RoleService = b.RoleService
 	h.AuthorizationHandler.Logger = b.Logger.With(zap.String("handler", "auth"))
 
 	h.SourceHandler = NewSourceHandler()
@@ -127,6 +148,30 @@ func NewAPIHandler(b *APIBackend) *APIHandler {
 	h.WriteHandler.BucketService = b.BucketService
 	h.WriteHandler.Logger = b.Logger.With(zap.String("handler", "write"))
 
+	h.DeleteHandler = NewDeleteHandler(b.PredicateDeleter)
+	h.DeleteHandler.OrganizationService = b.OrganizationService
+	h.DeleteHandler.BucketService = b.BucketService
+	h.DeleteHandler.Logger = b.Logger.With(zap.String("handler", "delete"))
+
+	h.ConfigHandler = NewConfigHandler()
+	h.ConfigHandler.EngineMode = b.EngineMode
+	h.ConfigHandler.Logger = b.Logger.With(zap.String("handler", "config"))
+
+	h.UsageHandler = NewUsageHandler()
+	h.UsageHandler.UsageService = b.UsageService
+	h.UsageHandler.Logger = b.Logger.With(zap.String("handler", "usage"))
+
+	h.BackupHandler = NewBackupHandler()
+	h.BackupHandler.BucketService = b.BucketService
+	h.BackupHandler.MetadataBackuper = b.MetadataBackuper
+	h.BackupHandler.EngineBackuper = b.EngineBackuper
+	h.BackupHandler.Logger = b.Logger.With(zap.String("handler", "backup"))
+
+	h.ReplicationHandler = NewReplicationHandler()
+	h.ReplicationHandler.ReplicationService = b.ReplicationService
+	h.ReplicationHandler.BucketService = b.BucketService
+	h.ReplicationHandler.Logger = b.Logger.With(zap.String("handler", "replication"))
+
 	h.QueryHandler = NewFluxHandler()
 	h.QueryHandler.OrganizationService = b.OrganizationService
 	h.QueryHandler.Logger = b.Logger.With(zap.String("handler", "query"))
@@ -143,8 +188,11 @@ var apiLinks = map[string]interface{}{
 	// when adding new links, please take care to keep this list alphabetical
 	// as this makes it easier to verify values against the swagger document.
 	"authorizations": "/api/v2/authorizations",
+	"backup":         "/api/v2/backup",
 	"buckets":        "/api/v2/buckets",
+	"config":         "/api/v2/config",
 	"dashboards":     "/api/v2/dashboards",
+	"delete":         "/api/v2/delete",
 	"external": map[string]string{
 		"statusFeed": "https://www.influxdata.com/feed/json",
 	},
@@ -159,10 +207,11 @@ var apiLinks = map[string]interface{}{
 		"spec":        "/api/v2/query/spec",
 		"suggestions": "/api/v2/query/suggestions",
 	},
-	"setup":   "/api/v2/setup",
-	"signin":  "/api/v2/signin",
-	"signout": "/api/v2/signout",
-	"sources": "/api/v2/sources",
+	"replications": "/api/v2/replications",
+	"setup":        "/api/v2/setup",
+	"signin":       "/api/v2/signin",
+	"signout":      "/api/v2/signout",
+	"sources":      "/api/v2/sources",
 	"system": map[string]string{
 		"metrics": "/metrics",
 		"debug":   "/debug/pprof",
@@ -170,6 +219,7 @@ var apiLinks = map[string]interface{}{
 	},
 	"tasks":     "/api/v2/tasks",
 	"telegrafs": "/api/v2/telegrafs",
+	"usage":     "/api/v2/usage",
 	"users":     "/api/v2/users",
 	"write":     "/api/v2/write",
 }
@@ -195,7 +245,7 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if r.URL.Path == "/api/v2/signin" || r.URL.Path == "/api/v2/signout" {
+	if strings.HasPrefix(r.URL.Path, "/api/v2/signin") || r.URL.Path == "/api/v2/signout" {
 		h.SessionHandler.ServeHTTP(w, r)
 		return
 	}
@@ -210,6 +260,31 @@ func (h *APIHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.URL.Path, "/api/v2/delete") {
+		h.DeleteHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/backup") {
+		h.BackupHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/replications") {
+		h.ReplicationHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/config") {
+		h.ConfigHandler.ServeHTTP(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/api/v2/usage") {
+		h.UsageHandler.ServeHTTP(w, r)
+		return
+	}
+
 	if strings.HasPrefix(r.URL.Path, "/api/v2/query") {
 		h.QueryHandler.ServeHTTP(w, r)
 		return
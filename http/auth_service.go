@@ -6,8 +6,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"path"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -25,6 +27,8 @@ type AuthorizationHandler struct {
 	UserService          platform.UserService
 	AuthorizationService platform.AuthorizationService
 	LookupService        platform.LookupService
+	// RoleService, if set, checks that the roles of new authorizations belong to their organization.
+	RoleService platform.RoleService
 }
 
 // NewAuthorizationHandler returns a new instance of AuthorizationHandler.
@@ -40,6 +44,7 @@ func NewAuthorizationHandler(userService platform.UserService) *AuthorizationHan
 	h.HandlerFunc("GET", "/api/v2/authorizations/:id", h.handleGetAuthorization)
 	h.HandlerFunc("PATCH", "/api/v2/authorizations/:id", h.handleSetAuthorizationStatus)
 	h.HandlerFunc("DELETE", "/api/v2/authorizations/:id", h.handleDeleteAuthorization)
+	h.HandlerFunc("POST", "/api/v2/authorizations/:id/rotate", h.handleRotateAuthorization)
 	return h
 }
 
@@ -53,6 +58,10 @@ type authResponse struct {
 	UserID      platform.ID          `json:"userID"`
 	User        string               `json:"user"`
 	Permissions []permissionResponse `json:"permissions"`
+	RoleIDs     []platform.ID        `json:"roleIDs,omitempty"`
+	CreatedAt   time.Time            `json:"createdAt"`
+	ExpiresAt   *time.Time           `json:"expiresAt,omitempty"`
+	LastUsedAt  *time.Time           `json:"lastUsedAt,omitempty"`
 	Links       map[string]string    `json:"links"`
 }
 
@@ -67,6 +76,10 @@ func newAuthResponse(a *platform.Authorization, org *platform.Organization, user
 		User:        user.Name,
 		Org:         org.Name,
 		Permissions: ps,
+		RoleIDs:     a.RoleIDs,
+		CreatedAt:   a.CreatedAt,
+		ExpiresAt:   a.ExpiresAt,
+		LastUsedAt:  a.LastUsedAt,
 		Links: map[string]string{
 			"self": fmt.Sprintf("/api/v2/authorizations/%s", a.ID),
 			"user": fmt.Sprintf("/api/v2/users/%s", a.UserID),
@@ -83,6 +96,10 @@ func (a *authResponse) toPlatform() *platform.Authorization {
 		Description: a.Description,
 		OrgID:       a.OrgID,
 		UserID:      a.UserID,
+		RoleIDs:     a.RoleIDs,
+		CreatedAt:   a.CreatedAt,
+		ExpiresAt:   a.ExpiresAt,
+		LastUsedAt:  a.LastUsedAt,
 	}
 	for _, p := range a.Permissions {
 		res.Permissions = append(res.Permissions, p.Permission)
@@ -152,6 +169,11 @@ func (h *AuthorizationHandler) handlePostAuthorization(w http.ResponseWriter, r
 		return
 	}
 
+	if err := h.checkRoles(ctx, auth); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
 	if err := h.AuthorizationService.CreateAuthorization(ctx, auth); err != nil {
 		EncodeError(ctx, err, w)
 		return
@@ -169,11 +191,33 @@ func (h *AuthorizationHandler) handlePostAuthorization(w http.ResponseWriter, r
 	}
 }
 
+// checkRoles returns an error if a role of a does not exist in the organization of a.
+func (h *AuthorizationHandler) checkRoles(ctx context.Context, a *platform.Authorization) error {
+	if h.RoleService == nil {
+		return nil
+	}
+	for _, id := range a.RoleIDs {
+		r, err := h.RoleService.FindRoleByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if r.OrganizationID != a.OrgID {
+			return &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  fmt.Sprintf("role %s does not belong to organization %s", id, a.OrgID),
+			}
+		}
+	}
+	return nil
+}
+
 type postAuthorizationRequest struct {
 	Status      platform.Status       `json:"status"`
 	OrgID       platform.ID           `json:"orgID"`
 	Description string                `json:"description"`
 	Permissions []platform.Permission `json:"permissions"`
+	RoleIDs     []platform.ID         `json:"roleIDs,omitempty"`
+	ExpiresAt   *time.Time            `json:"expiresAt,omitempty"`
 }
 
 func (p *postAuthorizationRequest) toPlatform(userID platform.ID) *platform.Authorization {
@@ -182,6 +226,8 @@ func (p *postAuthorizationRequest) toPlatform(userID platform.ID) *platform.Auth
 		Status:      p.Status,
 		Description: p.Description,
 		Permissions: p.Permissions,
+		RoleIDs:     p.RoleIDs,
+		ExpiresAt:   p.ExpiresAt,
 		UserID:      userID,
 	}
 }
@@ -191,7 +237,9 @@ func newPostAuthorizationRequest(a *platform.Authorization) (*postAuthorizationR
 		OrgID:       a.OrgID,
 		Description: a.Description,
 		Permissions: a.Permissions,
+		RoleIDs:     a.RoleIDs,
 		Status:      a.Status,
+		ExpiresAt:   a.ExpiresAt,
 	}
 
 	res.SetDefaults()
@@ -206,10 +254,10 @@ func (p *postAuthorizationRequest) SetDefaults() {
 }
 
 func (p *postAuthorizationRequest) Validate() error {
-	if len(p.Permissions) == 0 {
+	if len(p.Permissions) == 0 && len(p.RoleIDs) == 0 {
 		return &platform.Error{
 			Code: platform.EInvalid,
-			Msg:  "authorization must include permissions",
+			Msg:  "authorization must include permissions or roles",
 		}
 	}
 
@@ -229,6 +277,13 @@ func (p *postAuthorizationRequest) Validate() error {
 		}
 	}
 
+	if p.ExpiresAt != nil && !p.ExpiresAt.After(time.Now()) {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "authorization expiration must be in the future",
+		}
+	}
+
 	if p.Status == "" {
 		p.Status = platform.Active
 	}
@@ -528,6 +583,100 @@ func decodeDeleteAuthorizationRequest(ctx context.Context, r *http.Request) (*de
 	}, nil
 }
 
+// handleRotateAuthorization is the HTTP handler for the POST /api/v2/authorizations/:id/rotate route.
+func (h *AuthorizationHandler) handleRotateAuthorization(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodeRotateAuthorizationRequest(ctx, r)
+	if err != nil {
+		h.Logger.Info("failed to decode request", zap.String("handler", "rotateAuthorization"), zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	a, err := h.AuthorizationService.RotateAuthorization(ctx, req.ID, req.GracePeriod)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	o, err := h.OrganizationService.FindOrganizationByID(ctx, a.OrgID)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	u, err := h.UserService.FindUserByID(ctx, a.UserID)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	ps, err := newPermissionsResponse(ctx, a.Permissions, h.LookupService)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newAuthResponse(a, o, u, ps)); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+}
+
+type rotateAuthorizationRequest struct {
+	ID          platform.ID
+	GracePeriod time.Duration
+}
+
+type postRotateAuthorizationRequest struct {
+	// GracePeriodSeconds is how long the replaced token remains valid. It defaults
+	// to platform.DefaultTokenGracePeriod when omitted.
+	GracePeriodSeconds *int `json:"gracePeriodSeconds,omitempty"`
+}
+
+func decodeRotateAuthorizationRequest(ctx context.Context, r *http.Request) (*rotateAuthorizationRequest, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	id := params.ByName("id")
+	if id == "" {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "url missing id",
+		}
+	}
+
+	var i platform.ID
+	if err := i.DecodeFromString(id); err != nil {
+		return nil, err
+	}
+
+	req := &rotateAuthorizationRequest{
+		ID:          i,
+		GracePeriod: platform.DefaultTokenGracePeriod,
+	}
+
+	// An empty body rotates the token with the default grace period.
+	var p postRotateAuthorizationRequest
+	if err := json.NewDecoder(r.Body).Decode(&p); err != nil && err != io.EOF {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid json structure",
+			Err:  err,
+		}
+	}
+	if p.GracePeriodSeconds != nil {
+		if *p.GracePeriodSeconds < 0 {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Msg:  "grace period must not be negative",
+			}
+		}
+		req.GracePeriod = time.Duration(*p.GracePeriodSeconds) * time.Second
+	}
+
+	return req, nil
+}
+
 func getAuthorizedUser(r *http.Request, svc platform.UserService) (*platform.User, error) {
 	ctx := r.Context()
 
@@ -748,6 +897,48 @@ func (s *AuthorizationService) DeleteAuthorization(ctx context.Context, id platf
 	return CheckError(resp, true)
 }
 
+// RotateAuthorization replaces the token of an authorization, keeping the old token valid for gracePeriod.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, gracePeriod time.Duration) (*platform.Authorization, error) {
+	u, err := newURL(s.Addr, authorizationIDPath(id)+"/rotate")
+	if err != nil {
+		return nil, err
+	}
+
+	secs := int(gracePeriod / time.Second)
+	b, err := json.Marshal(postRotateAuthorizationRequest{
+		GracePeriodSeconds: &secs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var a platform.Authorization
+	if err := json.NewDecoder(resp.Body).Decode(&a); err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
 func authorizationIDPath(id platform.ID) string {
 	return path.Join(authorizationPath, id.String())
 }
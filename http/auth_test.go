@@ -107,6 +107,7 @@ func TestService_handleGetAuthorizations(t *testing.T) {
       "status": "",
 	  "token": "hello",
 	  "description": "t1",
+	  "createdAt": "0001-01-01T00:00:00Z",
 	  "permissions": %s
     },
     {
@@ -122,6 +123,7 @@ func TestService_handleGetAuthorizations(t *testing.T) {
       "status": "",
       "token": "example",
 	  "description": "t2",
+	  "createdAt": "0001-01-01T00:00:00Z",
 	  "permissions": %s
     }
   ]
@@ -283,6 +285,7 @@ func TestService_handleGetAuthorization(t *testing.T) {
   "token": "hello",
   "status": "",
   "description": "",
+  "createdAt": "0001-01-01T00:00:00Z",
   "permissions": [{"action": "read","id": "020f755c3c084000", "name": "b1", "resource": "buckets"}]
 }
 `,
@@ -359,6 +362,7 @@ func TestService_handlePostAuthorization(t *testing.T) {
 		AuthorizationService platform.AuthorizationService
 		UserService          platform.UserService
 		OrganizationService  platform.OrganizationService
+		RoleService          platform.RoleService
 	}
 	type args struct {
 		session       *platform.Authorization
@@ -447,11 +451,135 @@ func TestService_handlePostAuthorization(t *testing.T) {
   "token": "new-test-token",
   "status": "active",
   "description": "only read dashboards sucka",
+  "createdAt": "0001-01-01T00:00:00Z",
   "permissions": [{"action": "read", "resource": "dashboards"}]
 }
 `,
 			},
 		},
+		{
+			name: "create a new authorization with a role",
+			fields: fields{
+				AuthorizationService: &mock.AuthorizationService{
+					CreateAuthorizationFn: func(ctx context.Context, c *platform.Authorization) error {
+						c.ID = platformtesting.MustIDBase16("020f755c3c082000")
+						c.Token = "new-test-token"
+						return nil
+					},
+				},
+				UserService: &mock.UserService{
+					FindUserByIDFn: func(ctx context.Context, id platform.ID) (*platform.User, error) {
+						return &platform.User{
+							ID:   id,
+							Name: "u1",
+						}, nil
+					},
+				},
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+						return &platform.Organization{
+							ID:   id,
+							Name: "o1",
+						}, nil
+					},
+				},
+				RoleService: &mock.RoleService{
+					FindRoleByIDF: func(ctx context.Context, id platform.ID) (*platform.Role, error) {
+						return &platform.Role{
+							ID:             id,
+							OrganizationID: platformtesting.MustIDBase16("020f755c3c083000"),
+							Name:           "viewer",
+						}, nil
+					},
+				},
+			},
+			args: args{
+				session: &platform.Authorization{
+					Token:  "session-token",
+					ID:     platformtesting.MustIDBase16("020f755c3c082000"),
+					UserID: platformtesting.MustIDBase16("aaaaaaaaaaaaaaaa"),
+					OrgID:  platformtesting.MustIDBase16("020f755c3c083000"),
+				},
+				authorization: &platform.Authorization{
+					ID:          platformtesting.MustIDBase16("020f755c3c082000"),
+					UserID:      platformtesting.MustIDBase16("aaaaaaaaaaaaaaaa"),
+					OrgID:       platformtesting.MustIDBase16("020f755c3c083000"),
+					Description: "view the org",
+					RoleIDs:     []platform.ID{platformtesting.MustIDBase16("020f755c3c085000")},
+				},
+			},
+			wants: wants{
+				statusCode:  http.StatusCreated,
+				contentType: "application/json; charset=utf-8",
+				body: `
+{
+  "links": {
+    "user": "/api/v2/users/aaaaaaaaaaaaaaaa",
+    "self": "/api/v2/authorizations/020f755c3c082000"
+  },
+  "id": "020f755c3c082000",
+  "user": "u1",
+  "userID": "aaaaaaaaaaaaaaaa",
+  "orgID": "020f755c3c083000",
+  "org": "o1",
+  "token": "new-test-token",
+  "status": "active",
+  "description": "view the org",
+  "createdAt": "0001-01-01T00:00:00Z",
+  "permissions": [],
+  "roleIDs": ["020f755c3c085000"]
+}
+`,
+			},
+		},
+		{
+			name: "create a new authorization with a role of another organization",
+			fields: fields{
+				AuthorizationService: &mock.AuthorizationService{},
+				UserService: &mock.UserService{
+					FindUserByIDFn: func(ctx context.Context, id platform.ID) (*platform.User, error) {
+						return &platform.User{
+							ID:   id,
+							Name: "u1",
+						}, nil
+					},
+				},
+				OrganizationService: &mock.OrganizationService{
+					FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+						return &platform.Organization{
+							ID:   id,
+							Name: "o1",
+						}, nil
+					},
+				},
+				RoleService: &mock.RoleService{
+					FindRoleByIDF: func(ctx context.Context, id platform.ID) (*platform.Role, error) {
+						return &platform.Role{
+							ID:             id,
+							OrganizationID: platformtesting.MustIDBase16("020f755c3c083001"),
+							Name:           "viewer",
+						}, nil
+					},
+				},
+			},
+			args: args{
+				session: &platform.Authorization{
+					Token:  "session-token",
+					ID:     platformtesting.MustIDBase16("020f755c3c082000"),
+					UserID: platformtesting.MustIDBase16("aaaaaaaaaaaaaaaa"),
+					OrgID:  platformtesting.MustIDBase16("020f755c3c083000"),
+				},
+				authorization: &platform.Authorization{
+					ID:      platformtesting.MustIDBase16("020f755c3c082000"),
+					UserID:  platformtesting.MustIDBase16("aaaaaaaaaaaaaaaa"),
+					OrgID:   platformtesting.MustIDBase16("020f755c3c083000"),
+					RoleIDs: []platform.ID{platformtesting.MustIDBase16("020f755c3c085000")},
+				},
+			},
+			wants: wants{
+				statusCode: http.StatusBadRequest,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -460,6 +588,7 @@ func TestService_handlePostAuthorization(t *testing.T) {
 			h.AuthorizationService = tt.fields.AuthorizationService
 			h.UserService = tt.fields.UserService
 			h.OrganizationService = tt.fields.OrganizationService
+			h.RoleService = tt.fields.RoleService
 
 			b, err := json.Marshal(tt.args.authorization)
 			if err != nil {
@@ -617,6 +746,12 @@ func initAuthorizationService(f platformtesting.AuthorizationFields, t *testing.
 	svc := inmem.NewService()
 	svc.IDGenerator = f.IDGenerator
 	svc.TokenGenerator = f.TokenGenerator
+	if f.TokenHasher != nil {
+		svc.TokenHasher = f.TokenHasher
+	}
+	if f.NowFn != nil {
+		svc.WithTime(f.NowFn)
+	}
 
 	ctx := context.Background()
 
@@ -682,6 +817,10 @@ func TestAuthorizationService_FindAuthorizations(t *testing.T) {
 	platformtesting.FindAuthorizations(initAuthorizationService, t)
 }
 
+func TestAuthorizationService_RotateAuthorization(t *testing.T) {
+	platformtesting.RotateAuthorization(initAuthorizationService, t)
+}
+
 func TestAuthorizationService_DeleteAuthorization(t *testing.T) {
 	platformtesting.DeleteAuthorization(initAuthorizationService, t)
 }
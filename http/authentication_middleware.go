@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/influxdata/platform"
 	platcontext "github.com/influxdata/platform/context"
@@ -18,6 +19,12 @@ type AuthenticationHandler struct {
 	AuthorizationService platform.AuthorizationService
 	SessionService       platform.SessionService
 
+	// AuthorizationUsageService, if set, records the last use of the authorizations of tokens.
+	AuthorizationUsageService platform.AuthorizationUsageService
+
+	// RoleService, if set, resolves the permissions of the roles of authorizations.
+	RoleService platform.RoleService
+
 	// This is only really used for it's lookup method the specific http
 	// hanlder used to register routes does not matter.
 	noAuthRouter *httprouter.Router
@@ -40,6 +47,10 @@ func (h *AuthenticationHandler) RegisterNoAuthRoute(method, path string) {
 	h.noAuthRouter.HandlerFunc(method, path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
 }
 
+// lastUsedResolution is the precision the last use of an authorization is recorded with,
+// so that the authorization is not written on every request.
+const lastUsedResolution = time.Minute
+
 const (
 	tokenAuthScheme   = "token"
 	sessionAuthScheme = "session"
@@ -108,9 +119,54 @@ func (h *AuthenticationHandler) extractAuthorization(ctx context.Context, r *htt
 		return ctx, err
 	}
 
+	now := time.Now()
+	if a.ExpiredAt(now) {
+		return ctx, &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  "token expired",
+		}
+	}
+	h.recordLastUsed(ctx, a, now)
+
+	a, err = h.resolveRoles(ctx, a)
+	if err != nil {
+		return ctx, err
+	}
+
 	return platcontext.SetAuthorizer(ctx, a), nil
 }
 
+// resolveRoles returns a copy of a that has the permissions of the roles of a added to its permissions.
+func (h *AuthenticationHandler) resolveRoles(ctx context.Context, a *platform.Authorization) (*platform.Authorization, error) {
+	if h.RoleService == nil || len(a.RoleIDs) == 0 {
+		return a, nil
+	}
+
+	ps, err := platform.RolePermissions(ctx, h.RoleService, a.OrgID, a.RoleIDs)
+	if err != nil {
+		return nil, err
+	}
+	ra := *a
+	ra.Permissions = append(append([]platform.Permission{}, a.Permissions...), ps...)
+	return &ra, nil
+}
+
+// recordLastUsed records that a was used at now. Errors are logged rather than failing the request.
+func (h *AuthenticationHandler) recordLastUsed(ctx context.Context, a *platform.Authorization, now time.Time) {
+	if h.AuthorizationUsageService == nil {
+		return
+	}
+	if a.LastUsedAt != nil && now.Sub(*a.LastUsedAt) < lastUsedResolution {
+		return
+	}
+
+	if err := h.AuthorizationUsageService.SetAuthorizationLastUsed(ctx, a.ID, now); err != nil {
+		h.Logger.Info("failed to record authorization use", zap.String("authorization", a.ID.String()), zap.Error(err))
+		return
+	}
+	a.LastUsedAt = &now
+}
+
 func (h *AuthenticationHandler) extractSession(ctx context.Context, r *http.Request) (context.Context, error) {
 	k, err := decodeCookieSession(ctx, r)
 	if err != nil {
@@ -6,9 +6,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/influxdata/platform"
+	platcontext "github.com/influxdata/platform/context"
 	platformhttp "github.com/influxdata/platform/http"
+	"github.com/influxdata/platform/inmem"
 	"github.com/influxdata/platform/mock"
 )
 
@@ -82,6 +85,24 @@ func TestAuthenticationHandler(t *testing.T) {
 				code: http.StatusOK,
 			},
 		},
+		{
+			name: "token expired",
+			fields: fields{
+				AuthorizationService: &mock.AuthorizationService{
+					FindAuthorizationByTokenFn: func(ctx context.Context, token string) (*platform.Authorization, error) {
+						expiresAt := time.Now().Add(-time.Minute)
+						return &platform.Authorization{ExpiresAt: &expiresAt}, nil
+					},
+				},
+				SessionService: mock.NewSessionService(),
+			},
+			args: args{
+				token: "abc123",
+			},
+			wants: wants{
+				code: http.StatusForbidden,
+			},
+		},
 		{
 			name: "token does not exist",
 			fields: fields{
@@ -143,6 +164,108 @@ func TestAuthenticationHandler(t *testing.T) {
 	}
 }
 
+func TestAuthenticationHandler_LastUsed(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+	a := &platform.Authorization{
+		ID:     platform.ID(1),
+		Token:  "abc123",
+		Status: platform.Active,
+	}
+	if err := svc.PutAuthorization(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+
+	h := platformhttp.NewAuthenticationHandler()
+	h.AuthorizationService = svc
+	h.AuthorizationUsageService = svc
+	h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	lastUsed := func() *time.Time {
+		t.Helper()
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest("GET", "http://any.url", nil)
+		platformhttp.SetToken(a.Token, r)
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("unexpected status code: %d", w.Code)
+		}
+
+		a, err := svc.FindAuthorizationByID(ctx, a.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return a.LastUsedAt
+	}
+
+	first := lastUsed()
+	if first == nil {
+		t.Fatal("expected the use of the token to be recorded")
+	}
+	// Uses within the resolution of the last use leave it unchanged.
+	if second := lastUsed(); second == nil || !second.Equal(*first) {
+		t.Fatalf("unexpected last use: got %v, want %v", second, first)
+	}
+}
+
+func TestAuthenticationHandler_Roles(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+	orgID, otherOrgID := platform.ID(10), platform.ID(11)
+	roles := []*platform.Role{
+		{ID: platform.ID(20), OrganizationID: orgID, Name: "viewer", Permissions: platform.ViewerPermissions(orgID)},
+		{ID: platform.ID(21), OrganizationID: otherOrgID, Name: "viewer", Permissions: platform.ViewerPermissions(otherOrgID)},
+	}
+	for _, r := range roles {
+		if err := svc.PutRole(ctx, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	a := &platform.Authorization{
+		ID:      platform.ID(1),
+		Token:   "abc123",
+		Status:  platform.Active,
+		OrgID:   orgID,
+		RoleIDs: []platform.ID{roles[0].ID, roles[1].ID, platform.ID(22)},
+	}
+	if err := svc.PutAuthorization(ctx, a); err != nil {
+		t.Fatal(err)
+	}
+
+	readBuckets := func(orgID platform.ID) platform.Permission {
+		return platform.Permission{Action: platform.ReadAction, Resource: platform.BucketsResource, OrgID: &orgID}
+	}
+
+	h := platformhttp.NewAuthenticationHandler()
+	h.AuthorizationService = svc
+	h.RoleService = svc
+	h.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth, err := platcontext.GetAuthorizer(r.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Roles of other organizations than the one of the authorization, and roles that
+		// no longer exist, grant nothing.
+		if !auth.Allowed(readBuckets(orgID)) {
+			t.Errorf("expected the permissions of the role in the organization to be granted")
+		}
+		if auth.Allowed(readBuckets(otherOrgID)) {
+			t.Errorf("expected the permissions of the role in another organization not to be granted")
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("GET", "http://any.url", nil)
+	platformhttp.SetToken(a.Token, r)
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("unexpected status code: %d", w.Code)
+	}
+}
+
 func TestProbeAuthScheme(t *testing.T) {
 	type args struct {
 		token   string
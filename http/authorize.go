@@ -0,0 +1,56 @@
+package http
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+)
+
+// authorize returns an error unless the authorizer of the context is allowed the permission.
+func authorize(ctx context.Context, p platform.Permission) error {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+	if err := p.Valid(); err != nil {
+		return err
+	}
+	if !a.Allowed(p) {
+		return &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  fmt.Sprintf("insufficient permissions to %s %s", p.Action, p.Resource),
+		}
+	}
+	return nil
+}
+
+// authorizeCreate returns an error unless the authorizer of the context may create a shared resource
+// of type r. Every user with access to the shared resources of a type may create them.
+func authorizeCreate(ctx context.Context, r platform.Resource) error {
+	if err := authorize(ctx, platform.Permission{Action: platform.WriteAction, Resource: r}); err == nil {
+		return nil
+	}
+	return authorize(ctx, platform.Permission{Action: platform.ReadAction, Resource: r})
+}
+
+// addCreator makes the user of the authorizer of the context an owner of the shared resource id of
+// type r that it created, so that the user may write and delete it. Nothing is done if the authorizer
+// does not belong to a user.
+func addCreator(ctx context.Context, s platform.UserResourceMappingService, r platform.Resource, id platform.ID) error {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+	userID := a.GetUserID()
+	if !userID.Valid() {
+		return nil
+	}
+	return s.CreateUserResourceMapping(ctx, &platform.UserResourceMapping{
+		UserID:     userID,
+		UserType:   platform.Owner,
+		Resource:   r,
+		ResourceID: id,
+	})
+}
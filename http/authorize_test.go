@@ -0,0 +1,152 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/mock"
+	platformtesting "github.com/influxdata/platform/testing"
+)
+
+// withOperRequest returns r with an authorizer holding the operator permissions.
+func withOperRequest(r *http.Request) *http.Request {
+	return r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+		Status:      platform.Active,
+		Permissions: platform.OperPermissions(),
+	}))
+}
+
+// withOperHandler returns a handler serving requests with an authorizer holding the operator permissions.
+func withOperHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, withOperRequest(r))
+	})
+}
+
+func TestAuthorize(t *testing.T) {
+	orgID := platformtesting.MustIDBase16("020f755c3c082000")
+	macroID := platformtesting.MustIDBase16("020f755c3c082001")
+
+	tests := []struct {
+		name        string
+		permissions []platform.Permission
+		permission  platform.Permission
+		code        string
+	}{
+		{
+			name:        "allowed",
+			permissions: []platform.Permission{{Action: platform.ReadAction, Resource: platform.MacrosResource}},
+			permission:  platform.Permission{Action: platform.ReadAction, Resource: platform.MacrosResource, ID: &macroID},
+		},
+		{
+			name:        "delete requires the delete action",
+			permissions: []platform.Permission{{Action: platform.WriteAction, Resource: platform.MacrosResource}},
+			permission:  platform.Permission{Action: platform.DeleteAction, Resource: platform.MacrosResource, ID: &macroID},
+			code:        platform.EForbidden,
+		},
+		{
+			name:        "secrets in another organization",
+			permissions: platform.OrgAdminPermissions(macroID),
+			permission:  platform.Permission{Action: platform.ReadAction, Resource: platform.SecretsResource, OrgID: &orgID},
+			code:        platform.EForbidden,
+		},
+		{
+			name:        "labels of a resource in the organization",
+			permissions: ownerPermissions(t, orgID),
+			permission:  platform.Permission{Action: platform.WriteAction, Resource: platform.LabelsResource, OrgID: &orgID, ID: &macroID},
+		},
+		{
+			name:        "labels of a resource in another organization",
+			permissions: ownerPermissions(t, macroID),
+			permission:  platform.Permission{Action: platform.WriteAction, Resource: platform.LabelsResource, OrgID: &orgID, ID: &macroID},
+			code:        platform.EForbidden,
+		},
+		{
+			name:        "org owners read shared resources",
+			permissions: ownerPermissions(t, orgID),
+			permission:  platform.Permission{Action: platform.ReadAction, Resource: platform.MacrosResource, ID: &macroID},
+		},
+		{
+			name:        "org owners do not write shared resources they do not own",
+			permissions: ownerPermissions(t, orgID),
+			permission:  platform.Permission{Action: platform.WriteAction, Resource: platform.MacrosResource, ID: &macroID},
+			code:        platform.EForbidden,
+		},
+		{
+			name:        "shared resources are not in an organization",
+			permissions: platform.OperPermissions(),
+			permission:  platform.Permission{Action: platform.ReadAction, Resource: platform.MacrosResource, OrgID: &orgID},
+			code:        platform.EInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := pcontext.SetAuthorizer(context.Background(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: tt.permissions,
+			})
+			if code := platform.ErrorCode(authorize(ctx, tt.permission)); code != tt.code {
+				t.Fatalf("unexpected error code: got %q, want %q", code, tt.code)
+			}
+		})
+	}
+}
+
+// ownerPermissions returns the permissions of an owner of the organization orgID.
+func ownerPermissions(t *testing.T, orgID platform.ID) []platform.Permission {
+	t.Helper()
+	m := platform.UserResourceMapping{ResourceID: orgID, Resource: platform.OrgsResource, UserType: platform.Owner}
+	ps, err := m.ToPermissions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ps
+}
+
+func TestAddCreator(t *testing.T) {
+	userID := platformtesting.MustIDBase16("020f755c3c082000")
+	macroID := platformtesting.MustIDBase16("020f755c3c082001")
+
+	var mappings []*platform.UserResourceMapping
+	s := mock.NewUserResourceMappingService()
+	s.CreateMappingFn = func(_ context.Context, m *platform.UserResourceMapping) error {
+		mappings = append(mappings, m)
+		return nil
+	}
+
+	ctx := pcontext.SetAuthorizer(context.Background(), &platform.Authorization{
+		Status:      platform.Active,
+		UserID:      userID,
+		Permissions: ownerPermissions(t, userID),
+	})
+	if err := authorizeCreate(ctx, platform.MacrosResource); err != nil {
+		t.Fatal(err)
+	}
+	if err := addCreator(ctx, s, platform.MacrosResource, macroID); err != nil {
+		t.Fatal(err)
+	}
+	if len(mappings) != 1 {
+		t.Fatalf("expected 1 mapping, got %d", len(mappings))
+	}
+	ps, err := mappings[0].ToPermissions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !platform.PermissionAllowed(platform.Permission{Action: platform.DeleteAction, Resource: platform.MacrosResource, ID: &macroID}, ps) {
+		t.Fatal("expected the creator to be allowed to delete the macro")
+	}
+
+	// Operators do not belong to a user.
+	mappings = nil
+	if err := addCreator(withOperRequest(httptest.NewRequest("POST", "/", nil)).Context(), s, platform.MacrosResource, macroID); err != nil {
+		t.Fatal(err)
+	}
+	if len(mappings) != 0 {
+		t.Fatalf("expected no mappings, got %d", len(mappings))
+	}
+}
@@ -0,0 +1,188 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/backup"
+	pcontext "github.com/influxdata/platform/context"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// BackupHandler represents an HTTP API handler for backups.
+type BackupHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	BucketService    platform.BucketService
+	MetadataBackuper backup.MetadataBackuper
+	EngineBackuper   backup.EngineBackuper
+}
+
+const backupPath = "/api/v2/backup"
+
+// NewBackupHandler returns a new instance of BackupHandler.
+func NewBackupHandler() *BackupHandler {
+	h := &BackupHandler{
+		Router: NewRouter(),
+		Logger: zap.NewNop(),
+	}
+
+	h.HandlerFunc("GET", backupPath, h.handleGetBackup)
+	return h
+}
+
+// handleGetBackup is the HTTP handler for the GET /api/v2/backup route.
+// It streams an archive that can be restored with backup.Restore.
+func (h *BackupHandler) handleGetBackup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := authorizeBackup(ctx); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	filter, err := h.decodeGetBackupRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	snapshot, err := backup.Create(ctx, h.MetadataBackuper, h.EngineBackuper, filter)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	defer snapshot.Close()
+
+	name := fmt.Sprintf("influxd-%s.tar.gz", time.Now().UTC().Format("20060102T150405Z"))
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	w.WriteHeader(http.StatusOK)
+
+	// The status has been sent, so an error can only be logged. The client sees a truncated archive.
+	if err := snapshot.Write(w); err != nil {
+		h.Logger.Info("Failed to write backup", zap.Error(err))
+	}
+}
+
+// authorizeBackup returns an error unless the authorizer in ctx may read every resource.
+// A backup always contains all of the metadata, including authorizations, even when
+// its time series data is limited to one organization or bucket.
+func authorizeBackup(ctx context.Context) error {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range platform.AllResources {
+		p, err := platform.NewPermission(platform.ReadAction, r)
+		if err != nil {
+			return err
+		}
+		if !a.Allowed(*p) {
+			return &platform.Error{
+				Code: platform.EForbidden,
+				Op:   "http/authorizeBackup",
+				Msg:  "backups require permission to read all resources",
+			}
+		}
+	}
+	return nil
+}
+
+func (h *BackupHandler) decodeGetBackupRequest(ctx context.Context, r *http.Request) (backup.Filter, error) {
+	var filter backup.Filter
+	qp := r.URL.Query()
+
+	if s := qp.Get("orgID"); s != "" {
+		id, err := platform.IDFromString(s)
+		if err != nil {
+			return filter, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   "http/decodeGetBackupRequest",
+				Msg:  "invalid orgID",
+				Err:  err,
+			}
+		}
+		filter.OrganizationID = id
+	}
+
+	if s := qp.Get("bucketID"); s != "" {
+		id, err := platform.IDFromString(s)
+		if err != nil {
+			return filter, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   "http/decodeGetBackupRequest",
+				Msg:  "invalid bucketID",
+				Err:  err,
+			}
+		}
+
+		b, err := h.BucketService.FindBucketByID(ctx, *id)
+		if err != nil {
+			return filter, err
+		}
+		if filter.OrganizationID != nil && *filter.OrganizationID != b.OrganizationID {
+			return filter, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   "http/decodeGetBackupRequest",
+				Msg:  "bucket does not belong to the organization",
+			}
+		}
+		filter.OrganizationID = &b.OrganizationID
+		filter.BucketID = id
+	}
+
+	return filter, nil
+}
+
+// BackupService downloads backups over HTTP.
+type BackupService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// Backup writes an archive of the metadata and of the time series data selected by filter to w.
+func (s *BackupService) Backup(ctx context.Context, w io.Writer, filter backup.Filter) error {
+	u, err := newURL(s.Addr, backupPath)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	params := req.URL.Query()
+	if filter.OrganizationID != nil {
+		params.Set("orgID", filter.OrganizationID.String())
+	}
+	if filter.BucketID != nil {
+		params.Set("bucketID", filter.BucketID.String())
+	}
+	req.URL.RawQuery = params.Encode()
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/mock"
+)
+
+// metadataBackuperFunc adapts a function to a backup.MetadataBackuper.
+type metadataBackuperFunc func(context.Context, io.Writer) error
+
+func (f metadataBackuperFunc) Backup(ctx context.Context, w io.Writer) error {
+	return f(ctx, w)
+}
+
+// engineBackuperFunc adapts a function to a backup.EngineBackuper.
+type engineBackuperFunc func(orgID, bucketID *platform.ID) (string, error)
+
+func (f engineBackuperFunc) CreateBackup(orgID, bucketID *platform.ID) (string, error) {
+	return f(orgID, bucketID)
+}
+
+func TestBackupHandler_handleGetBackup(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+
+	tests := []struct {
+		name        string
+		query       string
+		permissions []platform.Permission
+		status      int
+		orgID       *platform.ID
+		files       []string
+	}{
+		{
+			name:        "all",
+			permissions: platform.OperPermissions(),
+			status:      http.StatusOK,
+			files:       []string{"manifest.json", "influxd.bolt", "engine/000000001-000000001.tsm"},
+		},
+		{
+			name:        "bucket",
+			query:       "?bucketID=" + bucketID.String(),
+			permissions: platform.OperPermissions(),
+			status:      http.StatusOK,
+			orgID:       &orgID,
+			files:       []string{"manifest.json", "influxd.bolt", "engine/000000001-000000001.tsm"},
+		},
+		{
+			name:        "org admin",
+			query:       "?orgID=" + orgID.String(),
+			permissions: platform.OrgAdminPermissions(orgID),
+			status:      http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewBackupHandler()
+			h.MetadataBackuper = metadataBackuperFunc(func(ctx context.Context, w io.Writer) error {
+				_, err := w.Write([]byte("bolt"))
+				return err
+			})
+			h.EngineBackuper = engineBackuperFunc(func(org, bucket *platform.ID) (string, error) {
+				if (org == nil) != (tt.orgID == nil) || (org != nil && *org != *tt.orgID) {
+					t.Errorf("unexpected organization %v", org)
+				}
+				dir, err := ioutil.TempDir("", "backup_service_test")
+				if err != nil {
+					return "", err
+				}
+				return dir, ioutil.WriteFile(filepath.Join(dir, "000000001-000000001.tsm"), []byte("tsm"), 0666)
+			})
+			bs := mock.NewBucketService()
+			bs.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+				return &platform.Bucket{ID: id, OrganizationID: orgID}, nil
+			}
+			h.BucketService = bs
+
+			r := httptest.NewRequest("GET", "/api/v2/backup"+tt.query, nil)
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: tt.permissions,
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got := w.Code; got != tt.status {
+				t.Fatalf("unexpected status: got %d, want %d; body: %s", got, tt.status, w.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			gr, err := gzip.NewReader(w.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var files []string
+			tr := tar.NewReader(gr)
+			for {
+				hdr, err := tr.Next()
+				if err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatal(err)
+				}
+				files = append(files, hdr.Name)
+			}
+			if len(files) != len(tt.files) {
+				t.Fatalf("unexpected files in archive: got %v, want %v", files, tt.files)
+			}
+			for i := range files {
+				if files[i] != tt.files[i] {
+					t.Fatalf("unexpected files in archive: got %v, want %v", files, tt.files)
+				}
+			}
+		})
+	}
+}
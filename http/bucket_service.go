@@ -24,21 +24,25 @@ type BucketHandler struct {
 
 	BucketService              platform.BucketService
 	BucketOperationLogService  platform.BucketOperationLogService
+	BucketTransferService      platform.BucketTransferService
 	UserResourceMappingService platform.UserResourceMappingService
 	LabelService               platform.LabelService
 	UserService                platform.UserService
 }
 
 const (
-	bucketsPath             = "/api/v2/buckets"
-	bucketsIDPath           = "/api/v2/buckets/:id"
-	bucketsIDLogPath        = "/api/v2/buckets/:id/log"
-	bucketsIDMembersPath    = "/api/v2/buckets/:id/members"
-	bucketsIDMembersIDPath  = "/api/v2/buckets/:id/members/:userID"
-	bucketsIDOwnersPath     = "/api/v2/buckets/:id/owners"
-	bucketsIDOwnersIDPath   = "/api/v2/buckets/:id/owners/:userID"
-	bucketsIDLabelsPath     = "/api/v2/buckets/:id/labels"
-	bucketsIDLabelsNamePath = "/api/v2/buckets/:id/labels/:name"
+	bucketsPath              = "/api/v2/buckets"
+	bucketsIDPath            = "/api/v2/buckets/:id"
+	bucketsIDLogPath         = "/api/v2/buckets/:id/log"
+	bucketsIDMembersPath     = "/api/v2/buckets/:id/members"
+	bucketsIDMembersIDPath   = "/api/v2/buckets/:id/members/:userID"
+	bucketsIDOwnersPath      = "/api/v2/buckets/:id/owners"
+	bucketsIDOwnersIDPath    = "/api/v2/buckets/:id/owners/:userID"
+	bucketsIDLabelsPath      = "/api/v2/buckets/:id/labels"
+	bucketsIDLabelsNamePath  = "/api/v2/buckets/:id/labels/:name"
+	bucketsIDClonePath       = "/api/v2/buckets/:id/clone"
+	bucketsIDMovePath        = "/api/v2/buckets/:id/move"
+	bucketsIDTransfersIDPath = "/api/v2/buckets/:id/transfers/:transferID"
 )
 
 // NewBucketHandler returns a new instance of BucketHandler.
@@ -57,6 +61,9 @@ func NewBucketHandler(mappingService platform.UserResourceMappingService, labelS
 	h.HandlerFunc("GET", bucketsIDLogPath, h.handleGetBucketLog)
 	h.HandlerFunc("PATCH", bucketsIDPath, h.handlePatchBucket)
 	h.HandlerFunc("DELETE", bucketsIDPath, h.handleDeleteBucket)
+	h.HandlerFunc("POST", bucketsIDClonePath, h.handlePostBucketClone)
+	h.HandlerFunc("POST", bucketsIDMovePath, h.handlePostBucketMove)
+	h.HandlerFunc("GET", bucketsIDTransfersIDPath, h.handleGetBucketTransfer)
 
 	h.HandlerFunc("POST", bucketsIDMembersPath, newPostMemberHandler(h.UserResourceMappingService, h.UserService, platform.BucketsResource, platform.Member))
 	h.HandlerFunc("GET", bucketsIDMembersPath, newGetMembersHandler(h.UserResourceMappingService, h.UserService, platform.BucketsResource, platform.Member))
@@ -66,14 +73,24 @@ func NewBucketHandler(mappingService platform.UserResourceMappingService, labelS
 	h.HandlerFunc("GET", bucketsIDOwnersPath, newGetMembersHandler(h.UserResourceMappingService, h.UserService, platform.BucketsResource, platform.Owner))
 	h.HandlerFunc("DELETE", bucketsIDOwnersIDPath, newDeleteMemberHandler(h.UserResourceMappingService, platform.Owner))
 
-	h.HandlerFunc("GET", bucketsIDLabelsPath, newGetLabelsHandler(h.LabelService))
-	h.HandlerFunc("POST", bucketsIDLabelsPath, newPostLabelHandler(h.LabelService))
-	h.HandlerFunc("DELETE", bucketsIDLabelsNamePath, newDeleteLabelHandler(h.LabelService))
-	h.HandlerFunc("PATCH", bucketsIDLabelsNamePath, newPatchLabelHandler(h.LabelService))
+	authorizeLabels := orgLabelAuthorizer(h.bucketOrg)
+	h.HandlerFunc("GET", bucketsIDLabelsPath, newGetLabelsHandler(h.LabelService, authorizeLabels))
+	h.HandlerFunc("POST", bucketsIDLabelsPath, newPostLabelHandler(h.LabelService, authorizeLabels))
+	h.HandlerFunc("DELETE", bucketsIDLabelsNamePath, newDeleteLabelHandler(h.LabelService, authorizeLabels))
+	h.HandlerFunc("PATCH", bucketsIDLabelsNamePath, newPatchLabelHandler(h.LabelService, authorizeLabels))
 
 	return h
 }
 
+// bucketOrg returns the organization of the bucket id, whose labels are scoped by it.
+func (h *BucketHandler) bucketOrg(ctx context.Context, id platform.ID) (platform.ID, error) {
+	b, err := h.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return b.OrganizationID, nil
+}
+
 // bucket is used for serialization/deserialization with duration string syntax.
 type bucket struct {
 	ID                  platform.ID     `json:"id,omitempty"`
@@ -82,6 +99,17 @@ type bucket struct {
 	Name                string          `json:"name"`
 	RetentionPolicyName string          `json:"rp,omitempty"` // This to support v1 sources
 	RetentionRules      []retentionRule `json:"retentionRules"`
+
+	// ShardGroupDurationSeconds of zero uses the default shard group duration of the retention period.
+	ShardGroupDurationSeconds int64 `json:"shardGroupDurationSeconds,omitempty"`
+
+	// ColdStorageAfterSeconds of zero keeps all data of the bucket in local storage.
+	ColdStorageAfterSeconds int64 `json:"coldStorageAfterSeconds,omitempty"`
+
+	SchemaType         platform.SchemaType          `json:"schemaType,omitempty"`
+	MeasurementSchemas []platform.MeasurementSchema `json:"measurementSchemas,omitempty"`
+
+	DownsampleTiers []downsampleTier `json:"downsampleTiers,omitempty"`
 }
 
 // retentionRule is the retention rule action for a bucket.
@@ -90,6 +118,57 @@ type retentionRule struct {
 	EverySeconds int64  `json:"everySeconds"`
 }
 
+// downsampleTier is a downsample tier of a bucket, with its durations in seconds.
+// A RetentionSeconds of zero keeps the tier's data forever.
+type downsampleTier struct {
+	EverySeconds     int64       `json:"everySeconds"`
+	Aggregates       []string    `json:"aggregates"`
+	RetentionSeconds int64       `json:"retentionSeconds"`
+	OffsetSeconds    int64       `json:"offsetSeconds,omitempty"`
+	Fields           []string    `json:"fields,omitempty"`
+	BucketID         platform.ID `json:"bucketID,omitempty"`
+	TaskID           platform.ID `json:"taskID,omitempty"`
+}
+
+// toPlatformDownsampleTiers converts tiers, keeping a nil slice nil so that an update can leave the tiers unchanged.
+func toPlatformDownsampleTiers(tiers []downsampleTier) []platform.DownsampleTier {
+	if tiers == nil {
+		return nil
+	}
+	pts := make([]platform.DownsampleTier, 0, len(tiers))
+	for _, t := range tiers {
+		pts = append(pts, platform.DownsampleTier{
+			Every:           time.Duration(t.EverySeconds) * time.Second,
+			Aggregates:      t.Aggregates,
+			RetentionPeriod: time.Duration(t.RetentionSeconds) * time.Second,
+			Offset:          time.Duration(t.OffsetSeconds) * time.Second,
+			Fields:          t.Fields,
+			BucketID:        t.BucketID,
+			TaskID:          t.TaskID,
+		})
+	}
+	return pts
+}
+
+func newDownsampleTiers(pts []platform.DownsampleTier) []downsampleTier {
+	if pts == nil {
+		return nil
+	}
+	tiers := make([]downsampleTier, 0, len(pts))
+	for _, t := range pts {
+		tiers = append(tiers, downsampleTier{
+			EverySeconds:     int64(t.Every.Round(time.Second) / time.Second),
+			Aggregates:       t.Aggregates,
+			RetentionSeconds: int64(t.RetentionPeriod.Round(time.Second) / time.Second),
+			OffsetSeconds:    int64(t.Offset.Round(time.Second) / time.Second),
+			Fields:           t.Fields,
+			BucketID:         t.BucketID,
+			TaskID:           t.TaskID,
+		})
+	}
+	return tiers
+}
+
 func (b *bucket) toPlatform() (*platform.Bucket, error) {
 	if b == nil {
 		return nil, nil
@@ -112,6 +191,11 @@ func (b *bucket) toPlatform() (*platform.Bucket, error) {
 		Name:                b.Name,
 		RetentionPolicyName: b.RetentionPolicyName,
 		RetentionPeriod:     d,
+		ShardGroupDuration:  time.Duration(b.ShardGroupDurationSeconds) * time.Second,
+		ColdStorageAfter:    time.Duration(b.ColdStorageAfterSeconds) * time.Second,
+		SchemaType:          b.SchemaType,
+		MeasurementSchemas:  b.MeasurementSchemas,
+		DownsampleTiers:     toPlatformDownsampleTiers(b.DownsampleTiers),
 	}, nil
 }
 
@@ -130,19 +214,35 @@ func newBucket(pb *platform.Bucket) *bucket {
 	}
 
 	return &bucket{
-		ID:                  pb.ID,
-		OrganizationID:      pb.OrganizationID,
-		Organization:        pb.Organization,
-		Name:                pb.Name,
-		RetentionPolicyName: pb.RetentionPolicyName,
-		RetentionRules:      rules,
+		ID:                        pb.ID,
+		OrganizationID:            pb.OrganizationID,
+		Organization:              pb.Organization,
+		Name:                      pb.Name,
+		RetentionPolicyName:       pb.RetentionPolicyName,
+		RetentionRules:            rules,
+		ShardGroupDurationSeconds: int64(pb.ShardGroupDuration.Round(time.Second) / time.Second),
+		ColdStorageAfterSeconds:   int64(pb.ColdStorageAfter.Round(time.Second) / time.Second),
+		SchemaType:                pb.SchemaType,
+		MeasurementSchemas:        pb.MeasurementSchemas,
+		DownsampleTiers:           newDownsampleTiers(pb.DownsampleTiers),
 	}
 }
 
 // bucketUpdate is used for serialization/deserialization with retention rules.
+// It cannot change the organization of a bucket, which is moved along with its data by
+// a POST to the move endpoint of the bucket.
 type bucketUpdate struct {
 	Name           *string         `json:"name,omitempty"`
 	RetentionRules []retentionRule `json:"retentionRules,omitempty"`
+
+	ShardGroupDurationSeconds *int64 `json:"shardGroupDurationSeconds,omitempty"`
+	ColdStorageAfterSeconds   *int64 `json:"coldStorageAfterSeconds,omitempty"`
+
+	SchemaType *platform.SchemaType `json:"schemaType,omitempty"`
+	// MeasurementSchemas is not omitted when empty, so that an empty list can clear the bucket's schemas.
+	MeasurementSchemas []platform.MeasurementSchema `json:"measurementSchemas"`
+	// DownsampleTiers is not omitted when empty, so that an empty list can remove the bucket's tiers.
+	DownsampleTiers []downsampleTier `json:"downsampleTiers"`
 }
 
 func (b *bucketUpdate) toPlatform() (*platform.BucketUpdate, error) {
@@ -159,9 +259,26 @@ func (b *bucketUpdate) toPlatform() (*platform.BucketUpdate, error) {
 		}
 	}
 
+	var sgd *time.Duration
+	if b.ShardGroupDurationSeconds != nil {
+		v := time.Duration(*b.ShardGroupDurationSeconds) * time.Second
+		sgd = &v
+	}
+
+	var csa *time.Duration
+	if b.ColdStorageAfterSeconds != nil {
+		v := time.Duration(*b.ColdStorageAfterSeconds) * time.Second
+		csa = &v
+	}
+
 	return &platform.BucketUpdate{
-		Name:            b.Name,
-		RetentionPeriod: &d,
+		Name:               b.Name,
+		RetentionPeriod:    &d,
+		ShardGroupDuration: sgd,
+		ColdStorageAfter:   csa,
+		SchemaType:         b.SchemaType,
+		MeasurementSchemas: b.MeasurementSchemas,
+		DownsampleTiers:    toPlatformDownsampleTiers(b.DownsampleTiers),
 	}, nil
 }
 
@@ -171,8 +288,21 @@ func newBucketUpdate(pb *platform.BucketUpdate) *bucketUpdate {
 	}
 
 	up := &bucketUpdate{
-		Name:           pb.Name,
-		RetentionRules: []retentionRule{},
+		Name:               pb.Name,
+		RetentionRules:     []retentionRule{},
+		SchemaType:         pb.SchemaType,
+		MeasurementSchemas: pb.MeasurementSchemas,
+		DownsampleTiers:    newDownsampleTiers(pb.DownsampleTiers),
+	}
+
+	if pb.ShardGroupDuration != nil {
+		d := int64((*pb.ShardGroupDuration).Round(time.Second) / time.Second)
+		up.ShardGroupDurationSeconds = &d
+	}
+
+	if pb.ColdStorageAfter != nil {
+		d := int64((*pb.ColdStorageAfter).Round(time.Second) / time.Second)
+		up.ColdStorageAfterSeconds = &d
 	}
 
 	if pb.RetentionPeriod != nil {
@@ -256,7 +386,16 @@ func (b postBucketRequest) Validate() error {
 	if b.Bucket.Organization == "" && !b.Bucket.OrganizationID.Valid() {
 		return fmt.Errorf("bucket requires an organization")
 	}
-	return nil
+	if err := platform.ValidateShardGroupDuration(b.Bucket.ShardGroupDuration); err != nil {
+		return err
+	}
+	if err := platform.ValidateColdStorageAfter(b.Bucket.ColdStorageAfter); err != nil {
+		return err
+	}
+	if err := b.Bucket.ValidateSchema(); err != nil {
+		return err
+	}
+	return platform.ValidateDownsampleTiers(b.Bucket.DownsampleTiers)
 }
 
 func decodePostBucketRequest(ctx context.Context, r *http.Request) (*postBucketRequest, error) {
@@ -479,6 +618,28 @@ func decodePatchBucketRequest(ctx context.Context, r *http.Request) (*patchBucke
 		return nil, err
 	}
 
+	if upd.ShardGroupDuration != nil {
+		if err := platform.ValidateShardGroupDuration(*upd.ShardGroupDuration); err != nil {
+			return nil, err
+		}
+	}
+	if upd.ColdStorageAfter != nil {
+		if err := platform.ValidateColdStorageAfter(*upd.ColdStorageAfter); err != nil {
+			return nil, err
+		}
+	}
+	if upd.SchemaType != nil {
+		if err := upd.SchemaType.Valid(); err != nil {
+			return nil, err
+		}
+	}
+	if err := platform.ValidateMeasurementSchemas(upd.MeasurementSchemas); err != nil {
+		return nil, err
+	}
+	if err := platform.ValidateDownsampleTiers(upd.DownsampleTiers); err != nil {
+		return nil, err
+	}
+
 	return &patchBucketRequest{
 		Update:   *upd,
 		BucketID: i,
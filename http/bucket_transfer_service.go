@@ -0,0 +1,289 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+	"github.com/julienschmidt/httprouter"
+)
+
+type bucketTransferLinks struct {
+	Self   string `json:"self"`
+	Bucket string `json:"bucket"`
+}
+
+type bucketTransferResponse struct {
+	Links bucketTransferLinks `json:"links"`
+	platform.BucketTransfer
+}
+
+func newBucketTransferResponse(t *platform.BucketTransfer) *bucketTransferResponse {
+	return &bucketTransferResponse{
+		Links: bucketTransferLinks{
+			Self:   fmt.Sprintf("/api/v2/buckets/%s/transfers/%s", t.BucketID, t.ID),
+			Bucket: fmt.Sprintf("/api/v2/buckets/%s", t.DestinationBucketID),
+		},
+		BucketTransfer: *t,
+	}
+}
+
+// authorizeBucketTransfer returns an error unless the authorizer of the context is allowed the
+// action on the bucket b, and, if destOrgID is valid, to write the buckets of that organization.
+func authorizeBucketTransfer(ctx context.Context, b *platform.Bucket, action platform.Action, destOrgID platform.ID) error {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+
+	ps := make([]platform.Permission, 0, 2)
+	p, err := platform.NewPermissionAtIDInOrg(b.OrganizationID, b.ID, action, platform.BucketsResource)
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	ps = append(ps, *p)
+	if destOrgID.Valid() {
+		p, err := platform.NewPermissionInOrg(destOrgID, platform.WriteAction, platform.BucketsResource)
+		if err != nil {
+			return &platform.Error{
+				Code: platform.EInvalid,
+				Err:  err,
+			}
+		}
+		ps = append(ps, *p)
+	}
+
+	for _, p := range ps {
+		if !a.Allowed(p) {
+			return &platform.Error{
+				Code: platform.EForbidden,
+				Msg:  fmt.Sprintf("insufficient permissions for bucket transfer: %s", p),
+			}
+		}
+	}
+	return nil
+}
+
+// handlePostBucketClone is the HTTP handler for the POST /api/v2/buckets/:id/clone route.
+// Cloning a bucket reads the bucket, and writes the buckets of the organization of the clone.
+func (h *BucketHandler) handlePostBucketClone(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeBucketTransferBucketID(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	var clone platform.BucketClone
+	if err := json.NewDecoder(r.Body).Decode(&clone); err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid bucket clone",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	b, err := h.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	destOrgID := clone.OrganizationID
+	if !destOrgID.Valid() {
+		destOrgID = b.OrganizationID
+	}
+	if err := authorizeBucketTransfer(ctx, b, platform.ReadAction, destOrgID); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	t, err := h.BucketTransferService.CloneBucket(ctx, id, clone)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusAccepted, newBucketTransferResponse(t)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePostBucketMove is the HTTP handler for the POST /api/v2/buckets/:id/move route.
+// Moving a bucket writes the bucket, and the buckets of the organization it is moved to.
+func (h *BucketHandler) handlePostBucketMove(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeBucketTransferBucketID(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	var move platform.BucketMove
+	if err := json.NewDecoder(r.Body).Decode(&move); err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid bucket move",
+			Err:  err,
+		}, w)
+		return
+	}
+	if !move.OrganizationID.Valid() {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "organization ID is required",
+		}, w)
+		return
+	}
+	b, err := h.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	if err := authorizeBucketTransfer(ctx, b, platform.WriteAction, move.OrganizationID); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	t, err := h.BucketTransferService.MoveBucket(ctx, id, move)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusAccepted, newBucketTransferResponse(t)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetBucketTransfer is the HTTP handler for the GET /api/v2/buckets/:id/transfers/:transferID route.
+func (h *BucketHandler) handleGetBucketTransfer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := decodeBucketTransferBucketID(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	var transferID platform.ID
+	if err := transferID.DecodeFromString(httprouter.ParamsFromContext(ctx).ByName("transferID")); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	b, err := h.BucketService.FindBucketByID(ctx, id)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	if err := authorizeBucketTransfer(ctx, b, platform.ReadAction, 0); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	t, err := h.BucketTransferService.FindBucketTransferByID(ctx, id, transferID)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newBucketTransferResponse(t)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func decodeBucketTransferBucketID(ctx context.Context) (platform.ID, error) {
+	var id platform.ID
+	if err := id.DecodeFromString(httprouter.ParamsFromContext(ctx).ByName("id")); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// BucketTransferService connects to Influx via HTTP using tokens to clone and move buckets.
+type BucketTransferService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.BucketTransferService = (*BucketTransferService)(nil)
+
+// CloneBucket creates a copy of the bucket id, and starts copying its data into the copy.
+func (s *BucketTransferService) CloneBucket(ctx context.Context, id platform.ID, clone platform.BucketClone) (*platform.BucketTransfer, error) {
+	return s.post(path.Join(bucketIDPath(id), "clone"), clone)
+}
+
+// MoveBucket moves the bucket id into another organization, and starts moving its data.
+func (s *BucketTransferService) MoveBucket(ctx context.Context, id platform.ID, move platform.BucketMove) (*platform.BucketTransfer, error) {
+	return s.post(path.Join(bucketIDPath(id), "move"), move)
+}
+
+func (s *BucketTransferService) post(p string, v interface{}) (*platform.BucketTransfer, error) {
+	u, err := newURL(s.Addr, p)
+	if err != nil {
+		return nil, err
+	}
+
+	octets, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	return s.do(req)
+}
+
+// FindBucketTransferByID returns the progress of the transfer id of the bucket bucketID.
+func (s *BucketTransferService) FindBucketTransferByID(ctx context.Context, bucketID, id platform.ID) (*platform.BucketTransfer, error) {
+	u, err := newURL(s.Addr, path.Join(bucketIDPath(bucketID), "transfers", id.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	return s.do(req)
+}
+
+func (s *BucketTransferService) do(req *http.Request) (*platform.BucketTransfer, error) {
+	hc := newClient(req.URL.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var t bucketTransferResponse
+	if err := json.NewDecoder(resp.Body).Decode(&t); err != nil {
+		return nil, err
+	}
+	return &t.BucketTransfer, nil
+}
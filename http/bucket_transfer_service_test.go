@@ -0,0 +1,174 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/mock"
+)
+
+// bucketTransfers is a platform.BucketTransferService recording the transfers it starts.
+type bucketTransfers struct {
+	started []*platform.BucketTransfer
+}
+
+func (s *bucketTransfers) CloneBucket(ctx context.Context, id platform.ID, clone platform.BucketClone) (*platform.BucketTransfer, error) {
+	t := &platform.BucketTransfer{ID: 100, Type: platform.BucketTransferClone, BucketID: id, DestinationOrganizationID: clone.OrganizationID, Status: platform.BucketTransferRunning}
+	s.started = append(s.started, t)
+	return t, nil
+}
+
+func (s *bucketTransfers) MoveBucket(ctx context.Context, id platform.ID, move platform.BucketMove) (*platform.BucketTransfer, error) {
+	t := &platform.BucketTransfer{ID: 100, Type: platform.BucketTransferMove, BucketID: id, DestinationOrganizationID: move.OrganizationID, Status: platform.BucketTransferRunning}
+	s.started = append(s.started, t)
+	return t, nil
+}
+
+func (s *bucketTransfers) FindBucketTransferByID(ctx context.Context, bucketID, id platform.ID) (*platform.BucketTransfer, error) {
+	for _, t := range s.started {
+		if t.ID == id && t.BucketID == bucketID {
+			return t, nil
+		}
+	}
+	return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrBucketTransferNotFound}
+}
+
+func TestBucketHandler_BucketTransfers(t *testing.T) {
+	orgID, otherOrgID, bucketID, otherBucketID := platform.ID(1), platform.ID(2), platform.ID(3), platform.ID(4)
+
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		body        string
+		permissions []platform.Permission
+		status      int
+	}{
+		{
+			name:   "clone into same organization",
+			method: "POST",
+			path:   "/api/v2/buckets/0000000000000003/clone",
+			body:   `{"name": "copy"}`,
+			permissions: []platform.Permission{
+				{Action: platform.ReadAction, Resource: platform.BucketsResource, ID: &bucketID},
+				{Action: platform.WriteAction, Resource: platform.BucketsResource, OrgID: &orgID},
+			},
+			status: http.StatusAccepted,
+		},
+		{
+			name:   "clone with permissions on the organization",
+			method: "POST",
+			path:   "/api/v2/buckets/0000000000000003/clone",
+			body:   `{"name": "copy"}`,
+			permissions: []platform.Permission{
+				{Action: platform.ReadAction, Resource: platform.BucketsResource, OrgID: &orgID},
+				{Action: platform.WriteAction, Resource: platform.BucketsResource, OrgID: &orgID},
+			},
+			status: http.StatusAccepted,
+		},
+		{
+			name:   "clone with permissions on another organization",
+			method: "POST",
+			path:   "/api/v2/buckets/0000000000000003/clone",
+			body:   `{"name": "copy"}`,
+			permissions: []platform.Permission{
+				{Action: platform.ReadAction, Resource: platform.BucketsResource, OrgID: &otherOrgID},
+				{Action: platform.WriteAction, Resource: platform.BucketsResource, OrgID: &orgID},
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "clone without permission to create buckets",
+			method: "POST",
+			path:   "/api/v2/buckets/0000000000000003/clone",
+			body:   `{"orgID": "0000000000000002"}`,
+			permissions: []platform.Permission{
+				{Action: platform.ReadAction, Resource: platform.BucketsResource, ID: &bucketID},
+				{Action: platform.WriteAction, Resource: platform.BucketsResource, OrgID: &orgID},
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "move",
+			method: "POST",
+			path:   "/api/v2/buckets/0000000000000003/move",
+			body:   `{"orgID": "0000000000000002"}`,
+			permissions: []platform.Permission{
+				{Action: platform.WriteAction, Resource: platform.BucketsResource, ID: &bucketID},
+				{Action: platform.WriteAction, Resource: platform.BucketsResource, OrgID: &otherOrgID},
+			},
+			status: http.StatusAccepted,
+		},
+		{
+			name:   "move with read permission",
+			method: "POST",
+			path:   "/api/v2/buckets/0000000000000003/move",
+			body:   `{"orgID": "0000000000000002"}`,
+			permissions: []platform.Permission{
+				{Action: platform.ReadAction, Resource: platform.BucketsResource, ID: &bucketID},
+				{Action: platform.WriteAction, Resource: platform.BucketsResource, OrgID: &otherOrgID},
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "move without organization",
+			method: "POST",
+			path:   "/api/v2/buckets/0000000000000003/move",
+			body:   `{}`,
+			permissions: []platform.Permission{
+				{Action: platform.WriteAction, Resource: platform.BucketsResource, ID: &bucketID},
+			},
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "get transfer",
+			method: "GET",
+			path:   "/api/v2/buckets/0000000000000003/transfers/0000000000000100",
+			permissions: []platform.Permission{
+				{Action: platform.ReadAction, Resource: platform.BucketsResource, ID: &bucketID},
+			},
+			status: http.StatusOK,
+		},
+		{
+			name:   "get transfer of other bucket",
+			method: "GET",
+			path:   "/api/v2/buckets/0000000000000004/transfers/0000000000000100",
+			permissions: []platform.Permission{
+				{Action: platform.ReadAction, Resource: platform.BucketsResource, ID: &otherBucketID},
+			},
+			status: http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfers := &bucketTransfers{started: []*platform.BucketTransfer{{ID: 0x100, BucketID: bucketID}}}
+			h := NewBucketHandler(mock.NewUserResourceMappingService(), mock.NewLabelService(), mock.NewUserService())
+			bs := mock.NewBucketService()
+			bs.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+				return &platform.Bucket{ID: id, OrganizationID: orgID}, nil
+			}
+			h.BucketService = bs
+			h.BucketTransferService = transfers
+
+			r := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: tt.permissions,
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got := w.Code; got != tt.status {
+				t.Fatalf("unexpected status: got %d, want %d; body: %s", got, tt.status, w.Body.String())
+			}
+			if want := tt.method == "POST" && tt.status == http.StatusAccepted; want != (len(transfers.started) == 2) {
+				t.Fatalf("unexpected transfers started: %v", transfers.started[1:])
+			}
+		})
+	}
+}
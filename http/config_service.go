@@ -0,0 +1,149 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/storage"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// ConfigHandler is the HTTP handler of the runtime configuration of the server.
+type ConfigHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	EngineMode storage.ModeSetter
+}
+
+const configPath = "/api/v2/config"
+
+// NewConfigHandler returns a new instance of ConfigHandler.
+func NewConfigHandler() *ConfigHandler {
+	h := &ConfigHandler{
+		Router: NewRouter(),
+		Logger: zap.NewNop(),
+	}
+
+	h.HandlerFunc("GET", configPath, h.handleGetConfig)
+	h.HandlerFunc("PATCH", configPath, h.handlePatchConfig)
+	return h
+}
+
+type storageConfig struct {
+	Mode storage.Mode `json:"mode,omitempty"`
+}
+
+type configResponse struct {
+	Storage storageConfig `json:"storage"`
+}
+
+type configUpdate struct {
+	Storage *storageConfig `json:"storage,omitempty"`
+}
+
+// authorizeConfig returns an error unless the authorizer in ctx is allowed the action on
+// every resource. The configuration applies to the whole server.
+func authorizeConfig(ctx context.Context, action platform.Action) error {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range platform.AllResources {
+		p, err := platform.NewPermission(action, r)
+		if err != nil {
+			return err
+		}
+		if !a.Allowed(*p) {
+			return &platform.Error{
+				Code: platform.EForbidden,
+				Op:   "http/authorizeConfig",
+				Msg:  "the configuration requires permission to " + string(action) + " all resources",
+			}
+		}
+	}
+	return nil
+}
+
+// handleGetConfig is the HTTP handler for the GET /api/v2/config route.
+func (h *ConfigHandler) handleGetConfig(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := authorizeConfig(ctx, platform.ReadAction); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, h.config()); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchConfig is the HTTP handler for the PATCH /api/v2/config route.
+func (h *ConfigHandler) handlePatchConfig(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	if err := authorizeConfig(ctx, platform.WriteAction); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	var upd configUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/handlePatchConfig",
+			Msg:  "invalid configuration update",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	if upd.Storage != nil && upd.Storage.Mode != "" {
+		if _, err := storage.ParseMode(string(upd.Storage.Mode)); err != nil {
+			EncodeError(ctx, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   "http/handlePatchConfig",
+				Msg:  err.Error(),
+			}, w)
+			return
+		}
+		if err := h.EngineMode.SetMode(upd.Storage.Mode); err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, h.config()); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+func (h *ConfigHandler) config() *configResponse {
+	return &configResponse{
+		Storage: storageConfig{
+			Mode: h.EngineMode.Mode(),
+		},
+	}
+}
+
+// engineModeError returns the error of an operation op rejected by the mode of the storage
+// engine, or nil if err is not such an error.
+func engineModeError(op string, err error) error {
+	if err != storage.ErrEngineReadOnly && err != storage.ErrEngineMaintenance {
+		return nil
+	}
+	return &platform.Error{
+		Code: platform.EUnavailable,
+		Op:   op,
+		Msg:  err.Error(),
+	}
+}
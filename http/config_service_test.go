@@ -0,0 +1,111 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/storage"
+)
+
+// engineMode is a storage.ModeSetter recording the mode it is set to.
+type engineMode struct {
+	mode storage.Mode
+}
+
+func (e *engineMode) Mode() storage.Mode { return e.mode }
+
+func (e *engineMode) SetMode(m storage.Mode) error {
+	e.mode = m
+	return nil
+}
+
+func TestConfigHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		body        string
+		permissions []platform.Permission
+		status      int
+		mode        storage.Mode
+	}{
+		{
+			name:        "get",
+			method:      "GET",
+			permissions: platform.OperPermissions(),
+			status:      http.StatusOK,
+			mode:        storage.ModeReadWrite,
+		},
+		{
+			name:        "set read-only",
+			method:      "PATCH",
+			body:        `{"storage": {"mode": "read-only"}}`,
+			permissions: platform.OperPermissions(),
+			status:      http.StatusOK,
+			mode:        storage.ModeReadOnly,
+		},
+		{
+			name:        "set invalid mode",
+			method:      "PATCH",
+			body:        `{"storage": {"mode": "off"}}`,
+			permissions: platform.OperPermissions(),
+			status:      http.StatusBadRequest,
+			mode:        storage.ModeReadWrite,
+		},
+		{
+			name:   "set without permission",
+			method: "PATCH",
+			body:   `{"storage": {"mode": "maintenance"}}`,
+			permissions: []platform.Permission{
+				{Action: platform.WriteAction, Resource: platform.BucketsResource},
+			},
+			status: http.StatusForbidden,
+			mode:   storage.ModeReadWrite,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mode := &engineMode{mode: storage.ModeReadWrite}
+			h := NewConfigHandler()
+			h.EngineMode = mode
+
+			r := httptest.NewRequest(tt.method, "/api/v2/config", strings.NewReader(tt.body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: tt.permissions,
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got := w.Code; got != tt.status {
+				t.Fatalf("unexpected status: got %d, want %d; body: %s", got, tt.status, w.Body.String())
+			}
+			if mode.mode != tt.mode {
+				t.Fatalf("unexpected mode: got %s, want %s", mode.mode, tt.mode)
+			}
+		})
+	}
+}
+
+func TestReadyHandler_EngineMode(t *testing.T) {
+	tests := []struct {
+		mode   storage.Mode
+		status int
+	}{
+		{mode: storage.ModeReadWrite, status: http.StatusOK},
+		{mode: storage.ModeReadOnly, status: http.StatusOK},
+		{mode: storage.ModeMaintenance, status: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			w := httptest.NewRecorder()
+			NewReadyHandler(&engineMode{mode: tt.mode}).ServeHTTP(w, httptest.NewRequest("GET", "/ready", nil))
+			if got := w.Code; got != tt.status {
+				t.Fatalf("unexpected status: got %d, want %d", got, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/influxdata/influxql"
+	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/kit/errors"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/storage/reads"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// DeleteHandler deletes the data of a bucket within a time range, optionally only from the series matching a predicate.
+type DeleteHandler struct {
+	*httprouter.Router
+
+	Logger *zap.Logger
+
+	BucketService       platform.BucketService
+	OrganizationService platform.OrganizationService
+
+	Deleter storage.PredicateDeleter
+}
+
+const deletePath = "/api/v2/delete"
+
+// NewDeleteHandler creates a new handler at /api/v2/delete to delete data.
+func NewDeleteHandler(deleter storage.PredicateDeleter) *DeleteHandler {
+	h := &DeleteHandler{
+		Router:  NewRouter(),
+		Logger:  zap.NewNop(),
+		Deleter: deleter,
+	}
+
+	h.HandlerFunc("POST", deletePath, h.handleDelete)
+	return h
+}
+
+func (h *DeleteHandler) handleDelete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	req, err := decodeDeleteRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	logger := h.Logger.With(zap.String("org", req.Org), zap.String("bucket", req.Bucket))
+
+	org, bucket, err := findOrganizationAndBucket(ctx, logger, h.OrganizationService, h.BucketService, req.Org, req.Bucket)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	p, err := platform.NewPermissionAtIDInOrg(org.ID, bucket.ID, platform.DeleteAction, platform.BucketsResource)
+	if err != nil {
+		EncodeError(ctx, fmt.Errorf("could not create permission for bucket: %v", err), w)
+		return
+	}
+
+	if !a.Allowed(*p) {
+		EncodeError(ctx, errors.Forbiddenf("insufficient permissions for delete"), w)
+		return
+	}
+
+	// The stop time is exclusive, as in the range of a query.
+	min, max := req.Start.UnixNano(), req.Stop.UnixNano()-1
+	if err := h.Deleter.DeleteBucketRangePredicate(org.ID, bucket.ID, min, max, req.Predicate); err != nil {
+		if err := engineModeError("http/handleDelete", err); err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+		logger.Info("Error deleting data", zap.Error(err))
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInternal,
+			Op:   "http/handleDelete",
+			Msg:  "unable to delete data",
+			Err:  err,
+		}, w)
+		return
+	}
+
+	logger.Info("Deleted data", zap.Time("start", req.Start), zap.Time("stop", req.Stop), zap.String("predicate", req.RawPredicate))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// deleteRequestBody is the body of a request to delete data.
+type deleteRequestBody struct {
+	Start     time.Time `json:"start"`
+	Stop      time.Time `json:"stop"`
+	Predicate string    `json:"predicate,omitempty"`
+}
+
+type deleteRequest struct {
+	Org       string
+	Bucket    string
+	Start     time.Time
+	Stop      time.Time
+	Predicate influxql.Expr // nil matches every series.
+
+	RawPredicate string // Predicate as it was sent.
+}
+
+func decodeDeleteRequest(ctx context.Context, r *http.Request) (*deleteRequest, error) {
+	var body deleteRequestBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeDeleteRequest",
+			Msg:  "unable to decode delete request",
+			Err:  err,
+		}
+	}
+
+	if body.Start.IsZero() || body.Stop.IsZero() {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeDeleteRequest",
+			Msg:  "start and stop are required",
+		}
+	}
+	if !body.Start.Before(body.Stop) {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeDeleteRequest",
+			Msg:  "start must be before stop",
+		}
+	}
+
+	pred, err := parseDeletePredicate(body.Predicate)
+	if err != nil {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeDeleteRequest",
+			Msg:  fmt.Sprintf("invalid predicate %q", body.Predicate),
+			Err:  err,
+		}
+	}
+
+	qp := r.URL.Query()
+	return &deleteRequest{
+		Org:          qp.Get("org"),
+		Bucket:       qp.Get("bucket"),
+		Start:        body.Start,
+		Stop:         body.Stop,
+		Predicate:    pred,
+		RawPredicate: body.Predicate,
+	}, nil
+}
+
+// parseDeletePredicate parses a predicate that selects the series to delete. The series index can only
+// match tags, including _measurement and _field, for equality or against a regular expression.
+func parseDeletePredicate(s string) (influxql.Expr, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	p, err := reads.ParsePredicate(s)
+	if err != nil {
+		return nil, err
+	}
+	expr, err := reads.NodeToExpr(p.Root, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	influxql.WalkFunc(expr, func(node influxql.Node) {
+		switch n := node.(type) {
+		case *influxql.BinaryExpr:
+			switch n.Op {
+			case influxql.EQ, influxql.NEQ, influxql.EQREGEX, influxql.NEQREGEX, influxql.AND, influxql.OR:
+			default:
+				if err == nil {
+					err = fmt.Errorf("unsupported operator %s; only ==, !=, =~, !~, and, and or are supported", n.Op)
+				}
+			}
+		case *influxql.VarRef:
+			if n.Val == "$" && err == nil {
+				err = fmt.Errorf("predicate cannot match _value; only tags, _measurement, and _field are supported")
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return expr, nil
+}
+
+// DeleteService deletes data over HTTP.
+type DeleteService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+// DeleteBucketRangePredicate deletes the data of a bucket between start, inclusive, and stop, exclusive.
+// If predicate is not empty only the series matching it are deleted. See /api/v2/delete for its syntax.
+func (s *DeleteService) DeleteBucketRangePredicate(ctx context.Context, orgID, bucketID platform.ID, start, stop time.Time, predicate string) error {
+	u, err := newURL(s.Addr, deletePath)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(deleteRequestBody{
+		Start:     start,
+		Stop:      stop,
+		Predicate: predicate,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	params := req.URL.Query()
+	params.Set("org", orgID.String())
+	params.Set("bucket", bucketID.String())
+	req.URL.RawQuery = params.Encode()
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	return CheckError(resp, true)
+}
@@ -0,0 +1,126 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/influxql"
+	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/mock"
+)
+
+// predicateDeleterFunc adapts a function to a storage.PredicateDeleter.
+type predicateDeleterFunc func(orgID, bucketID platform.ID, min, max int64, pred influxql.Expr) error
+
+func (f predicateDeleterFunc) DeleteBucketRangePredicate(orgID, bucketID platform.ID, min, max int64, pred influxql.Expr) error {
+	return f(orgID, bucketID, min, max, pred)
+}
+
+func TestDeleteHandler_handleDelete(t *testing.T) {
+	orgID, bucketID, otherBucketID := platform.ID(1), platform.ID(2), platform.ID(3)
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	stop := start.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		bucketID platform.ID
+		body     string
+		status   int
+		pred     string
+	}{
+		{
+			name:     "bucket",
+			bucketID: bucketID,
+			body:     `{"start": "2018-01-01T00:00:00Z", "stop": "2018-01-01T01:00:00Z"}`,
+			status:   http.StatusNoContent,
+		},
+		{
+			name:     "predicate",
+			bucketID: bucketID,
+			body:     `{"start": "2018-01-01T00:00:00Z", "stop": "2018-01-01T01:00:00Z", "predicate": "r._measurement == \"cpu\" and r.customer =~ /^acme/"}`,
+			status:   http.StatusNoContent,
+			pred:     `_m::tag = 'cpu' AND customer::tag =~ /^acme/`,
+		},
+		{
+			name:     "value predicate",
+			bucketID: bucketID,
+			body:     `{"start": "2018-01-01T00:00:00Z", "stop": "2018-01-01T01:00:00Z", "predicate": "r._value == 1"}`,
+			status:   http.StatusBadRequest,
+		},
+		{
+			name:     "comparison predicate",
+			bucketID: bucketID,
+			body:     `{"start": "2018-01-01T00:00:00Z", "stop": "2018-01-01T01:00:00Z", "predicate": "r.host > \"a\""}`,
+			status:   http.StatusBadRequest,
+		},
+		{
+			name:     "missing stop",
+			bucketID: bucketID,
+			body:     `{"start": "2018-01-01T00:00:00Z"}`,
+			status:   http.StatusBadRequest,
+		},
+		{
+			name:     "stop before start",
+			bucketID: bucketID,
+			body:     `{"start": "2018-01-01T01:00:00Z", "stop": "2018-01-01T00:00:00Z"}`,
+			status:   http.StatusBadRequest,
+		},
+		{
+			name:     "write permission",
+			bucketID: otherBucketID,
+			body:     `{"start": "2018-01-01T00:00:00Z", "stop": "2018-01-01T01:00:00Z"}`,
+			status:   http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleted := false
+			h := NewDeleteHandler(predicateDeleterFunc(func(org, bucket platform.ID, min, max int64, pred influxql.Expr) error {
+				deleted = true
+				if org != orgID || bucket != tt.bucketID {
+					t.Errorf("unexpected org %s and bucket %s", org, bucket)
+				}
+				if min != start.UnixNano() || max != stop.UnixNano()-1 {
+					t.Errorf("unexpected range [%d, %d]", min, max)
+				}
+				if (pred == nil && tt.pred != "") || (pred != nil && pred.String() != tt.pred) {
+					t.Errorf("unexpected predicate: got %v, want %q", pred, tt.pred)
+				}
+				return nil
+			}))
+			h.OrganizationService = &mock.OrganizationService{
+				FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+					return &platform.Organization{ID: id}, nil
+				},
+			}
+			bs := mock.NewBucketService()
+			bs.FindBucketFn = func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+				return &platform.Bucket{ID: *filter.ID, OrganizationID: *filter.OrganizationID}, nil
+			}
+			h.BucketService = bs
+
+			r := httptest.NewRequest("POST", "/api/v2/delete?org="+orgID.String()+"&bucket="+tt.bucketID.String(), strings.NewReader(tt.body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status: platform.Active,
+				Permissions: []platform.Permission{
+					{Action: platform.DeleteAction, Resource: platform.BucketsResource, ID: &bucketID},
+					{Action: platform.WriteAction, Resource: platform.BucketsResource, OrgID: &orgID},
+				},
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got := w.Code; got != tt.status {
+				t.Fatalf("unexpected status: got %d, want %d; body: %s", got, tt.status, w.Body.String())
+			}
+			if want := tt.status == http.StatusNoContent; deleted != want {
+				t.Fatalf("unexpected delete: got %v, want %v", deleted, want)
+			}
+		})
+	}
+}
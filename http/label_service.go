@@ -50,8 +50,35 @@ func newLabelsResponse(opts plat.FindOptions, f plat.LabelFilter, ls []*plat.Lab
 	}
 }
 
+// A labelAuthorizer returns an error unless the authorizer of the context is allowed the action
+// on the labels of the resource id.
+type labelAuthorizer func(ctx context.Context, a plat.Action, id plat.ID) error
+
+// orgLabelAuthorizer returns a labelAuthorizer for resources that belong to the organization
+// returned by orgOf. The labels permission is scoped by that organization.
+func orgLabelAuthorizer(orgOf func(ctx context.Context, id plat.ID) (plat.ID, error)) labelAuthorizer {
+	return func(ctx context.Context, a plat.Action, id plat.ID) error {
+		orgID, err := orgOf(ctx, id)
+		if err != nil {
+			return err
+		}
+		return authorize(ctx, plat.Permission{Action: a, Resource: plat.LabelsResource, OrgID: &orgID, ID: &id})
+	}
+}
+
+// resourceLabelAuthorizer returns a labelAuthorizer for resources of type r that do not belong to an
+// organization. Their labels are read with the resource, and changed by those who may write it.
+func resourceLabelAuthorizer(r plat.Resource) labelAuthorizer {
+	return func(ctx context.Context, a plat.Action, id plat.ID) error {
+		if a == plat.DeleteAction {
+			a = plat.WriteAction
+		}
+		return authorize(ctx, plat.Permission{Action: a, Resource: r, ID: &id})
+	}
+}
+
 // newGetLabelsHandler returns a handler func for a GET to /labels endpoints
-func newGetLabelsHandler(s plat.LabelService) http.HandlerFunc {
+func newGetLabelsHandler(s plat.LabelService, authorizeLabels labelAuthorizer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -61,6 +88,11 @@ func newGetLabelsHandler(s plat.LabelService) http.HandlerFunc {
 			return
 		}
 
+		if err := authorizeLabels(ctx, plat.ReadAction, req.filter.ResourceID); err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+
 		opts := plat.FindOptions{}
 		labels, err := s.FindLabels(ctx, req.filter)
 		if err != nil {
@@ -106,7 +138,7 @@ func decodeGetLabelsRequest(ctx context.Context, r *http.Request) (*getLabelsReq
 }
 
 // newPostLabelHandler returns a handler func for a POST to /labels endpoints
-func newPostLabelHandler(s plat.LabelService) http.HandlerFunc {
+func newPostLabelHandler(s plat.LabelService, authorizeLabels labelAuthorizer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -121,6 +153,11 @@ func newPostLabelHandler(s plat.LabelService) http.HandlerFunc {
 			return
 		}
 
+		if err := authorizeLabels(ctx, plat.WriteAction, req.Label.ResourceID); err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+
 		if err := s.CreateLabel(ctx, &req.Label); err != nil {
 			EncodeError(ctx, err, w)
 			return
@@ -210,7 +247,7 @@ func decodePatchLabelRequest(ctx context.Context, r *http.Request) (*patchLabelR
 }
 
 // newPatchLabelHandler returns a handler func for a PATCH to /labels endpoints
-func newPatchLabelHandler(s plat.LabelService) http.HandlerFunc {
+func newPatchLabelHandler(s plat.LabelService, authorizeLabels labelAuthorizer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -220,6 +257,11 @@ func newPatchLabelHandler(s plat.LabelService) http.HandlerFunc {
 			return
 		}
 
+		if err := authorizeLabels(ctx, plat.WriteAction, req.label.ResourceID); err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+
 		label, err := s.UpdateLabel(ctx, req.label, req.upd)
 		if err != nil {
 			EncodeError(ctx, err, w)
@@ -237,7 +279,7 @@ func newPatchLabelHandler(s plat.LabelService) http.HandlerFunc {
 }
 
 // newDeleteLabelHandler returns a handler func for a DELETE to /labels endpoints
-func newDeleteLabelHandler(s plat.LabelService) http.HandlerFunc {
+func newDeleteLabelHandler(s plat.LabelService, authorizeLabels labelAuthorizer) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		ctx := r.Context()
 
@@ -252,6 +294,11 @@ func newDeleteLabelHandler(s plat.LabelService) http.HandlerFunc {
 			Name:       req.Name,
 		}
 
+		if err := authorizeLabels(ctx, plat.DeleteAction, label.ResourceID); err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+
 		if err := s.DeleteLabel(ctx, label); err != nil {
 			EncodeError(ctx, err, w)
 			return
@@ -24,7 +24,8 @@ type MacroHandler struct {
 
 	Logger *zap.Logger
 
-	MacroService platform.MacroService
+	MacroService               platform.MacroService
+	UserResourceMappingService platform.UserResourceMappingService
 }
 
 // NewMacroHandler creates a new MacroHandler
@@ -85,6 +86,15 @@ func (h *MacroHandler) handleGetMacros(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Only the macros that can be read are returned.
+	allowed := macros[:0]
+	for _, m := range macros {
+		if authorize(ctx, platform.Permission{Action: platform.ReadAction, Resource: platform.MacrosResource, ID: &m.ID}) == nil {
+			allowed = append(allowed, m)
+		}
+	}
+	macros = allowed
+
 	err = encodeResponse(ctx, w, http.StatusOK, newGetMacrosResponse(macros))
 	if err != nil {
 		logEncodingError(h.Logger, r, err)
@@ -118,6 +128,10 @@ func (h *MacroHandler) handleGetMacro(w http.ResponseWriter, r *http.Request) {
 		EncodeError(ctx, err, w)
 		return
 	}
+	if err := authorize(ctx, platform.Permission{Action: platform.ReadAction, Resource: platform.MacrosResource, ID: &id}); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
 
 	macro, err := h.MacroService.FindMacroByID(ctx, id)
 	if err != nil {
@@ -159,12 +173,22 @@ func (h *MacroHandler) handlePostMacro(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := authorizeCreate(ctx, platform.MacrosResource); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
 	err = h.MacroService.CreateMacro(ctx, req.macro)
 	if err != nil {
 		EncodeError(ctx, err, w)
 		return
 	}
 
+	if err := addCreator(ctx, h.UserResourceMappingService, platform.MacrosResource, req.macro.ID); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
 	err = encodeResponse(ctx, w, http.StatusCreated, newMacroResponse(req.macro))
 	if err != nil {
 		logEncodingError(h.Logger, r, err)
@@ -208,6 +232,11 @@ func (h *MacroHandler) handlePatchMacro(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if err := authorize(ctx, platform.Permission{Action: platform.WriteAction, Resource: platform.MacrosResource, ID: &req.id}); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
 	macro, err := h.MacroService.UpdateMacro(ctx, req.id, req.macroUpdate)
 	if err != nil {
 		EncodeError(ctx, err, w)
@@ -264,6 +293,11 @@ func (h *MacroHandler) handlePutMacro(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := authorize(ctx, platform.Permission{Action: platform.WriteAction, Resource: platform.MacrosResource, ID: &req.macro.ID}); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
 	err = h.MacroService.ReplaceMacro(ctx, req.macro)
 	if err != nil {
 		EncodeError(ctx, err, w)
@@ -313,6 +347,11 @@ func (h *MacroHandler) handleDeleteMacro(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if err := authorize(ctx, platform.Permission{Action: platform.DeleteAction, Resource: platform.MacrosResource, ID: &id}); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
 	err = h.MacroService.DeleteMacro(ctx, id)
 	if err != nil {
 		EncodeError(ctx, err, w)
@@ -15,6 +15,9 @@ func initOnboardingService(f platformtesting.OnboardingFields, t *testing.T) (pl
 	svc := inmem.NewService()
 	svc.IDGenerator = f.IDGenerator
 	svc.TokenGenerator = f.TokenGenerator
+	if f.NowFn != nil {
+		svc.WithTime(f.NowFn)
+	}
 
 	ctx := context.Background()
 	if err := svc.PutOnboardingStatus(ctx, !f.IsOnboarding); err != nil {
@@ -28,6 +28,7 @@ type OrgHandler struct {
 	SecretService                   platform.SecretService
 	LabelService                    platform.LabelService
 	UserService                     platform.UserService
+	RoleService                     platform.RoleService
 }
 
 const (
@@ -43,6 +44,10 @@ const (
 	organizationsIDSecretsDeletePath = "/api/v2/orgs/:id/secrets/delete"
 	organizationsIDLabelsPath        = "/api/v2/orgs/:id/labels"
 	organizationsIDLabelsNamePath    = "/api/v2/orgs/:id/labels/:name"
+	organizationsIDRolesPath         = "/api/v2/orgs/:id/roles"
+	organizationsIDRolesIDPath       = "/api/v2/orgs/:id/roles/:roleID"
+	organizationsIDRolesMembersPath  = "/api/v2/orgs/:id/roles/:roleID/members"
+	organizationsIDRolesMemberIDPath = "/api/v2/orgs/:id/roles/:roleID/members/:userID"
 )
 
 // NewOrgHandler returns a new instance of OrgHandler.
@@ -76,14 +81,29 @@ func NewOrgHandler(mappingService platform.UserResourceMappingService,
 	// TODO(desa): need a way to specify which secrets to delete. this should work for now
 	h.HandlerFunc("POST", organizationsIDSecretsDeletePath, h.handleDeleteSecrets)
 
-	h.HandlerFunc("GET", organizationsIDLabelsPath, newGetLabelsHandler(h.LabelService))
-	h.HandlerFunc("POST", organizationsIDLabelsPath, newPostLabelHandler(h.LabelService))
-	h.HandlerFunc("DELETE", organizationsIDLabelsNamePath, newDeleteLabelHandler(h.LabelService))
-	h.HandlerFunc("PATCH", organizationsIDLabelsNamePath, newPatchLabelHandler(h.LabelService))
+	authorizeLabels := orgLabelAuthorizer(orgOfOrg)
+	h.HandlerFunc("GET", organizationsIDLabelsPath, newGetLabelsHandler(h.LabelService, authorizeLabels))
+	h.HandlerFunc("POST", organizationsIDLabelsPath, newPostLabelHandler(h.LabelService, authorizeLabels))
+	h.HandlerFunc("DELETE", organizationsIDLabelsNamePath, newDeleteLabelHandler(h.LabelService, authorizeLabels))
+	h.HandlerFunc("PATCH", organizationsIDLabelsNamePath, newPatchLabelHandler(h.LabelService, authorizeLabels))
+
+	h.HandlerFunc("POST", organizationsIDRolesPath, h.handlePostRole)
+	h.HandlerFunc("GET", organizationsIDRolesPath, h.handleGetRoles)
+	h.HandlerFunc("GET", organizationsIDRolesIDPath, h.handleGetRole)
+	h.HandlerFunc("PATCH", organizationsIDRolesIDPath, h.handlePatchRole)
+	h.HandlerFunc("DELETE", organizationsIDRolesIDPath, h.handleDeleteRole)
+	h.HandlerFunc("GET", organizationsIDRolesMembersPath, h.handleGetRoleMembers)
+	h.HandlerFunc("POST", organizationsIDRolesMembersPath, h.handlePostRoleMember)
+	h.HandlerFunc("DELETE", organizationsIDRolesMemberIDPath, h.handleDeleteRoleMember)
 
 	return h
 }
 
+// orgOfOrg returns the organization id itself, by which the labels of the organization are scoped.
+func orgOfOrg(ctx context.Context, id platform.ID) (platform.ID, error) {
+	return id, nil
+}
+
 type orgsResponse struct {
 	Links         map[string]string `json:"links"`
 	Organizations []*orgResponse    `json:"orgs"`
@@ -372,6 +392,11 @@ func (h *OrgHandler) handleGetSecrets(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := authorize(ctx, platform.Permission{Action: platform.ReadAction, Resource: platform.SecretsResource, OrgID: &req.orgID}); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
 	ks, err := h.SecretService.GetSecretKeys(ctx, req.orgID)
 	if err != nil {
 		EncodeError(ctx, err, w)
@@ -415,6 +440,11 @@ func (h *OrgHandler) handlePatchSecrets(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if err := authorize(ctx, platform.Permission{Action: platform.WriteAction, Resource: platform.SecretsResource, OrgID: &req.orgID}); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
 	if err := h.SecretService.PatchSecrets(ctx, req.orgID, req.secrets); err != nil {
 		EncodeError(ctx, err, w)
 		return
@@ -460,6 +490,11 @@ func (h *OrgHandler) handleDeleteSecrets(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if err := authorize(ctx, platform.Permission{Action: platform.DeleteAction, Resource: platform.SecretsResource, OrgID: &req.orgID}); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
 	if err := h.SecretService.DeleteSecret(ctx, req.orgID, req.secrets...); err != nil {
 		EncodeError(ctx, err, w)
 		return
@@ -31,7 +31,7 @@ func initOrganizationService(f platformtesting.OrganizationFields, t *testing.T)
 	handler := NewOrgHandler(mock.NewUserResourceMappingService(), mock.NewLabelService(), mock.NewUserService())
 	handler.OrganizationService = svc
 	handler.BucketService = svc
-	server := httptest.NewServer(handler)
+	server := httptest.NewServer(withOperHandler(handler))
 	client := OrganizationService{
 		Addr:     server.URL,
 		OpPrefix: inmem.OpPrefix,
@@ -130,6 +130,7 @@ func TestSecretService_handleGetSecrets(t *testing.T) {
 			r := httptest.NewRequest("GET", u, nil)
 			w := httptest.NewRecorder()
 
+			r = withOperRequest(r)
 			h.ServeHTTP(w, r)
 
 			res := w.Result()
@@ -206,6 +207,7 @@ func TestSecretService_handlePatchSecrets(t *testing.T) {
 			r := httptest.NewRequest("PATCH", u, buf)
 			w := httptest.NewRecorder()
 
+			r = withOperRequest(r)
 			h.ServeHTTP(w, r)
 
 			res := w.Result()
@@ -282,6 +284,7 @@ func TestSecretService_handleDeleteSecrets(t *testing.T) {
 			r := httptest.NewRequest("POST", u, buf)
 			w := httptest.NewRecorder()
 
+			r = withOperRequest(r)
 			h.ServeHTTP(w, r)
 
 			res := w.Result()
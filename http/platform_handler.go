@@ -27,9 +27,14 @@ func NewPlatformHandler(b *APIBackend) *PlatformHandler {
 	h.Handler = NewAPIHandler(b)
 	h.AuthorizationService = b.AuthorizationService
 	h.SessionService = b.SessionService
+	h.AuthorizationUsageService = b.AuthorizationUsageService
+	h.RoleService = b.RoleService
 
 	h.RegisterNoAuthRoute("GET", "/api/v2")
 	h.RegisterNoAuthRoute("POST", "/api/v2/signin")
+	h.RegisterNoAuthRoute("GET", "/api/v2/signin")
+	h.RegisterNoAuthRoute("GET", "/api/v2/signin/:provider")
+	h.RegisterNoAuthRoute("GET", "/api/v2/signin/:provider/callback")
 	h.RegisterNoAuthRoute("POST", "/api/v2/signout")
 	h.RegisterNoAuthRoute("POST", "/api/v2/setup")
 	h.RegisterNoAuthRoute("GET", "/api/v2/setup")
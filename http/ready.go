@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/influxdata/platform/storage"
 	"github.com/influxdata/platform/toml"
 )
 
@@ -13,21 +14,41 @@ var up = time.Now()
 
 // ReadyHandler is a default readiness handler. The default behaviour is always ready.
 func ReadyHandler(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusOK)
+	writeReady(w, http.StatusOK, "ready")
+}
+
+// NewReadyHandler returns a readiness handler reporting the mode of the storage engine.
+// The server is not ready while the engine is in maintenance mode, as queries are rejected.
+// In read-only mode it is ready, with the status read-only.
+func NewReadyHandler(engine storage.ModeSetter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch m := engine.Mode(); m {
+		case storage.ModeReadWrite:
+			writeReady(w, http.StatusOK, "ready")
+		case storage.ModeMaintenance:
+			writeReady(w, http.StatusServiceUnavailable, string(m))
+		default:
+			writeReady(w, http.StatusOK, string(m))
+		}
+	})
+}
+
+func writeReady(w http.ResponseWriter, code int, status string) {
+	w.WriteHeader(code)
 
-	var status = struct {
+	var s = struct {
 		Status string        `json:"status"`
 		Start  time.Time     `json:"started"`
 		Up     toml.Duration `json:"up"`
 	}{
-		Status: "ready",
+		Status: status,
 		Start:  up,
 		Up:     toml.Duration(time.Since(up)),
 	}
 
 	enc := json.NewEncoder(w)
 	enc.SetIndent("", "    ")
-	err := enc.Encode(status)
+	err := enc.Encode(s)
 	if err != nil {
 		fmt.Fprintf(w, "Error encoding status data: %v\n", err)
 	}
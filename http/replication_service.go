@@ -0,0 +1,424 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+	"github.com/julienschmidt/httprouter"
+	"go.uber.org/zap"
+)
+
+// ReplicationHandler represents an HTTP API handler for replications.
+type ReplicationHandler struct {
+	*httprouter.Router
+	Logger *zap.Logger
+
+	ReplicationService platform.ReplicationService
+	BucketService      platform.BucketService
+}
+
+const (
+	replicationsPath   = "/api/v2/replications"
+	replicationsIDPath = "/api/v2/replications/:id"
+)
+
+// NewReplicationHandler returns a new instance of ReplicationHandler.
+func NewReplicationHandler() *ReplicationHandler {
+	h := &ReplicationHandler{
+		Router: NewRouter(),
+		Logger: zap.NewNop(),
+	}
+
+	h.HandlerFunc("POST", replicationsPath, h.handlePostReplication)
+	h.HandlerFunc("GET", replicationsPath, h.handleGetReplications)
+	h.HandlerFunc("GET", replicationsIDPath, h.handleGetReplication)
+	h.HandlerFunc("DELETE", replicationsIDPath, h.handleDeleteReplication)
+	return h
+}
+
+type replicationLinks struct {
+	Self string `json:"self"`
+}
+
+type replicationResponse struct {
+	Links replicationLinks `json:"links"`
+	platform.Replication
+}
+
+func newReplicationResponse(r *platform.Replication) *replicationResponse {
+	return &replicationResponse{
+		Links: replicationLinks{
+			Self: fmt.Sprintf("/api/v2/replications/%s", r.ID),
+		},
+		Replication: *r,
+	}
+}
+
+type replicationsResponse struct {
+	Links        *platform.PagingLinks  `json:"links"`
+	Replications []*replicationResponse `json:"replications"`
+}
+
+// authorizeReplication returns an error unless the authorizer of the context is allowed
+// the action on the local bucket of the replication, in the organization of the replication.
+func authorizeReplication(ctx context.Context, r *platform.Replication, action platform.Action) error {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+	p, err := platform.NewPermissionAtIDInOrg(r.OrganizationID, r.LocalBucketID, action, platform.BucketsResource)
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	if !a.Allowed(*p) {
+		return &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  "insufficient permissions for the local bucket of the replication",
+		}
+	}
+	return nil
+}
+
+// authorizeReplicationSecret returns an error unless the authorizer of the context is allowed
+// to read the secrets of the organization of the replication, since the replication uses a
+// secret of the organization as the token for the remote instance.
+func authorizeReplicationSecret(ctx context.Context, r *platform.Replication) error {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+	p, err := platform.NewPermissionInOrg(r.OrganizationID, platform.ReadAction, platform.SecretsResource)
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	if !a.Allowed(*p) {
+		return &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  "insufficient permissions for the secrets of the organization",
+		}
+	}
+	return nil
+}
+
+// validateLocalBucket returns an error unless the local bucket of the replication
+// belongs to the organization of the replication.
+func (h *ReplicationHandler) validateLocalBucket(ctx context.Context, r *platform.Replication) error {
+	b, err := h.BucketService.FindBucketByID(ctx, r.LocalBucketID)
+	if err != nil {
+		return err
+	}
+	if b.OrganizationID != r.OrganizationID {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "local bucket does not belong to the organization of the replication",
+		}
+	}
+	return nil
+}
+
+// handlePostReplication is the HTTP handler for the POST /api/v2/replications route.
+func (h *ReplicationHandler) handlePostReplication(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	rep := &platform.Replication{}
+	if err := json.NewDecoder(r.Body).Decode(rep); err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid replication",
+			Err:  err,
+		}, w)
+		return
+	}
+	if err := rep.Valid(); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	if err := authorizeReplication(ctx, rep, platform.WriteAction); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	if err := authorizeReplicationSecret(ctx, rep); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	if err := h.validateLocalBucket(ctx, rep); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.ReplicationService.CreateReplication(ctx, rep); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newReplicationResponse(rep)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetReplications is the HTTP handler for the GET /api/v2/replications route.
+// Only the replications of local buckets that can be read are returned.
+func (h *ReplicationHandler) handleGetReplications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var filter platform.ReplicationFilter
+	qp := r.URL.Query()
+	if id := qp.Get("orgID"); id != "" {
+		orgID, err := platform.IDFromString(id)
+		if err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+		filter.OrganizationID = orgID
+	}
+	if id := qp.Get("localBucketID"); id != "" {
+		bucketID, err := platform.IDFromString(id)
+		if err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+		filter.LocalBucketID = bucketID
+	}
+
+	rs, _, err := h.ReplicationService.FindReplications(ctx, filter)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	res := &replicationsResponse{
+		Links: &platform.PagingLinks{
+			Self: replicationsPath,
+		},
+		Replications: []*replicationResponse{},
+	}
+	for _, rep := range rs {
+		if authorizeReplication(ctx, rep, platform.ReadAction) != nil {
+			continue
+		}
+		res.Replications = append(res.Replications, newReplicationResponse(rep))
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// findReplication returns the replication of the id in the path, if the local bucket of
+// the replication is allowed the action.
+func (h *ReplicationHandler) findReplication(ctx context.Context, action platform.Action) (*platform.Replication, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	var id platform.ID
+	if err := id.DecodeFromString(params.ByName("id")); err != nil {
+		return nil, err
+	}
+
+	rep, err := h.ReplicationService.FindReplicationByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeReplication(ctx, rep, action); err != nil {
+		return nil, err
+	}
+	return rep, nil
+}
+
+// handleGetReplication is the HTTP handler for the GET /api/v2/replications/:id route.
+func (h *ReplicationHandler) handleGetReplication(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	rep, err := h.findReplication(ctx, platform.ReadAction)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newReplicationResponse(rep)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteReplication is the HTTP handler for the DELETE /api/v2/replications/:id route.
+func (h *ReplicationHandler) handleDeleteReplication(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	rep, err := h.findReplication(ctx, platform.DeleteAction)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.ReplicationService.DeleteReplication(ctx, rep.ID); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ReplicationService connects to Influx via HTTP using tokens to manage replications.
+type ReplicationService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+}
+
+var _ platform.ReplicationService = (*ReplicationService)(nil)
+
+// FindReplicationByID returns a single replication by ID.
+func (s *ReplicationService) FindReplicationByID(ctx context.Context, id platform.ID) (*platform.Replication, error) {
+	u, err := newURL(s.Addr, path.Join(replicationsPath, id.String()))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, err
+	}
+
+	var rep replicationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rep); err != nil {
+		return nil, err
+	}
+	return &rep.Replication, nil
+}
+
+// FindReplications returns a list of replications that match filter and the total count of matching replications.
+func (s *ReplicationService) FindReplications(ctx context.Context, filter platform.ReplicationFilter) ([]*platform.Replication, int, error) {
+	if filter.ID != nil {
+		rep, err := s.FindReplicationByID(ctx, *filter.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		return []*platform.Replication{rep}, 1, nil
+	}
+
+	u, err := newURL(s.Addr, replicationsPath)
+	if err != nil {
+		return nil, 0, err
+	}
+	qp := u.Query()
+	if filter.OrganizationID != nil {
+		qp.Set("orgID", filter.OrganizationID.String())
+	}
+	if filter.LocalBucketID != nil {
+		qp.Set("localBucketID", filter.LocalBucketID.String())
+	}
+	u.RawQuery = qp.Encode()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return nil, 0, err
+	}
+
+	var res replicationsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, 0, err
+	}
+	rs := make([]*platform.Replication, 0, len(res.Replications))
+	for _, rep := range res.Replications {
+		rs = append(rs, &rep.Replication)
+	}
+	return rs, len(rs), nil
+}
+
+// CreateReplication creates a new replication and sets r.ID with the new identifier.
+func (s *ReplicationService) CreateReplication(ctx context.Context, r *platform.Replication) error {
+	u, err := newURL(s.Addr, replicationsPath)
+	if err != nil {
+		return err
+	}
+
+	octets, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return err
+	}
+
+	var rep replicationResponse
+	if err := json.NewDecoder(resp.Body).Decode(&rep); err != nil {
+		return err
+	}
+	*r = rep.Replication
+	return nil
+}
+
+// DeleteReplication removes a replication by ID.
+func (s *ReplicationService) DeleteReplication(ctx context.Context, id platform.ID) error {
+	u, err := newURL(s.Addr, path.Join(replicationsPath, id.String()))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return CheckError(resp, true)
+}
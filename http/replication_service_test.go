@@ -0,0 +1,99 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/mock"
+	platformtesting "github.com/influxdata/platform/testing"
+)
+
+func TestReplicationHandler_handlePostReplication(t *testing.T) {
+	orgID := platformtesting.MustIDBase16("020f755c3c082000")
+	otherOrgID := platformtesting.MustIDBase16("020f755c3c082001")
+	bucketID := platformtesting.MustIDBase16("020f755c3c082002")
+	remoteID := platformtesting.MustIDBase16("020f755c3c082003")
+
+	writeBucket, err := platform.NewPermissionAtIDInOrg(orgID, bucketID, platform.WriteAction, platform.BucketsResource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readSecrets, err := platform.NewPermissionInOrg(orgID, platform.ReadAction, platform.SecretsResource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		permissions []platform.Permission
+		bucketOrgID platform.ID
+		status      int
+	}{
+		{
+			name:        "create replication",
+			permissions: []platform.Permission{*writeBucket, *readSecrets},
+			bucketOrgID: orgID,
+			status:      http.StatusCreated,
+		},
+		{
+			name:        "create replication without permission to read secrets",
+			permissions: []platform.Permission{*writeBucket},
+			bucketOrgID: orgID,
+			status:      http.StatusForbidden,
+		},
+		{
+			name:        "create replication of a bucket in another organization",
+			permissions: []platform.Permission{*writeBucket, *readSecrets},
+			bucketOrgID: otherOrgID,
+			status:      http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bs := mock.NewBucketService()
+			bs.FindBucketByIDFn = func(ctx context.Context, id platform.ID) (*platform.Bucket, error) {
+				return &platform.Bucket{ID: id, OrganizationID: tt.bucketOrgID, Name: "b"}, nil
+			}
+
+			h := NewReplicationHandler()
+			h.BucketService = bs
+			h.ReplicationService = &mock.ReplicationService{
+				CreateReplicationF: func(ctx context.Context, r *platform.Replication) error {
+					r.ID = remoteID
+					return nil
+				},
+			}
+
+			b, err := json.Marshal(&platform.Replication{
+				OrganizationID:       orgID,
+				Name:                 "r",
+				LocalBucketID:        bucketID,
+				RemoteURL:            "http://localhost:9999",
+				RemoteOrganizationID: remoteID,
+				RemoteBucketID:       remoteID,
+				TokenSecretKey:       "token",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := httptest.NewRequest("POST", replicationsPath, bytes.NewReader(b))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: tt.permissions,
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got := w.Code; got != tt.status {
+				t.Fatalf("unexpected status: got %d, want %d; body: %s", got, tt.status, w.Body.String())
+			}
+		})
+	}
+}
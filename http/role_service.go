@@ -0,0 +1,525 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+	"github.com/julienschmidt/httprouter"
+)
+
+type roleLinks struct {
+	Self    string `json:"self"`
+	Members string `json:"members"`
+}
+
+type roleResponse struct {
+	Links roleLinks `json:"links"`
+	platform.Role
+}
+
+func newRoleResponse(r *platform.Role) *roleResponse {
+	self := rolePath(r.OrganizationID, r.ID)
+	return &roleResponse{
+		Links: roleLinks{
+			Self:    self,
+			Members: path.Join(self, "members"),
+		},
+		Role: *r,
+	}
+}
+
+type rolesResponse struct {
+	Links *platform.PagingLinks `json:"links"`
+	Roles []*roleResponse       `json:"roles"`
+}
+
+func rolesPath(orgID platform.ID) string {
+	return path.Join(organizationsPath, orgID.String(), "roles")
+}
+
+func rolePath(orgID, id platform.ID) string {
+	return path.Join(rolesPath(orgID), id.String())
+}
+
+// authorizeRole returns an error unless the authorizer of the context is allowed the action on the
+// roles of the organization, or on the role id if it is valid. As assigning a role grants its
+// permissions, the authorizer must also be allowed each of the permissions in grants.
+func authorizeRole(ctx context.Context, orgID, id platform.ID, action platform.Action, grants []platform.Permission) error {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+
+	var p *platform.Permission
+	if id.Valid() {
+		p, err = platform.NewPermissionAtIDInOrg(orgID, id, action, platform.RolesResource)
+	} else {
+		p, err = platform.NewPermissionInOrg(orgID, action, platform.RolesResource)
+	}
+	if err != nil {
+		return &platform.Error{
+			Code: platform.EInvalid,
+			Err:  err,
+		}
+	}
+	if !a.Allowed(*p) {
+		return &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  fmt.Sprintf("insufficient permissions for roles: %s", p),
+		}
+	}
+
+	for _, g := range grants {
+		if !a.Allowed(g) {
+			return &platform.Error{
+				Code: platform.EForbidden,
+				Msg:  fmt.Sprintf("insufficient permissions to grant %s", g),
+			}
+		}
+	}
+	return nil
+}
+
+// decodeRoleParams returns the organization and role ids in the path; the role id is not
+// valid if the path has none.
+func decodeRoleParams(ctx context.Context) (orgID, id platform.ID, err error) {
+	params := httprouter.ParamsFromContext(ctx)
+	if err := orgID.DecodeFromString(params.ByName("id")); err != nil {
+		return 0, 0, err
+	}
+	if roleID := params.ByName("roleID"); roleID != "" {
+		if err := id.DecodeFromString(roleID); err != nil {
+			return 0, 0, err
+		}
+	}
+	return orgID, id, nil
+}
+
+// findRole returns the role of the id in the path, if the role belongs to the organization
+// of the path and the action on it is allowed.
+func (h *OrgHandler) findRole(ctx context.Context, action platform.Action) (*platform.Role, error) {
+	orgID, id, err := decodeRoleParams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeRole(ctx, orgID, id, action, nil); err != nil {
+		return nil, err
+	}
+
+	role, err := h.RoleService.FindRoleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if role.OrganizationID != orgID {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  platform.ErrRoleNotFound,
+		}
+	}
+	return role, nil
+}
+
+// handlePostRole is the HTTP handler for the POST /api/v2/orgs/:id/roles route.
+func (h *OrgHandler) handlePostRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orgID, _, err := decodeRoleParams(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	role := &platform.Role{}
+	if err := json.NewDecoder(r.Body).Decode(role); err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid role",
+			Err:  err,
+		}, w)
+		return
+	}
+	role.OrganizationID = orgID
+
+	if err := authorizeRole(ctx, orgID, 0, platform.WriteAction, role.Permissions); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.RoleService.CreateRole(ctx, role); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newRoleResponse(role)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetRoles is the HTTP handler for the GET /api/v2/orgs/:id/roles route.
+func (h *OrgHandler) handleGetRoles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orgID, _, err := decodeRoleParams(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	if err := authorizeRole(ctx, orgID, 0, platform.ReadAction, nil); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	filter := platform.RoleFilter{OrganizationID: &orgID}
+	if name := r.URL.Query().Get("name"); name != "" {
+		filter.Name = &name
+	}
+
+	rs, _, err := h.RoleService.FindRoles(ctx, filter)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	res := &rolesResponse{
+		Links: &platform.PagingLinks{
+			Self: rolesPath(orgID),
+		},
+		Roles: make([]*roleResponse, 0, len(rs)),
+	}
+	for _, role := range rs {
+		res.Roles = append(res.Roles, newRoleResponse(role))
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleGetRole is the HTTP handler for the GET /api/v2/orgs/:id/roles/:roleID route.
+func (h *OrgHandler) handleGetRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	role, err := h.findRole(ctx, platform.ReadAction)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRoleResponse(role)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePatchRole is the HTTP handler for the PATCH /api/v2/orgs/:id/roles/:roleID route.
+func (h *OrgHandler) handlePatchRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	role, err := h.findRole(ctx, platform.WriteAction)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	var upd platform.RoleUpdate
+	if err := json.NewDecoder(r.Body).Decode(&upd); err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid role update",
+			Err:  err,
+		}, w)
+		return
+	}
+	if upd.Permissions != nil {
+		if err := authorizeRole(ctx, role.OrganizationID, role.ID, platform.WriteAction, *upd.Permissions); err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+	}
+
+	role, err = h.RoleService.UpdateRole(ctx, role.ID, upd)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newRoleResponse(role)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteRole is the HTTP handler for the DELETE /api/v2/orgs/:id/roles/:roleID route.
+func (h *OrgHandler) handleDeleteRole(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	role, err := h.findRole(ctx, platform.DeleteAction)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.RoleService.DeleteRole(ctx, role.ID); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleGetRoleMembers is the HTTP handler for the GET /api/v2/orgs/:id/roles/:roleID/members route.
+func (h *OrgHandler) handleGetRoleMembers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	role, err := h.findRole(ctx, platform.ReadAction)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	filter := platform.UserResourceMappingFilter{
+		ResourceID: role.ID,
+		Resource:   platform.RolesResource,
+		UserType:   platform.Member,
+	}
+	ms, _, err := h.UserResourceMappingService.FindUserResourceMappings(ctx, filter)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	res := &resourceUsersResponse{
+		Links: map[string]string{
+			"self": path.Join(rolePath(role.OrganizationID, role.ID), "members"),
+		},
+		Users: make([]*resourceUserResponse, 0, len(ms)),
+	}
+	for _, m := range ms {
+		u, err := h.UserService.FindUserByID(ctx, m.UserID)
+		if err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+		res.Users = append(res.Users, newResourceUserResponse(u, platform.Member))
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handlePostRoleMember is the HTTP handler for the POST /api/v2/orgs/:id/roles/:roleID/members route.
+// Assigning a role to a user grants the permissions of the role to the sessions of the user.
+func (h *OrgHandler) handlePostRoleMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	role, err := h.findRole(ctx, platform.WriteAction)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+	if err := authorizeRole(ctx, role.OrganizationID, role.ID, platform.WriteAction, role.Permissions); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	var member platform.User
+	if err := json.NewDecoder(r.Body).Decode(&member); err != nil {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "invalid role member",
+			Err:  err,
+		}, w)
+		return
+	}
+	if !member.ID.Valid() {
+		EncodeError(ctx, &platform.Error{
+			Code: platform.EInvalid,
+			Msg:  "user id missing or invalid",
+		}, w)
+		return
+	}
+
+	u, err := h.UserService.FindUserByID(ctx, member.ID)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.UserResourceMappingService.CreateUserResourceMapping(ctx, &platform.UserResourceMapping{
+		ResourceID: role.ID,
+		Resource:   platform.RolesResource,
+		UserID:     u.ID,
+		UserType:   platform.Member,
+	}); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newResourceUserResponse(u, platform.Member)); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleDeleteRoleMember is the HTTP handler for the DELETE /api/v2/orgs/:id/roles/:roleID/members/:userID route.
+func (h *OrgHandler) handleDeleteRoleMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	role, err := h.findRole(ctx, platform.WriteAction)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	var userID platform.ID
+	if err := userID.DecodeFromString(httprouter.ParamsFromContext(ctx).ByName("userID")); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.UserResourceMappingService.DeleteUserResourceMapping(ctx, role.ID, userID); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RoleService connects to Influx via HTTP using tokens to manage the roles of an organization.
+type RoleService struct {
+	Addr               string
+	Token              string
+	InsecureSkipVerify bool
+
+	// OrganizationID is the organization of the roles that are found, updated and deleted by ID.
+	OrganizationID platform.ID
+}
+
+var _ platform.RoleService = (*RoleService)(nil)
+
+// do sends a request with the query and the JSON of body, if not nil, and decodes the response
+// into v, if not nil.
+func (s *RoleService) do(method, p string, query url.Values, body, v interface{}) error {
+	u, err := newURL(s.Addr, p)
+	if err != nil {
+		return err
+	}
+	u.RawQuery = query.Encode()
+
+	var octets []byte
+	if body != nil {
+		if octets, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(octets))
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	SetToken(s.Token, req)
+
+	hc := newClient(u.Scheme, s.InsecureSkipVerify)
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp, true); err != nil {
+		return err
+	}
+
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// FindRoleByID returns a single role of the organization by ID.
+func (s *RoleService) FindRoleByID(ctx context.Context, id platform.ID) (*platform.Role, error) {
+	var res roleResponse
+	if err := s.do("GET", rolePath(s.OrganizationID, id), nil, nil, &res); err != nil {
+		return nil, err
+	}
+	return &res.Role, nil
+}
+
+// FindRoles returns a list of roles that match filter and the total count of matching roles.
+// The roles of the organization of the service are returned if filter has no OrganizationID.
+func (s *RoleService) FindRoles(ctx context.Context, filter platform.RoleFilter) ([]*platform.Role, int, error) {
+	if filter.ID != nil {
+		r, err := s.FindRoleByID(ctx, *filter.ID)
+		if err != nil {
+			return nil, 0, err
+		}
+		return []*platform.Role{r}, 1, nil
+	}
+
+	orgID := s.OrganizationID
+	if filter.OrganizationID != nil {
+		orgID = *filter.OrganizationID
+	}
+	query := url.Values{}
+	if filter.Name != nil {
+		query.Set("name", *filter.Name)
+	}
+
+	var res rolesResponse
+	if err := s.do("GET", rolesPath(orgID), query, nil, &res); err != nil {
+		return nil, 0, err
+	}
+	rs := make([]*platform.Role, 0, len(res.Roles))
+	for _, r := range res.Roles {
+		rs = append(rs, &r.Role)
+	}
+	return rs, len(rs), nil
+}
+
+// CreateRole creates a new role in the organization of r and sets r.ID with the new identifier.
+func (s *RoleService) CreateRole(ctx context.Context, r *platform.Role) error {
+	var res roleResponse
+	if err := s.do("POST", rolesPath(r.OrganizationID), nil, r, &res); err != nil {
+		return err
+	}
+	*r = res.Role
+	return nil
+}
+
+// UpdateRole updates a single role of the organization with changeset.
+func (s *RoleService) UpdateRole(ctx context.Context, id platform.ID, upd platform.RoleUpdate) (*platform.Role, error) {
+	var res roleResponse
+	if err := s.do("PATCH", rolePath(s.OrganizationID, id), nil, upd, &res); err != nil {
+		return nil, err
+	}
+	return &res.Role, nil
+}
+
+// DeleteRole removes a role of the organization by ID.
+func (s *RoleService) DeleteRole(ctx context.Context, id platform.ID) error {
+	return s.do("DELETE", rolePath(s.OrganizationID, id), nil, nil, nil)
+}
+
+// AddRoleMember assigns the role of the organization to the user.
+func (s *RoleService) AddRoleMember(ctx context.Context, id, userID platform.ID) error {
+	return s.do("POST", path.Join(rolePath(s.OrganizationID, id), "members"), nil, &platform.User{ID: userID}, nil)
+}
+
+// RemoveRoleMember removes the assignment of the role of the organization to the user.
+func (s *RoleService) RemoveRoleMember(ctx context.Context, id, userID platform.ID) error {
+	return s.do("DELETE", path.Join(rolePath(s.OrganizationID, id), "members", userID.String()), nil, nil, nil)
+}
@@ -0,0 +1,179 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/inmem"
+	platformtesting "github.com/influxdata/platform/testing"
+)
+
+func initRoleService(f platformtesting.RoleFields, t *testing.T) (platform.RoleService, string, func()) {
+	t.Helper()
+	svc := inmem.NewService()
+	svc.IDGenerator = f.IDGenerator
+
+	ctx := context.Background()
+	for _, o := range f.Organizations {
+		if err := svc.PutOrganization(ctx, o); err != nil {
+			t.Fatalf("failed to populate organizations")
+		}
+	}
+	for _, r := range f.Roles {
+		if err := svc.PutRole(ctx, r); err != nil {
+			t.Fatalf("failed to populate roles")
+		}
+	}
+
+	handler := NewOrgHandler(svc, svc, svc)
+	handler.OrganizationService = svc
+	handler.RoleService = svc
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+			Status:      platform.Active,
+			Permissions: platform.OperPermissions(),
+		}))
+		handler.ServeHTTP(w, r)
+	}))
+	client := RoleService{
+		Addr: server.URL,
+	}
+	if len(f.Organizations) > 0 {
+		client.OrganizationID = f.Organizations[0].ID
+	}
+	done := server.Close
+
+	return &client, inmem.OpPrefix, done
+}
+
+func TestRoleService_CreateRole(t *testing.T) {
+	platformtesting.CreateRole(initRoleService, t)
+}
+
+func TestRoleService_FindRoleByID(t *testing.T) {
+	platformtesting.FindRoleByID(initRoleService, t)
+}
+
+func TestRoleService_FindRoles(t *testing.T) {
+	platformtesting.FindRoles(initRoleService, t)
+}
+
+func TestRoleService_UpdateRole(t *testing.T) {
+	platformtesting.UpdateRole(initRoleService, t)
+}
+
+func TestRoleService_DeleteRole(t *testing.T) {
+	platformtesting.DeleteRole(initRoleService, t)
+}
+
+func TestOrgHandler_handlePostRole(t *testing.T) {
+	orgID := platformtesting.MustIDBase16("020f755c3c082000")
+	otherOrgID := platformtesting.MustIDBase16("020f755c3c082001")
+
+	writeRoles, err := platform.NewPermissionInOrg(orgID, platform.WriteAction, platform.RolesResource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readBuckets, err := platform.NewPermissionInOrg(orgID, platform.ReadAction, platform.BucketsResource)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		orgID       platform.ID
+		permissions []platform.Permission
+		role        platform.Role
+		status      int
+	}{
+		{
+			name:        "create role with permissions the caller has",
+			orgID:       orgID,
+			permissions: []platform.Permission{*writeRoles, *readBuckets},
+			role: platform.Role{
+				Name:        "reader",
+				Permissions: []platform.Permission{*readBuckets},
+			},
+			status: http.StatusCreated,
+		},
+		{
+			name:        "create role without permission to write roles",
+			orgID:       orgID,
+			permissions: []platform.Permission{*readBuckets},
+			role: platform.Role{
+				Name:        "reader",
+				Permissions: []platform.Permission{*readBuckets},
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:        "create role with permissions the caller does not have",
+			orgID:       orgID,
+			permissions: []platform.Permission{*writeRoles},
+			role: platform.Role{
+				Name:        "reader",
+				Permissions: []platform.Permission{*readBuckets},
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:        "create role in another organization",
+			orgID:       otherOrgID,
+			permissions: []platform.Permission{*writeRoles, *readBuckets},
+			role: platform.Role{
+				Name: "reader",
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name:        "create role with permissions outside of its organization",
+			orgID:       orgID,
+			permissions: platform.OperPermissions(),
+			role: platform.Role{
+				Name: "reader",
+				Permissions: []platform.Permission{
+					{Action: platform.ReadAction, Resource: platform.BucketsResource},
+				},
+			},
+			status: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := inmem.NewService()
+			ctx := context.Background()
+			for _, id := range []platform.ID{orgID, otherOrgID} {
+				if err := svc.PutOrganization(ctx, &platform.Organization{ID: id, Name: id.String()}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			h := NewOrgHandler(svc, svc, svc)
+			h.OrganizationService = svc
+			h.RoleService = svc
+
+			b, err := json.Marshal(tt.role)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := httptest.NewRequest("POST", rolesPath(tt.orgID), bytes.NewReader(b))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: tt.permissions,
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got := w.Code; got != tt.status {
+				t.Fatalf("unexpected status: got %d, want %d; body: %s", got, tt.status, w.Body.String())
+			}
+		})
+	}
+}
@@ -16,8 +16,9 @@ import (
 // ScraperHandler represents an HTTP API handler for scraper targets.
 type ScraperHandler struct {
 	*httprouter.Router
-	Logger                *zap.Logger
-	ScraperStorageService platform.ScraperTargetStoreService
+	Logger                     *zap.Logger
+	ScraperStorageService      platform.ScraperTargetStoreService
+	UserResourceMappingService platform.UserResourceMappingService
 }
 
 const (
@@ -48,10 +49,19 @@ func (h *ScraperHandler) handlePostScraperTarget(w http.ResponseWriter, r *http.
 		return
 	}
 
+	if err := authorizeCreate(ctx, platform.ScrapersResource); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
 	if err := h.ScraperStorageService.AddTarget(ctx, req); err != nil {
 		EncodeError(ctx, err, w)
 		return
 	}
+	if err := addCreator(ctx, h.UserResourceMappingService, platform.ScrapersResource, req.ID); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
 	if err := encodeResponse(ctx, w, http.StatusCreated, newTargetResponse(*req)); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
@@ -68,6 +78,11 @@ func (h *ScraperHandler) handleDeleteScraperTarget(w http.ResponseWriter, r *htt
 		return
 	}
 
+	if err := authorize(ctx, platform.Permission{Action: platform.DeleteAction, Resource: platform.ScrapersResource, ID: id}); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
 	if err := h.ScraperStorageService.RemoveTarget(ctx, *id); err != nil {
 		EncodeError(ctx, err, w)
 		return
@@ -86,6 +101,11 @@ func (h *ScraperHandler) handlePatchScraperTarget(w http.ResponseWriter, r *http
 		return
 	}
 
+	if err := authorize(ctx, platform.Permission{Action: platform.WriteAction, Resource: platform.ScrapersResource, ID: &update.ID}); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
 	target, err := h.ScraperStorageService.UpdateTarget(ctx, update)
 	if err != nil {
 		EncodeError(ctx, err, w)
@@ -106,6 +126,10 @@ func (h *ScraperHandler) handleGetScraperTarget(w http.ResponseWriter, r *http.R
 		EncodeError(ctx, err, w)
 		return
 	}
+	if err := authorize(ctx, platform.Permission{Action: platform.ReadAction, Resource: platform.ScrapersResource, ID: id}); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
 	target, err := h.ScraperStorageService.GetTargetByID(ctx, *id)
 	if err != nil {
 		EncodeError(ctx, err, w)
@@ -128,6 +152,15 @@ func (h *ScraperHandler) handleGetScraperTargets(w http.ResponseWriter, r *http.
 		return
 	}
 
+	// Only the targets that can be read are returned.
+	allowed := targets[:0]
+	for i := range targets {
+		if authorize(ctx, platform.Permission{Action: platform.ReadAction, Resource: platform.ScrapersResource, ID: &targets[i].ID}) == nil {
+			allowed = append(allowed, targets[i])
+		}
+	}
+	targets = allowed
+
 	if err := encodeResponse(ctx, w, http.StatusOK, newListTargetsResponse(targets)); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
@@ -157,6 +157,7 @@ func TestService_handleGetScraperTargets(t *testing.T) {
 
 			w := httptest.NewRecorder()
 
+			r = withOperRequest(r)
 			h.handleGetScraperTargets(w, r)
 
 			res := w.Result()
@@ -261,6 +262,7 @@ func TestService_handleGetScraperTarget(t *testing.T) {
 
 			w := httptest.NewRecorder()
 
+			r = withOperRequest(r)
 			h.handleGetScraperTarget(w, r)
 
 			res := w.Result()
@@ -361,6 +363,7 @@ func TestService_handleDeleteScraperTarget(t *testing.T) {
 
 			w := httptest.NewRecorder()
 
+			r = withOperRequest(r)
 			h.handleDeleteScraperTarget(w, r)
 
 			res := w.Result()
@@ -456,6 +459,7 @@ func TestService_handlePostScraperTarget(t *testing.T) {
 			r := httptest.NewRequest("GET", "http://any.tld", bytes.NewReader(st))
 			w := httptest.NewRecorder()
 
+			r = withOperRequest(r)
 			h.handlePostScraperTarget(w, r)
 
 			res := w.Result()
@@ -599,6 +603,7 @@ func TestService_handlePatchScraperTarget(t *testing.T) {
 
 			w := httptest.NewRecorder()
 
+			r = withOperRequest(r)
 			h.handlePatchScraperTarget(w, r)
 
 			res := w.Result()
@@ -632,7 +637,7 @@ func initScraperService(f platformtesting.TargetFields, t *testing.T) (platform.
 
 	handler := NewScraperHandler()
 	handler.ScraperStorageService = svc
-	server := httptest.NewServer(handler)
+	server := httptest.NewServer(withOperHandler(handler))
 	client := ScraperService{
 		Addr:     server.URL,
 		OpPrefix: inmem.OpPrefix,
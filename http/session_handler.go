@@ -2,9 +2,12 @@ package http
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/chronograf/oauth2"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
 )
@@ -16,6 +19,9 @@ type SessionBackend struct {
 
 	BasicAuthService platform.BasicAuthService
 	SessionService   platform.SessionService
+
+	// SSOProviders are the identity providers users can sign in with through OAuth2.
+	SSOProviders []*oauth2.AuthMux
 }
 
 func NewSessionBackend(b *APIBackend) *SessionBackend {
@@ -24,6 +30,7 @@ func NewSessionBackend(b *APIBackend) *SessionBackend {
 
 		BasicAuthService: b.BasicAuthService,
 		SessionService:   b.SessionService,
+		SSOProviders:     b.SSOProviders,
 	}
 }
 
@@ -34,6 +41,8 @@ type SessionHandler struct {
 
 	BasicAuthService platform.BasicAuthService
 	SessionService   platform.SessionService
+
+	ssoProviders map[string]*oauth2.AuthMux
 }
 
 // NewSessionHandler returns a new instance of SessionHandler.
@@ -44,9 +53,17 @@ func NewSessionHandler(b *SessionBackend) *SessionHandler {
 
 		BasicAuthService: b.BasicAuthService,
 		SessionService:   b.SessionService,
+
+		ssoProviders: make(map[string]*oauth2.AuthMux, len(b.SSOProviders)),
+	}
+	for _, m := range b.SSOProviders {
+		h.ssoProviders[ssoProviderPathName(m.Provider.Name())] = m
 	}
 
 	h.HandlerFunc("POST", "/api/v2/signin", h.handleSignin)
+	h.HandlerFunc("GET", "/api/v2/signin", h.handleGetSSOProviders)
+	h.HandlerFunc("GET", "/api/v2/signin/:provider", h.handleSSOSignin)
+	h.HandlerFunc("GET", "/api/v2/signin/:provider/callback", h.handleSSOCallback)
 	h.HandlerFunc("POST", "/api/v2/signout", h.handleSignout)
 	return h
 }
@@ -78,6 +95,77 @@ func (h *SessionHandler) handleSignin(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+type ssoProviderLinks struct {
+	Signin string `json:"signin"`
+}
+
+type ssoProviderResponse struct {
+	Name  string           `json:"name"`
+	Links ssoProviderLinks `json:"links"`
+}
+
+type ssoProvidersResponse struct {
+	Providers []ssoProviderResponse `json:"providers"`
+}
+
+// handleGetSSOProviders is the HTTP handler for the GET /signin route, listing the identity
+// providers users can sign in with.
+func (h *SessionHandler) handleGetSSOProviders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	res := ssoProvidersResponse{Providers: []ssoProviderResponse{}}
+	for name, m := range h.ssoProviders {
+		res.Providers = append(res.Providers, ssoProviderResponse{
+			Name: m.Provider.Name(),
+			Links: ssoProviderLinks{
+				Signin: "/api/v2/signin/" + name,
+			},
+		})
+	}
+	sort.Slice(res.Providers, func(i, j int) bool {
+		return res.Providers[i].Name < res.Providers[j].Name
+	})
+
+	if err := encodeResponse(ctx, w, http.StatusOK, res); err != nil {
+		logEncodingError(h.Logger, r, err)
+		return
+	}
+}
+
+// handleSSOSignin is the HTTP handler for the GET /signin/:provider route, redirecting to the
+// identity provider.
+func (h *SessionHandler) handleSSOSignin(w http.ResponseWriter, r *http.Request) {
+	m, err := h.ssoProvider(r.Context())
+	if err != nil {
+		EncodeError(r.Context(), err, w)
+		return
+	}
+	m.Login().ServeHTTP(w, r)
+}
+
+// handleSSOCallback is the HTTP handler for the GET /signin/:provider/callback route, to which
+// the identity provider redirects once the user is authenticated.
+func (h *SessionHandler) handleSSOCallback(w http.ResponseWriter, r *http.Request) {
+	m, err := h.ssoProvider(r.Context())
+	if err != nil {
+		EncodeError(r.Context(), err, w)
+		return
+	}
+	m.Callback().ServeHTTP(w, r)
+}
+
+func (h *SessionHandler) ssoProvider(ctx context.Context) (*oauth2.AuthMux, error) {
+	name := httprouter.ParamsFromContext(ctx).ByName("provider")
+	m, ok := h.ssoProviders[name]
+	if !ok {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  fmt.Sprintf("identity provider %q not found", name),
+		}
+	}
+	return m, nil
+}
+
 type signinRequest struct {
 	Username string
 	Password string
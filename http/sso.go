@@ -0,0 +1,274 @@
+package http
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/chronograf"
+	"github.com/influxdata/platform/chronograf/oauth2"
+	"go.uber.org/zap"
+)
+
+// SSOGroupMapping grants the users of a group of an identity provider the membership of an organization.
+type SSOGroupMapping struct {
+	Group          string
+	OrganizationID platform.ID
+	UserType       platform.UserType
+}
+
+// ParseSSOGroupMapping parses a group mapping of the form group=orgID or group=orgID:userType.
+// The users of the group are members of the organization unless the user type is owner.
+func ParseSSOGroupMapping(s string) (SSOGroupMapping, error) {
+	i := strings.LastIndex(s, "=")
+	if i <= 0 {
+		return SSOGroupMapping{}, fmt.Errorf("invalid group mapping %q: expected group=orgID[:userType]", s)
+	}
+
+	m := SSOGroupMapping{Group: s[:i], UserType: platform.Member}
+	org := s[i+1:]
+	if j := strings.Index(org, ":"); j >= 0 {
+		m.UserType = platform.UserType(org[j+1:])
+		org = org[:j]
+	}
+	if err := m.OrganizationID.DecodeFromString(org); err != nil {
+		return SSOGroupMapping{}, fmt.Errorf("invalid organization ID in group mapping %q: %v", s, err)
+	}
+	if err := m.UserType.Valid(); err != nil {
+		return SSOGroupMapping{}, fmt.Errorf("invalid user type in group mapping %q: %v", s, err)
+	}
+	return m, nil
+}
+
+// SSOAuthenticator is an oauth2.Authenticator signing in the users authenticated by an identity
+// provider with a platform.Session.
+//
+// The principal signs in as the user its identity at the provider is linked to. On first sign in,
+// a user named after the principal is created and linked to its identity, unless a user of that
+// name already exists: users are never matched to principals by name, as a local user may have it.
+// The memberships of the organizations in GroupMappings are updated at every sign in to match the
+// groups of the principal, and the memberships of other organizations are left unchanged.
+type SSOAuthenticator struct {
+	Logger *zap.Logger
+
+	UserService                platform.UserService
+	UserResourceMappingService platform.UserResourceMappingService
+	SessionService             platform.SessionService
+	SSOIdentityService         platform.SSOIdentityService
+
+	GroupMappings []SSOGroupMapping
+}
+
+var _ oauth2.Authenticator = (*SSOAuthenticator)(nil)
+
+// Validate returns the principal of the session of the request.
+func (a *SSOAuthenticator) Validate(ctx context.Context, r *http.Request) (oauth2.Principal, error) {
+	key, pe := decodeCookieSession(ctx, r)
+	if pe != nil {
+		return oauth2.Principal{}, pe
+	}
+	s, err := a.SessionService.FindSession(ctx, key)
+	if err != nil {
+		return oauth2.Principal{}, err
+	}
+	u, err := a.UserService.FindUserByID(ctx, s.UserID)
+	if err != nil {
+		return oauth2.Principal{}, err
+	}
+	return oauth2.Principal{
+		Subject:   u.Name,
+		IssuedAt:  s.CreatedAt,
+		ExpiresAt: s.ExpiresAt,
+	}, nil
+}
+
+// Authorize provisions the user of the principal and its memberships, and sets the cookie of a
+// new session of the user.
+func (a *SSOAuthenticator) Authorize(ctx context.Context, w http.ResponseWriter, p oauth2.Principal) error {
+	u, err := a.provisionUser(ctx, p)
+	if err != nil {
+		return err
+	}
+
+	var groups []string
+	if p.Group != "" {
+		groups = strings.Split(p.Group, ",")
+	}
+	if err := a.syncMemberships(ctx, u.ID, groups); err != nil {
+		return err
+	}
+
+	s, err := a.SessionService.CreateSession(ctx, u.Name)
+	if err != nil {
+		return err
+	}
+	encodeCookieSession(w, s)
+	return nil
+}
+
+// Extend returns the principal unchanged, as sessions expire at a fixed time.
+func (a *SSOAuthenticator) Extend(ctx context.Context, w http.ResponseWriter, p oauth2.Principal) (oauth2.Principal, error) {
+	return p, nil
+}
+
+// Expire removes the session cookie.
+func (a *SSOAuthenticator) Expire(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:   cookieSessionName,
+		Value:  "",
+		MaxAge: -1,
+	})
+}
+
+// provisionUser returns the user linked to the identity of p, creating and linking a new user on
+// first sign in.
+func (a *SSOAuthenticator) provisionUser(ctx context.Context, p oauth2.Principal) (*platform.User, error) {
+	if p.Subject == "" || p.Issuer == "" {
+		return nil, &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  "identity provider returned no user name",
+		}
+	}
+
+	i, err := a.SSOIdentityService.FindSSOIdentity(ctx, p.Issuer, p.Subject)
+	switch {
+	case err == nil:
+		u, err := a.UserService.FindUserByID(ctx, i.UserID)
+		if platform.ErrorCode(err) != platform.ENotFound {
+			return u, err
+		}
+		// The linked user was deleted; a new user is provisioned.
+	case platform.ErrorCode(err) != platform.ENotFound:
+		return nil, err
+	}
+
+	name := p.Subject
+	if _, err := a.UserService.FindUser(ctx, platform.UserFilter{Name: &name}); err == nil {
+		return nil, &platform.Error{
+			Code: platform.EForbidden,
+			Msg:  fmt.Sprintf("user %s exists and is not linked to the identity provider %s", name, p.Issuer),
+		}
+	} else if platform.ErrorCode(err) != platform.ENotFound {
+		return nil, err
+	}
+
+	u := &platform.User{Name: name}
+	if err := a.UserService.CreateUser(ctx, u); err != nil {
+		return nil, err
+	}
+	if err := a.SSOIdentityService.PutSSOIdentity(ctx, &platform.SSOIdentity{
+		Provider: p.Issuer,
+		Subject:  p.Subject,
+		UserID:   u.ID,
+	}); err != nil {
+		return nil, err
+	}
+	a.Logger.Info("Provisioned user from identity provider", zap.String("user", name), zap.String("provider", p.Issuer))
+	return u, nil
+}
+
+// syncMemberships updates the memberships of user in the organizations of the group mappings
+// to the memberships granted by groups. Owners of an organization are not members as well.
+func (a *SSOAuthenticator) syncMemberships(ctx context.Context, userID platform.ID, groups []string) error {
+	inGroup := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		inGroup[strings.TrimSpace(g)] = true
+	}
+
+	// The user type of every managed organization; empty if the groups grant no membership.
+	want := make(map[platform.ID]platform.UserType)
+	for _, m := range a.GroupMappings {
+		if _, ok := want[m.OrganizationID]; !ok {
+			want[m.OrganizationID] = ""
+		}
+		if inGroup[m.Group] && want[m.OrganizationID] != platform.Owner {
+			want[m.OrganizationID] = m.UserType
+		}
+	}
+
+	mappings, _, err := a.UserResourceMappingService.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{
+		UserID:   userID,
+		Resource: platform.OrgsResource,
+	})
+	if err != nil {
+		return err
+	}
+	have := make(map[platform.ID]platform.UserType, len(mappings))
+	for _, m := range mappings {
+		have[m.ResourceID] = m.UserType
+	}
+
+	for orgID, userType := range want {
+		if have[orgID] == userType {
+			continue
+		}
+		if have[orgID] != "" {
+			if err := a.UserResourceMappingService.DeleteUserResourceMapping(ctx, orgID, userID); err != nil {
+				return err
+			}
+		}
+		if userType == "" {
+			continue
+		}
+		if err := a.UserResourceMappingService.CreateUserResourceMapping(ctx, &platform.UserResourceMapping{
+			UserID:     userID,
+			UserType:   userType,
+			Resource:   platform.OrgsResource,
+			ResourceID: orgID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewSSOAuthMux returns the OAuth2 sign in with the identity provider p, which signs in users with a.
+// Users are redirected to the UI after signing in, or to its sign in page if it failed.
+func NewSSOAuthMux(p oauth2.Provider, a oauth2.Authenticator, tokens oauth2.Tokenizer, useIDToken bool, logger *zap.Logger) *oauth2.AuthMux {
+	m := oauth2.NewAuthMux(p, a, tokens, "", NewOAuth2Logger(logger), useIDToken)
+	m.FailureURL = "/signin"
+	return m
+}
+
+// SSOCallbackPath returns the path of the OAuth2 callback of the identity provider named name,
+// to be registered with the provider.
+func SSOCallbackPath(name string) string {
+	return "/api/v2/signin/" + ssoProviderPathName(name) + "/callback"
+}
+
+func ssoProviderPathName(name string) string {
+	return url.PathEscape(strings.ToLower(name))
+}
+
+// NewOAuth2Logger returns a chronograf.Logger, as used by the oauth2 providers, writing to l.
+func NewOAuth2Logger(l *zap.Logger) chronograf.Logger {
+	return &oauth2Logger{l}
+}
+
+type oauth2Logger struct {
+	*zap.Logger
+}
+
+func (l *oauth2Logger) Debug(v ...interface{}) { l.Logger.Debug(fmt.Sprint(v...)) }
+func (l *oauth2Logger) Info(v ...interface{})  { l.Logger.Info(fmt.Sprint(v...)) }
+func (l *oauth2Logger) Error(v ...interface{}) { l.Logger.Error(fmt.Sprint(v...)) }
+
+func (l *oauth2Logger) WithField(key string, value interface{}) chronograf.Logger {
+	return &oauth2Logger{l.Logger.With(zap.String(key, fmt.Sprint(value)))}
+}
+
+func (l *oauth2Logger) Writer() *io.PipeWriter {
+	r, w := io.Pipe()
+	go func() {
+		s := bufio.NewScanner(r)
+		for s.Scan() {
+			l.Logger.Info(s.Text())
+		}
+	}()
+	return w
+}
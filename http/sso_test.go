@@ -0,0 +1,243 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/chronograf"
+	"github.com/influxdata/platform/chronograf/oauth2"
+	"github.com/influxdata/platform/inmem"
+	"github.com/influxdata/platform/mock"
+	"go.uber.org/zap"
+)
+
+// newOIDCServer returns a stand-in OpenID Connect provider authenticating users as email with
+// the groups returned by groups.
+func newOIDCServer(email string, groups func() []string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/token":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token": "access",
+				"token_type":   "bearer",
+				"expires_in":   3600,
+			})
+		case "/userinfo":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"email":  email,
+				"groups": groups(),
+			})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestSessionHandler_SSO(t *testing.T) {
+	ctx := context.Background()
+	groups := []string{"admins", "ops"}
+	idp := newOIDCServer("alice@example.com", func() []string { return groups })
+	defer idp.Close()
+
+	svc := inmem.NewService()
+	org := &platform.Organization{Name: "example"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+
+	sessions := mock.NewSessionService()
+	sessions.CreateSessionFn = func(ctx context.Context, user string) (*platform.Session, error) {
+		return &platform.Session{Key: "key-" + user, ExpiresAt: time.Now().Add(time.Hour)}, nil
+	}
+
+	provider := &oauth2.Generic{
+		ClientID:     "influxd",
+		ClientSecret: "secret",
+		AuthURL:      idp.URL + "/authorize",
+		TokenURL:     idp.URL + "/token",
+		APIURL:       idp.URL + "/userinfo",
+		APIKey:       "email",
+		GroupsKey:    "groups",
+		RedirectURL:  "http://influxd" + SSOCallbackPath("generic"),
+		Logger:       &chronograf.NoopLogger{},
+	}
+	auth := &SSOAuthenticator{
+		Logger:                     zap.NewNop(),
+		UserService:                svc,
+		UserResourceMappingService: svc,
+		SessionService:             sessions,
+		SSOIdentityService:         svc,
+		GroupMappings: []SSOGroupMapping{
+			{Group: "ops", OrganizationID: org.ID, UserType: platform.Member},
+			{Group: "admins", OrganizationID: org.ID, UserType: platform.Owner},
+		},
+	}
+	h := NewSessionHandler(&SessionBackend{
+		Logger:         zap.NewNop(),
+		SessionService: sessions,
+		SSOProviders:   []*oauth2.AuthMux{NewSSOAuthMux(provider, auth, oauth2.NewJWT("state-secret", ""), false, zap.NewNop())},
+	})
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		return w
+	}
+
+	w := serve("/api/v2/signin")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"signin":"/api/v2/signin/generic"`) {
+		t.Fatalf("unexpected providers: %d %s", w.Code, w.Body.String())
+	}
+	if w := serve("/api/v2/signin/github"); w.Code != http.StatusNotFound {
+		t.Fatalf("unexpected status of unknown provider: %d", w.Code)
+	}
+
+	signin := func() *httptest.ResponseRecorder {
+		t.Helper()
+		w := serve("/api/v2/signin/generic")
+		if w.Code != http.StatusTemporaryRedirect {
+			t.Fatalf("unexpected status of sign in: %d", w.Code)
+		}
+		loc, err := url.Parse(w.Header().Get("Location"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := loc.Scheme+"://"+loc.Host+loc.Path, idp.URL+"/authorize"; got != want {
+			t.Fatalf("unexpected redirect: got %s, want %s", got, want)
+		}
+		return serve("/api/v2/signin/generic/callback?" + url.Values{
+			"state": {loc.Query().Get("state")},
+			"code":  {"code"},
+		}.Encode())
+	}
+	membership := func() platform.UserType {
+		t.Helper()
+		u, err := svc.FindUser(ctx, platform.UserFilter{Name: &[]string{"alice@example.com"}[0]})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ms, _, err := svc.FindUserResourceMappings(ctx, platform.UserResourceMappingFilter{UserID: u.ID, Resource: platform.OrgsResource})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range ms {
+			if m.ResourceID == org.ID {
+				return m.UserType
+			}
+		}
+		return ""
+	}
+
+	// The first sign in provisions the user, with the membership of its groups.
+	w = signin()
+	if w.Code != http.StatusTemporaryRedirect || w.Header().Get("Location") != "/" {
+		t.Fatalf("unexpected callback response: %d %v", w.Code, w.Header())
+	}
+	if got := w.Header().Get("Set-Cookie"); got != "session=key-alice@example.com" {
+		t.Fatalf("unexpected session cookie: %q", got)
+	}
+	if got := membership(); got != platform.Owner {
+		t.Fatalf("unexpected membership: %q", got)
+	}
+
+	// Memberships follow the groups of later sign ins.
+	groups = []string{"ops"}
+	if w := signin(); w.Header().Get("Location") != "/" {
+		t.Fatalf("unexpected callback response: %d %v", w.Code, w.Header())
+	}
+	if got := membership(); got != platform.Member {
+		t.Fatalf("unexpected membership: %q", got)
+	}
+	groups = nil
+	if w := signin(); w.Header().Get("Location") != "/" {
+		t.Fatalf("unexpected callback response: %d %v", w.Code, w.Header())
+	}
+	if got := membership(); got != "" {
+		t.Fatalf("unexpected membership: %q", got)
+	}
+
+	// A callback without valid state is rejected.
+	w = serve("/api/v2/signin/generic/callback?state=forged&code=code")
+	if w.Header().Get("Location") != "/signin" || w.Header().Get("Set-Cookie") != "" {
+		t.Fatalf("unexpected response to forged callback: %d %v", w.Code, w.Header())
+	}
+}
+
+func TestSSOAuthenticator_Authorize(t *testing.T) {
+	ctx := context.Background()
+	svc := inmem.NewService()
+	sessions := mock.NewSessionService()
+	sessions.CreateSessionFn = func(ctx context.Context, user string) (*platform.Session, error) {
+		return &platform.Session{Key: "key-" + user, ExpiresAt: time.Now().Add(time.Hour)}, nil
+	}
+	auth := &SSOAuthenticator{
+		Logger:                     zap.NewNop(),
+		UserService:                svc,
+		UserResourceMappingService: svc,
+		SessionService:             sessions,
+		SSOIdentityService:         svc,
+	}
+
+	admin := &platform.User{Name: "admin"}
+	if err := svc.CreateUser(ctx, admin); err != nil {
+		t.Fatal(err)
+	}
+
+	// A principal is never signed in as a local user of the same name.
+	if err := auth.Authorize(ctx, httptest.NewRecorder(), oauth2.Principal{Subject: "admin", Issuer: "github"}); platform.ErrorCode(err) != platform.EForbidden {
+		t.Fatalf("expected sign in as a local user to be forbidden, got %v", err)
+	}
+
+	// A principal is linked to the user provisioned on its first sign in, which is not
+	// shared with the same subject at another provider.
+	if err := auth.Authorize(ctx, httptest.NewRecorder(), oauth2.Principal{Subject: "alice", Issuer: "github"}); err != nil {
+		t.Fatal(err)
+	}
+	i, err := svc.FindSSOIdentity(ctx, "github", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u, err := svc.FindUserByID(ctx, i.UserID); err != nil || u.Name != "alice" {
+		t.Fatalf("unexpected linked user: %v %v", u, err)
+	}
+	if err := auth.Authorize(ctx, httptest.NewRecorder(), oauth2.Principal{Subject: "alice", Issuer: "github"}); err != nil {
+		t.Fatalf("expected linked principal to sign in again: %v", err)
+	}
+	if err := auth.Authorize(ctx, httptest.NewRecorder(), oauth2.Principal{Subject: "alice", Issuer: "generic"}); platform.ErrorCode(err) != platform.EForbidden {
+		t.Fatalf("expected sign in as the user of another provider to be forbidden, got %v", err)
+	}
+}
+
+func TestParseSSOGroupMapping(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    SSOGroupMapping
+		wantErr bool
+	}{
+		{s: "ops=0000000000000001", want: SSOGroupMapping{Group: "ops", OrganizationID: 1, UserType: platform.Member}},
+		{s: "cn=admins=0000000000000002:owner", want: SSOGroupMapping{Group: "cn=admins", OrganizationID: 2, UserType: platform.Owner}},
+		{s: "ops", wantErr: true},
+		{s: "ops=0000000000000001:admin", wantErr: true},
+		{s: "ops=org", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseSSOGroupMapping(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("unexpected mapping: got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
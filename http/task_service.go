@@ -46,6 +46,8 @@ const (
 	tasksIDRunsIDPath      = "/api/v2/tasks/:id/runs/:rid"
 	tasksIDRunsIDLogsPath  = "/api/v2/tasks/:id/runs/:rid/logs"
 	tasksIDRunsIDRetryPath = "/api/v2/tasks/:id/runs/:rid/retry"
+	tasksIDBackfillPath    = "/api/v2/tasks/:id/backfill"
+	tasksIDBackfillIDPath  = "/api/v2/tasks/:id/backfill/:bid"
 	tasksIDLabelsPath      = "/api/v2/tasks/:id/labels"
 	tasksIDLabelsNamePath  = "/api/v2/tasks/:id/labels/:name"
 )
@@ -85,14 +87,28 @@ func NewTaskHandler(mappingService platform.UserResourceMappingService, labelSer
 	h.HandlerFunc("POST", tasksIDRunsIDRetryPath, h.handleRetryRun)
 	h.HandlerFunc("DELETE", tasksIDRunsIDPath, h.handleCancelRun)
 
-	h.HandlerFunc("GET", tasksIDLabelsPath, newGetLabelsHandler(h.LabelService))
-	h.HandlerFunc("POST", tasksIDLabelsPath, newPostLabelHandler(h.LabelService))
-	h.HandlerFunc("DELETE", tasksIDLabelsNamePath, newDeleteLabelHandler(h.LabelService))
-	h.HandlerFunc("PATCH", tasksIDLabelsNamePath, newPatchLabelHandler(h.LabelService))
+	h.HandlerFunc("GET", tasksIDBackfillPath, h.handleGetBackfills)
+	h.HandlerFunc("POST", tasksIDBackfillPath, h.handlePostBackfill)
+	h.HandlerFunc("DELETE", tasksIDBackfillIDPath, h.handleCancelBackfill)
+
+	authorizeLabels := orgLabelAuthorizer(h.taskOrg)
+	h.HandlerFunc("GET", tasksIDLabelsPath, newGetLabelsHandler(h.LabelService, authorizeLabels))
+	h.HandlerFunc("POST", tasksIDLabelsPath, newPostLabelHandler(h.LabelService, authorizeLabels))
+	h.HandlerFunc("DELETE", tasksIDLabelsNamePath, newDeleteLabelHandler(h.LabelService, authorizeLabels))
+	h.HandlerFunc("PATCH", tasksIDLabelsNamePath, newPatchLabelHandler(h.LabelService, authorizeLabels))
 
 	return h
 }
 
+// taskOrg returns the organization of the task id, whose labels are scoped by it.
+func (h *TaskHandler) taskOrg(ctx context.Context, id platform.ID) (platform.ID, error) {
+	t, err := h.TaskService.FindTaskByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return t.Organization, nil
+}
+
 type taskResponse struct {
 	Links  map[string]string `json:"links"`
 	Labels []platform.Label  `json:"labels"`
@@ -201,6 +217,43 @@ func newRunsResponse(rs []*platform.Run, taskID platform.ID) runsResponse {
 	return r
 }
 
+type backfillResponse struct {
+	Links map[string]string `json:"links,omitempty"`
+	platform.Backfill
+}
+
+func newBackfillResponse(b platform.Backfill) backfillResponse {
+	return backfillResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/tasks/%s/backfill/%s", b.TaskID, b.ID),
+			"task": fmt.Sprintf("/api/v2/tasks/%s", b.TaskID),
+			"runs": fmt.Sprintf("/api/v2/tasks/%s/runs", b.TaskID),
+		},
+		Backfill: b,
+	}
+}
+
+type backfillsResponse struct {
+	Links     map[string]string   `json:"links"`
+	Backfills []*backfillResponse `json:"backfills"`
+}
+
+func newBackfillsResponse(bs []*platform.Backfill, taskID platform.ID) backfillsResponse {
+	r := backfillsResponse{
+		Links: map[string]string{
+			"self": fmt.Sprintf("/api/v2/tasks/%s/backfill", taskID),
+			"task": fmt.Sprintf("/api/v2/tasks/%s", taskID),
+		},
+		Backfills: make([]*backfillResponse, len(bs)),
+	}
+
+	for i := range bs {
+		b := newBackfillResponse(*bs[i])
+		r.Backfills[i] = &b
+	}
+	return r
+}
+
 func (h *TaskHandler) handleGetTasks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -534,6 +587,33 @@ func decodeGetLogsRequest(ctx context.Context, r *http.Request, orgs platform.Or
 		req.filter.Run = id
 	}
 
+	var afterTime, beforeTime time.Time
+	if at := qp.Get("afterTime"); at != "" {
+		afterTime, err = time.Parse(time.RFC3339, at)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.AfterTime = at
+	}
+	if bt := qp.Get("beforeTime"); bt != "" {
+		beforeTime, err = time.Parse(time.RFC3339, bt)
+		if err != nil {
+			return nil, err
+		}
+		req.filter.BeforeTime = bt
+	}
+	if req.filter.AfterTime != "" && req.filter.BeforeTime != "" && !beforeTime.After(afterTime) {
+		return nil, kerrors.InvalidDataf("beforeTime must be later than afterTime")
+	}
+
+	if status := qp.Get("status"); status != "" {
+		if err := validateRunStatus(status); err != nil {
+			return nil, err
+		}
+		req.filter.Status = status
+	}
+	req.filter.Match = qp.Get("match")
+
 	return req, nil
 }
 
@@ -636,9 +716,27 @@ func decodeGetRunsRequest(ctx context.Context, r *http.Request, orgs platform.Or
 		return nil, kerrors.InvalidDataf("beforeTime must be later than afterTime")
 	}
 
+	if status := qp.Get("status"); status != "" {
+		if err := validateRunStatus(status); err != nil {
+			return nil, err
+		}
+		req.filter.Status = status
+	}
+	req.filter.Match = qp.Get("match")
+
 	return req, nil
 }
 
+// validateRunStatus returns an error if status is not the name of a run status.
+func validateRunStatus(status string) error {
+	for s := backend.RunStarted; s <= backend.RunScheduled; s++ {
+		if s.String() == status {
+			return nil
+		}
+	}
+	return kerrors.InvalidDataf("unknown run status %q", status)
+}
+
 func (h *TaskHandler) handleForceRun(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -847,6 +945,132 @@ func decodeRetryRunRequest(ctx context.Context, r *http.Request) (*retryRunReque
 	}, nil
 }
 
+func (h *TaskHandler) handleGetBackfills(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, err := decodeTaskIDParam(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	backfills, err := h.TaskService.FindBackfills(ctx, taskID)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusOK, newBackfillsResponse(backfills, taskID)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+func (h *TaskHandler) handlePostBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	req, err := decodePostBackfillRequest(ctx, r)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	backfill, err := h.TaskService.BackfillTask(ctx, req.TaskID, req.Start, req.Stop)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := encodeResponse(ctx, w, http.StatusCreated, newBackfillResponse(*backfill)); err != nil {
+		logEncodingError(h.logger, r, err)
+		return
+	}
+}
+
+type postBackfillRequest struct {
+	TaskID      platform.ID
+	Start, Stop int64
+}
+
+func decodePostBackfillRequest(ctx context.Context, r *http.Request) (*postBackfillRequest, error) {
+	taskID, err := decodeTaskIDParam(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var req struct {
+		Start string `json:"start"`
+		Stop  string `json:"stop"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return nil, err
+	}
+	if req.Start == "" || req.Stop == "" {
+		return nil, kerrors.InvalidDataf("you must provide a start and stop time")
+	}
+
+	start, err := time.Parse(time.RFC3339, req.Start)
+	if err != nil {
+		return nil, err
+	}
+	stop, err := time.Parse(time.RFC3339, req.Stop)
+	if err != nil {
+		return nil, err
+	}
+	if start.After(stop) {
+		return nil, kerrors.InvalidDataf("start must not be later than stop")
+	}
+
+	return &postBackfillRequest{
+		TaskID: taskID,
+		Start:  start.Unix(),
+		Stop:   stop.Unix(),
+	}, nil
+}
+
+func (h *TaskHandler) handleCancelBackfill(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	taskID, err := decodeTaskIDParam(ctx)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	bid := httprouter.ParamsFromContext(ctx).ByName("bid")
+	if bid == "" {
+		EncodeError(ctx, kerrors.InvalidDataf("you must provide a backfill ID"), w)
+		return
+	}
+	var backfillID platform.ID
+	if err := backfillID.DecodeFromString(bid); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.TaskService.CancelBackfill(ctx, taskID, backfillID); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// decodeTaskIDParam returns the task ID from the request's route parameters.
+func decodeTaskIDParam(ctx context.Context) (platform.ID, error) {
+	params := httprouter.ParamsFromContext(ctx)
+	tid := params.ByName("id")
+	if tid == "" {
+		return platform.InvalidID(), kerrors.InvalidDataf("you must provide a task ID")
+	}
+
+	var ti platform.ID
+	if err := ti.DecodeFromString(tid); err != nil {
+		return platform.InvalidID(), err
+	}
+	return ti, nil
+}
+
 // TaskService connects to Influx via HTTP using tokens to manage tasks.
 type TaskService struct {
 	Addr               string
@@ -1072,6 +1296,18 @@ func (t TaskService) FindLogs(ctx context.Context, filter platform.LogFilter) ([
 	if filter.Org != nil {
 		val.Set("orgID", filter.Org.String())
 	}
+	if filter.AfterTime != "" {
+		val.Set("afterTime", filter.AfterTime)
+	}
+	if filter.BeforeTime != "" {
+		val.Set("beforeTime", filter.BeforeTime)
+	}
+	if filter.Status != "" {
+		val.Set("status", filter.Status)
+	}
+	if filter.Match != "" {
+		val.Set("match", filter.Match)
+	}
 	u.RawQuery = val.Encode()
 
 	req, err := http.NewRequest("GET", u.String(), nil)
@@ -1118,6 +1354,21 @@ func (t TaskService) FindRuns(ctx context.Context, filter platform.RunFilter) ([
 	if filter.After != nil {
 		val.Set("after", filter.After.String())
 	}
+	if filter.Limit > 0 {
+		val.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	if filter.AfterTime != "" {
+		val.Set("afterTime", filter.AfterTime)
+	}
+	if filter.BeforeTime != "" {
+		val.Set("beforeTime", filter.BeforeTime)
+	}
+	if filter.Status != "" {
+		val.Set("status", filter.Status)
+	}
+	if filter.Match != "" {
+		val.Set("match", filter.Match)
+	}
 	u.RawQuery = val.Encode()
 	req, err := http.NewRequest("GET", u.String(), nil)
 	if err != nil {
@@ -1311,6 +1562,125 @@ func (t TaskService) CancelRun(ctx context.Context, taskID, runID platform.ID) e
 	return nil
 }
 
+// BackfillTask requests runs of the task for every schedule between start and stop.
+func (t TaskService) BackfillTask(ctx context.Context, taskID platform.ID, start, stop int64) (*platform.Backfill, error) {
+	u, err := newURL(t.Addr, taskIDBackfillPath(taskID))
+	if err != nil {
+		return nil, err
+	}
+
+	body := fmt.Sprintf(`{"start": %q, "stop": %q}`,
+		time.Unix(start, 0).UTC().Format(time.RFC3339),
+		time.Unix(stop, 0).UTC().Format(time.RFC3339),
+	)
+	req, err := http.NewRequest("POST", u.String(), strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	SetToken(t.Token, req)
+
+	hc := newClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		// RequestStillQueuedError is part of the contract.
+		if e := backend.ParseRequestStillQueuedError(err.Error()); e != nil {
+			return nil, *e
+		}
+
+		return nil, err
+	}
+
+	bs := &backfillResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(bs); err != nil {
+		return nil, err
+	}
+	return &bs.Backfill, nil
+}
+
+// FindBackfills returns the backfills of the task that have runs yet to be created.
+func (t TaskService) FindBackfills(ctx context.Context, taskID platform.ID) ([]*platform.Backfill, error) {
+	u, err := newURL(t.Addr, taskIDBackfillPath(taskID))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	SetToken(t.Token, req)
+
+	hc := newClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		return nil, err
+	}
+
+	var bs backfillsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&bs); err != nil {
+		return nil, err
+	}
+
+	backfills := make([]*platform.Backfill, len(bs.Backfills))
+	for i := range bs.Backfills {
+		backfills[i] = &bs.Backfills[i].Backfill
+	}
+	return backfills, nil
+}
+
+// CancelBackfill stops a backfill from creating more runs, and cancels its runs in progress.
+func (t TaskService) CancelBackfill(ctx context.Context, taskID, backfillID platform.ID) error {
+	u, err := newURL(t.Addr, path.Join(taskIDBackfillPath(taskID), backfillID.String()))
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("DELETE", u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	SetToken(t.Token, req)
+
+	hc := newClient(u.Scheme, t.InsecureSkipVerify)
+
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := CheckError(resp); err != nil {
+		if err.Error() == backend.ErrManualRunNotFound.Error() {
+			// ErrManualRunNotFound is expected as part of the CancelBackfill contract,
+			// so return that actual error instead of a different error that looks like it.
+			return backend.ErrManualRunNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func taskIDBackfillPath(id platform.ID) string {
+	return path.Join(tasksPath, id.String(), "backfill")
+}
+
 func taskIDPath(id platform.ID) string {
 	return path.Join(tasksPath, id.String())
 }
@@ -68,14 +68,24 @@ func NewTelegrafHandler(
 	h.HandlerFunc("GET", telegrafsIDOwnersPath, newGetMembersHandler(h.UserResourceMappingService, h.UserService, platform.TelegrafsResource, platform.Owner))
 	h.HandlerFunc("DELETE", telegrafsIDOwnersIDPath, newDeleteMemberHandler(h.UserResourceMappingService, platform.Owner))
 
-	h.HandlerFunc("GET", telegrafsIDLabelsPath, newGetLabelsHandler(h.LabelService))
-	h.HandlerFunc("POST", telegrafsIDLabelsPath, newPostLabelHandler(h.LabelService))
-	h.HandlerFunc("DELETE", telegrafsIDLabelsNamePath, newDeleteLabelHandler(h.LabelService))
-	h.HandlerFunc("PATCH", telegrafsIDLabelsNamePath, newPatchLabelHandler(h.LabelService))
+	authorizeLabels := orgLabelAuthorizer(h.telegrafOrg)
+	h.HandlerFunc("GET", telegrafsIDLabelsPath, newGetLabelsHandler(h.LabelService, authorizeLabels))
+	h.HandlerFunc("POST", telegrafsIDLabelsPath, newPostLabelHandler(h.LabelService, authorizeLabels))
+	h.HandlerFunc("DELETE", telegrafsIDLabelsNamePath, newDeleteLabelHandler(h.LabelService, authorizeLabels))
+	h.HandlerFunc("PATCH", telegrafsIDLabelsNamePath, newPatchLabelHandler(h.LabelService, authorizeLabels))
 
 	return h
 }
 
+// telegrafOrg returns the organization of the telegraf config id, whose labels are scoped by it.
+func (h *TelegrafHandler) telegrafOrg(ctx context.Context, id platform.ID) (platform.ID, error) {
+	tc, err := h.TelegrafService.FindTelegrafConfigByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return tc.OrganizationID, nil
+}
+
 type telegrafLinks struct {
 	Self   string `json:"self"`
 	Labels string `json:"labels"`
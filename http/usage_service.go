@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
 	"github.com/julienschmidt/httprouter"
 	"go.uber.org/zap"
 )
@@ -41,6 +42,11 @@ func (h *UsageHandler) handleGetUsage(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := authorizeUsage(ctx, req.filter); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
 	b, err := h.UsageService.GetUsage(ctx, req.filter)
 	if err != nil {
 		EncodeError(ctx, err, w)
@@ -53,6 +59,40 @@ func (h *UsageHandler) handleGetUsage(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// authorizeUsage returns an error unless the authorizer in ctx may read the organization or bucket in filter.
+// Usage across all organizations requires permission to read every organization.
+// The usage of a bucket is only allowed by permissions scoped to an organization if filter has its OrgID.
+func authorizeUsage(ctx context.Context, filter platform.UsageFilter) error {
+	a, err := pcontext.GetAuthorizer(ctx)
+	if err != nil {
+		return err
+	}
+
+	var p *platform.Permission
+	switch {
+	case filter.BucketID != nil && filter.OrgID != nil:
+		p, err = platform.NewPermissionAtIDInOrg(*filter.OrgID, *filter.BucketID, platform.ReadAction, platform.BucketsResource)
+	case filter.BucketID != nil:
+		p, err = platform.NewPermissionAtID(*filter.BucketID, platform.ReadAction, platform.BucketsResource)
+	case filter.OrgID != nil:
+		p, err = platform.NewPermissionAtID(*filter.OrgID, platform.ReadAction, platform.OrgsResource)
+	default:
+		p, err = platform.NewPermission(platform.ReadAction, platform.OrgsResource)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !a.Allowed(*p) {
+		return &platform.Error{
+			Code: platform.EForbidden,
+			Op:   "http/authorizeUsage",
+			Msg:  "insufficient permissions to read usage",
+		}
+	}
+	return nil
+}
+
 type getUsageRequest struct {
 	filter platform.UsageFilter
 }
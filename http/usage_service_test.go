@@ -0,0 +1,81 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+)
+
+// usageServiceFunc adapts a function to a platform.UsageService.
+type usageServiceFunc func(context.Context, platform.UsageFilter) (map[platform.UsageMetric]*platform.Usage, error)
+
+func (f usageServiceFunc) GetUsage(ctx context.Context, filter platform.UsageFilter) (map[platform.UsageMetric]*platform.Usage, error) {
+	return f(ctx, filter)
+}
+
+func TestUsageHandler_handleGetUsage(t *testing.T) {
+	orgID, otherOrgID := platform.ID(1), platform.ID(2)
+
+	tests := []struct {
+		name        string
+		query       string
+		permissions []platform.Permission
+		status      int
+	}{
+		{
+			name:        "org usage",
+			query:       "?orgID=" + orgID.String(),
+			permissions: []platform.Permission{{Action: platform.ReadAction, Resource: platform.OrgsResource, ID: &orgID}},
+			status:      http.StatusOK,
+		},
+		{
+			name:        "other org usage",
+			query:       "?orgID=" + otherOrgID.String(),
+			permissions: []platform.Permission{{Action: platform.ReadAction, Resource: platform.OrgsResource, ID: &orgID}},
+			status:      http.StatusForbidden,
+		},
+		{
+			name:        "all usage",
+			permissions: []platform.Permission{{Action: platform.ReadAction, Resource: platform.OrgsResource, ID: &orgID}},
+			status:      http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewUsageHandler()
+			h.UsageService = usageServiceFunc(func(ctx context.Context, filter platform.UsageFilter) (map[platform.UsageMetric]*platform.Usage, error) {
+				return map[platform.UsageMetric]*platform.Usage{
+					platform.UsageSeries: {OrganizationID: filter.OrgID, Type: platform.UsageSeries, Value: 42},
+				}, nil
+			})
+
+			r := httptest.NewRequest("GET", "/api/v2/usage"+tt.query, nil)
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: tt.permissions,
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got := w.Code; got != tt.status {
+				t.Fatalf("unexpected status: got %d, want %d; body: %s", got, tt.status, w.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+
+			var res map[platform.UsageMetric]*platform.Usage
+			if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+				t.Fatal(err)
+			}
+			if u := res[platform.UsageSeries]; u == nil || u.Value != 42 || *u.OrganizationID != orgID {
+				t.Fatalf("unexpected usage: %+v", u)
+			}
+		})
+	}
+}
@@ -62,10 +62,11 @@ func NewViewHandler(mappingService platform.UserResourceMappingService, labelSer
 	h.HandlerFunc("GET", viewsIDOwnersPath, newGetMembersHandler(h.UserResourceMappingService, h.UserService, platform.DashboardsResource, platform.Owner))
 	h.HandlerFunc("DELETE", viewsIDOwnersIDPath, newDeleteMemberHandler(h.UserResourceMappingService, platform.Owner))
 
-	h.HandlerFunc("GET", viewsIDLabelsPath, newGetLabelsHandler(h.LabelService))
-	h.HandlerFunc("POST", viewsIDLabelsPath, newPostLabelHandler(h.LabelService))
-	h.HandlerFunc("DELETE", viewsIDLabelsNamePath, newDeleteLabelHandler(h.LabelService))
-	h.HandlerFunc("PATCH", viewsIDLabelsNamePath, newPatchLabelHandler(h.LabelService))
+	authorizeLabels := resourceLabelAuthorizer(platform.ViewsResource)
+	h.HandlerFunc("GET", viewsIDLabelsPath, newGetLabelsHandler(h.LabelService, authorizeLabels))
+	h.HandlerFunc("POST", viewsIDLabelsPath, newPostLabelHandler(h.LabelService, authorizeLabels))
+	h.HandlerFunc("DELETE", viewsIDLabelsNamePath, newDeleteLabelHandler(h.LabelService, authorizeLabels))
+	h.HandlerFunc("PATCH", viewsIDLabelsNamePath, newPatchLabelHandler(h.LabelService, authorizeLabels))
 
 	return h
 }
@@ -117,6 +118,15 @@ func (h *ViewHandler) handleGetViews(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Only the views that can be read are returned.
+	allowed := views[:0]
+	for _, v := range views {
+		if authorize(ctx, platform.Permission{Action: platform.ReadAction, Resource: platform.ViewsResource, ID: &v.ID}) == nil {
+			allowed = append(allowed, v)
+		}
+	}
+	views = allowed
+
 	if err := encodeResponse(ctx, w, http.StatusOK, newGetViewsResponse(views)); err != nil {
 		logEncodingError(h.Logger, r, err)
 		return
@@ -170,10 +180,18 @@ func (h *ViewHandler) handlePostViews(w http.ResponseWriter, r *http.Request) {
 		EncodeError(ctx, err, w)
 		return
 	}
+	if err := authorizeCreate(ctx, platform.ViewsResource); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
 	if err := h.ViewService.CreateView(ctx, req.View); err != nil {
 		EncodeError(ctx, err, w)
 		return
 	}
+	if err := addCreator(ctx, h.UserResourceMappingService, platform.ViewsResource, req.View.ID); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
 
 	if err := encodeResponse(ctx, w, http.StatusCreated, newViewResponse(req.View)); err != nil {
 		logEncodingError(h.Logger, r, err)
@@ -205,6 +223,11 @@ func (h *ViewHandler) handleGetView(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := authorize(ctx, platform.Permission{Action: platform.ReadAction, Resource: platform.ViewsResource, ID: &req.ViewID}); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
 	view, err := h.ViewService.FindViewByID(ctx, req.ViewID)
 	if err != nil {
 		EncodeError(ctx, err, w)
@@ -251,6 +274,11 @@ func (h *ViewHandler) handleDeleteView(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := authorize(ctx, platform.Permission{Action: platform.DeleteAction, Resource: platform.ViewsResource, ID: &req.ViewID}); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
 	if err := h.ViewService.DeleteView(ctx, req.ViewID); err != nil {
 		EncodeError(ctx, err, w)
 		return
@@ -292,6 +320,10 @@ func (h *ViewHandler) handlePatchView(w http.ResponseWriter, r *http.Request) {
 		EncodeError(ctx, pe, w)
 		return
 	}
+	if err := authorize(ctx, platform.Permission{Action: platform.WriteAction, Resource: platform.ViewsResource, ID: &req.ViewID}); err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
 	view, err := h.ViewService.UpdateView(ctx, req.ViewID, req.Upd)
 	if err != nil {
 		EncodeError(ctx, err, w)
@@ -0,0 +1,255 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/influxdata/flux"
+	"github.com/influxdata/flux/csv"
+	"github.com/influxdata/flux/execute"
+	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/platform/models"
+)
+
+// Content types accepted by the write endpoint.
+const (
+	writeContentTypeLineProtocol = "text/plain"
+	writeContentTypeCSV          = "text/csv"
+	writeContentTypeJSON         = "application/json"
+)
+
+// Column labels used when converting annotated CSV rows to points.
+const (
+	csvMeasurementColLabel = "_measurement"
+	csvFieldColLabel       = "_field"
+)
+
+// writeFormat returns the format of a write request body from its Content-Type header.
+// Any type other than CSV or JSON is treated as line protocol, since clients such as curl
+// send line protocol with whatever Content-Type they default to.
+func writeFormat(contentType string) string {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return writeContentTypeLineProtocol
+	}
+
+	switch mt {
+	case writeContentTypeCSV, "application/csv":
+		return writeContentTypeCSV
+	case writeContentTypeJSON:
+		return writeContentTypeJSON
+	default:
+		return writeContentTypeLineProtocol
+	}
+}
+
+// parseCSVPoints converts the rows of annotated CSV, as returned by the query endpoint, into points.
+// Every table must have _measurement, _field, _value, and _time columns.
+// The remaining string columns are written as tags; all other columns are ignored.
+func parseCSVPoints(data []byte) ([]models.Point, error) {
+	dec := csv.NewMultiResultDecoder(csv.ResultDecoderConfig{})
+	results, err := dec.Decode(ioutil.NopCloser(bytes.NewReader(data)))
+	if err != nil {
+		return nil, err
+	}
+	defer results.Release()
+
+	var points []models.Point
+	for results.More() {
+		res := results.Next()
+		if err := res.Tables().Do(func(tbl flux.Table) error {
+			pts, err := tablePoints(tbl)
+			if err != nil {
+				return fmt.Errorf("result %q: %v", res.Name(), err)
+			}
+			points = append(points, pts...)
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if err := results.Err(); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// tablePoints converts each row of tbl into a point.
+func tablePoints(tbl flux.Table) ([]models.Point, error) {
+	cols := tbl.Cols()
+	measurementIdx := execute.ColIdx(csvMeasurementColLabel, cols)
+	fieldIdx := execute.ColIdx(csvFieldColLabel, cols)
+	valueIdx := execute.ColIdx(execute.DefaultValueColLabel, cols)
+	timeIdx := execute.ColIdx(execute.DefaultTimeColLabel, cols)
+
+	for _, label := range []string{csvMeasurementColLabel, csvFieldColLabel, execute.DefaultValueColLabel, execute.DefaultTimeColLabel} {
+		if execute.ColIdx(label, cols) < 0 {
+			return nil, fmt.Errorf("table has no %s column", label)
+		}
+	}
+	if cols[measurementIdx].Type != flux.TString || cols[fieldIdx].Type != flux.TString {
+		return nil, fmt.Errorf("%s and %s columns must be strings", csvMeasurementColLabel, csvFieldColLabel)
+	}
+	if cols[timeIdx].Type != flux.TTime {
+		return nil, fmt.Errorf("column %s of type %s is not of type %s", execute.DefaultTimeColLabel, cols[timeIdx].Type, flux.TTime)
+	}
+
+	var tagIdxs []int
+	for j, col := range cols {
+		if col.Type == flux.TString && j != measurementIdx && j != fieldIdx && j != valueIdx {
+			tagIdxs = append(tagIdxs, j)
+		}
+	}
+
+	var points []models.Point
+	err := tbl.Do(func(cr flux.ColReader) error {
+		for i := 0; i < cr.Len(); i++ {
+			tags := make(models.Tags, 0, len(tagIdxs))
+			for _, j := range tagIdxs {
+				if v := cr.Strings(j)[i]; v != "" {
+					tags = append(tags, models.NewTag([]byte(cols[j].Label), []byte(v)))
+				}
+			}
+			sort.Sort(tags)
+
+			v, err := fieldValue(execute.ValueForRow(cr, i, valueIdx))
+			if err != nil {
+				return err
+			}
+			fields := models.Fields{cr.Strings(fieldIdx)[i]: v}
+
+			pt, err := models.NewPoint(cr.Strings(measurementIdx)[i], tags, fields, cr.Times(timeIdx)[i].Time())
+			if err != nil {
+				return err
+			}
+			points = append(points, pt)
+		}
+		return nil
+	})
+	return points, err
+}
+
+// fieldValue returns v as a value that can be stored in a field.
+func fieldValue(v values.Value) (interface{}, error) {
+	switch v.Type() {
+	case semantic.Float:
+		return v.Float(), nil
+	case semantic.Int:
+		return v.Int(), nil
+	case semantic.UInt:
+		return v.UInt(), nil
+	case semantic.String:
+		return v.Str(), nil
+	case semantic.Bool:
+		return v.Bool(), nil
+	default:
+		return nil, fmt.Errorf("unsupported field value type %s", v.Type())
+	}
+}
+
+// jsonPoint is a single point in the JSON write format.
+//
+// Fields hold JSON numbers, strings, or booleans. Numbers without a fraction or exponent are written as integers,
+// or as unsigned integers if they are too large for an integer, and all other numbers as floats.
+// Time is either a Unix timestamp in the precision of the request or an RFC3339 string; when omitted, the server time is used.
+type jsonPoint struct {
+	Measurement string                 `json:"measurement"`
+	Tags        map[string]string      `json:"tags"`
+	Fields      map[string]interface{} `json:"fields"`
+	Time        json.RawMessage        `json:"time"`
+}
+
+// parseJSONPoints converts a JSON array of points into points.
+func parseJSONPoints(data []byte, defaultTime time.Time, precision string) ([]models.Point, error) {
+	var jpts []jsonPoint
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&jpts); err != nil {
+		return nil, fmt.Errorf("unable to decode JSON points: %v", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unable to decode JSON points: unexpected data after the array of points")
+	}
+
+	points := make([]models.Point, 0, len(jpts))
+	for i, jpt := range jpts {
+		pt, err := jpt.point(defaultTime, precision)
+		if err != nil {
+			return nil, fmt.Errorf("point %d: %v", i, err)
+		}
+		points = append(points, pt)
+	}
+	return points, nil
+}
+
+func (p *jsonPoint) point(defaultTime time.Time, precision string) (models.Point, error) {
+	if p.Measurement == "" {
+		return nil, fmt.Errorf("missing measurement")
+	}
+	if len(p.Fields) == 0 {
+		return nil, fmt.Errorf("missing fields")
+	}
+
+	fields := make(models.Fields, len(p.Fields))
+	for k, v := range p.Fields {
+		switch v := v.(type) {
+		case json.Number:
+			n, err := jsonNumberValue(v)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %v", k, err)
+			}
+			fields[k] = n
+		case string, bool:
+			fields[k] = v
+		default:
+			return nil, fmt.Errorf("field %q must be a number, string, or boolean", k)
+		}
+	}
+
+	ts := defaultTime
+	if len(p.Time) > 0 && string(p.Time) != "null" {
+		var n int64
+		var s string
+		if err := json.Unmarshal(p.Time, &n); err == nil {
+			t, err := models.SafeCalcTime(n, precision)
+			if err != nil {
+				return nil, fmt.Errorf("invalid time: %v", err)
+			}
+			ts = t
+		} else if err := json.Unmarshal(p.Time, &s); err == nil {
+			t, err := time.Parse(time.RFC3339Nano, s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid time: %v", err)
+			}
+			ts = t
+		} else {
+			return nil, fmt.Errorf("time must be an integer or an RFC3339 string")
+		}
+	}
+
+	return models.NewPoint(p.Measurement, models.NewTags(p.Tags), fields, ts)
+}
+
+// jsonNumberValue returns n as an integer, unsigned integer, or float field value, following the JSON write format.
+func jsonNumberValue(n json.Number) (interface{}, error) {
+	if strings.ContainsAny(string(n), ".eE") {
+		return strconv.ParseFloat(string(n), 64)
+	}
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		return i, nil
+	}
+	u, err := strconv.ParseUint(string(n), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("integer %s out of range", n)
+	}
+	return u, nil
+}
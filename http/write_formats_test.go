@@ -0,0 +1,179 @@
+package http
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform/models"
+)
+
+func TestWriteFormat(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        string
+	}{
+		{contentType: "", want: writeContentTypeLineProtocol},
+		{contentType: "text/plain; charset=utf-8", want: writeContentTypeLineProtocol},
+		{contentType: "application/x-www-form-urlencoded", want: writeContentTypeLineProtocol},
+		{contentType: "text/csv", want: writeContentTypeCSV},
+		{contentType: "application/csv; charset=utf-8", want: writeContentTypeCSV},
+		{contentType: "application/json", want: writeContentTypeJSON},
+	}
+	for _, tt := range tests {
+		if got := writeFormat(tt.contentType); got != tt.want {
+			t.Errorf("writeFormat(%q) = %q, want %q", tt.contentType, got, tt.want)
+		}
+	}
+}
+
+func TestParseCSVPoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "query output",
+			csv: `#datatype,string,long,dateTime:RFC3339,dateTime:RFC3339,dateTime:RFC3339,double,string,string,string
+#group,false,false,true,true,false,false,true,true,true
+#default,_result,,,,,,,,
+,result,table,_start,_stop,_time,_value,_field,_measurement,host
+,,0,2018-01-01T00:00:00Z,2018-01-02T00:00:00Z,2018-01-01T00:00:01Z,1.5,usage,cpu,b
+,,0,2018-01-01T00:00:00Z,2018-01-02T00:00:00Z,2018-01-01T00:00:02Z,2.5,usage,cpu,b
+
+#datatype,string,long,dateTime:RFC3339,long,string,string,string,string
+#group,false,false,false,false,true,true,true,true
+#default,_result,,,,,,,
+,result,table,_time,_value,_field,_measurement,region,host
+,,1,2018-01-01T00:00:03Z,7,count,mem,west,a
+`,
+			want: []string{
+				"cpu,host=b usage=1.5 1514764801000000000",
+				"cpu,host=b usage=2.5 1514764802000000000",
+				"mem,host=a,region=west count=7i 1514764803000000000",
+			},
+		},
+		{
+			name: "missing field column",
+			csv: `#datatype,string,long,dateTime:RFC3339,double,string
+#group,false,false,false,false,true
+#default,_result,,,,
+,result,table,_time,_value,_measurement
+,,0,2018-01-01T00:00:01Z,1.5,cpu
+`,
+			wantErr: "table has no _field column",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points, err := parseCSVPoints([]byte(tt.csv))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertPoints(t, points, tt.want)
+		})
+	}
+}
+
+func TestParseJSONPoints(t *testing.T) {
+	now := time.Unix(0, 5)
+
+	tests := []struct {
+		name      string
+		json      string
+		precision string
+		want      []string
+		wantErr   string
+	}{
+		{
+			name:      "points",
+			precision: "s",
+			json: `[
+				{"measurement": "cpu", "tags": {"host": "a", "dc": "x"}, "fields": {"usage": 1, "ok": true}, "time": 10},
+				{"measurement": "cpu", "fields": {"msg": "hi"}, "time": "1970-01-01T00:00:20Z"},
+				{"measurement": "cpu", "fields": {"usage": 2.5}}
+			]`,
+			want: []string{
+				"cpu,dc=x,host=a ok=true,usage=1i 10000000000",
+				`cpu msg="hi" 20000000000`,
+				"cpu usage=2.5 5",
+			},
+		},
+		{
+			name: "numbers",
+			json: `[{"measurement": "cpu", "fields": {"i": -3, "u": 18446744073709551615, "f": 1.0, "e": 1e3}, "time": 1}]`,
+			want: []string{"cpu e=1000,f=1,i=-3i,u=18446744073709551615u 1"},
+		},
+		{
+			name:    "integer out of range",
+			json:    `[{"measurement": "cpu", "fields": {"usage": 18446744073709551616}}]`,
+			wantErr: `field "usage": integer 18446744073709551616 out of range`,
+		},
+		{
+			name:      "time out of range",
+			precision: "s",
+			json:      `[{"measurement": "cpu", "fields": {"usage": 1}, "time": 9223372037}]`,
+			wantErr:   "point 0: invalid time",
+		},
+		{
+			name:    "trailing data",
+			json:    `[{"measurement": "cpu", "fields": {"usage": 1}}] []`,
+			wantErr: "unexpected data after the array of points",
+		},
+		{
+			name:    "missing measurement",
+			json:    `[{"fields": {"usage": 1}}]`,
+			wantErr: "point 0: missing measurement",
+		},
+		{
+			name:    "unsupported field",
+			json:    `[{"measurement": "cpu", "fields": {"usage": [1]}}]`,
+			wantErr: `field "usage" must be a number, string, or boolean`,
+		},
+		{
+			name:    "not an array",
+			json:    `{"measurement": "cpu"}`,
+			wantErr: "unable to decode JSON points",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			precision := tt.precision
+			if precision == "" {
+				precision = "ns"
+			}
+			points, err := parseJSONPoints([]byte(tt.json), now, precision)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertPoints(t, points, tt.want)
+		})
+	}
+}
+
+func assertPoints(t *testing.T, points []models.Point, want []string) {
+	t.Helper()
+
+	if len(points) != len(want) {
+		t.Fatalf("got %d points, want %d: %v", len(points), len(want), points)
+	}
+	for i, pt := range points {
+		if got := pt.String(); got != want[i] {
+			t.Errorf("point %d: got %q, want %q", i, got, want[i])
+		}
+	}
+}
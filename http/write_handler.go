@@ -7,6 +7,9 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/influxdata/platform"
@@ -19,7 +22,7 @@ import (
 	"go.uber.org/zap"
 )
 
-// WriteHandler receives line protocol and sends to a publish function.
+// WriteHandler receives line protocol, annotated CSV, or JSON points and sends to a publish function.
 type WriteHandler struct {
 	*httprouter.Router
 
@@ -37,7 +40,7 @@ const (
 	errInvalidPrecision  = "invalid precision; valid precision units are ns, us, ms, and s"
 )
 
-// NewWriteHandler creates a new handler at /api/v2/write to receive line protocol.
+// NewWriteHandler creates a new handler at /api/v2/write to receive points.
 func NewWriteHandler(writer storage.PointsWriter) *WriteHandler {
 	h := &WriteHandler{
 		Router:       NewRouter(),
@@ -83,102 +86,288 @@ func (h *WriteHandler) handleWrite(w http.ResponseWriter, r *http.Request) {
 
 	logger := h.Logger.With(zap.String("org", req.Org), zap.String("bucket", req.Bucket))
 
+	org, bucket, err := findOrganizationAndBucket(ctx, logger, h.OrganizationService, h.BucketService, req.Org, req.Bucket)
+	if err != nil {
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	p, err := platform.NewPermissionAtIDInOrg(org.ID, bucket.ID, platform.WriteAction, platform.BucketsResource)
+	if err != nil {
+		EncodeError(ctx, fmt.Errorf("could not create permission for bucket: %v", err), w)
+		return
+	}
+
+	if !a.Allowed(*p) {
+		EncodeError(ctx, errors.Forbiddenf("insufficient permissions for write"), w)
+		return
+	}
+
+	// TODO(jeff): we should be publishing with the org and bucket instead of
+	// parsing, rewriting, and publishing, but the interface isn't quite there yet.
+	// be sure to remove this when it is there!
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		logger.Info("Error reading body", zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if req.Partial {
+		h.writePartial(ctx, w, r, logger, org.ID, bucket, data, req.Precision)
+		return
+	}
+
+	points, err := parsePoints(data, req.Format, req.Precision)
+	if err != nil {
+		logger.Info("Error parsing points", zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := validateSchema(storage.NewSchemaValidator(bucket), points); err != nil {
+		logger.Info("Points do not match bucket schema", zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	exploded, err := tsdb.ExplodePoints(org.ID, bucket.ID, points)
+	if err != nil {
+		logger.Info("Error exploding points", zap.Error(err))
+		EncodeError(ctx, err, w)
+		return
+	}
+
+	if err := h.PointsWriter.WritePoints(exploded); err != nil {
+		if err := engineModeError("http/handleWrite", err); err != nil {
+			EncodeError(ctx, err, w)
+			return
+		}
+		EncodeError(ctx, errors.BadRequestError(err.Error()), w)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findOrganizationAndBucket returns the organization and bucket referred to by org and bucket,
+// which may each be either an ID or a name.
+func findOrganizationAndBucket(ctx context.Context, logger *zap.Logger, orgSvc platform.OrganizationService, bucketSvc platform.BucketService, orgRef, bucketRef string) (*platform.Organization, *platform.Bucket, error) {
 	var org *platform.Organization
-	if id, err := platform.IDFromString(req.Org); err == nil {
+	if id, err := platform.IDFromString(orgRef); err == nil {
 		// Decoded ID successfully. Make sure it's a real org.
-		o, err := h.OrganizationService.FindOrganizationByID(ctx, *id)
+		o, err := orgSvc.FindOrganizationByID(ctx, *id)
 		if err == nil {
 			org = o
 		} else if platform.ErrorCode(err) != platform.ENotFound {
-			EncodeError(ctx, err, w)
-			return
+			return nil, nil, err
 		}
 	}
 	if org == nil {
-		o, err := h.OrganizationService.FindOrganization(ctx, platform.OrganizationFilter{Name: &req.Org})
+		o, err := orgSvc.FindOrganization(ctx, platform.OrganizationFilter{Name: &orgRef})
 		if err != nil {
 			logger.Info("Failed to find organization", zap.Error(err))
-			EncodeError(ctx, fmt.Errorf("organization %q not found", req.Org), w)
-			return
+			return nil, nil, fmt.Errorf("organization %q not found", orgRef)
 		}
 
 		org = o
 	}
 
 	var bucket *platform.Bucket
-	if id, err := platform.IDFromString(req.Bucket); err == nil {
+	if id, err := platform.IDFromString(bucketRef); err == nil {
 		// Decoded ID successfully. Make sure it's a real bucket.
-		b, err := h.BucketService.FindBucket(ctx, platform.BucketFilter{
+		b, err := bucketSvc.FindBucket(ctx, platform.BucketFilter{
 			OrganizationID: &org.ID,
 			ID:             id,
 		})
 		if err == nil {
 			bucket = b
 		} else if platform.ErrorCode(err) != platform.ENotFound {
-			EncodeError(ctx, err, w)
-			return
+			return nil, nil, err
 		}
 	}
 
 	if bucket == nil {
-		b, err := h.BucketService.FindBucket(ctx, platform.BucketFilter{
+		b, err := bucketSvc.FindBucket(ctx, platform.BucketFilter{
 			OrganizationID: &org.ID,
-			Name:           &req.Bucket,
+			Name:           &bucketRef,
 		})
 		if err != nil {
-			EncodeError(ctx, &platform.Error{
+			return nil, nil, &platform.Error{
 				Code: platform.ENotFound,
-				Op:   "http/handleWrite",
+				Op:   "http/findOrganizationAndBucket",
 				Err:  err,
-				Msg:  fmt.Sprintf("bucket %q not found", req.Bucket),
-			}, w)
-			return
+				Msg:  fmt.Sprintf("bucket %q not found", bucketRef),
+			}
 		}
 
 		bucket = b
 	}
 
-	p, err := platform.NewPermissionAtID(bucket.ID, platform.WriteAction, platform.BucketsResource)
+	return org, bucket, nil
+}
+
+// parsePoints parses the points in data according to format, one of the writeContentType constants.
+func parsePoints(data []byte, format, precision string) ([]models.Point, error) {
+	var points []models.Point
+	var err error
+	switch format {
+	case writeContentTypeCSV:
+		points, err = parseCSVPoints(data)
+	case writeContentTypeJSON:
+		points, err = parseJSONPoints(data, time.Now(), precision)
+	default:
+		return models.ParsePointsWithPrecision(data, time.Now(), precision)
+	}
 	if err != nil {
-		EncodeError(ctx, fmt.Errorf("could not create permission for bucket: %v", err), w)
-		return
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/parsePoints",
+			Msg:  fmt.Sprintf("unable to parse %s body", format),
+			Err:  err,
+		}
 	}
+	return points, nil
+}
 
-	if !a.Allowed(*p) {
-		EncodeError(ctx, errors.Forbiddenf("insufficient permissions for write"), w)
-		return
+// maxSchemaErrors is the number of schema violations reported when a write is rejected.
+const maxSchemaErrors = 10
+
+// validateSchema returns an error listing the points that do not match the bucket schema checked by v.
+func validateSchema(v *storage.SchemaValidator, points []models.Point) error {
+	if v == nil {
+		return nil
 	}
 
-	// TODO(jeff): we should be publishing with the org and bucket instead of
-	// parsing, rewriting, and publishing, but the interface isn't quite there yet.
-	// be sure to remove this when it is there!
-	data, err := ioutil.ReadAll(in)
-	if err != nil {
-		logger.Info("Error reading body", zap.Error(err))
-		EncodeError(ctx, err, w)
-		return
+	var msgs []string
+	rejected := 0
+	for i, pt := range points {
+		if err := v.Validate(pt); err != nil {
+			if rejected < maxSchemaErrors {
+				msgs = append(msgs, fmt.Sprintf("point %d: %v", i+1, err))
+			}
+			rejected++
+		}
 	}
+	if rejected == 0 {
+		return nil
+	}
+	if rejected > maxSchemaErrors {
+		msgs = append(msgs, fmt.Sprintf("and %d more", rejected-maxSchemaErrors))
+	}
+	return &platform.Error{
+		Code: platform.EInvalid,
+		Op:   "http/validateSchema",
+		Msg:  fmt.Sprintf("rejected %d of %d points that do not match the bucket schema: %s", rejected, len(points), strings.Join(msgs, "; ")),
+	}
+}
 
-	points, err := models.ParsePointsWithPrecision(data, time.Now(), req.Precision)
-	if err != nil {
-		logger.Info("Error parsing points", zap.Error(err))
-		EncodeError(ctx, err, w)
-		return
+// partialWriteCode is the error code of a response to a partial write in which some lines were rejected.
+const partialWriteCode = "partial write"
+
+// partialWriteResponse is the body of a response to a partial write in which some lines were rejected.
+type partialWriteResponse struct {
+	Code     string         `json:"code"`
+	Message  string         `json:"message"`
+	Rejected []rejectedLine `json:"rejected"`
+}
+
+// rejectedLine describes a line of a partial write that was not written.
+type rejectedLine struct {
+	Line int    `json:"line"`
+	Err  string `json:"err"`
+}
+
+// writePartial writes every point in data that can be parsed and stored, instead of rejecting the entire body
+// when any line is invalid. If any lines are rejected, because they cannot be parsed, do not match the bucket schema,
+// or the engine dropped their points, the response lists each rejected line with the reason.
+func (h *WriteHandler) writePartial(ctx context.Context, w http.ResponseWriter, r *http.Request, logger *zap.Logger, orgID platform.ID, bucket *platform.Bucket, data []byte, precision string) {
+	points, lines, parseErrs := models.ParsePointsWithLines(data, time.Now(), precision)
+	schema := storage.NewSchemaValidator(bucket)
+
+	rejected := make(map[int]string, len(parseErrs))
+	for _, le := range parseErrs {
+		rejected[le.Line] = fmt.Sprintf("unable to parse '%s': %v", le.Text, le.Err)
 	}
 
-	exploded, err := tsdb.ExplodePoints(org.ID, bucket.ID, points)
-	if err != nil {
-		logger.Info("Error exploding points", zap.Error(err))
-		EncodeError(ctx, err, w)
-		return
+	// Explode each point on its own, so that points the engine drops can be traced back to their lines.
+	exploded := make([]models.Point, 0, len(points))
+	keyLines := make(map[string][]int, len(points))
+	for i, pt := range points {
+		if err := schema.Validate(pt); err != nil {
+			rejected[lines[i]] = err.Error()
+			continue
+		}
+		pts, err := tsdb.ExplodePoints(orgID, bucket.ID, []models.Point{pt})
+		if err != nil {
+			rejected[lines[i]] = err.Error()
+			continue
+		}
+		for _, ept := range pts {
+			key := string(ept.Key())
+			keyLines[key] = append(keyLines[key], lines[i])
+		}
+		exploded = append(exploded, pts...)
 	}
 
-	if err := h.PointsWriter.WritePoints(exploded); err != nil {
-		EncodeError(ctx, errors.BadRequestError(err.Error()), w)
+	if len(exploded) > 0 {
+		if err := h.PointsWriter.WritePoints(exploded); err != nil {
+			if err := engineModeError("http/writePartial", err); err != nil {
+				EncodeError(ctx, err, w)
+				return
+			}
+			pwe, ok := err.(tsdb.PartialWriteError)
+			if !ok {
+				EncodeError(ctx, errors.BadRequestError(err.Error()), w)
+				return
+			}
+			for _, key := range pwe.DroppedKeys {
+				reason, ok := pwe.DroppedReasons[string(key)]
+				if !ok {
+					reason = pwe.Reason
+				}
+				for _, line := range keyLines[string(key)] {
+					if _, ok := rejected[line]; !ok {
+						rejected[line] = reason
+					}
+				}
+			}
+		}
+	}
+
+	if len(rejected) == 0 {
+		w.WriteHeader(http.StatusNoContent)
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	res := partialWriteResponse{
+		Code:     partialWriteCode,
+		Rejected: make([]rejectedLine, 0, len(rejected)),
+	}
+	for line, msg := range rejected {
+		res.Rejected = append(res.Rejected, rejectedLine{Line: line, Err: msg})
+	}
+	sort.Slice(res.Rejected, func(i, j int) bool { return res.Rejected[i].Line < res.Rejected[j].Line })
+
+	// Only count lines as written if all of their points were written.
+	written := 0
+	for _, line := range lines {
+		if _, ok := rejected[line]; !ok {
+			written++
+		}
+	}
+	res.Message = fmt.Sprintf("wrote %d lines; rejected %d lines", written, len(rejected))
+	logger.Info("Partial write", zap.Int("written", written), zap.Int("rejected", len(rejected)))
+
+	// A partial write where nothing was written is an outright failure.
+	code := http.StatusMultiStatus
+	if written == 0 {
+		code = http.StatusBadRequest
+	}
+	w.Header().Set(PlatformErrorCodeHeader, partialWriteCode)
+	if err := encodeResponse(ctx, w, code, res); err != nil {
+		logEncodingError(logger, r, err)
+	}
 }
 
 func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest, error) {
@@ -196,10 +385,35 @@ func decodeWriteRequest(ctx context.Context, r *http.Request) (*postWriteRequest
 		}
 	}
 
+	var partial bool
+	if s := qp.Get("partial"); s != "" {
+		var err error
+		partial, err = strconv.ParseBool(s)
+		if err != nil {
+			return nil, &platform.Error{
+				Code: platform.EInvalid,
+				Op:   "http/decodeWriteRequest",
+				Msg:  "partial must be true or false",
+				Err:  err,
+			}
+		}
+	}
+
+	format := writeFormat(r.Header.Get("Content-Type"))
+	if partial && format != writeContentTypeLineProtocol {
+		return nil, &platform.Error{
+			Code: platform.EInvalid,
+			Op:   "http/decodeWriteRequest",
+			Msg:  "partial writes are only supported for line protocol",
+		}
+	}
+
 	return &postWriteRequest{
 		Bucket:    qp.Get("bucket"),
 		Org:       qp.Get("org"),
 		Precision: p,
+		Partial:   partial,
+		Format:    format,
 	}, nil
 }
 
@@ -207,6 +421,8 @@ type postWriteRequest struct {
 	Org       string
 	Bucket    string
 	Precision string
+	Partial   bool
+	Format    string // Content type of the body; one of the writeContentType constants.
 }
 
 // WriteService sends data over HTTP to influxdb via line protocol.
@@ -215,6 +431,9 @@ type WriteService struct {
 	Token              string
 	Precision          string
 	InsecureSkipVerify bool
+
+	// ContentType is the format of the data being written; line protocol is used when empty.
+	ContentType string
 }
 
 var _ platform.WriteService = (*WriteService)(nil)
@@ -248,7 +467,11 @@ func (s *WriteService) Write(ctx context.Context, orgID, bucketID platform.ID, r
 		return err
 	}
 
-	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	contentType := s.ContentType
+	if contentType == "" {
+		contentType = "text/plain; charset=utf-8"
+	}
+	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("Content-Encoding", "gzip")
 	SetToken(s.Token, req)
 
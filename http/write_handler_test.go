@@ -3,14 +3,21 @@ package http
 import (
 	"compress/gzip"
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/mock"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/tsdb"
 )
 
 func TestWriteService_Write(t *testing.T) {
@@ -69,3 +76,320 @@ func TestWriteService_Write(t *testing.T) {
 		})
 	}
 }
+
+// pointsWriterFunc adapts a function to a storage.PointsWriter.
+type pointsWriterFunc func([]models.Point) error
+
+func (f pointsWriterFunc) WritePoints(points []models.Point) error { return f(points) }
+
+func TestWriteHandler_handleWritePartial(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+
+	tests := []struct {
+		name     string
+		body     string
+		status   int
+		written  []string
+		rejected []rejectedLine
+	}{
+		{
+			name:    "all lines valid",
+			body:    "m,t=a f=1 1\nm,t=b f=2 2",
+			status:  http.StatusNoContent,
+			written: []string{"a", "b"},
+		},
+		{
+			name:    "unparseable and dropped lines",
+			body:    "m,t=a f=1 1\nm,t=b f= 2\n\nm,t=conflict f=3 3\nm,t=c f=4 4\nm,t=limit f=5 5",
+			status:  http.StatusMultiStatus,
+			written: []string{"a", "c"},
+			rejected: []rejectedLine{
+				{Line: 2, Err: "unable to parse 'm,t=b f= 2': missing field value"},
+				{Line: 4, Err: "field type conflict"},
+				{Line: 6, Err: "max series per bucket exceeded"},
+			},
+		},
+		{
+			name:   "nothing written",
+			body:   "m,t=b f= 2",
+			status: http.StatusBadRequest,
+			rejected: []rejectedLine{
+				{Line: 1, Err: "unable to parse 'm,t=b f= 2': missing field value"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written []string
+			h := NewWriteHandler(pointsWriterFunc(func(points []models.Point) error {
+				reasons := map[string]string{"conflict": "field type conflict", "limit": "max series per bucket exceeded"}
+				pwe := tsdb.PartialWriteError{DroppedReasons: make(map[string]string)}
+				for _, pt := range points {
+					v := string(pt.Tags().Get([]byte("t")))
+					if reason, ok := reasons[v]; ok {
+						if pwe.Reason == "" {
+							pwe.Reason = reason
+						}
+						pwe.Dropped++
+						pwe.DroppedKeys = append(pwe.DroppedKeys, pt.Key())
+						pwe.DroppedReasons[string(pt.Key())] = reason
+						continue
+					}
+					written = append(written, v)
+				}
+				if pwe.Dropped > 0 {
+					return pwe
+				}
+				return nil
+			}))
+			h.OrganizationService = &mock.OrganizationService{
+				FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+					return &platform.Organization{ID: id}, nil
+				},
+			}
+			bs := mock.NewBucketService()
+			bs.FindBucketFn = func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+				return &platform.Bucket{ID: *filter.ID, OrganizationID: orgID}, nil
+			}
+			h.BucketService = bs
+
+			r := httptest.NewRequest("POST", "/api/v2/write?partial=true&org="+orgID.String()+"&bucket="+bucketID.String(), strings.NewReader(tt.body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: []platform.Permission{{Action: platform.WriteAction, Resource: platform.BucketsResource, ID: &bucketID}},
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got := w.Code; got != tt.status {
+				t.Fatalf("unexpected status: got %d, want %d; body: %s", got, tt.status, w.Body.String())
+			}
+			if !reflect.DeepEqual(written, tt.written) {
+				t.Fatalf("unexpected written points: got %v, want %v", written, tt.written)
+			}
+			if tt.rejected == nil {
+				return
+			}
+
+			var res partialWriteResponse
+			if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+				t.Fatal(err)
+			}
+			if res.Code != partialWriteCode {
+				t.Fatalf("unexpected code: %q", res.Code)
+			}
+			if !reflect.DeepEqual(res.Rejected, tt.rejected) {
+				t.Fatalf("unexpected rejected lines: got %+v, want %+v", res.Rejected, tt.rejected)
+			}
+		})
+	}
+}
+
+func TestWriteHandler_handleWriteFormats(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+
+	tests := []struct {
+		name        string
+		contentType string
+		query       string
+		body        string
+		status      int
+		written     []string
+	}{
+		{
+			name:        "line protocol",
+			contentType: "text/plain; charset=utf-8",
+			body:        "m,t=a f=1 1",
+			status:      http.StatusNoContent,
+			written:     []string{"m,t=a f=1 1"},
+		},
+		{
+			name:        "csv",
+			contentType: "text/csv",
+			body: `#datatype,string,long,dateTime:RFC3339,double,string,string,string
+#group,false,false,false,false,true,true,true
+#default,_result,,,,,,
+,result,table,_time,_value,_field,_measurement,t
+,,0,1970-01-01T00:00:00.000000001Z,1,f,m,a
+`,
+			status:  http.StatusNoContent,
+			written: []string{"m,t=a f=1 1"},
+		},
+		{
+			name:        "json",
+			contentType: "application/json",
+			body:        `[{"measurement": "m", "tags": {"t": "a"}, "fields": {"f": 1}, "time": 1}]`,
+			status:      http.StatusNoContent,
+			written:     []string{"m,t=a f=1 1"},
+		},
+		{
+			name:        "invalid json",
+			contentType: "application/json",
+			body:        `m,t=a f=1 1`,
+			status:      http.StatusBadRequest,
+		},
+		{
+			name:        "partial json",
+			contentType: "application/json",
+			query:       "&partial=true",
+			body:        `[{"measurement": "m", "fields": {"f": 1}}]`,
+			status:      http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written []string
+			h := NewWriteHandler(pointsWriterFunc(func(points []models.Point) error {
+				for _, pt := range points {
+					// Undo the explosion of the point into the org and bucket.
+					tags := pt.Tags()
+					fields := mustFields(t, pt)
+					written = append(written, fmt.Sprintf("%s,t=%s %s=%v %d",
+						tags.Get(tsdb.MeasurementTagKeyBytes), tags.Get([]byte("t")), tags.Get(tsdb.FieldKeyTagKeyBytes),
+						fields[string(tags.Get(tsdb.FieldKeyTagKeyBytes))], pt.UnixNano()))
+				}
+				return nil
+			}))
+			h.OrganizationService = &mock.OrganizationService{
+				FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+					return &platform.Organization{ID: id}, nil
+				},
+			}
+			bs := mock.NewBucketService()
+			bs.FindBucketFn = func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+				return &platform.Bucket{ID: *filter.ID, OrganizationID: orgID}, nil
+			}
+			h.BucketService = bs
+
+			r := httptest.NewRequest("POST", "/api/v2/write?org="+orgID.String()+"&bucket="+bucketID.String()+tt.query, strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.contentType)
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: []platform.Permission{{Action: platform.WriteAction, Resource: platform.BucketsResource, ID: &bucketID}},
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got := w.Code; got != tt.status {
+				t.Fatalf("unexpected status: got %d, want %d; body: %s", got, tt.status, w.Body.String())
+			}
+			if !reflect.DeepEqual(written, tt.written) {
+				t.Fatalf("unexpected written points: got %v, want %v", written, tt.written)
+			}
+		})
+	}
+}
+
+func mustFields(t *testing.T, pt models.Point) models.Fields {
+	t.Helper()
+
+	fields, err := pt.Fields()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return fields
+}
+
+func TestWriteHandler_handleWriteSchema(t *testing.T) {
+	orgID, bucketID := platform.ID(1), platform.ID(2)
+	bucket := &platform.Bucket{
+		ID:             bucketID,
+		OrganizationID: orgID,
+		SchemaType:     platform.SchemaTypeExplicit,
+		MeasurementSchemas: []platform.MeasurementSchema{
+			{
+				Name:   "cpu",
+				Tags:   []string{"host"},
+				Fields: []platform.FieldSchema{{Name: "usage", Type: platform.SchemaFieldTypeFloat}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		body     string
+		status   int
+		written  int
+		message  string
+		rejected []rejectedLine
+	}{
+		{
+			name:    "valid",
+			body:    "cpu,host=a usage=1 1\ncpu,host=b usage=2 2",
+			status:  http.StatusNoContent,
+			written: 2,
+		},
+		{
+			name:    "invalid",
+			body:    "cpu,host=a usage=1 1\njunk,host=b usage=2 2\ncpu,host=c usage=3i 3",
+			status:  http.StatusBadRequest,
+			message: `rejected 2 of 3 points that do not match the bucket schema: point 2: measurement "junk" is not in the bucket schema; point 3: field "usage" of measurement "cpu" has type integer; the schema requires float`,
+		},
+		{
+			name:    "partial",
+			query:   "&partial=true",
+			body:    "cpu,host=a usage=1 1\ncpu,host=b,rogue=x usage=2 2",
+			status:  http.StatusMultiStatus,
+			written: 1,
+			rejected: []rejectedLine{
+				{Line: 2, Err: `tag "rogue" is not in the schema for measurement "cpu"`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var written int
+			h := NewWriteHandler(pointsWriterFunc(func(points []models.Point) error {
+				written += len(points)
+				return nil
+			}))
+			h.OrganizationService = &mock.OrganizationService{
+				FindOrganizationByIDF: func(ctx context.Context, id platform.ID) (*platform.Organization, error) {
+					return &platform.Organization{ID: id}, nil
+				},
+			}
+			bs := mock.NewBucketService()
+			bs.FindBucketFn = func(ctx context.Context, filter platform.BucketFilter) (*platform.Bucket, error) {
+				return bucket, nil
+			}
+			h.BucketService = bs
+
+			r := httptest.NewRequest("POST", "/api/v2/write?org="+orgID.String()+"&bucket="+bucketID.String()+tt.query, strings.NewReader(tt.body))
+			r = r.WithContext(pcontext.SetAuthorizer(r.Context(), &platform.Authorization{
+				Status:      platform.Active,
+				Permissions: []platform.Permission{{Action: platform.WriteAction, Resource: platform.BucketsResource, ID: &bucketID}},
+			}))
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if got := w.Code; got != tt.status {
+				t.Fatalf("unexpected status: got %d, want %d; body: %s", got, tt.status, w.Body.String())
+			}
+			if written != tt.written {
+				t.Fatalf("unexpected number of points written: got %d, want %d", written, tt.written)
+			}
+
+			switch {
+			case tt.message != "":
+				var res struct {
+					Message string `json:"message"`
+				}
+				if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+					t.Fatal(err)
+				}
+				if res.Message != tt.message {
+					t.Fatalf("unexpected message:\ngot  %s\nwant %s", res.Message, tt.message)
+				}
+			case tt.rejected != nil:
+				var res partialWriteResponse
+				if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+					t.Fatal(err)
+				}
+				if !reflect.DeepEqual(res.Rejected, tt.rejected) {
+					t.Fatalf("unexpected rejected lines: got %+v, want %+v", res.Rejected, tt.rejected)
+				}
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package inmem
 
 import (
 	"context"
+	"time"
 
 	"github.com/influxdata/platform"
 )
@@ -31,11 +32,17 @@ func (s *Service) loadAuthorization(ctx context.Context, id platform.ID) (*platf
 }
 
 // PutAuthorization overwrites the authorization with the contents of a.
+// The token of a is stored as its hash.
 func (s *Service) PutAuthorization(ctx context.Context, a *platform.Authorization) error {
 	if a.Status == "" {
 		a.Status = platform.Active
 	}
-	s.authorizationKV.Store(a.ID.String(), *a)
+	if a.Token != "" {
+		a.TokenHash = s.TokenHasher.HashToken(a.Token)
+	}
+	stored := *a
+	stored.Token = ""
+	s.authorizationKV.Store(a.ID.String(), stored)
 	return nil
 }
 
@@ -73,7 +80,7 @@ func (s *Service) FindAuthorizationByToken(ctx context.Context, t string) (*plat
 	return as[0], nil
 }
 
-func filterAuthorizationsFn(filter platform.AuthorizationFilter) func(a *platform.Authorization) bool {
+func filterAuthorizationsFn(filter platform.AuthorizationFilter, h platform.TokenHasher, now time.Time) func(a *platform.Authorization) bool {
 	if filter.ID != nil {
 		return func(a *platform.Authorization) bool {
 			return a.ID == *filter.ID
@@ -81,8 +88,9 @@ func filterAuthorizationsFn(filter platform.AuthorizationFilter) func(a *platfor
 	}
 
 	if filter.Token != nil {
+		tokenHash := h.HashToken(*filter.Token)
 		return func(a *platform.Authorization) bool {
-			return a.Token == *filter.Token
+			return a.HasTokenHash(tokenHash, now)
 		}
 	}
 
@@ -122,7 +130,7 @@ func (s *Service) FindAuthorizations(ctx context.Context, filter platform.Author
 		filter.UserID = &u.ID
 	}
 	var err error
-	filterF := filterAuthorizationsFn(filter)
+	filterF := filterAuthorizationsFn(filter, s.TokenHasher, s.time())
 	s.authorizationKV.Range(func(k, v interface{}) bool {
 		a, ok := v.(platform.Authorization)
 		if !ok {
@@ -148,7 +156,8 @@ func (s *Service) FindAuthorizations(ctx context.Context, filter platform.Author
 	return as, len(as), nil
 }
 
-// CreateAuthorization sets a.Token and a.ID and creates an platform.Authorization
+// CreateAuthorization sets a.Token and a.ID and creates an platform.Authorization.
+// The token is only stored as its hash.
 func (s *Service) CreateAuthorization(ctx context.Context, a *platform.Authorization) error {
 	op := OpPrefix + platform.OpCreateAuthorization
 
@@ -173,6 +182,7 @@ func (s *Service) CreateAuthorization(ctx context.Context, a *platform.Authoriza
 
 	a.ID = s.IDGenerator.ID()
 	a.Status = platform.Active
+	a.CreatedAt = s.time()
 
 	return s.PutAuthorization(ctx, a)
 }
@@ -218,3 +228,52 @@ func (s *Service) SetAuthorizationStatus(ctx context.Context, id platform.ID, st
 	a.Status = status
 	return s.PutAuthorization(ctx, a)
 }
+
+// RotateAuthorization replaces the token of the authorization with a new token.
+// The previous token remains valid for gracePeriod.
+func (s *Service) RotateAuthorization(ctx context.Context, id platform.ID, gracePeriod time.Duration) (*platform.Authorization, error) {
+	op := OpPrefix + platform.OpRotateAuthorization
+	a, err := s.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+			Op:  op,
+		}
+	}
+
+	token, err := s.TokenGenerator.Token()
+	if err != nil {
+		return nil, &platform.Error{
+			Err: err,
+			Op:  op,
+		}
+	}
+
+	a.PreviousTokenHash, a.PreviousTokenExpiresAt = "", nil
+	if gracePeriod > 0 {
+		expiresAt := s.time().Add(gracePeriod)
+		a.PreviousTokenHash, a.PreviousTokenExpiresAt = a.TokenHash, &expiresAt
+	}
+	a.Token = token
+
+	if err := s.PutAuthorization(ctx, a); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+var _ platform.AuthorizationUsageService = (*Service)(nil)
+
+// SetAuthorizationLastUsed records that the authorization was last used at t.
+func (s *Service) SetAuthorizationLastUsed(ctx context.Context, id platform.ID, t time.Time) error {
+	a, err := s.FindAuthorizationByID(ctx, id)
+	if err != nil {
+		return &platform.Error{
+			Err: err,
+			Op:  OpPrefix + platform.OpSetAuthorizationLastUsed,
+		}
+	}
+
+	a.LastUsedAt = &t
+	return s.PutAuthorization(ctx, a)
+}
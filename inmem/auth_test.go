@@ -12,6 +12,12 @@ func initAuthorizationService(f platformtesting.AuthorizationFields, t *testing.
 	s := NewService()
 	s.IDGenerator = f.IDGenerator
 	s.TokenGenerator = f.TokenGenerator
+	if f.TokenHasher != nil {
+		s.TokenHasher = f.TokenHasher
+	}
+	if f.NowFn != nil {
+		s.WithTime(f.NowFn)
+	}
 	ctx := context.Background()
 
 	for _, u := range f.Users {
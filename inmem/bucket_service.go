@@ -260,7 +260,53 @@ func (s *Service) UpdateBucket(ctx context.Context, id platform.ID, upd platform
 		b.RetentionPeriod = *upd.RetentionPeriod
 	}
 
-	s.bucketKV.Store(b.ID.String(), b)
+	if upd.ShardGroupDuration != nil {
+		b.ShardGroupDuration = *upd.ShardGroupDuration
+	}
+
+	if upd.ColdStorageAfter != nil {
+		b.ColdStorageAfter = *upd.ColdStorageAfter
+	}
+
+	if upd.SchemaType != nil {
+		b.SchemaType = *upd.SchemaType
+	}
+
+	if upd.MeasurementSchemas != nil {
+		b.MeasurementSchemas = upd.MeasurementSchemas
+	}
+
+	if upd.DownsampleTiers != nil {
+		b.DownsampleTiers = upd.DownsampleTiers
+	}
+
+	if upd.OrganizationID != nil {
+		o, err := s.FindOrganizationByID(ctx, *upd.OrganizationID)
+		if err != nil {
+			return nil, &platform.Error{
+				Op:  OpPrefix + platform.OpUpdateBucket,
+				Err: err,
+			}
+		}
+		b.OrganizationID = o.ID
+		b.Organization = o.Name
+	}
+
+	if upd.Name != nil || upd.OrganizationID != nil {
+		filter := platform.BucketFilter{
+			Name:           &b.Name,
+			OrganizationID: &b.OrganizationID,
+		}
+		if other, err := s.FindBucket(ctx, filter); err == nil && other.ID != b.ID {
+			return nil, &platform.Error{
+				Code: platform.EConflict,
+				Op:   OpPrefix + platform.OpUpdateBucket,
+				Msg:  fmt.Sprintf("bucket with name %s already exists", b.Name),
+			}
+		}
+	}
+
+	s.bucketKV.Store(b.ID.String(), *b)
 
 	return b, nil
 }
@@ -52,6 +52,26 @@ func (s *Service) Name(ctx context.Context, resource platform.Resource, id platf
 			return "", err
 		}
 		return r.Name, nil
+	case platform.MacrosResource: // 8
+		r, err := s.FindMacroByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return r.Name, nil
+	case platform.ViewsResource: // 9
+		r, err := s.FindViewByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return r.Name, nil
+	case platform.ScrapersResource: // 10
+		r, err := s.GetTargetByID(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return r.Name, nil
+	case platform.SecretsResource: // 11 secrets are identified by key, not ID
+	case platform.LabelsResource: // 12 labels are identified by name
 	}
 
 	return "", nil
@@ -0,0 +1,175 @@
+package inmem
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/platform"
+)
+
+var _ platform.RoleService = (*Service)(nil)
+
+func (s *Service) loadRole(ctx context.Context, id platform.ID) (*platform.Role, *platform.Error) {
+	i, ok := s.roleKV.Load(id.String())
+	if !ok {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Msg:  platform.ErrRoleNotFound,
+		}
+	}
+
+	r, ok := i.(platform.Role)
+	if !ok {
+		return nil, &platform.Error{
+			Code: platform.EInternal,
+			Msg:  fmt.Sprintf("type %T is not a role", i),
+		}
+	}
+	return &r, nil
+}
+
+// FindRoleByID returns a single role by ID.
+func (s *Service) FindRoleByID(ctx context.Context, id platform.ID) (*platform.Role, error) {
+	r, pe := s.loadRole(ctx, id)
+	if pe != nil {
+		pe.Op = OpPrefix + platform.OpFindRoleByID
+		return nil, pe
+	}
+	return r, nil
+}
+
+func (s *Service) filterRoles(ctx context.Context, fn func(r *platform.Role) bool) ([]*platform.Role, error) {
+	var err error
+	rs := []*platform.Role{}
+	s.roleKV.Range(func(k, v interface{}) bool {
+		r, ok := v.(platform.Role)
+		if !ok {
+			err = fmt.Errorf("type %T is not a role", v)
+			return false
+		}
+		if fn(&r) {
+			rs = append(rs, &r)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// FindRoles returns a list of roles that match filter and the total count of matching roles.
+func (s *Service) FindRoles(ctx context.Context, filter platform.RoleFilter) ([]*platform.Role, int, error) {
+	op := OpPrefix + platform.OpFindRoles
+	if filter.ID != nil {
+		r, err := s.FindRoleByID(ctx, *filter.ID)
+		if err != nil {
+			return nil, 0, &platform.Error{
+				Op:  op,
+				Err: err,
+			}
+		}
+		return []*platform.Role{r}, 1, nil
+	}
+
+	rs, err := s.filterRoles(ctx, func(r *platform.Role) bool {
+		return (filter.OrganizationID == nil || r.OrganizationID == *filter.OrganizationID) &&
+			(filter.Name == nil || r.Name == *filter.Name)
+	})
+	if err != nil {
+		return nil, 0, &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	return rs, len(rs), nil
+}
+
+// CreateRole creates a new role and sets r.ID with the new identifier.
+func (s *Service) CreateRole(ctx context.Context, r *platform.Role) error {
+	op := OpPrefix + platform.OpCreateRole
+	if err := r.Valid(); err != nil {
+		return &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	if _, err := s.FindOrganizationByID(ctx, r.OrganizationID); err != nil {
+		return &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+
+	r.ID = s.IDGenerator.ID()
+	if err := s.putRole(ctx, r); err != nil {
+		return &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// PutRole puts a role in the store, without checking that its name is unique.
+func (s *Service) PutRole(ctx context.Context, r *platform.Role) error {
+	s.roleKV.Store(r.ID.String(), *r)
+	return nil
+}
+
+// putRole stores r unless another role of the organization has its name.
+func (s *Service) putRole(ctx context.Context, r *platform.Role) error {
+	rs, err := s.filterRoles(ctx, func(o *platform.Role) bool {
+		return o.ID != r.ID && o.OrganizationID == r.OrganizationID && o.Name == r.Name
+	})
+	if err != nil {
+		return err
+	}
+	if len(rs) > 0 {
+		return &platform.Error{
+			Code: platform.EConflict,
+			Msg:  fmt.Sprintf("role with name %s already exists", r.Name),
+		}
+	}
+	return s.PutRole(ctx, r)
+}
+
+// UpdateRole updates a single role with changeset.
+func (s *Service) UpdateRole(ctx context.Context, id platform.ID, upd platform.RoleUpdate) (*platform.Role, error) {
+	op := OpPrefix + platform.OpUpdateRole
+	r, pe := s.loadRole(ctx, id)
+	if pe != nil {
+		pe.Op = op
+		return nil, pe
+	}
+
+	upd.Apply(r)
+	if err := r.Valid(); err != nil {
+		return nil, &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	if err := s.putRole(ctx, r); err != nil {
+		return nil, &platform.Error{
+			Op:  op,
+			Err: err,
+		}
+	}
+	return r, nil
+}
+
+// DeleteRole removes a role by ID, and its assignments to users.
+func (s *Service) DeleteRole(ctx context.Context, id platform.ID) error {
+	op := OpPrefix + platform.OpDeleteRole
+	if _, pe := s.loadRole(ctx, id); pe != nil {
+		pe.Op = op
+		return pe
+	}
+
+	s.roleKV.Delete(id.String())
+	return s.deleteUserResourceMapping(ctx, platform.UserResourceMappingFilter{
+		ResourceID: id,
+		Resource:   platform.RolesResource,
+	})
+}
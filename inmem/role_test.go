@@ -0,0 +1,46 @@
+package inmem
+
+import (
+	"context"
+	"testing"
+
+	"github.com/influxdata/platform"
+	platformtesting "github.com/influxdata/platform/testing"
+)
+
+func initRoleService(f platformtesting.RoleFields, t *testing.T) (platform.RoleService, string, func()) {
+	s := NewService()
+	s.IDGenerator = f.IDGenerator
+	ctx := context.Background()
+	for _, o := range f.Organizations {
+		if err := s.PutOrganization(ctx, o); err != nil {
+			t.Fatalf("failed to populate organizations")
+		}
+	}
+	for _, r := range f.Roles {
+		if err := s.PutRole(ctx, r); err != nil {
+			t.Fatalf("failed to populate roles")
+		}
+	}
+	return s, OpPrefix, func() {}
+}
+
+func TestRoleService_CreateRole(t *testing.T) {
+	platformtesting.CreateRole(initRoleService, t)
+}
+
+func TestRoleService_FindRoleByID(t *testing.T) {
+	platformtesting.FindRoleByID(initRoleService, t)
+}
+
+func TestRoleService_FindRoles(t *testing.T) {
+	platformtesting.FindRoles(initRoleService, t)
+}
+
+func TestRoleService_UpdateRole(t *testing.T) {
+	platformtesting.UpdateRole(initRoleService, t)
+}
+
+func TestRoleService_DeleteRole(t *testing.T) {
+	platformtesting.DeleteRole(initRoleService, t)
+}
@@ -28,16 +28,25 @@ type Service struct {
 	telegrafConfigKV      sync.Map
 	onboardingKV          sync.Map
 	basicAuthKV           sync.Map
+	roleKV                sync.Map
+	ssoIdentityKV         sync.Map
 
 	TokenGenerator platform.TokenGenerator
+	TokenHasher    platform.TokenHasher
 	IDGenerator    platform.IDGenerator
 	time           func() time.Time
 }
 
 // NewService creates an instance of a Service.
 func NewService() *Service {
+	// Tokens only live as long as the service, so are hashed with a key of their own.
+	key, err := rand.NewTokenHashKey()
+	if err != nil {
+		panic(err)
+	}
 	return &Service{
 		TokenGenerator: rand.NewTokenGenerator(64),
+		TokenHasher:    rand.NewTokenHasher(key),
 		IDGenerator:    snowflake.NewIDGenerator(),
 		time:           time.Now,
 	}
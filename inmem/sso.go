@@ -0,0 +1,38 @@
+package inmem
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/platform"
+)
+
+var _ platform.SSOIdentityService = (*Service)(nil)
+
+// FindSSOIdentity returns the identity of the subject of the provider.
+func (s *Service) FindSSOIdentity(ctx context.Context, provider, subject string) (*platform.SSOIdentity, error) {
+	v, ok := s.ssoIdentityKV.Load(provider + "\x00" + subject)
+	if !ok {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Op:   OpPrefix + platform.OpFindSSOIdentity,
+			Msg:  platform.ErrSSOIdentityNotFound,
+		}
+	}
+
+	i, ok := v.(platform.SSOIdentity)
+	if !ok {
+		return nil, &platform.Error{
+			Code: platform.EInternal,
+			Op:   OpPrefix + platform.OpFindSSOIdentity,
+			Msg:  fmt.Sprintf("type %T is not an sso identity", v),
+		}
+	}
+	return &i, nil
+}
+
+// PutSSOIdentity links the subject of the provider to the user of the identity.
+func (s *Service) PutSSOIdentity(ctx context.Context, i *platform.SSOIdentity) error {
+	s.ssoIdentityKV.Store(i.Provider+"\x00"+i.Subject, *i)
+	return nil
+}
@@ -0,0 +1,32 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/platform"
+)
+
+var _ platform.ReplicationService = &ReplicationService{}
+
+type ReplicationService struct {
+	FindReplicationByIDF func(context.Context, platform.ID) (*platform.Replication, error)
+	FindReplicationsF    func(context.Context, platform.ReplicationFilter) ([]*platform.Replication, int, error)
+	CreateReplicationF   func(context.Context, *platform.Replication) error
+	DeleteReplicationF   func(context.Context, platform.ID) error
+}
+
+func (s *ReplicationService) FindReplicationByID(ctx context.Context, id platform.ID) (*platform.Replication, error) {
+	return s.FindReplicationByIDF(ctx, id)
+}
+
+func (s *ReplicationService) FindReplications(ctx context.Context, filter platform.ReplicationFilter) ([]*platform.Replication, int, error) {
+	return s.FindReplicationsF(ctx, filter)
+}
+
+func (s *ReplicationService) CreateReplication(ctx context.Context, r *platform.Replication) error {
+	return s.CreateReplicationF(ctx, r)
+}
+
+func (s *ReplicationService) DeleteReplication(ctx context.Context, id platform.ID) error {
+	return s.DeleteReplicationF(ctx, id)
+}
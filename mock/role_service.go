@@ -0,0 +1,37 @@
+package mock
+
+import (
+	"context"
+
+	"github.com/influxdata/platform"
+)
+
+var _ platform.RoleService = &RoleService{}
+
+type RoleService struct {
+	FindRoleByIDF func(context.Context, platform.ID) (*platform.Role, error)
+	FindRolesF    func(context.Context, platform.RoleFilter) ([]*platform.Role, int, error)
+	CreateRoleF   func(context.Context, *platform.Role) error
+	UpdateRoleF   func(ctx context.Context, id platform.ID, upd platform.RoleUpdate) (*platform.Role, error)
+	DeleteRoleF   func(context.Context, platform.ID) error
+}
+
+func (s *RoleService) FindRoleByID(ctx context.Context, id platform.ID) (*platform.Role, error) {
+	return s.FindRoleByIDF(ctx, id)
+}
+
+func (s *RoleService) FindRoles(ctx context.Context, filter platform.RoleFilter) ([]*platform.Role, int, error) {
+	return s.FindRolesF(ctx, filter)
+}
+
+func (s *RoleService) CreateRole(ctx context.Context, r *platform.Role) error {
+	return s.CreateRoleF(ctx, r)
+}
+
+func (s *RoleService) UpdateRole(ctx context.Context, id platform.ID, upd platform.RoleUpdate) (*platform.Role, error) {
+	return s.UpdateRoleF(ctx, id, upd)
+}
+
+func (s *RoleService) DeleteRole(ctx context.Context, id platform.ID) error {
+	return s.DeleteRoleF(ctx, id)
+}
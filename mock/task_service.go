@@ -9,17 +9,20 @@ import (
 var _ platform.TaskService = &TaskService{}
 
 type TaskService struct {
-	FindTaskByIDFn func(context.Context, platform.ID) (*platform.Task, error)
-	FindTasksFn    func(context.Context, platform.TaskFilter) ([]*platform.Task, int, error)
-	CreateTaskFn   func(context.Context, *platform.Task) error
-	UpdateTaskFn   func(context.Context, platform.ID, platform.TaskUpdate) (*platform.Task, error)
-	DeleteTaskFn   func(context.Context, platform.ID) error
-	FindLogsFn     func(context.Context, platform.LogFilter) ([]*platform.Log, int, error)
-	FindRunsFn     func(context.Context, platform.RunFilter) ([]*platform.Run, int, error)
-	FindRunByIDFn  func(context.Context, platform.ID, platform.ID) (*platform.Run, error)
-	CancelRunFn    func(context.Context, platform.ID, platform.ID) error
-	RetryRunFn     func(context.Context, platform.ID, platform.ID) (*platform.Run, error)
-	ForceRunFn     func(context.Context, platform.ID, int64) (*platform.Run, error)
+	FindTaskByIDFn   func(context.Context, platform.ID) (*platform.Task, error)
+	FindTasksFn      func(context.Context, platform.TaskFilter) ([]*platform.Task, int, error)
+	CreateTaskFn     func(context.Context, *platform.Task) error
+	UpdateTaskFn     func(context.Context, platform.ID, platform.TaskUpdate) (*platform.Task, error)
+	DeleteTaskFn     func(context.Context, platform.ID) error
+	FindLogsFn       func(context.Context, platform.LogFilter) ([]*platform.Log, int, error)
+	FindRunsFn       func(context.Context, platform.RunFilter) ([]*platform.Run, int, error)
+	FindRunByIDFn    func(context.Context, platform.ID, platform.ID) (*platform.Run, error)
+	CancelRunFn      func(context.Context, platform.ID, platform.ID) error
+	RetryRunFn       func(context.Context, platform.ID, platform.ID) (*platform.Run, error)
+	ForceRunFn       func(context.Context, platform.ID, int64) (*platform.Run, error)
+	BackfillTaskFn   func(context.Context, platform.ID, int64, int64) (*platform.Backfill, error)
+	FindBackfillsFn  func(context.Context, platform.ID) ([]*platform.Backfill, error)
+	CancelBackfillFn func(context.Context, platform.ID, platform.ID) error
 }
 
 func (s *TaskService) FindTaskByID(ctx context.Context, id platform.ID) (*platform.Task, error) {
@@ -65,3 +68,15 @@ func (s *TaskService) RetryRun(ctx context.Context, taskID, runID platform.ID) (
 func (s *TaskService) ForceRun(ctx context.Context, taskID platform.ID, scheduledFor int64) (*platform.Run, error) {
 	return s.ForceRunFn(ctx, taskID, scheduledFor)
 }
+
+func (s *TaskService) BackfillTask(ctx context.Context, taskID platform.ID, start, stop int64) (*platform.Backfill, error) {
+	return s.BackfillTaskFn(ctx, taskID, start, stop)
+}
+
+func (s *TaskService) FindBackfills(ctx context.Context, taskID platform.ID) ([]*platform.Backfill, error) {
+	return s.FindBackfillsFn(ctx, taskID)
+}
+
+func (s *TaskService) CancelBackfill(ctx context.Context, taskID, backfillID platform.ID) error {
+	return s.CancelBackfillFn(ctx, taskID, backfillID)
+}
@@ -349,12 +349,67 @@ func ValidPrecision(precision string) bool {
 // This can have the unintended effect preventing buf from being garbage collected.
 func ParsePointsWithPrecision(buf []byte, defaultTime time.Time, precision string) ([]Point, error) {
 	points := make([]Point, 0, bytes.Count(buf, []byte{'\n'})+1)
+	var failed []string
+	scanPoints(buf, defaultTime, precision, func(_ int, text []byte, pt Point, err error) {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("unable to parse '%s': %v", string(text), err))
+		} else {
+			points = append(points, pt)
+		}
+	})
+	if len(failed) > 0 {
+		return points, fmt.Errorf("%s", strings.Join(failed, "\n"))
+	}
+	return points, nil
+
+}
+
+// LineError describes a line of line protocol that could not be parsed.
+type LineError struct {
+	Line int    // Line number, starting at 1, on which the line begins.
+	Text string // The line that failed to parse.
+	Err  error
+}
+
+func (e LineError) Error() string {
+	return fmt.Sprintf("line %d: unable to parse '%s': %v", e.Line, e.Text, e.Err)
+}
+
+// ParsePointsWithLines is similar to ParsePointsWithPrecision, but rather than failing on
+// the first line that cannot be parsed, it returns every such line as a LineError.
+// The line number on which each parsed point begins is returned in lines, at the same index as the point.
+//
+// NOTE: to minimize heap allocations, the returned Points will refer to subslices of buf.
+func ParsePointsWithLines(buf []byte, defaultTime time.Time, precision string) (points []Point, lines []int, errs []LineError) {
+	n := bytes.Count(buf, []byte{'\n'}) + 1
+	points = make([]Point, 0, n)
+	lines = make([]int, 0, n)
+	scanPoints(buf, defaultTime, precision, func(line int, text []byte, pt Point, err error) {
+		if err != nil {
+			errs = append(errs, LineError{Line: line, Text: string(text), Err: err})
+			return
+		}
+		points = append(points, pt)
+		lines = append(lines, line)
+	})
+	return points, lines, errs
+}
+
+// scanPoints parses each line of buf, skipping blank lines and comments.
+// For each line, fn is called with the line number on which the line begins, the line's text,
+// and either the parsed point or the parse error.
+func scanPoints(buf []byte, defaultTime time.Time, precision string, fn func(line int, text []byte, pt Point, err error)) {
 	var (
-		pos    int
-		block  []byte
-		failed []string
+		pos   int
+		block []byte
+
+		line    = 1
+		counted int // Position in buf up to which newlines have been counted.
 	)
 	for pos < len(buf) {
+		line += bytes.Count(buf[counted:pos], []byte{'\n'})
+		counted = pos
+
 		pos, block = scanLine(buf, pos)
 		pos++
 
@@ -380,18 +435,8 @@ func ParsePointsWithPrecision(buf []byte, defaultTime time.Time, precision strin
 		}
 
 		pt, err := parsePoint(block[start:], defaultTime, precision)
-		if err != nil {
-			failed = append(failed, fmt.Sprintf("unable to parse '%s': %v", string(block[start:]), err))
-		} else {
-			points = append(points, pt)
-		}
-
+		fn(line, block[start:], pt, err)
 	}
-	if len(failed) > 0 {
-		return points, fmt.Errorf("%s", strings.Join(failed, "\n"))
-	}
-	return points, nil
-
 }
 
 func parsePoint(buf []byte, defaultTime time.Time, precision string) (Point, error) {
@@ -2091,6 +2091,38 @@ func TestRoundedString(t *testing.T) {
 	}
 }
 
+func TestParsePointsWithLines(t *testing.T) {
+	batch := `# header
+cpu,host=a value=1 1
+
+cpu,host=b value= 2
+mem,host=a note="multi
+line" 3
+cpu,host=c value=3 4
+cpu,host=d,bad value=4 5`
+
+	pts, lines, errs := models.ParsePointsWithLines([]byte(batch), time.Now().UTC(), "n")
+	if got, exp := len(pts), 3; got != exp {
+		t.Fatalf("unexpected number of points: got %d, exp %d", got, exp)
+	}
+	if got, exp := lines, []int{2, 5, 7}; !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected point lines: got %v, exp %v", got, exp)
+	}
+	if got, exp := string(pts[2].Key()), "cpu,host=c"; got != exp {
+		t.Fatalf("unexpected point on line 7: got %s, exp %s", got, exp)
+	}
+
+	if got, exp := len(errs), 2; got != exp {
+		t.Fatalf("unexpected number of errors: got %d, exp %d", got, exp)
+	}
+	if errs[0].Line != 4 || errs[0].Text != "cpu,host=b value= 2" {
+		t.Fatalf("unexpected first error: %v", errs[0])
+	}
+	if errs[1].Line != 8 || errs[1].Err == nil {
+		t.Fatalf("unexpected second error: %v", errs[1])
+	}
+}
+
 func TestParsePointsStringWithExtraBuffer(t *testing.T) {
 	b := make([]byte, 70*5000)
 	buf := bytes.NewBuffer(b)
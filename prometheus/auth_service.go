@@ -124,6 +124,21 @@ func (s *AuthorizationService) SetAuthorizationStatus(ctx context.Context, id pl
 	return s.AuthorizationService.SetAuthorizationStatus(ctx, id, status)
 }
 
+// RotateAuthorization replaces the token of the authorization, keeping the old token valid
+// for gracePeriod.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, gracePeriod time.Duration) (a *platform.Authorization, err error) {
+	defer func(start time.Time) {
+		labels := prometheus.Labels{
+			"method": "RotateAuthorization",
+			"error":  fmt.Sprint(err != nil),
+		}
+		s.requestCount.With(labels).Add(1)
+		s.requestDuration.With(labels).Observe(time.Since(start).Seconds())
+	}(time.Now())
+
+	return s.AuthorizationService.RotateAuthorization(ctx, id, gracePeriod)
+}
+
 // PrometheusCollectors returns all authorization service prometheus collectors.
 func (s *AuthorizationService) PrometheusCollectors() []prometheus.Collector {
 	return []prometheus.Collector{
@@ -55,8 +55,9 @@ func TestPreAuthorizer_PreAuthorize(t *testing.T) {
 	// (still no authorization)
 	id, _ := platform.IDFromString("deadbeefdeadbeef")
 	bucketService := newBucketServiceWithOneBucket(platform.Bucket{
-		Name: "my_bucket",
-		ID:   *id,
+		Name:           "my_bucket",
+		ID:             *id,
+		OrganizationID: platform.ID(1),
 	})
 
 	preAuthorizer = query.NewPreAuthorizer(bucketService)
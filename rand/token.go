@@ -1,8 +1,11 @@
 package rand
 
 import (
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 
 	"github.com/influxdata/platform"
 )
@@ -26,6 +29,33 @@ func (t *TokenGenerator) Token() (string, error) {
 	return generateRandomString(t.size)
 }
 
+// TokenHashKeySize is the size of the keys generated by NewTokenHashKey.
+const TokenHashKeySize = 32
+
+// TokenHasher implements platform.TokenHasher with HMAC-SHA256.
+type TokenHasher struct {
+	key []byte
+}
+
+// NewTokenHasher creates an instance of a platform.TokenHasher hashing tokens with key.
+func NewTokenHasher(key []byte) platform.TokenHasher {
+	return &TokenHasher{
+		key: key,
+	}
+}
+
+// NewTokenHashKey returns a new random key for a TokenHasher.
+func NewTokenHashKey() ([]byte, error) {
+	return generateRandomBytes(TokenHashKeySize)
+}
+
+// HashToken returns the hex encoded HMAC-SHA256 of token.
+func (h *TokenHasher) HashToken(token string) string {
+	m := hmac.New(sha256.New, h.key)
+	m.Write([]byte(token))
+	return hex.EncodeToString(m.Sum(nil))
+}
+
 func generateRandomString(s int) (string, error) {
 	b, err := generateRandomBytes(s)
 	return base64.URLEncoding.EncodeToString(b), err
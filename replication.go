@@ -0,0 +1,90 @@
+package platform
+
+import (
+	"context"
+	"net/url"
+)
+
+// ErrReplicationNotFound is the error message for a missing replication.
+const ErrReplicationNotFound = "replication not found"
+
+// ops for replications.
+const (
+	OpFindReplicationByID = "FindReplicationByID"
+	OpFindReplications    = "FindReplications"
+	OpCreateReplication   = "CreateReplication"
+	OpDeleteReplication   = "DeleteReplication"
+)
+
+// Replication forwards the points written to a local bucket to a bucket of a remote influxd.
+type Replication struct {
+	ID             ID     `json:"id,omitempty"`
+	OrganizationID ID     `json:"orgID"`
+	Name           string `json:"name"`
+	LocalBucketID  ID     `json:"localBucketID"`
+
+	// RemoteURL is the address of the remote influxd, such as http://localhost:9999.
+	RemoteURL            string `json:"remoteURL"`
+	RemoteOrganizationID ID     `json:"remoteOrgID"`
+	RemoteBucketID       ID     `json:"remoteBucketID"`
+
+	// TokenSecretKey is the key of the secret of the organization holding the token
+	// used to write to the remote bucket.
+	TokenSecretKey string `json:"tokenSecretKey"`
+
+	// MaxQueueSizeBytes is the size at which the queue of points waiting to be
+	// forwarded stops accepting writes. Zero means the default size.
+	MaxQueueSizeBytes int64 `json:"maxQueueSizeBytes,omitempty"`
+}
+
+// Valid returns an error if the replication is missing required fields.
+func (r *Replication) Valid() error {
+	var msg string
+	switch {
+	case r.Name == "":
+		msg = "replication name is required"
+	case !r.OrganizationID.Valid():
+		msg = "replication organization ID is required"
+	case !r.LocalBucketID.Valid():
+		msg = "replication local bucket ID is required"
+	case !r.RemoteOrganizationID.Valid():
+		msg = "replication remote organization ID is required"
+	case !r.RemoteBucketID.Valid():
+		msg = "replication remote bucket ID is required"
+	case r.TokenSecretKey == "":
+		msg = "replication token secret key is required"
+	case r.MaxQueueSizeBytes < 0:
+		msg = "replication max queue size must not be negative"
+	}
+	if msg == "" {
+		if u, err := url.Parse(r.RemoteURL); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			msg = "replication remote URL must be an http or https URL"
+		}
+	}
+	if msg != "" {
+		return &Error{Code: EInvalid, Msg: msg}
+	}
+	return nil
+}
+
+// ReplicationFilter represents a set of filters that restrict the returned replications.
+type ReplicationFilter struct {
+	ID             *ID
+	OrganizationID *ID
+	LocalBucketID  *ID
+}
+
+// ReplicationService manages the replications of buckets.
+type ReplicationService interface {
+	// FindReplicationByID returns a single replication by ID.
+	FindReplicationByID(ctx context.Context, id ID) (*Replication, error)
+
+	// FindReplications returns a list of replications that match filter and the total count of matching replications.
+	FindReplications(ctx context.Context, filter ReplicationFilter) ([]*Replication, int, error)
+
+	// CreateReplication creates a new replication and sets r.ID with the new identifier.
+	CreateReplication(ctx context.Context, r *Replication) error
+
+	// DeleteReplication removes a replication by ID.
+	DeleteReplication(ctx context.Context, id ID) error
+}
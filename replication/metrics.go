@@ -0,0 +1,82 @@
+package replication
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// namespace is the leading part of all published metrics for the replication service.
+const namespace = "replication"
+
+// metrics is a set of metrics of each replication, labeled by the replication ID.
+type metrics struct {
+	QueueBytes     *prometheus.GaugeVec
+	LagSeconds     *prometheus.GaugeVec
+	Points         *prometheus.CounterVec
+	Dropped        *prometheus.CounterVec
+	Errors         *prometheus.CounterVec
+	RejectedWrites *prometheus.CounterVec
+}
+
+func newMetrics() *metrics {
+	names := []string{"replication_id"}
+	return &metrics{
+		QueueBytes: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "queue_bytes",
+			Help:      "Size of the queue of points waiting to be forwarded to the remote bucket.",
+		}, names),
+
+		LagSeconds: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "lag_seconds",
+			Help:      "Time since the queue was last empty, or zero if it is empty.",
+		}, names),
+
+		Points: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "points_total",
+			Help:      "Number of points forwarded to the remote bucket.",
+		}, names),
+
+		Dropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "dropped_points_total",
+			Help:      "Number of points rejected as invalid by the remote bucket.",
+		}, names),
+
+		Errors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "errors_total",
+			Help:      "Number of failed attempts to forward points, which are retried.",
+		}, names),
+
+		RejectedWrites: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rejected_writes_total",
+			Help:      "Number of local writes rejected because the queue was full.",
+		}, names),
+	}
+}
+
+// delete removes the metrics of the replication id.
+func (m *metrics) delete(id string) {
+	labels := prometheus.Labels{"replication_id": id}
+	m.QueueBytes.Delete(labels)
+	m.LagSeconds.Delete(labels)
+	m.Points.Delete(labels)
+	m.Dropped.Delete(labels)
+	m.Errors.Delete(labels)
+	m.RejectedWrites.Delete(labels)
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (m *metrics) PrometheusCollectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.QueueBytes,
+		m.LagSeconds,
+		m.Points,
+		m.Dropped,
+		m.Errors,
+		m.RejectedWrites,
+	}
+}
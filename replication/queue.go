@@ -0,0 +1,240 @@
+package replication
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/influxdata/platform/tsdb/tsm1"
+)
+
+// DefaultMaxQueueSize is the size of a queue at which it stops accepting writes,
+// unless the replication sets its own.
+const DefaultMaxQueueSize = 1 << 30
+
+// segmentSize is the size at which the segment being appended to is closed.
+const segmentSize = 10 * 1024 * 1024
+
+var (
+	errQueueFull   = errors.New("replication queue is full")
+	errQueueClosed = errors.New("replication queue is closed")
+)
+
+// queue is a durable queue of the writes to be forwarded by a replication. Writes are
+// appended to WAL segment files in the queue's directory, and segments are removed
+// once all of their writes have been forwarded.
+type queue struct {
+	dir     string
+	maxSize int64
+
+	mu       sync.Mutex
+	closed   bool
+	segments []int // Closed segments, oldest first.
+	nextID   int
+	f        *os.File
+	w        *tsm1.WALSegmentWriter
+	wSize    int64
+	size     int64
+	since    time.Time     // When the queue was last empty.
+	appended chan struct{} // Signalled when an entry is appended.
+	removed  chan struct{} // Closed and replaced when a segment is removed.
+}
+
+// openQueue opens the queue in dir, creating it if it does not exist.
+// All existing segments are considered closed.
+func openQueue(dir string, maxSize int64) (*queue, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &queue{
+		dir:      dir,
+		maxSize:  maxSize,
+		nextID:   1,
+		appended: make(chan struct{}, 1),
+		removed:  make(chan struct{}),
+	}
+	for _, fi := range fis {
+		id, ok := segmentID(fi.Name())
+		if !ok {
+			continue
+		}
+		if fi.Size() == 0 {
+			os.Remove(filepath.Join(dir, fi.Name()))
+			continue
+		}
+		q.segments = append(q.segments, id)
+		q.size += fi.Size()
+		if q.since.IsZero() || fi.ModTime().Before(q.since) {
+			q.since = fi.ModTime()
+		}
+	}
+	sort.Ints(q.segments)
+	if n := len(q.segments); n > 0 {
+		q.nextID = q.segments[n-1] + 1
+	}
+	return q, nil
+}
+
+func segmentID(name string) (int, bool) {
+	if !strings.HasPrefix(name, tsm1.WALFilePrefix) || filepath.Ext(name) != "."+tsm1.WALFileExtension {
+		return 0, false
+	}
+	id, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, tsm1.WALFilePrefix), "."+tsm1.WALFileExtension))
+	return id, err == nil
+}
+
+func (q *queue) segmentPath(id int) string {
+	return filepath.Join(q.dir, fmt.Sprintf("%s%05d.%s", tsm1.WALFilePrefix, id, tsm1.WALFileExtension))
+}
+
+// append durably appends the values to the queue. If the queue is full, append waits
+// up to timeout for segments to be removed before returning errQueueFull.
+func (q *queue) append(values map[string][]tsm1.Value, timeout time.Duration) error {
+	entry := &tsm1.WriteWALEntry{Values: values}
+	b, err := entry.Encode(nil)
+	if err != nil {
+		return err
+	}
+	compressed := snappy.Encode(nil, b)
+
+	var timer <-chan time.Time
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for q.size > 0 && q.size+int64(len(compressed)) > q.maxSize {
+		if q.closed {
+			return errQueueClosed
+		}
+		if timer == nil {
+			timer = time.After(timeout)
+		}
+		removed := q.removed
+		q.mu.Unlock()
+		select {
+		case <-removed:
+		case <-timer:
+			q.mu.Lock()
+			return errQueueFull
+		}
+		q.mu.Lock()
+	}
+	if q.closed {
+		return errQueueClosed
+	}
+
+	if q.w == nil {
+		f, err := os.OpenFile(q.segmentPath(q.nextID), os.O_CREATE|os.O_RDWR|os.O_EXCL, 0600)
+		if err != nil {
+			return err
+		}
+		q.nextID++
+		q.f, q.w, q.wSize = f, tsm1.NewWALSegmentWriter(f), 0
+	}
+
+	if err := q.w.Write(entry.Type(), compressed); err != nil {
+		return err
+	}
+	if err := q.w.Flush(); err != nil {
+		return err
+	}
+	if err := q.f.Sync(); err != nil {
+		return err
+	}
+
+	n := int64(5 + len(compressed))
+	if q.size == 0 {
+		q.since = time.Now()
+	}
+	q.size += n
+	q.wSize += n
+	if q.wSize > segmentSize {
+		if err := q.rollSegment(); err != nil {
+			return err
+		}
+	}
+
+	select {
+	case q.appended <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// rollSegment closes the segment being appended to. q.mu must be held.
+func (q *queue) rollSegment() error {
+	if q.w == nil {
+		return nil
+	}
+	id, _ := segmentID(filepath.Base(q.f.Name()))
+	err := q.f.Close()
+	q.f, q.w, q.wSize = nil, nil, 0
+	q.segments = append(q.segments, id)
+	return err
+}
+
+// next returns the path of the oldest segment, closing the segment being appended to
+// if there are no others. It returns false if the queue is empty.
+func (q *queue) next() (string, bool, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.segments) == 0 && q.wSize > 0 {
+		if err := q.rollSegment(); err != nil {
+			return "", false, err
+		}
+	}
+	if len(q.segments) == 0 {
+		return "", false, nil
+	}
+	return q.segmentPath(q.segments[0]), true, nil
+}
+
+// remove removes the oldest segment, whose path was returned by next.
+func (q *queue) remove(path string) error {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.segments = q.segments[1:]
+	q.size -= fi.Size()
+	if q.size <= 0 {
+		q.size, q.since = 0, time.Time{}
+	}
+	close(q.removed)
+	q.removed = make(chan struct{})
+	return nil
+}
+
+// stats returns the size of the queue and the time it was last empty.
+func (q *queue) stats() (int64, time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.size, q.since
+}
+
+// close closes the segment being appended to. Waiting appends fail with errQueueClosed.
+func (q *queue) close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	close(q.removed)
+	q.removed = make(chan struct{})
+	return q.rollSegment()
+}
@@ -0,0 +1,312 @@
+// Package replication forwards the points written to local buckets to buckets of remote influxd servers.
+package replication
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+	"github.com/influxdata/platform/tsdb/tsm1"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+// Default settings of a Service.
+const (
+	DefaultMaxQueueWait     = 10 * time.Second
+	DefaultRetryInterval    = time.Second
+	DefaultMaxRetryInterval = time.Minute
+)
+
+// Service wraps a storage.PointsWriter and a platform.ReplicationService.
+//
+// Points written through the Service to a bucket with replications are queued on disk
+// for each replication once they have been written to the wrapped PointsWriter, and
+// forwarded in the background to the replication's remote bucket. Creating and
+// deleting replications through the Service starts and stops their forwarding.
+type Service struct {
+	platform.ReplicationService
+	PointsWriter storage.PointsWriter
+
+	// MaxQueueWait is how long a write waits for space in a full queue before it is rejected.
+	MaxQueueWait time.Duration
+
+	// RetryInterval is the initial interval between attempts to forward points,
+	// which doubles after each failure up to MaxRetryInterval.
+	RetryInterval    time.Duration
+	MaxRetryInterval time.Duration
+
+	Logger *zap.Logger
+
+	path    string
+	secrets platform.SecretService
+	metrics *metrics
+
+	mu       sync.RWMutex
+	streams  map[platform.ID]*stream
+	byBucket map[platform.ID][]*stream
+}
+
+// NewService returns a new Service writing points to pw, forwarding the points of the
+// replications of rs with the tokens in ss, and queueing them in directories under path.
+func NewService(path string, pw storage.PointsWriter, rs platform.ReplicationService, ss platform.SecretService) *Service {
+	return &Service{
+		ReplicationService: rs,
+		PointsWriter:       pw,
+		MaxQueueWait:       DefaultMaxQueueWait,
+		RetryInterval:      DefaultRetryInterval,
+		MaxRetryInterval:   DefaultMaxRetryInterval,
+		Logger:             zap.NewNop(),
+		path:               path,
+		secrets:            ss,
+		metrics:            newMetrics(),
+		streams:            make(map[platform.ID]*stream),
+		byBucket:           make(map[platform.ID][]*stream),
+	}
+}
+
+// Open starts forwarding the points of all replications, and removes the queues of
+// replications that no longer exist.
+func (s *Service) Open(ctx context.Context) error {
+	if err := os.MkdirAll(s.path, 0700); err != nil {
+		return err
+	}
+
+	rs, _, err := s.ReplicationService.FindReplications(ctx, platform.ReplicationFilter{})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range rs {
+		if err := s.openStream(*r); err != nil {
+			return err
+		}
+	}
+
+	fis, err := ioutil.ReadDir(s.path)
+	if err != nil {
+		return err
+	}
+	for _, fi := range fis {
+		var id platform.ID
+		if err := id.DecodeFromString(fi.Name()); err != nil {
+			continue
+		}
+		if _, ok := s.streams[id]; !ok {
+			s.Logger.Info("Removing queue of deleted replication", zap.String("replication_id", fi.Name()))
+			if err := os.RemoveAll(filepath.Join(s.path, fi.Name())); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Close stops forwarding points. Queued points are forwarded once the Service is reopened.
+func (s *Service) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var err error
+	for id, st := range s.streams {
+		if e := st.close(); e != nil && err == nil {
+			err = e
+		}
+		delete(s.streams, id)
+	}
+	s.byBucket = make(map[platform.ID][]*stream)
+	return err
+}
+
+// openStream starts forwarding the points of r. s.mu must be held.
+func (s *Service) openStream(r platform.Replication) error {
+	maxSize := r.MaxQueueSizeBytes
+	if maxSize == 0 {
+		maxSize = DefaultMaxQueueSize
+	}
+	q, err := openQueue(filepath.Join(s.path, r.ID.String()), maxSize)
+	if err != nil {
+		return err
+	}
+
+	st := &stream{
+		r:                r,
+		q:                q,
+		secrets:          s.secrets,
+		metrics:          s.metrics,
+		logger:           s.Logger.With(zap.String("replication_id", r.ID.String())),
+		retryInterval:    s.RetryInterval,
+		maxRetryInterval: s.MaxRetryInterval,
+	}
+	st.open()
+	s.streams[r.ID] = st
+	s.byBucket[r.LocalBucketID] = append(s.byBucket[r.LocalBucketID], st)
+	return nil
+}
+
+// CreateReplication creates a new replication and starts forwarding the points written to its local bucket.
+func (s *Service) CreateReplication(ctx context.Context, r *platform.Replication) error {
+	if err := s.ReplicationService.CreateReplication(ctx, r); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.openStream(*r)
+}
+
+// DeleteReplication removes a replication by ID, along with its queue of points not yet forwarded.
+func (s *Service) DeleteReplication(ctx context.Context, id platform.ID) error {
+	if err := s.ReplicationService.DeleteReplication(ctx, id); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	st, ok := s.streams[id]
+	if ok {
+		delete(s.streams, id)
+		streams := s.byBucket[st.r.LocalBucketID]
+		for i := range streams {
+			if streams[i] == st {
+				s.byBucket[st.r.LocalBucketID] = append(streams[:i:i], streams[i+1:]...)
+				break
+			}
+		}
+		if len(s.byBucket[st.r.LocalBucketID]) == 0 {
+			delete(s.byBucket, st.r.LocalBucketID)
+		}
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+	err := st.close()
+	s.metrics.delete(id.String())
+	if e := os.RemoveAll(st.q.dir); e != nil && err == nil {
+		err = e
+	}
+	return err
+}
+
+// WritePoints writes the exploded points to the wrapped PointsWriter, and then queues the
+// points of each bucket for each of its replications. The points dropped by a partial write
+// are not queued, and the tsdb.PartialWriteError is returned once the accepted points have
+// been queued. An error is returned if the queue of a replication remains full for
+// MaxQueueWait, in which case the points have been written locally but may not be forwarded.
+func (s *Service) WritePoints(points []models.Point) error {
+	var dropped map[string]struct{}
+	writeErr := s.PointsWriter.WritePoints(points)
+	if pwe, ok := writeErr.(tsdb.PartialWriteError); ok {
+		dropped = make(map[string]struct{}, len(pwe.DroppedKeys))
+		for _, key := range pwe.DroppedKeys {
+			dropped[string(key)] = struct{}{}
+		}
+	} else if writeErr != nil {
+		return writeErr
+	}
+
+	if err := s.queuePoints(points, dropped); err != nil {
+		return err
+	}
+	return writeErr
+}
+
+// queuePoints queues the points not in dropped for the replications of their buckets.
+// s.mu is only held while looking up the replications, so that waiting for space in a
+// full queue does not block creating and deleting replications.
+func (s *Service) queuePoints(points []models.Point, dropped map[string]struct{}) error {
+	s.mu.RLock()
+	if len(s.byBucket) == 0 {
+		s.mu.RUnlock()
+		return nil
+	}
+	streams := make(map[platform.ID][]*stream)
+	byBucket := make(map[platform.ID]map[string][]tsm1.Value)
+	for _, pt := range points {
+		name := pt.Name()
+		if len(name) != platform.IDLength {
+			continue
+		}
+		if _, ok := dropped[string(pt.Key())]; ok {
+			continue
+		}
+		var n [16]byte
+		copy(n[:], name)
+		_, bucketID := tsdb.DecodeName(n)
+
+		values := byBucket[bucketID]
+		if values == nil {
+			sts, ok := s.byBucket[bucketID]
+			if !ok {
+				continue
+			}
+			streams[bucketID] = append([]*stream(nil), sts...)
+			values = make(map[string][]tsm1.Value)
+			byBucket[bucketID] = values
+		}
+		t := pt.Time().UnixNano()
+		iter := pt.FieldIterator()
+		for iter.Next() {
+			v, err := fieldValue(iter)
+			if err != nil {
+				s.mu.RUnlock()
+				return err
+			}
+			key := string(tsm1.SeriesFieldKeyBytes(string(pt.Key()), string(iter.FieldKey())))
+			values[key] = append(values[key], tsm1.NewValue(t, v))
+		}
+	}
+	s.mu.RUnlock()
+
+	for bucketID, values := range byBucket {
+		for _, st := range streams[bucketID] {
+			switch err := st.q.append(values, s.MaxQueueWait); err {
+			case nil:
+			case errQueueClosed:
+				// The replication was deleted or the Service closed while queueing.
+			case errQueueFull:
+				s.metrics.RejectedWrites.WithLabelValues(st.r.ID.String()).Inc()
+				return &platform.Error{
+					Code: platform.EUnavailable,
+					Msg:  fmt.Sprintf("replication %q is not keeping up with writes", st.r.Name),
+					Err:  err,
+				}
+			default:
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func fieldValue(iter models.FieldIterator) (interface{}, error) {
+	switch iter.Type() {
+	case models.Float:
+		return iter.FloatValue()
+	case models.Integer:
+		return iter.IntegerValue()
+	case models.Unsigned:
+		return iter.UnsignedValue()
+	case models.Boolean:
+		return iter.BooleanValue()
+	case models.String:
+		return iter.StringValue(), nil
+	}
+	return nil, fmt.Errorf("unknown field type %v", iter.Type())
+}
+
+// PrometheusCollectors satisfies the prom.PrometheusCollector interface.
+func (s *Service) PrometheusCollectors() []prometheus.Collector {
+	return s.metrics.PrometheusCollectors()
+}
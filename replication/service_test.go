@@ -0,0 +1,412 @@
+package replication_test
+
+import (
+	"context"
+	"io/ioutil"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform"
+	pcontext "github.com/influxdata/platform/context"
+	"github.com/influxdata/platform/http"
+	"github.com/influxdata/platform/inmem"
+	"github.com/influxdata/platform/mock"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/replication"
+	"github.com/influxdata/platform/tsdb"
+)
+
+// remote is a write endpoint of an influxd accepting writes with the token "secret".
+type remote struct {
+	*httptest.Server
+	Org    *platform.Organization
+	Bucket *platform.Bucket
+	Points *mock.PointsWriter
+	down   int32
+}
+
+func newRemote(t *testing.T) *remote {
+	ctx := context.Background()
+	svc := inmem.NewService()
+	rm := &remote{
+		Org:    &platform.Organization{Name: "remote"},
+		Points: &mock.PointsWriter{},
+	}
+	if err := svc.CreateOrganization(ctx, rm.Org); err != nil {
+		t.Fatal(err)
+	}
+	rm.Bucket = &platform.Bucket{OrganizationID: rm.Org.ID, Name: "remote"}
+	if err := svc.CreateBucket(ctx, rm.Bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	h := http.NewWriteHandler(rm.Points)
+	h.OrganizationService = svc
+	h.BucketService = svc
+	rm.Server = httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		ctx := r.Context()
+		if atomic.LoadInt32(&rm.down) == 1 {
+			http.EncodeError(ctx, &platform.Error{Code: platform.EUnavailable, Msg: "down"}, w)
+			return
+		}
+		if r.Header.Get("Authorization") != "Token secret" {
+			http.EncodeError(ctx, &platform.Error{Code: platform.EForbidden, Msg: "bad token"}, w)
+			return
+		}
+		auth := &platform.Authorization{Status: platform.Active, Permissions: []platform.Permission{
+			{Action: platform.WriteAction, Resource: platform.BucketsResource, ID: &rm.Bucket.ID},
+		}}
+		h.ServeHTTP(w, r.WithContext(pcontext.SetAuthorizer(ctx, auth)))
+	}))
+	return rm
+}
+
+func (rm *remote) setDown(down bool) {
+	var v int32
+	if down {
+		v = 1
+	}
+	atomic.StoreInt32(&rm.down, v)
+}
+
+// lines returns the points written to the remote as sorted line protocol without the exploded tags.
+func (rm *remote) lines() []string {
+	rm.Points.ForceError(nil)
+	var lines []string
+	for {
+		pt := rm.Points.Next()
+		if pt == nil {
+			break
+		}
+		tags := pt.Tags()
+		m := tags.GetString(tsdb.MeasurementTagKey)
+		tags.Delete([]byte(tsdb.MeasurementTagKey))
+		tags.Delete([]byte(tsdb.FieldKeyTagKey))
+		np, _ := models.NewPoint(m, tags, mustFields(pt), pt.Time())
+		lines = append(lines, np.String())
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func mustFields(pt models.Point) models.Fields {
+	f, err := pt.Fields()
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// replications is a platform.ReplicationService keeping replications in memory.
+type replications struct {
+	mu sync.Mutex
+	id platform.ID
+	m  map[platform.ID]*platform.Replication
+}
+
+func (s *replications) FindReplicationByID(ctx context.Context, id platform.ID) (*platform.Replication, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.m[id]
+	if !ok {
+		return nil, &platform.Error{Code: platform.ENotFound, Msg: platform.ErrReplicationNotFound}
+	}
+	return r, nil
+}
+
+func (s *replications) FindReplications(ctx context.Context, filter platform.ReplicationFilter) ([]*platform.Replication, int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var rs []*platform.Replication
+	for _, r := range s.m {
+		rs = append(rs, r)
+	}
+	return rs, len(rs), nil
+}
+
+func (s *replications) CreateReplication(ctx context.Context, r *platform.Replication) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.id++
+	r.ID = s.id
+	if s.m == nil {
+		s.m = make(map[platform.ID]*platform.Replication)
+	}
+	s.m[r.ID] = r
+	return nil
+}
+
+func (s *replications) DeleteReplication(ctx context.Context, id platform.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.m, id)
+	return nil
+}
+
+func secrets() platform.SecretService {
+	ss := mock.NewSecretService()
+	ss.LoadSecretFn = func(ctx context.Context, orgID platform.ID, k string) (string, error) {
+		if orgID != 1 || k != "remote-token" {
+			return "", &platform.Error{Code: platform.ENotFound, Msg: "secret not found"}
+		}
+		return "secret", nil
+	}
+	return ss
+}
+
+func newService(t *testing.T, dir string, rs platform.ReplicationService) (*replication.Service, *mock.PointsWriter) {
+	local := &mock.PointsWriter{}
+	s := replication.NewService(dir, local, rs, secrets())
+	s.RetryInterval = 10 * time.Millisecond
+	s.MaxRetryInterval = 50 * time.Millisecond
+	s.MaxQueueWait = 50 * time.Millisecond
+	if err := s.Open(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return s, local
+}
+
+func mustExplode(t *testing.T, bucketID platform.ID, lp string) []models.Point {
+	points, err := models.ParsePointsString(lp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exploded, err := tsdb.ExplodePoints(1, bucketID, points)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return exploded
+}
+
+func waitForLines(t *testing.T, rm *remote, exp []string) {
+	t.Helper()
+	var got []string
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		got = append(got, rm.lines()...)
+		sort.Strings(got)
+		if strings.Join(got, "\n") == strings.Join(exp, "\n") {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("unexpected points forwarded:\n%s\nexpected:\n%s", strings.Join(got, "\n"), strings.Join(exp, "\n"))
+}
+
+func TestService(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replication-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rm := newRemote(t)
+	defer rm.Close()
+
+	ctx := context.Background()
+	rs := &replications{}
+	s, local := newService(t, dir, rs)
+
+	r := &platform.Replication{
+		OrganizationID:       1,
+		Name:                 "r",
+		LocalBucketID:        10,
+		RemoteURL:            rm.URL,
+		RemoteOrganizationID: rm.Org.ID,
+		RemoteBucketID:       rm.Bucket.ID,
+		TokenSecretKey:       "remote-token",
+	}
+	if err := s.CreateReplication(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+
+	// Points of replicated buckets are written locally and forwarded; others are only written locally.
+	if err := s.WritePoints(mustExplode(t, 10, "cpu,host=a value=1,ok=true 10\nmem,host=b used=2i 20")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WritePoints(mustExplode(t, 11, "cpu,host=a value=3 30")); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(local.Points); got != 4 {
+		t.Fatalf("expected 4 points written locally, got %d", got)
+	}
+	waitForLines(t, rm, []string{
+		"cpu,host=a ok=true 10",
+		"cpu,host=a value=1 10",
+		"mem,host=b used=2i 20",
+	})
+
+	// Points written while the remote is down are queued durably and forwarded once it is back up,
+	// even if the service is restarted in between.
+	rm.setDown(true)
+	if err := s.WritePoints(mustExplode(t, 10, "cpu,host=a value=4 40")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := rm.lines(); len(got) != 0 {
+		t.Fatalf("unexpected points forwarded while remote is down: %v", got)
+	}
+
+	rm.setDown(false)
+	s, _ = newService(t, dir, rs)
+	waitForLines(t, rm, []string{"cpu,host=a value=4 40"})
+
+	// Deleting the replication stops forwarding and removes its queue.
+	if err := s.DeleteReplication(ctx, r.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(dir + "/" + r.ID.String()); !os.IsNotExist(err) {
+		t.Fatalf("expected queue of deleted replication to be removed, got %v", err)
+	}
+	if err := s.WritePoints(mustExplode(t, 10, "cpu,host=a value=5 50")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := rm.lines(); len(got) != 0 {
+		t.Fatalf("unexpected points forwarded after replication was deleted: %v", got)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestService_Backpressure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replication-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rm := newRemote(t)
+	defer rm.Close()
+	rm.setDown(true)
+
+	s, _ := newService(t, dir, &replications{})
+	defer s.Close()
+
+	if err := s.CreateReplication(context.Background(), &platform.Replication{
+		OrganizationID:       1,
+		Name:                 "r",
+		LocalBucketID:        10,
+		RemoteURL:            rm.URL,
+		RemoteOrganizationID: rm.Org.ID,
+		RemoteBucketID:       rm.Bucket.ID,
+		TokenSecretKey:       "remote-token",
+		MaxQueueSizeBytes:    1,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A write to an empty queue is always accepted, but the queue is then full until it is forwarded.
+	if err := s.WritePoints(mustExplode(t, 10, "cpu value=1 10")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WritePoints(mustExplode(t, 10, "cpu value=2 20")); platform.ErrorCode(err) != platform.EUnavailable {
+		t.Fatalf("expected unavailable error writing to a full queue, got %v", err)
+	}
+
+	rm.setDown(false)
+	waitForLines(t, rm, []string{"cpu value=1 10"})
+	if err := s.WritePoints(mustExplode(t, 10, "cpu value=2 20")); err != nil {
+		t.Fatal(err)
+	}
+	waitForLines(t, rm, []string{"cpu value=2 20"})
+}
+
+func TestService_PartialWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replication-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rm := newRemote(t)
+	defer rm.Close()
+
+	s, local := newService(t, dir, &replications{})
+	defer s.Close()
+
+	if err := s.CreateReplication(context.Background(), &platform.Replication{
+		OrganizationID:       1,
+		Name:                 "r",
+		LocalBucketID:        10,
+		RemoteURL:            rm.URL,
+		RemoteOrganizationID: rm.Org.ID,
+		RemoteBucketID:       rm.Bucket.ID,
+		TokenSecretKey:       "remote-token",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The points accepted by a partial write are forwarded, and the partial write error is returned.
+	points := mustExplode(t, 10, "cpu value=1 10\nmem value=2 20")
+	local.ForceError(tsdb.PartialWriteError{Reason: "dropped", Dropped: 1, DroppedKeys: [][]byte{points[1].Key()}})
+	if _, ok := s.WritePoints(points).(tsdb.PartialWriteError); !ok {
+		t.Fatal("expected partial write error")
+	}
+	waitForLines(t, rm, []string{"cpu value=1 10"})
+}
+
+func TestService_WriteToFullQueueDoesNotBlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "replication-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	rm := newRemote(t)
+	defer rm.Close()
+	rm.setDown(true)
+
+	ctx := context.Background()
+	s, _ := newService(t, dir, &replications{})
+	defer s.Close()
+	s.MaxQueueWait = 5 * time.Second
+
+	r := &platform.Replication{
+		OrganizationID:       1,
+		Name:                 "r",
+		LocalBucketID:        10,
+		RemoteURL:            rm.URL,
+		RemoteOrganizationID: rm.Org.ID,
+		RemoteBucketID:       rm.Bucket.ID,
+		TokenSecretKey:       "remote-token",
+		MaxQueueSizeBytes:    1,
+	}
+	if err := s.CreateReplication(ctx, r); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.WritePoints(mustExplode(t, 10, "cpu value=1 10")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A write waiting for space in the full queue does not block deleting the replication,
+	// and is not rejected once the replication is deleted.
+	points := mustExplode(t, 10, "cpu value=2 20")
+	errC := make(chan error, 1)
+	go func() {
+		errC <- s.WritePoints(points)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	start := time.Now()
+	if err := s.DeleteReplication(ctx, r.ID); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("deleting the replication was blocked by a waiting write for %s", d)
+	}
+	if err := <-errC; err != nil {
+		t.Fatalf("unexpected error from write waiting on deleted replication: %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package replication
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/http"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/tsdb"
+	"github.com/influxdata/platform/tsdb/tsm1"
+	"go.uber.org/zap"
+)
+
+// stream forwards the writes queued for a replication to its remote bucket.
+type stream struct {
+	r       platform.Replication
+	q       *queue
+	secrets platform.SecretService
+	metrics *metrics
+	logger  *zap.Logger
+
+	retryInterval    time.Duration
+	maxRetryInterval time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func (s *stream) open() {
+	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run()
+	}()
+}
+
+// close stops forwarding writes and closes the queue.
+func (s *stream) close() error {
+	s.cancel()
+	s.wg.Wait()
+	return s.q.close()
+}
+
+func (s *stream) run() {
+	id := s.r.ID.String()
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		size, since := s.q.stats()
+		s.metrics.QueueBytes.WithLabelValues(id).Set(float64(size))
+		if since.IsZero() {
+			s.metrics.LagSeconds.WithLabelValues(id).Set(0)
+		} else {
+			s.metrics.LagSeconds.WithLabelValues(id).Set(time.Since(since).Seconds())
+		}
+
+		path, ok, err := s.q.next()
+		if err != nil {
+			s.logger.Info("Error reading replication queue", zap.Error(err))
+		}
+		if !ok {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-s.q.appended:
+			case <-ticker.C:
+			}
+			continue
+		}
+
+		if err := s.forwardSegment(path); err != nil {
+			if s.ctx.Err() != nil {
+				return
+			}
+			s.logger.Info("Error forwarding replication queue segment", zap.String("path", path), zap.Error(err))
+			// Wait before trying the segment again.
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-time.After(s.retryInterval):
+			}
+			continue
+		}
+		if err := s.q.remove(path); err != nil {
+			s.logger.Info("Error removing replication queue segment", zap.String("path", path), zap.Error(err))
+		}
+	}
+}
+
+// forwardSegment forwards each entry of the segment, retrying each until it succeeds.
+// A corrupt segment is forwarded up to the first entry that cannot be read.
+func (s *stream) forwardSegment(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	r := tsm1.NewWALSegmentReader(f)
+	defer r.Close()
+
+	for r.Next() {
+		entry, err := r.Read()
+		if err != nil {
+			s.logger.Info("Skipping the remainder of corrupt replication queue segment", zap.String("path", path), zap.Error(err))
+			return nil
+		}
+		w, ok := entry.(*tsm1.WriteWALEntry)
+		if !ok {
+			continue
+		}
+		if err := s.forwardWithRetry(w.Values); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forwardWithRetry forwards the values, retrying with exponential backoff until they
+// are written, rejected as invalid, or the stream is closed.
+func (s *stream) forwardWithRetry(values map[string][]tsm1.Value) error {
+	id := s.r.ID.String()
+	lp, n := lineProtocol(values)
+	if n == 0 {
+		return nil
+	}
+
+	interval := s.retryInterval
+	for {
+		err := s.forward(lp)
+		if err == nil {
+			s.metrics.Points.WithLabelValues(id).Add(float64(n))
+			return nil
+		}
+		if platform.ErrorCode(err) == platform.EInvalid {
+			s.logger.Info("Dropping points rejected by the remote bucket", zap.Int("points", n), zap.Error(err))
+			s.metrics.Dropped.WithLabelValues(id).Add(float64(n))
+			return nil
+		}
+
+		s.metrics.Errors.WithLabelValues(id).Inc()
+		s.logger.Info("Error forwarding points, retrying", zap.Duration("interval", interval), zap.Error(err))
+		select {
+		case <-s.ctx.Done():
+			return s.ctx.Err()
+		case <-time.After(interval):
+		}
+		if interval *= 2; interval > s.maxRetryInterval {
+			interval = s.maxRetryInterval
+		}
+	}
+}
+
+// forward writes the line protocol to the remote bucket with the token of the replication.
+func (s *stream) forward(lp []byte) error {
+	token, err := s.secrets.LoadSecret(s.ctx, s.r.OrganizationID, s.r.TokenSecretKey)
+	if err != nil {
+		return err
+	}
+	w := &http.WriteService{
+		Addr:  s.r.RemoteURL,
+		Token: token,
+	}
+	return w.Write(s.ctx, s.r.RemoteOrganizationID, s.r.RemoteBucketID, bytes.NewReader(lp))
+}
+
+// lineProtocol returns the values of the exploded series keys as line protocol, restoring
+// the measurement and field names from the tags they are stored in, along with the number
+// of points.
+func lineProtocol(values map[string][]tsm1.Value) ([]byte, int) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var buf []byte
+	var n int
+	for _, k := range keys {
+		seriesKey, field := tsm1.SeriesAndFieldFromCompositeKey([]byte(k))
+		_, tags := models.ParseKeyBytes(seriesKey)
+
+		var measurement []byte
+		pointTags := make(models.Tags, 0, len(tags))
+		for _, t := range tags {
+			switch string(t.Key) {
+			case tsdb.MeasurementTagKey:
+				measurement = t.Value
+			case tsdb.FieldKeyTagKey:
+			default:
+				pointTags = append(pointTags, t)
+			}
+		}
+		if len(measurement) == 0 {
+			continue
+		}
+
+		for _, v := range values[k] {
+			pt, err := models.NewPoint(string(measurement), pointTags, models.Fields{string(field): v.Value()}, time.Unix(0, v.UnixNano()))
+			if err != nil {
+				continue
+			}
+			buf = pt.AppendString(buf)
+			buf = append(buf, '\n')
+			n++
+		}
+	}
+	return buf, n
+}
@@ -0,0 +1,176 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+)
+
+// ErrRoleNotFound is the error message for a missing role.
+const ErrRoleNotFound = "role not found"
+
+// ops for roles.
+const (
+	OpFindRoleByID = "FindRoleByID"
+	OpFindRoles    = "FindRoles"
+	OpCreateRole   = "CreateRole"
+	OpUpdateRole   = "UpdateRole"
+	OpDeleteRole   = "DeleteRole"
+)
+
+// Role is a named set of permissions in an organization.
+// A role is assigned to users by a user resource mapping of the roles resource,
+// and to authorizations by their RoleIDs.
+type Role struct {
+	ID             ID           `json:"id,omitempty"`
+	OrganizationID ID           `json:"orgID,omitempty"`
+	Name           string       `json:"name"`
+	Description    string       `json:"description,omitempty"`
+	Permissions    []Permission `json:"permissions"`
+}
+
+// Valid returns an error if the role is missing required fields, or if any of its
+// permissions reaches outside of the organization of the role.
+func (r *Role) Valid() error {
+	if r.Name == "" {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "role name is required",
+		}
+	}
+	if !r.OrganizationID.Valid() {
+		return &Error{
+			Code: EInvalid,
+			Msg:  "role organization ID is required",
+		}
+	}
+
+	for _, p := range r.Permissions {
+		if err := p.Valid(); err != nil {
+			return err
+		}
+
+		inOrg := false
+		switch {
+		case p.Resource.InOrg():
+			inOrg = p.OrgID != nil && *p.OrgID == r.OrganizationID
+		case p.Resource == OrgsResource:
+			inOrg = p.ID != nil && *p.ID == r.OrganizationID
+		}
+		if !inOrg {
+			return &Error{
+				Code: EInvalid,
+				Msg:  fmt.Sprintf("role permission %s is not restricted to organization %s", p, r.OrganizationID),
+			}
+		}
+	}
+	return nil
+}
+
+// RoleUpdate represents the changes to a role.
+type RoleUpdate struct {
+	Name        *string       `json:"name,omitempty"`
+	Description *string       `json:"description,omitempty"`
+	Permissions *[]Permission `json:"permissions,omitempty"`
+}
+
+// Apply applies the non-nil fields of the update to the role.
+func (u RoleUpdate) Apply(r *Role) {
+	if u.Name != nil {
+		r.Name = *u.Name
+	}
+	if u.Description != nil {
+		r.Description = *u.Description
+	}
+	if u.Permissions != nil {
+		r.Permissions = *u.Permissions
+	}
+}
+
+// RoleFilter represents a set of filters that restrict the returned roles.
+type RoleFilter struct {
+	ID             *ID
+	OrganizationID *ID
+	Name           *string
+}
+
+// RoleService manages the roles of organizations.
+type RoleService interface {
+	// FindRoleByID returns a single role by ID.
+	FindRoleByID(ctx context.Context, id ID) (*Role, error)
+
+	// FindRoles returns a list of roles that match filter and the total count of matching roles.
+	FindRoles(ctx context.Context, filter RoleFilter) ([]*Role, int, error)
+
+	// CreateRole creates a new role and sets r.ID with the new identifier.
+	// Role names are unique within an organization.
+	CreateRole(ctx context.Context, r *Role) error
+
+	// UpdateRole updates a single role with changeset.
+	// Returns the new role state after update.
+	UpdateRole(ctx context.Context, id ID, upd RoleUpdate) (*Role, error)
+
+	// DeleteRole removes a role by ID, and its assignments to users.
+	DeleteRole(ctx context.Context, id ID) error
+}
+
+// RolePermissions returns the permissions of the roles with the ids, ignoring the roles that
+// no longer exist or belong to another organization than orgID.
+func RolePermissions(ctx context.Context, s RoleService, orgID ID, ids []ID) ([]Permission, error) {
+	var ps []Permission
+	for _, id := range ids {
+		r, err := s.FindRoleByID(ctx, id)
+		if ErrorCode(err) == ENotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if r.OrganizationID != orgID {
+			continue
+		}
+		ps = append(ps, r.Permissions...)
+	}
+	return ps, nil
+}
+
+// RolePresets are the permissions of the roles most organizations need, by role name.
+var RolePresets = map[string]func(orgID ID) []Permission{
+	"viewer":        ViewerPermissions,
+	"editor":        EditorPermissions,
+	"task operator": TaskOperatorPermissions,
+}
+
+// ViewerPermissions are the permissions to read the organization and its resources.
+func ViewerPermissions(orgID ID) []Permission {
+	ps := []Permission{{ID: &orgID, Action: ReadAction, Resource: OrgsResource}}
+	return append(ps, OrgMemberPermissions(orgID)...)
+}
+
+// EditorPermissions are the permissions to read the organization, and to change
+// its resources other than its users, secrets and roles.
+func EditorPermissions(orgID ID) []Permission {
+	ps := ViewerPermissions(orgID)
+	for _, r := range OrgResources {
+		switch r {
+		case UsersResource, SecretsResource, RolesResource:
+			continue
+		}
+		for _, a := range []Action{WriteAction, DeleteAction} {
+			ps = append(ps, Permission{OrgID: &orgID, Action: a, Resource: r})
+		}
+	}
+	return ps
+}
+
+// TaskOperatorPermissions are the permissions to manage the tasks of the organization,
+// and to read and write the buckets they query.
+func TaskOperatorPermissions(orgID ID) []Permission {
+	ps := []Permission{{ID: &orgID, Action: ReadAction, Resource: OrgsResource}}
+	for _, a := range actions {
+		ps = append(ps, Permission{OrgID: &orgID, Action: a, Resource: TasksResource})
+	}
+	for _, a := range []Action{ReadAction, WriteAction} {
+		ps = append(ps, Permission{OrgID: &orgID, Action: a, Resource: BucketsResource})
+	}
+	return ps
+}
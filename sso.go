@@ -0,0 +1,30 @@
+package platform
+
+import "context"
+
+// ErrSSOIdentityNotFound is the error message for a missing single sign-on identity.
+const ErrSSOIdentityNotFound = "sso identity not found"
+
+// ops for single sign-on identities.
+const (
+	OpFindSSOIdentity = "FindSSOIdentity"
+	OpPutSSOIdentity  = "PutSSOIdentity"
+)
+
+// SSOIdentity links the subject of an identity provider to the user it signs in as.
+// Users are never matched to subjects by name, as a local user may have the name of a subject.
+type SSOIdentity struct {
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+	UserID   ID     `json:"userID"`
+}
+
+// SSOIdentityService manages the identities of users at identity providers.
+type SSOIdentityService interface {
+	// FindSSOIdentity returns the identity of the subject of the provider.
+	FindSSOIdentity(ctx context.Context, provider, subject string) (*SSOIdentity, error)
+
+	// PutSSOIdentity links the subject of the provider to the user of the identity,
+	// replacing any previous link of the subject.
+	PutSSOIdentity(ctx context.Context, i *SSOIdentity) error
+}
@@ -0,0 +1,282 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/tsdb"
+	"github.com/influxdata/platform/tsdb/tsi1"
+	"github.com/influxdata/platform/tsdb/tsm1"
+	"go.uber.org/zap"
+)
+
+// CreateBackup writes the cache to a TSM file and creates a directory of hard links to the TSM and
+// tombstone files of the engine, returning the path of the directory. The caller must remove the
+// directory once it has been copied.
+//
+// If orgID is not nil only the data of that organization, or of bucketID within it if bucketID is
+// also not nil, is included. The TSM files are then rewritten to contain only those series.
+// Cold TSM files are fetched from cold storage, so that the backup holds all of the data.
+func (e *Engine) CreateBackup(orgID, bucketID *platform.ID) (string, error) {
+	if orgID == nil && bucketID != nil {
+		return "", fmt.Errorf("an organization is required to back up a bucket")
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return "", ErrEngineClosed
+	}
+
+	if err := e.engine.WriteSnapshot(); err != nil {
+		return "", err
+	}
+	dir, err := e.engine.FileStore.CreateSnapshot()
+	if err != nil {
+		return "", err
+	}
+	store := e.config.coldObjectStore()
+	if orgID == nil {
+		if err := fetchColdFiles(dir, store); err != nil {
+			os.RemoveAll(dir)
+			return "", err
+		}
+		return dir, nil
+	}
+
+	// Measurement names begin with the organization ID, followed by the bucket ID.
+	encoded := tsdb.EncodeName(*orgID, 0)
+	prefix := encoded[:8]
+	if bucketID != nil {
+		encoded = tsdb.EncodeName(*orgID, *bucketID)
+		prefix = encoded[:]
+	}
+
+	if err := filterSnapshot(dir, models.EscapeMeasurement(prefix), store); err != nil {
+		os.RemoveAll(dir)
+		return "", err
+	}
+	return dir, nil
+}
+
+// fetchColdFiles replaces the stubs of cold TSM files in dir with the whole files, fetched from store.
+func fetchColdFiles(dir string, store tsm1.ObjectStore) error {
+	if store == nil {
+		return nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if _, err := tsm1.FetchColdFile(store, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filterSnapshot rewrites the TSM files in dir to contain only the keys starting with prefix.
+// TSM files without any such keys are removed along with their tombstones. The blocks of cold
+// TSM files are read from store.
+func filterSnapshot(dir string, prefix []byte, store tsm1.ObjectStore) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		n, err := filterTSMFile(path, path+".filtered", prefix, store)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			if err := os.Remove(path + ".filtered"); err != nil {
+				return err
+			}
+			if err := os.Remove(path); err != nil {
+				return err
+			}
+			tombstones, err := filepath.Glob(strings.TrimSuffix(path, "."+tsm1.TSMFileExtension) + ".*")
+			if err != nil {
+				return err
+			}
+			for _, ts := range tombstones {
+				if err := os.Remove(ts); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		// Replace the hard link rather than writing through it, which would modify the engine's file.
+		if err := os.Rename(path+".filtered", path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filterTSMFile writes the blocks of the TSM file at src whose keys start with prefix to a new TSM file at dst,
+// returning the number of blocks written. dst is empty if no blocks were written.
+func filterTSMFile(src, dst string, prefix []byte, store tsm1.ObjectStore) (int, error) {
+	f, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	r, err := tsm1.NewTSMReader(f, tsm1.WithTSMReaderObjectStore(store, nil))
+	if err != nil {
+		f.Close()
+		return 0, err
+	}
+	defer r.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_RDWR|os.O_EXCL, 0666)
+	if err != nil {
+		return 0, err
+	}
+	defer out.Close()
+
+	w, err := tsm1.NewTSMWriterWithDiskBuffer(out)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	iter := r.BlockIterator()
+	for iter.Next() {
+		key, minTime, maxTime, _, _, block, err := iter.Read()
+		if err != nil {
+			return 0, err
+		}
+		if !bytes.HasPrefix(key, prefix) {
+			if bytes.Compare(key, prefix) > 0 {
+				break // Keys are sorted, so no later key can match.
+			}
+			continue
+		}
+		if err := w.WriteBlock(key, minTime, maxTime, block); err != nil {
+			return 0, err
+		}
+		n++
+	}
+	if err := iter.Err(); err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, nil
+	}
+
+	if err := w.WriteIndex(); err != nil {
+		return 0, err
+	}
+	return n, w.Close()
+}
+
+// BuildIndex creates the series file and index of the engine at path from its TSM files, such as after
+// restoring a backup created by CreateBackup. The engine must not be open and must not have an index.
+func BuildIndex(path string, c Config, log *zap.Logger) error {
+	indexPath := c.GetIndexPath(path)
+	if _, err := os.Stat(indexPath); err == nil {
+		return fmt.Errorf("index already exists: %s", indexPath)
+	}
+
+	sfile := tsdb.NewSeriesFile(c.GetSeriesFilePath(path))
+	sfile.WithLogger(log)
+	if err := sfile.Open(); err != nil {
+		return err
+	}
+	defer sfile.Close()
+
+	index := tsi1.NewIndex(sfile, c.Index, tsi1.WithPath(indexPath), tsi1.DisableMetrics())
+	index.WithLogger(log)
+	if err := index.Open(); err != nil {
+		return err
+	}
+
+	paths, err := filepath.Glob(filepath.Join(c.GetEnginePath(path), "*."+tsm1.TSMFileExtension))
+	if err != nil {
+		index.Close()
+		return err
+	}
+	for _, p := range paths {
+		log.Info("Indexing TSM file", zap.String("path", p))
+		if err := indexTSMFile(index, p, c.coldObjectStore()); err != nil {
+			index.Close()
+			return err
+		}
+	}
+
+	index.Compact()
+	index.Wait()
+	return index.Close()
+}
+
+// indexBatchSize is the number of series added to the index at a time by BuildIndex.
+const indexBatchSize = 10000
+
+// indexTSMFile adds the series of the TSM file at path to index. The reader applies the tombstones
+// of the file when it is opened, removing the keys with all of their data deleted, so that deleted
+// series are not added back to the index. The index of a cold TSM file is read from its stub, but
+// store is required to open it.
+func indexTSMFile(index *tsi1.Index, path string, store tsm1.ObjectStore) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	r, err := tsm1.NewTSMReader(f, tsm1.WithTSMReaderObjectStore(store, nil))
+	if err != nil {
+		f.Close()
+		return err
+	}
+	defer r.Close()
+
+	collection := &tsdb.SeriesCollection{}
+	iter := r.Iterator(nil)
+	for iter.Next() {
+		seriesKey, _ := tsm1.SeriesAndFieldFromCompositeKey(iter.Key())
+		name, tags := models.ParseKeyBytes(seriesKey)
+
+		collection.Keys = append(collection.Keys, seriesKey)
+		collection.Names = append(collection.Names, name)
+		collection.Tags = append(collection.Tags, tags)
+		collection.Types = append(collection.Types, blockFieldType(iter.Type()))
+
+		if collection.Length() == indexBatchSize {
+			if err := index.CreateSeriesListIfNotExists(collection); err != nil {
+				return err
+			}
+			collection = &tsdb.SeriesCollection{}
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	if collection.Length() > 0 {
+		return index.CreateSeriesListIfNotExists(collection)
+	}
+	return nil
+}
+
+// blockFieldType returns the field type of the values in a TSM block of type typ.
+func blockFieldType(typ byte) models.FieldType {
+	switch typ {
+	case tsm1.BlockFloat64:
+		return models.Float
+	case tsm1.BlockInteger:
+		return models.Integer
+	case tsm1.BlockBoolean:
+		return models.Boolean
+	case tsm1.BlockString:
+		return models.String
+	case tsm1.BlockUnsigned:
+		return models.Unsigned
+	default:
+		return models.Empty
+	}
+}
@@ -0,0 +1,484 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/kv"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/tsdb"
+	"github.com/influxdata/platform/tsdb/tsm1"
+	"go.uber.org/zap"
+)
+
+// CopyBucket copies all of the data of the bucket srcBucketID of srcOrgID into the bucket
+// dstBucketID of dstOrgID. The blocks of the data are copied into new TSM files under the
+// series keys of the destination bucket, which are added to the index, without decoding the
+// points or writing them through the WAL. Points written to the source bucket while it is
+// being copied may or may not be copied.
+//
+// The destination bucket is expected to have the measurement schemas of the source bucket,
+// so the copied points are not validated again. New series are subject to the series limits.
+//
+// If progress is not nil, it is called after each batch of series copied with the number of
+// series copied, the total number of series and the number of points copied.
+func (e *Engine) CopyBucket(ctx context.Context, srcOrgID, srcBucketID, dstOrgID, dstBucketID platform.ID, progress func(copied, total int, points int64)) error {
+	src := tsdb.EncodeName(srcOrgID, srcBucketID)
+	dst := tsdb.EncodeName(dstOrgID, dstBucketID)
+	if src == dst {
+		return fmt.Errorf("cannot copy a bucket into itself")
+	}
+	if progress == nil {
+		progress = func(int, int, int64) {}
+	}
+
+	e.mu.RLock()
+	total, err := e.measurementSeriesN(src[:])
+	e.mu.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	var copied int
+	var points int64
+	if err := e.engine.CopyPrefix(ctx, models.EscapeMeasurement(src[:]), models.EscapeMeasurement(dst[:]), func(keys [][]byte, types []byte, n int64) error {
+		if err := e.createCopiedSeries(keys, types); err != nil {
+			return err
+		}
+		copied += len(keys)
+		points += n
+		progress(copied, total, points)
+		return nil
+	}); err != nil {
+		return err
+	}
+	progress(copied, total, points)
+	return nil
+}
+
+// measurementSeriesN returns the number of series of the measurement name.
+// e.mu must be held for reading.
+func (e *Engine) measurementSeriesN(name []byte) (int, error) {
+	if err := e.writable(); err != nil {
+		return 0, err
+	}
+	itr, err := e.index.MeasurementSeriesIDIterator(name)
+	if err != nil {
+		return 0, err
+	} else if itr == nil {
+		return 0, nil
+	}
+	defer itr.Close()
+
+	var n int
+	for {
+		elem, err := itr.Next()
+		if err != nil {
+			return 0, err
+		} else if elem.SeriesID.IsZero() {
+			return n, nil
+		}
+		n++
+	}
+}
+
+// createCopiedSeries adds the series of the TSM keys copied by CopyBucket to the series file
+// and index, given the block types of the keys. Unlike when writing points, a series dropped
+// for exceeding a series limit fails the copy.
+func (e *Engine) createCopiedSeries(keys [][]byte, types []byte) error {
+	collection := &tsdb.SeriesCollection{}
+	for i, key := range keys {
+		seriesKey, _ := tsm1.SeriesAndFieldFromCompositeKey(key)
+		name, tags := models.ParseKeyBytes(seriesKey)
+
+		collection.Keys = append(collection.Keys, seriesKey)
+		collection.Names = append(collection.Names, name)
+		collection.Tags = append(collection.Tags, tags)
+		collection.Types = append(collection.Types, blockFieldType(types[i]))
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if err := e.writable(); err != nil {
+		return err
+	}
+	if err := e.createSeries(collection); err != nil {
+		return err
+	}
+	return collection.PartialWriteError()
+}
+
+// BucketCopier copies and deletes the stored data of buckets.
+type BucketCopier interface {
+	CopyBucket(ctx context.Context, srcOrgID, srcBucketID, dstOrgID, dstBucketID platform.ID, progress func(copied, total int, points int64)) error
+	DeleteBucket(orgID, bucketID platform.ID) error
+}
+
+var _ BucketCopier = (*Engine)(nil)
+
+// BucketTransferService implements platform.BucketTransferService, copying the data of cloned
+// and moved buckets in the background.
+//
+// Transfers are saved in a kv.Store when they start and complete. Transfers that are running
+// when the service is closed are resumed when it is opened again, copying the data of their
+// bucket from the beginning, as copying the same points again does not change them.
+type BucketTransferService struct {
+	Logger *zap.Logger
+
+	engine      BucketCopier
+	buckets     platform.BucketService
+	idGenerator platform.IDGenerator
+	kv          kv.Store
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu        sync.RWMutex
+	transfers map[platform.ID]*platform.BucketTransfer
+}
+
+var _ platform.BucketTransferService = (*BucketTransferService)(nil)
+
+var bucketTransferBucket = []byte("buckettransfersv1")
+
+// NewBucketTransferService returns a BucketTransferService of the buckets of s, whose data is
+// stored by engine, which is typically an Engine, and which saves its transfers in store.
+func NewBucketTransferService(engine BucketCopier, s platform.BucketService, idGenerator platform.IDGenerator, store kv.Store) *BucketTransferService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &BucketTransferService{
+		Logger:      zap.NewNop(),
+		engine:      engine,
+		buckets:     s,
+		idGenerator: idGenerator,
+		kv:          store,
+		ctx:         ctx,
+		cancel:      cancel,
+		transfers:   make(map[platform.ID]*platform.BucketTransfer),
+	}
+}
+
+// Open loads the saved transfers and resumes the transfers that were running when the
+// service was last closed.
+func (s *BucketTransferService) Open(ctx context.Context) error {
+	var running []*platform.BucketTransfer
+	err := s.kv.Update(func(tx kv.Tx) error {
+		b, err := tx.Bucket(bucketTransferBucket)
+		if err != nil {
+			return err
+		}
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			t := &platform.BucketTransfer{}
+			if err := json.Unmarshal(v, t); err != nil {
+				return err
+			}
+			s.transfers[t.ID] = t
+			if t.Status == platform.BucketTransferRunning {
+				running = append(running, t)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, t := range running {
+		if !t.DestinationBucketID.Valid() {
+			// The service was closed before the destination bucket of the transfer was created.
+			s.finish(t, fmt.Errorf("transfer was interrupted before it started"))
+			continue
+		}
+		s.start(t, t.DestinationBucketID)
+	}
+	return nil
+}
+
+// Close stops the running transfers and waits for them to return. They are resumed when the
+// service is opened again.
+func (s *BucketTransferService) Close() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// CloneBucket creates a copy of the bucket id, except for its downsample tiers, and starts
+// copying its data into the copy.
+func (s *BucketTransferService) CloneBucket(ctx context.Context, id platform.ID, clone platform.BucketClone) (*platform.BucketTransfer, error) {
+	src, err := s.buckets.FindBucketByID(ctx, id)
+	if err != nil {
+		return nil, &platform.Error{Op: platform.OpCloneBucket, Err: err}
+	}
+
+	dst := &platform.Bucket{
+		OrganizationID:     clone.OrganizationID,
+		Name:               clone.Name,
+		RetentionPeriod:    src.RetentionPeriod,
+		ShardGroupDuration: src.ShardGroupDuration,
+		ColdStorageAfter:   src.ColdStorageAfter,
+		SchemaType:         src.SchemaType,
+		MeasurementSchemas: src.MeasurementSchemas,
+	}
+	if !dst.OrganizationID.Valid() {
+		dst.OrganizationID = src.OrganizationID
+	}
+	if dst.Name == "" {
+		dst.Name = src.Name
+	}
+
+	t := &platform.BucketTransfer{
+		Type:                      platform.BucketTransferClone,
+		BucketID:                  src.ID,
+		OrganizationID:            src.OrganizationID,
+		DestinationOrganizationID: dst.OrganizationID,
+	}
+	if err := s.reserve(t); err != nil {
+		return nil, &platform.Error{Op: platform.OpCloneBucket, Err: err}
+	}
+	if err := s.buckets.CreateBucket(ctx, dst); err != nil {
+		s.release(t)
+		return nil, &platform.Error{Op: platform.OpCloneBucket, Err: err}
+	}
+	return s.start(t, dst.ID), nil
+}
+
+// MoveBucket starts moving the bucket id into another organization, optionally renaming it.
+// The bucket is only moved once its data has been copied into the organization, after which
+// the data is removed from the previous organization. If the copy fails, the copied data is
+// removed and the bucket stays in its organization.
+func (s *BucketTransferService) MoveBucket(ctx context.Context, id platform.ID, move platform.BucketMove) (*platform.BucketTransfer, error) {
+	b, err := s.buckets.FindBucketByID(ctx, id)
+	if err != nil {
+		return nil, &platform.Error{Op: platform.OpMoveBucket, Err: err}
+	}
+	if !move.OrganizationID.Valid() {
+		return nil, &platform.Error{Code: platform.EInvalid, Op: platform.OpMoveBucket, Msg: "organization ID is required"}
+	}
+	if move.OrganizationID == b.OrganizationID {
+		return nil, &platform.Error{Code: platform.EInvalid, Op: platform.OpMoveBucket, Msg: "bucket is already in the organization"}
+	}
+
+	name := b.Name
+	if move.Name != "" {
+		name = move.Name
+	}
+	// The name is checked again when the bucket is moved, but a conflict is reported before any data is copied.
+	_, err = s.buckets.FindBucket(ctx, platform.BucketFilter{OrganizationID: &move.OrganizationID, Name: &name})
+	if err == nil {
+		return nil, &platform.Error{Code: platform.EConflict, Op: platform.OpMoveBucket, Msg: fmt.Sprintf("bucket with name %s already exists", name)}
+	} else if platform.ErrorCode(err) != platform.ENotFound {
+		return nil, &platform.Error{Op: platform.OpMoveBucket, Err: err}
+	}
+
+	t := &platform.BucketTransfer{
+		Type:                      platform.BucketTransferMove,
+		BucketID:                  b.ID,
+		OrganizationID:            b.OrganizationID,
+		DestinationOrganizationID: move.OrganizationID,
+		DestinationName:           move.Name,
+	}
+	if err := s.reserve(t); err != nil {
+		return nil, &platform.Error{Op: platform.OpMoveBucket, Err: err}
+	}
+	return s.start(t, b.ID), nil
+}
+
+// FindBucketTransferByID returns the progress of the transfer id of the bucket bucketID.
+func (s *BucketTransferService) FindBucketTransferByID(ctx context.Context, bucketID, id platform.ID) (*platform.BucketTransfer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, ok := s.transfers[id]
+	if !ok || (t.BucketID != bucketID && t.DestinationBucketID != bucketID) {
+		return nil, &platform.Error{
+			Code: platform.ENotFound,
+			Op:   platform.OpFindBucketTransferByID,
+			Msg:  platform.ErrBucketTransferNotFound,
+		}
+	}
+	cp := *t
+	return &cp, nil
+}
+
+// reserve adds and saves the transfer t as running, unless another transfer of its bucket is running.
+func (s *BucketTransferService) reserve(t *platform.BucketTransfer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.transfers {
+		if r.Status == platform.BucketTransferRunning && (r.BucketID == t.BucketID || r.DestinationBucketID == t.BucketID) {
+			return &platform.Error{
+				Code: platform.EConflict,
+				Msg:  fmt.Sprintf("bucket %s has a running transfer %s", t.BucketID, r.ID),
+			}
+		}
+	}
+	t.ID = s.idGenerator.ID()
+	t.Status = platform.BucketTransferRunning
+	t.StartedAt = time.Now().UTC()
+	if err := s.put(t); err != nil {
+		return err
+	}
+	s.transfers[t.ID] = t
+	return nil
+}
+
+// release removes the transfer t that was never started.
+func (s *BucketTransferService) release(t *platform.BucketTransfer) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.transfers, t.ID)
+	if err := s.delete(t.ID); err != nil {
+		s.Logger.Info("Failed to delete bucket transfer", zap.String("transfer_id", t.ID.String()), zap.Error(err))
+	}
+}
+
+// start copies the data of the transfer t into the bucket dstID in the background, returning a copy of t.
+func (s *BucketTransferService) start(t *platform.BucketTransfer, dstID platform.ID) *platform.BucketTransfer {
+	s.mu.Lock()
+	t.DestinationBucketID = dstID
+	if err := s.put(t); err != nil {
+		s.Logger.Info("Failed to save bucket transfer", zap.String("transfer_id", t.ID.String()), zap.Error(err))
+	}
+	cp := *t
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.run(t)
+	}()
+	return &cp
+}
+
+func (s *BucketTransferService) run(t *platform.BucketTransfer) {
+	log := s.Logger.With(
+		zap.String("transfer_id", t.ID.String()),
+		zap.String("type", string(t.Type)),
+		zap.String("bucket_id", t.BucketID.String()),
+		zap.String("destination_bucket_id", t.DestinationBucketID.String()))
+	log.Info("Starting bucket transfer")
+
+	err := s.copy(t)
+	if t.Type == platform.BucketTransferMove {
+		err = s.move(t, err)
+	}
+	if s.ctx.Err() != nil {
+		// The transfer is resumed when the service is opened again.
+		log.Info("Bucket transfer interrupted")
+		return
+	}
+	s.finish(t, err)
+	if err != nil {
+		log.Info("Bucket transfer failed", zap.Error(err))
+		return
+	}
+	log.Info("Bucket transfer completed", zap.Int64("series", t.SeriesCopied), zap.Int64("points", t.PointsCopied))
+}
+
+// copy copies the data of the transfer t, updating its progress.
+func (s *BucketTransferService) copy(t *platform.BucketTransfer) error {
+	return s.engine.CopyBucket(s.ctx, t.OrganizationID, t.BucketID, t.DestinationOrganizationID, t.DestinationBucketID, func(copied, total int, points int64) {
+		s.mu.Lock()
+		t.SeriesCopied, t.SeriesTotal, t.PointsCopied = int64(copied), int64(total), points
+		s.mu.Unlock()
+	})
+}
+
+// move completes the move transfer t once its data has been copied, or failed to be copied with err.
+// The bucket is moved into the destination organization, and its data is copied again to include
+// the points written to the bucket during the first copy, before it is removed from the previous
+// organization. If the data could not be copied, or the bucket could not be moved, the copied data
+// is removed instead, unless the bucket was moved before the transfer was interrupted.
+func (s *BucketTransferService) move(t *platform.BucketTransfer, err error) error {
+	if s.ctx.Err() != nil {
+		return err
+	}
+	b, ferr := s.buckets.FindBucketByID(s.ctx, t.BucketID)
+	if ferr != nil {
+		return ferr
+	}
+	moved := b.OrganizationID == t.DestinationOrganizationID
+	if err == nil && !moved {
+		upd := platform.BucketUpdate{OrganizationID: &t.DestinationOrganizationID}
+		if t.DestinationName != "" {
+			upd.Name = &t.DestinationName
+		}
+		_, err = s.buckets.UpdateBucket(s.ctx, t.BucketID, upd)
+		moved = err == nil
+	}
+	if err != nil {
+		if !moved && s.ctx.Err() == nil {
+			if derr := s.engine.DeleteBucket(t.DestinationOrganizationID, t.BucketID); derr != nil {
+				s.Logger.Info("Failed to remove data copied by bucket transfer", zap.String("transfer_id", t.ID.String()), zap.Error(derr))
+			}
+		}
+		return err
+	}
+
+	if err := s.copy(t); err != nil {
+		// The bucket has already been moved, so its data is kept in both organizations.
+		return fmt.Errorf("bucket moved, but its data could not be removed from the previous organization: %v", err)
+	}
+	return s.engine.DeleteBucket(t.OrganizationID, t.BucketID)
+}
+
+// finish marks the transfer t as completed, or as failed if err is not nil, and saves it.
+func (s *BucketTransferService) finish(t *platform.BucketTransfer, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UTC()
+	t.CompletedAt = &now
+	if err != nil {
+		t.Status = platform.BucketTransferFailed
+		t.Error = err.Error()
+	} else {
+		t.Status = platform.BucketTransferSucceeded
+	}
+	if err := s.put(t); err != nil {
+		s.Logger.Info("Failed to save bucket transfer", zap.String("transfer_id", t.ID.String()), zap.Error(err))
+	}
+}
+
+// put saves the transfer t. s.mu must be held.
+func (s *BucketTransferService) put(t *platform.BucketTransfer) error {
+	key, err := t.ID.Encode()
+	if err != nil {
+		return err
+	}
+	v, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return s.kv.Update(func(tx kv.Tx) error {
+		b, err := tx.Bucket(bucketTransferBucket)
+		if err != nil {
+			return err
+		}
+		return b.Put(key, v)
+	})
+}
+
+// delete removes the saved transfer id. s.mu must be held.
+func (s *BucketTransferService) delete(id platform.ID) error {
+	key, err := id.Encode()
+	if err != nil {
+		return err
+	}
+	return s.kv.Update(func(tx kv.Tx) error {
+		b, err := tx.Bucket(bucketTransferBucket)
+		if err != nil {
+			return err
+		}
+		return b.Delete(key)
+	})
+}
@@ -0,0 +1,392 @@
+package storage_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/inmem"
+	"github.com/influxdata/platform/kv"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/snowflake"
+	"github.com/influxdata/platform/storage"
+	"github.com/influxdata/platform/tsdb"
+	"github.com/influxdata/platform/tsdb/cursors"
+)
+
+// bucketLines returns the float data of a bucket as sorted lines of series keys, values and times.
+func bucketLines(t *testing.T, e *Engine, org, bucket platform.ID) []string {
+	t.Helper()
+	ctx := context.Background()
+	name := tsdb.EncodeName(org, bucket)
+	cur, err := e.CreateSeriesCursor(ctx, storage.SeriesCursorRequest{
+		Measurements: tsdb.NewMeasurementSliceIterator([][]byte{name[:]}),
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cur.Close()
+	itr, err := e.CreateCursorIterator(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	for {
+		row, err := cur.Next()
+		if err != nil {
+			t.Fatal(err)
+		} else if row == nil {
+			break
+		}
+		c, err := itr.Next(ctx, &cursors.CursorRequest{
+			Name:      row.Name,
+			Tags:      row.Tags,
+			Field:     string(row.Tags.Get(tsdb.FieldKeyTagKeyBytes)),
+			Ascending: true,
+			StartTime: models.MinNanoTime,
+			EndTime:   models.MaxNanoTime,
+		})
+		if err != nil {
+			t.Fatal(err)
+		} else if c == nil {
+			continue
+		}
+		fc := c.(cursors.FloatArrayCursor)
+		for a := fc.Next(); a.Len() > 0; a = fc.Next() {
+			for i, ts := range a.Timestamps {
+				lines = append(lines, fmt.Sprintf("%s %v %d", models.MakeKey(nil, row.Tags), a.Values[i], ts))
+			}
+		}
+		fc.Close()
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func TestEngine_CopyBucket(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	pts, err := models.ParsePointsString("cpu,host=a v=1 1\ncpu,host=a v=2 2\ncpu,host=b v=3 1\nmem,host=a u=4 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	points, err := tsdb.ExplodePoints(1, 2, pts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.WritePoints(points); err != nil {
+		t.Fatal(err)
+	}
+
+	var copied, total int
+	var n int64
+	if err := engine.CopyBucket(context.Background(), 1, 2, 3, 4, func(c, tot int, p int64) {
+		copied, total, n = c, tot, p
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if copied != 3 || total != 3 || n != 4 {
+		t.Fatalf("unexpected progress: %d/%d series, %d points", copied, total, n)
+	}
+
+	exp := strings.Join(bucketLines(t, engine, 1, 2), "\n")
+	if exp == "" {
+		t.Fatal("expected data in source bucket")
+	}
+	if got := strings.Join(bucketLines(t, engine, 3, 4), "\n"); got != exp {
+		t.Fatalf("unexpected data of copied bucket:\n%s\nexpected:\n%s", got, exp)
+	}
+
+	if err := engine.CopyBucket(context.Background(), 1, 2, 1, 2, nil); err == nil {
+		t.Fatal("expected error copying a bucket into itself")
+	}
+}
+
+func TestBucketTransferService(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	ctx := context.Background()
+	svc := inmem.NewService()
+	orgA, orgB := &platform.Organization{Name: "a"}, &platform.Organization{Name: "b"}
+	for _, o := range []*platform.Organization{orgA, orgB} {
+		if err := svc.CreateOrganization(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bucket := &platform.Bucket{OrganizationID: orgA.ID, Name: "metrics", RetentionPeriod: time.Hour}
+	if err := svc.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	pts, err := models.ParsePointsString("cpu,host=a v=1 1\ncpu,host=b v=2 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	points, err := tsdb.ExplodePoints(orgA.ID, bucket.ID, pts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.WritePoints(points); err != nil {
+		t.Fatal(err)
+	}
+	exp := strings.Join(bucketLines(t, engine, orgA.ID, bucket.ID), "\n")
+
+	s := storage.NewBucketTransferService(engine, svc, snowflake.NewIDGenerator(), inmem.NewKVStore())
+	if err := s.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	wait := func(tr *platform.BucketTransfer) *platform.BucketTransfer {
+		t.Helper()
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			got, err := s.FindBucketTransferByID(ctx, tr.BucketID, tr.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Status != platform.BucketTransferRunning {
+				return got
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatal("timed out waiting for transfer")
+		return nil
+	}
+
+	// Cloning into another organization creates a bucket with the same settings and data.
+	tr, err := s.CloneBucket(ctx, bucket.ID, platform.BucketClone{OrganizationID: orgB.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr = wait(tr); tr.Status != platform.BucketTransferSucceeded || tr.SeriesCopied != 2 || tr.SeriesTotal != 2 {
+		t.Fatalf("unexpected clone transfer: %+v", tr)
+	}
+	clone, err := svc.FindBucketByID(ctx, tr.DestinationBucketID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clone.OrganizationID != orgB.ID || clone.Name != bucket.Name || clone.RetentionPeriod != time.Hour {
+		t.Fatalf("unexpected clone: %+v", clone)
+	}
+	if got := strings.Join(bucketLines(t, engine, orgB.ID, clone.ID), "\n"); got != exp {
+		t.Fatalf("unexpected data of clone:\n%s\nexpected:\n%s", got, exp)
+	}
+
+	// Moving into an organization with a bucket of the same name requires renaming the bucket.
+	if _, err := s.MoveBucket(ctx, bucket.ID, platform.BucketMove{OrganizationID: orgB.ID}); platform.ErrorCode(err) != platform.EConflict {
+		t.Fatalf("expected conflict moving bucket, got %v", err)
+	}
+	tr, err = s.MoveBucket(ctx, bucket.ID, platform.BucketMove{OrganizationID: orgB.ID, Name: "moved"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr = wait(tr); tr.Status != platform.BucketTransferSucceeded {
+		t.Fatalf("unexpected move transfer: %+v", tr)
+	}
+	moved, err := svc.FindBucketByID(ctx, bucket.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if moved.OrganizationID != orgB.ID || moved.Name != "moved" {
+		t.Fatalf("unexpected moved bucket: %+v", moved)
+	}
+	if got := strings.Join(bucketLines(t, engine, orgB.ID, bucket.ID), "\n"); got != exp {
+		t.Fatalf("unexpected data of moved bucket:\n%s\nexpected:\n%s", got, exp)
+	}
+	if got := bucketLines(t, engine, orgA.ID, bucket.ID); len(got) != 0 {
+		t.Fatalf("expected data to be removed from previous organization, got %v", got)
+	}
+
+	if _, err := s.FindBucketTransferByID(ctx, orgA.ID, tr.ID); platform.ErrorCode(err) != platform.ENotFound {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+}
+
+func TestBucketTransferService_Resume(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	ctx := context.Background()
+	svc := inmem.NewService()
+	org := &platform.Organization{Name: "a"}
+	if err := svc.CreateOrganization(ctx, org); err != nil {
+		t.Fatal(err)
+	}
+	src, dst := &platform.Bucket{OrganizationID: org.ID, Name: "src"}, &platform.Bucket{OrganizationID: org.ID, Name: "dst"}
+	for _, b := range []*platform.Bucket{src, dst} {
+		if err := svc.CreateBucket(ctx, b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pts, err := models.ParsePointsString("cpu,host=a v=1 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	points, err := tsdb.ExplodePoints(org.ID, src.ID, pts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.WritePoints(points); err != nil {
+		t.Fatal(err)
+	}
+
+	// Save a running clone, and a clone interrupted before its bucket was created.
+	store := inmem.NewKVStore()
+	for _, tr := range []platform.BucketTransfer{
+		{ID: 1, Type: platform.BucketTransferClone, Status: platform.BucketTransferRunning, BucketID: src.ID, OrganizationID: org.ID, DestinationBucketID: dst.ID, DestinationOrganizationID: org.ID},
+		{ID: 2, Type: platform.BucketTransferClone, Status: platform.BucketTransferRunning, BucketID: src.ID, OrganizationID: org.ID, DestinationOrganizationID: org.ID},
+	} {
+		key, err := tr.ID.Encode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := json.Marshal(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Update(func(tx kv.Tx) error {
+			b, err := tx.Bucket([]byte("buckettransfersv1"))
+			if err != nil {
+				return err
+			}
+			return b.Put(key, v)
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := storage.NewBucketTransferService(engine, svc, snowflake.NewIDGenerator(), store)
+	if err := s.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		tr, err := s.FindBucketTransferByID(ctx, src.ID, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tr.Status == platform.BucketTransferSucceeded {
+			break
+		} else if tr.Status != platform.BucketTransferRunning || time.Now().After(deadline) {
+			t.Fatalf("unexpected resumed transfer: %+v", tr)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got, exp := bucketLines(t, engine, org.ID, dst.ID), bucketLines(t, engine, org.ID, src.ID); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected data of clone: %v != %v", got, exp)
+	}
+
+	if tr, err := s.FindBucketTransferByID(ctx, src.ID, 2); err != nil {
+		t.Fatal(err)
+	} else if tr.Status != platform.BucketTransferFailed {
+		t.Fatalf("expected interrupted transfer to fail: %+v", tr)
+	}
+}
+
+// failingCopier copies the data of buckets with an Engine, but fails once the data has been copied.
+type failingCopier struct {
+	*Engine
+	copied func()
+}
+
+func (c *failingCopier) CopyBucket(ctx context.Context, srcOrgID, srcBucketID, dstOrgID, dstBucketID platform.ID, progress func(copied, total int, points int64)) error {
+	if err := c.Engine.CopyBucket(ctx, srcOrgID, srcBucketID, dstOrgID, dstBucketID, progress); err != nil {
+		return err
+	}
+	c.copied()
+	return fmt.Errorf("copy failed")
+}
+
+func TestBucketTransferService_MoveFailed(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	ctx := context.Background()
+	svc := inmem.NewService()
+	orgA, orgB := &platform.Organization{Name: "a"}, &platform.Organization{Name: "b"}
+	for _, o := range []*platform.Organization{orgA, orgB} {
+		if err := svc.CreateOrganization(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bucket := &platform.Bucket{OrganizationID: orgA.ID, Name: "metrics"}
+	if err := svc.CreateBucket(ctx, bucket); err != nil {
+		t.Fatal(err)
+	}
+
+	pts, err := models.ParsePointsString("cpu,host=a v=1 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	points, err := tsdb.ExplodePoints(orgA.ID, bucket.ID, pts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.WritePoints(points); err != nil {
+		t.Fatal(err)
+	}
+	exp := bucketLines(t, engine, orgA.ID, bucket.ID)
+
+	// The bucket is not moved while its data is being copied.
+	copier := &failingCopier{Engine: engine, copied: func() {
+		b, err := svc.FindBucketByID(ctx, bucket.ID)
+		if err != nil {
+			t.Error(err)
+		} else if b.OrganizationID != orgA.ID {
+			t.Errorf("expected bucket to stay in its organization while its data is copied, got %s", b.OrganizationID)
+		}
+	}}
+	s := storage.NewBucketTransferService(copier, svc, snowflake.NewIDGenerator(), inmem.NewKVStore())
+	if err := s.Open(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	tr, err := s.MoveBucket(ctx, bucket.ID, platform.BucketMove{OrganizationID: orgB.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for tr.Status == platform.BucketTransferRunning {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for transfer")
+		}
+		time.Sleep(10 * time.Millisecond)
+		if tr, err = s.FindBucketTransferByID(ctx, bucket.ID, tr.ID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if tr.Status != platform.BucketTransferFailed {
+		t.Fatalf("expected move to fail: %+v", tr)
+	}
+
+	// The bucket and its data stay in the organization, and the copied data is removed.
+	b, err := svc.FindBucketByID(ctx, bucket.ID)
+	if err != nil {
+		t.Fatal(err)
+	} else if b.OrganizationID != orgA.ID {
+		t.Fatalf("expected failed move to keep the bucket in its organization, got %s", b.OrganizationID)
+	}
+	if got := bucketLines(t, engine, orgA.ID, bucket.ID); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected data of bucket: %v != %v", got, exp)
+	}
+	if got := bucketLines(t, engine, orgB.ID, bucket.ID); len(got) != 0 {
+		t.Fatalf("expected copied data to be removed, got %v", got)
+	}
+}
@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/tsdb"
+)
+
+// seriesLimited returns true if the engine limits the number of series in a bucket or organization.
+func (e *Engine) seriesLimited() bool {
+	return e.config.MaxSeriesPerBucket > 0 || e.config.MaxSeriesPerOrg > 0
+}
+
+// createSeries adds the series of collection to the series file and index, after dropping
+// any new series that would take a bucket or organization over its series limit.
+// e.mu must be held for reading.
+func (e *Engine) createSeries(collection *tsdb.SeriesCollection) error {
+	if e.seriesLimited() && e.hasNewSeries(collection) {
+		// Creating new series one write at a time means concurrent writes cannot together exceed a limit.
+		e.seriesLimitMu.Lock()
+		defer e.seriesLimitMu.Unlock()
+
+		if err := e.limitSeries(collection); err != nil {
+			return err
+		}
+	}
+
+	if err := e.index.CreateSeriesListIfNotExists(collection); err != nil {
+		// ignore PartialWriteErrors. The collection captures it.
+		// TODO(edd/jeff): should we just remove PartialWriteError from the index then?
+		if _, ok := err.(tsdb.PartialWriteError); !ok {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasNewSeries returns true if any series of collection is not in the index.
+func (e *Engine) hasNewSeries(collection *tsdb.SeriesCollection) bool {
+	var buf []byte
+	for iter := collection.Iterator(); iter.Next(); {
+		if !e.seriesExists(iter.Key(), iter.Name(), iter.Tags(), buf) {
+			return true
+		}
+	}
+	return false
+}
+
+// seriesExists returns true if the series is in the index. The series file keeps the ids
+// of series deleted from the index, so it cannot tell on its own.
+func (e *Engine) seriesExists(key, name []byte, tags models.Tags, buf []byte) bool {
+	id := e.sfile.SeriesID(name, tags, buf)
+	return !id.IsZero() && e.index.HasSeries(id, key)
+}
+
+// limitSeries drops the points of series that are not yet in the index if their bucket or organization
+// is at its series limit. Points of existing series are always kept.
+// e.mu must be held for reading and e.seriesLimitMu must be held.
+func (e *Engine) limitSeries(collection *tsdb.SeriesCollection) error {
+	var buf []byte
+	created := make(map[string]bool)
+	j := 0
+	for iter := collection.Iterator(); iter.Next(); {
+		if created[string(iter.Key())] || e.seriesExists(iter.Key(), iter.Name(), iter.Tags(), buf) {
+			collection.Copy(j, iter.Index())
+			j++
+			continue
+		}
+
+		name := string(iter.Name())
+		org, bucket, _ := decodeName(name)
+		bucketN, orgN, err := e.seriesCounts(name, org)
+		if err != nil {
+			return err
+		}
+
+		if reason := e.seriesLimitReason(org, bucket, bucketN, orgN); reason != "" {
+			collection.Drop(iter.Key(), reason)
+			continue
+		}
+
+		e.bucketSeriesN[name]++
+		if e.orgSeriesN != nil {
+			e.orgSeriesN[org]++
+		}
+		created[string(iter.Key())] = true
+		collection.Copy(j, iter.Index())
+		j++
+	}
+	collection.Truncate(j)
+	return nil
+}
+
+// seriesCounts returns the number of series of the bucket with the measurement name, and of its
+// organization org if organizations are limited, counting them from the index if they are not known.
+// e.mu must be held for reading and e.seriesLimitMu must be held.
+func (e *Engine) seriesCounts(name string, org platform.ID) (bucketN, orgN int, err error) {
+	if e.bucketSeriesN == nil {
+		e.bucketSeriesN = make(map[string]int)
+	}
+	bucketN, ok := e.bucketSeriesN[name]
+	if !ok {
+		if bucketN, err = e.measurementSeriesN([]byte(name)); err != nil {
+			return 0, 0, err
+		}
+		e.bucketSeriesN[name] = bucketN
+	}
+
+	if e.config.MaxSeriesPerOrg > 0 {
+		if e.orgSeriesN == nil {
+			// The index keeps a count of series for each measurement, which is an org and bucket pair.
+			e.orgSeriesN = make(map[platform.ID]int)
+			for name, n := range e.index.MeasurementCardinalityStats() {
+				if org, _, ok := decodeName(name); ok {
+					e.orgSeriesN[org] += n
+				}
+			}
+		}
+		orgN = e.orgSeriesN[org]
+	}
+	return bucketN, orgN, nil
+}
+
+// resetSeriesCounts discards the counts of series after series have been deleted, so that they
+// are counted again from the index when next needed.
+func (e *Engine) resetSeriesCounts() {
+	e.seriesLimitMu.Lock()
+	e.bucketSeriesN, e.orgSeriesN = nil, nil
+	e.seriesLimitMu.Unlock()
+}
+
+// seriesLimitReason returns why a new series cannot be added to a bucket that has bucketN series
+// in an organization that has orgN series, or the empty string if it can.
+func (e *Engine) seriesLimitReason(org, bucket platform.ID, bucketN, orgN int) string {
+	if max := e.config.MaxSeriesPerBucket; max > 0 && bucketN >= max {
+		return fmt.Sprintf("max series per bucket exceeded: bucket %s has %d series; the limit is %d", bucket, bucketN, max)
+	}
+	if max := e.config.MaxSeriesPerOrg; max > 0 && orgN >= max {
+		return fmt.Sprintf("max series per organization exceeded: organization %s has %d series; the limit is %d", org, orgN, max)
+	}
+	return ""
+}
+
+// decodeName returns the organization and bucket of a measurement name in the index.
+// ok is false if name is not an encoded organization and bucket pair.
+func decodeName(name string) (org, bucket platform.ID, ok bool) {
+	var encoded [16]byte
+	if len(name) != len(encoded) {
+		return 0, 0, false
+	}
+	copy(encoded[:], name)
+	org, bucket = tsdb.DecodeName(encoded)
+	return org, bucket, true
+}
+
+var _ platform.UsageService = (*Engine)(nil)
+
+// GetUsage returns the current series cardinality of the bucket or organization selected by filter,
+// or of the entire engine if neither is selected. Cardinality is not recorded over time, so the range
+// of the filter is ignored.
+func (e *Engine) GetUsage(ctx context.Context, filter platform.UsageFilter) (map[platform.UsageMetric]*platform.Usage, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil, ErrEngineClosed
+	}
+
+	var n int64
+	if filter.OrgID == nil && filter.BucketID == nil {
+		n = e.index.SeriesN()
+	} else {
+		for name, c := range e.index.MeasurementCardinalityStats() {
+			org, bucket, ok := decodeName(name)
+			if !ok {
+				continue
+			}
+			if filter.OrgID != nil && org != *filter.OrgID {
+				continue
+			}
+			if filter.BucketID != nil && bucket != *filter.BucketID {
+				continue
+			}
+			n += int64(c)
+		}
+	}
+
+	return map[platform.UsageMetric]*platform.Usage{
+		platform.UsageSeries: {
+			OrganizationID: filter.OrgID,
+			BucketID:       filter.BucketID,
+			Type:           platform.UsageSeries,
+			Value:          float64(n),
+		},
+	}, nil
+}
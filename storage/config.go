@@ -10,9 +10,10 @@ import (
 )
 
 const (
-	DefaultRetentionInterval   = 1 * time.Hour
-	DefaultValidateKeys        = false
-	DefaultTraceLoggingEnabled = false
+	DefaultRetentionInterval    = 1 * time.Hour
+	DefaultValidateKeys         = false
+	DefaultTraceLoggingEnabled  = false
+	DefaultColdStorageCacheSize = 256 * 1024 * 1024 // 256MB
 
 	DefaultSeriesFileDirectoryName = "_series"
 	DefaultIndexDirectoryName      = "index"
@@ -31,6 +32,11 @@ type Config struct {
 	// Enables trace logging for the engine.
 	TraceLoggingEnabled bool `toml:"trace-logging-enabled"`
 
+	// Maximum number of series in a bucket or organization; 0 is unlimited.
+	// Writes that would create new series beyond a limit drop those series.
+	MaxSeriesPerBucket int `toml:"max-series-per-bucket"`
+	MaxSeriesPerOrg    int `toml:"max-series-per-org"`
+
 	// Series file config.
 	SeriesFilePath string `toml:"series-file-path"` // Overrides the default path.
 
@@ -45,6 +51,12 @@ type Config struct {
 	// Index config.
 	Index     tsi1.Config `toml:"index"`
 	IndexPath string      `toml:"index-path"` // Overrides the default path.
+
+	// Cold storage config. TSM files of data older than the cold storage threshold of
+	// their bucket are offloaded to the directory ColdStoragePath, if it is set.
+	// Up to ColdStorageCacheSize bytes of the blocks read back are cached in memory.
+	ColdStoragePath      string    `toml:"cold-storage-path"`
+	ColdStorageCacheSize toml.Size `toml:"cold-storage-cache-size"`
 }
 
 // NewConfig initialises a new config for an Engine.
@@ -54,6 +66,8 @@ func NewConfig() Config {
 		ValidateKeys:        DefaultValidateKeys,
 		TraceLoggingEnabled: DefaultTraceLoggingEnabled,
 
+		ColdStorageCacheSize: toml.Size(DefaultColdStorageCacheSize),
+
 		WAL:    tsm1.NewWALConfig(),
 		Engine: tsm1.NewConfig(),
 		Index:  tsi1.NewConfig(),
@@ -91,3 +105,11 @@ func (c Config) GetEnginePath(base string) string {
 	}
 	return filepath.Join(base, DefaultEngineDirectoryName)
 }
+
+// coldObjectStore returns the object store of the cold TSM files, or nil if there is no cold storage.
+func (c Config) coldObjectStore() tsm1.ObjectStore {
+	if c.ColdStoragePath == "" {
+		return nil
+	}
+	return tsm1.NewDirObjectStore(c.ColdStoragePath)
+}
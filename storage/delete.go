@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"github.com/influxdata/influxql"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/tsdb"
+)
+
+// A PredicateDeleter deletes the data of a bucket within a time range that matches a predicate.
+type PredicateDeleter interface {
+	DeleteBucketRangePredicate(orgID, bucketID platform.ID, min, max int64, pred influxql.Expr) error
+}
+
+var _ PredicateDeleter = (*Engine)(nil)
+
+// DeleteBucketRangePredicate deletes the data between min and max, inclusive, of every series
+// in a bucket that matches pred. pred may only compare tags, including the measurement and field
+// tags, using equality and regular expression operators. A nil pred matches every series in the bucket.
+//
+// Series with no data remaining are removed from the index.
+func (e *Engine) DeleteBucketRangePredicate(orgID, bucketID platform.ID, min, max int64, pred influxql.Expr) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if err := e.writable(); err != nil {
+		return err
+	}
+	defer e.resetSeriesCounts()
+
+	encoded := tsdb.EncodeName(orgID, bucketID)
+	if pred == nil {
+		return e.engine.DeletePrefix(models.EscapeMeasurement(encoded[:]), min, max)
+	}
+
+	cur, err := newSeriesCursor(SeriesCursorRequest{
+		Measurements: tsdb.NewMeasurementSliceIterator([][]byte{encoded[:]}),
+	}, e.index, pred)
+	if err != nil {
+		return err
+	}
+	defer cur.Close()
+
+	return e.engine.DeleteSeriesRangeWithPredicate(newSeriesIteratorAdapter(cur), func([]byte, models.Tags) (int64, int64, bool) {
+		return min, max, true
+	})
+}
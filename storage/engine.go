@@ -35,11 +35,26 @@ type Engine struct {
 
 	mu                sync.RWMutex
 	closing           chan struct{} //closing returns the zero value when the engine is shutting down.
+	mode              Mode
 	index             *tsi1.Index
 	sfile             *tsdb.SeriesFile
 	engine            *tsm1.Engine
 	wal               *tsm1.WAL
 	retentionEnforcer *retentionEnforcer
+	shardGroups       *shardGroups
+
+	// Serializes the creation of new series while series limits are enforced, and guards the
+	// numbers of series of buckets, by measurement name, and of organizations, which are
+	// counted from the index when first needed.
+	seriesLimitMu sync.Mutex
+	bucketSeriesN map[string]int
+	orgSeriesN    map[platform.ID]int
+
+	// Retention periods of internal buckets that are not known to the retention enforcer's BucketFinder.
+	systemBucketRetention map[platform.ID]time.Duration
+
+	// Finds the measurement schemas of buckets; nil if schemas are not enforced.
+	schemaFinder BucketFinder
 
 	defaultMetricLabels prometheus.Labels
 
@@ -78,12 +93,37 @@ func WithNodeID(id int) Option {
 	}
 }
 
-// WithRetentionEnforcer initialises a retention enforcer on the engine.
+// WithRetentionEnforcer initialises a retention enforcer on the engine, and partitions
+// the TSM files of each bucket by the shard group duration of the bucket, so that the
+// enforcer can delete whole files of expired data.
 // WithRetentionEnforcer must be called after other options to ensure that all
 // metrics are labelled correctly.
 func WithRetentionEnforcer(finder BucketFinder) Option {
 	return func(e *Engine) {
 		e.retentionEnforcer = newRetentionEnforcer(e, finder)
+		e.shardGroups = newShardGroups(finder)
+		tsm1.WithPartitionFunc(e.shardGroups.Duration)(e.engine)
+	}
+}
+
+// WithBucketSchemas makes the engine drop the points of every write that do not match
+// the explicit measurement schema of their bucket, as found through finder.
+func WithBucketSchemas(finder BucketFinder) Option {
+	return func(e *Engine) {
+		e.schemaFinder = finder
+	}
+}
+
+// WithSystemBucketRetention sets the retention period for data in the internal
+// bucket id of every organization. Internal buckets, such as the task logs bucket,
+// are not managed by the BucketFinder given to WithRetentionEnforcer, so their
+// data is kept forever unless a retention period is set here.
+func WithSystemBucketRetention(id platform.ID, d time.Duration) Option {
+	return func(e *Engine) {
+		if e.systemBucketRetention == nil {
+			e.systemBucketRetention = make(map[platform.ID]time.Duration)
+		}
+		e.systemBucketRetention[id] = d
 	}
 }
 
@@ -107,6 +147,7 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 	e := &Engine{
 		config:              c,
 		path:                path,
+		mode:                ModeReadWrite,
 		defaultMetricLabels: prometheus.Labels{},
 		logger:              zap.NewNop(),
 	}
@@ -128,9 +169,14 @@ func NewEngine(path string, c Config, options ...Option) *Engine {
 	}
 
 	// Initialise Engine
-	e.engine = tsm1.NewEngine(c.GetEnginePath(path), e.index, c.Engine,
+	engineOptions := []tsm1.EngineOption{
 		tsm1.WithWAL(wal),
-		tsm1.WithTraceLogging(c.TraceLoggingEnabled))
+		tsm1.WithTraceLogging(c.TraceLoggingEnabled),
+	}
+	if store := c.coldObjectStore(); store != nil {
+		engineOptions = append(engineOptions, tsm1.WithObjectStore(store, int64(c.ColdStorageCacheSize)))
+	}
+	e.engine = tsm1.NewEngine(c.GetEnginePath(path), e.index, c.Engine, engineOptions...)
 
 	// Apply options.
 	for _, option := range options {
@@ -192,10 +238,15 @@ func (e *Engine) Open() error {
 		return err
 	}
 
+	if e.shardGroups != nil {
+		e.shardGroups.system = e.systemBucketRetention
+	}
+	// Compactions are enabled once the engine is open, and only in read-write mode.
+	e.engine.SetEnabled(false)
 	if err := e.engine.Open(); err != nil {
 		return err
 	}
-	e.engine.SetCompactionsEnabled(true) // TODO(edd):is this needed?
+	e.engine.SetEnabled(e.mode == ModeReadWrite)
 
 	e.closing = make(chan struct{})
 
@@ -226,6 +277,7 @@ func (e *Engine) runRetentionEnforcer() {
 	if e.retentionEnforcer != nil {
 		// Set default metric labels on retention enforcer.
 		e.retentionEnforcer.metrics = newRetentionMetrics(e.defaultMetricLabels)
+		e.retentionEnforcer.SystemBuckets = e.systemBucketRetention
 	}
 
 	l := e.logger.With(zap.String("component", "retention_enforcer"), logger.DurationLiteral("check_interval", interval))
@@ -243,6 +295,10 @@ func (e *Engine) runRetentionEnforcer() {
 				l.Info("Stopping")
 				return
 			case <-ticker.C:
+				// Retention is not enforced while the engine does not accept deletes.
+				if e.Mode() != ModeReadWrite {
+					continue
+				}
 				e.retentionEnforcer.run()
 			}
 		}
@@ -281,8 +337,8 @@ func (e *Engine) Close() error {
 func (e *Engine) CreateSeriesCursor(ctx context.Context, req SeriesCursorRequest, cond influxql.Expr) (SeriesCursor, error) {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
-	if e.closing == nil {
-		return nil, ErrEngineClosed
+	if err := e.readable(); err != nil {
+		return nil, err
 	}
 	return newSeriesCursor(req, e.index, cond)
 }
@@ -290,8 +346,8 @@ func (e *Engine) CreateSeriesCursor(ctx context.Context, req SeriesCursorRequest
 func (e *Engine) CreateCursorIterator(ctx context.Context) (tsdb.CursorIterator, error) {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
-	if e.closing == nil {
-		return nil, ErrEngineClosed
+	if err := e.readable(); err != nil {
+		return nil, err
 	}
 	return e.engine.CreateCursorIterator(ctx)
 }
@@ -300,41 +356,47 @@ func (e *Engine) CreateCursorIterator(ctx context.Context) (tsdb.CursorIterator,
 //
 // The Engine expects all points to have been correctly validated by the caller.
 // WritePoints will however determine if there are any field type conflicts, and
-// return an appropriate error in that case.
+// return an appropriate error in that case. Points that do not match the schema
+// of their bucket are dropped and reported in a tsdb.PartialWriteError.
 func (e *Engine) WritePoints(points []models.Point) error {
 	collection := tsdb.NewSeriesCollection(points)
 
+	// The schemas of the buckets written to, by measurement name.
+	schemas := make(map[string]*SchemaValidator)
+
 	j := 0
 	for iter := collection.Iterator(); iter.Next(); {
 		tags := iter.Tags()
 
 		if tags.Len() > 0 && bytes.Equal(tags[0].Key, tsdb.FieldKeyTagKeyBytes) && bytes.Equal(tags[0].Value, timeBytes) {
 			// Field key "time" is invalid
-			if collection.Reason == "" {
-				collection.Reason = fmt.Sprintf("invalid field key: input field %q is invalid", timeBytes)
-			}
-			collection.Dropped++
-			collection.DroppedKeys = append(collection.DroppedKeys, iter.Key())
+			collection.Drop(iter.Key(), fmt.Sprintf("invalid field key: input field %q is invalid", timeBytes))
 			continue
 		}
 
 		// Filter out any tags with key equal to "time": they are invalid.
 		if tags.Get(timeBytes) != nil {
-			if collection.Reason == "" {
-				collection.Reason = fmt.Sprintf("invalid tag key: input tag %q on measurement %q is invalid", timeBytes, iter.Name())
-			}
-			collection.Dropped++
-			collection.DroppedKeys = append(collection.DroppedKeys, iter.Key())
+			collection.Drop(iter.Key(), fmt.Sprintf("invalid tag key: input tag %q on measurement %q is invalid", timeBytes, iter.Name()))
 			continue
 		}
 
 		// Drop any series with invalid unicode characters in the key.
 		if e.config.ValidateKeys && !models.ValidKeyTokens(string(iter.Name()), tags) {
-			if collection.Reason == "" {
-				collection.Reason = fmt.Sprintf("key contains invalid unicode: %q", iter.Key())
+			collection.Drop(iter.Key(), fmt.Sprintf("key contains invalid unicode: %q", iter.Key()))
+			continue
+		}
+
+		// Drop any point that does not match the schema of its bucket.
+		schema, ok := schemas[string(iter.Name())]
+		if !ok {
+			var err error
+			if schema, err = e.bucketSchema(iter.Name()); err != nil {
+				return err
 			}
-			collection.Dropped++
-			collection.DroppedKeys = append(collection.DroppedKeys, iter.Key())
+			schemas[string(iter.Name())] = schema
+		}
+		if err := schema.validateSeries(tags, iter.Type()); err != nil {
+			collection.Drop(iter.Key(), err.Error())
 			continue
 		}
 
@@ -346,17 +408,13 @@ func (e *Engine) WritePoints(points []models.Point) error {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
-	if e.closing == nil {
-		return ErrEngineClosed
+	if err := e.writable(); err != nil {
+		return err
 	}
 
 	// Add new series to the index and series file. Check for partial writes.
-	if err := e.index.CreateSeriesListIfNotExists(collection); err != nil {
-		// ignore PartialWriteErrors. The collection captures it.
-		// TODO(edd/jeff): should we just remove PartialWriteError from the index then?
-		if _, ok := err.(tsdb.PartialWriteError); !ok {
-			return err
-		}
+	if err := e.createSeries(collection); err != nil {
+		return err
 	}
 
 	// Write the points to the cache and WAL.
@@ -370,8 +428,8 @@ func (e *Engine) WritePoints(points []models.Point) error {
 func (e *Engine) DeleteBucket(orgID, bucketID platform.ID) error {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
-	if e.closing == nil {
-		return ErrEngineClosed
+	if err := e.writable(); err != nil {
+		return err
 	}
 
 	// TODO(edd): we need to clean up how we're encoding the prefix so that we
@@ -379,6 +437,7 @@ func (e *Engine) DeleteBucket(orgID, bucketID platform.ID) error {
 	encoded := tsdb.EncodeName(orgID, bucketID)
 	prefix := models.EscapeMeasurement(encoded[:])
 
+	defer e.resetSeriesCounts()
 	return e.engine.DeletePrefix(prefix, math.MinInt64, math.MaxInt64)
 }
 
@@ -387,12 +446,50 @@ func (e *Engine) DeleteBucket(orgID, bucketID platform.ID) error {
 func (e *Engine) DeleteSeriesRangeWithPredicate(itr tsdb.SeriesIterator, fn func([]byte, models.Tags) (int64, int64, bool)) error {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
-	if e.closing == nil {
-		return ErrEngineClosed
+	if err := e.writable(); err != nil {
+		return err
 	}
+	defer e.resetSeriesCounts()
 	return e.engine.DeleteSeriesRangeWithPredicate(itr, fn)
 }
 
+// DeleteExpiredFiles removes the TSM files holding only data of a single bucket for which
+// expired returns true, given the encoded name of the bucket and the maximum time of the data in the file.
+func (e *Engine) DeleteExpiredFiles(expired func(name []byte, maxTime int64) bool) (int, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if err := e.writable(); err != nil {
+		return 0, err
+	}
+	defer e.resetSeriesCounts()
+	return e.engine.DeleteExpiredFiles(expired)
+}
+
+// UnpartitionedNameRanges returns the first and last encoded bucket names of each TSM file that
+// holds the data of more than one bucket or shard group, such as files written before the data was
+// partitioned. Only the data of these files needs to be expired series by series.
+func (e *Engine) UnpartitionedNameRanges() [][2][]byte {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closing == nil {
+		return nil
+	}
+	return e.engine.UnpartitionedNameRanges()
+}
+
+// OffloadColdFiles offloads the fully compacted TSM files holding only data of a single bucket
+// for which cold returns true, given the encoded name of the bucket and the maximum time of the
+// data in the file, to the cold storage of the engine. Nothing is offloaded if the engine has
+// no cold storage.
+func (e *Engine) OffloadColdFiles(cold func(name []byte, maxTime int64) bool) (int, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if err := e.writable(); err != nil {
+		return 0, err
+	}
+	return e.engine.OffloadColdFiles(cold)
+}
+
 // SeriesCardinality returns the number of series in the engine.
 func (e *Engine) SeriesCardinality() int64 {
 	e.mu.RLock()
@@ -1,12 +1,18 @@
 package storage_test
 
 import (
+	"bytes"
+	"context"
 	"io/ioutil"
+	"math"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/influxdata/influxql"
 	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/mock"
 	"github.com/influxdata/platform/models"
 	"github.com/influxdata/platform/storage"
 	"github.com/influxdata/platform/tsdb"
@@ -149,6 +155,270 @@ func TestEngine_WriteAddNewField(t *testing.T) {
 	}
 }
 
+func TestEngine_WriteFieldTypeConflict(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	pt := models.MustNewPoint(
+		"cpu",
+		models.NewTags(map[string]string{"host": "a"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 0),
+	)
+	if err := engine.Write1xPoints([]models.Point{pt}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The conflicting point is dropped, but the other point is still written.
+	conflict := models.MustNewPoint(
+		"cpu",
+		models.NewTags(map[string]string{"host": "a"}),
+		map[string]interface{}{"value": "one"},
+		time.Unix(2, 0),
+	)
+	ok := models.MustNewPoint(
+		"cpu",
+		models.NewTags(map[string]string{"host": "b"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(2, 0),
+	)
+	err := engine.Write1xPoints([]models.Point{conflict, ok})
+	pwe, isPartial := err.(tsdb.PartialWriteError)
+	if !isPartial {
+		t.Fatalf("expected partial write error, got %v", err)
+	}
+	if pwe.Dropped != 1 {
+		t.Fatalf("unexpected partial write error: %v", pwe)
+	}
+	if !bytes.Contains(pwe.DroppedKeys[0], []byte("host=a")) {
+		t.Fatalf("expected conflicting series to be dropped, got %q", pwe.DroppedKeys[0])
+	}
+	if got := pwe.DroppedReasons[string(pwe.DroppedKeys[0])]; got != pwe.Reason {
+		t.Fatalf("unexpected reason for dropped series: %q", got)
+	}
+
+	if got, exp := engine.SeriesCardinality(), int64(2); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+}
+
+func TestEngine_SeriesLimits(t *testing.T) {
+	c := storage.NewConfig()
+	c.MaxSeriesPerBucket = 3
+	c.MaxSeriesPerOrg = 4
+	engine := NewEngine(c)
+	defer engine.Close()
+	engine.MustOpen()
+
+	org := platform.ID(1)
+	bucketA, bucketB := platform.ID(2), platform.ID(3)
+	write := func(bucket platform.ID, lines string) error {
+		pts, err := models.ParsePointsString(lines)
+		if err != nil {
+			t.Fatal(err)
+		}
+		points, err := tsdb.ExplodePoints(org, bucket, pts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return engine.WritePoints(points)
+	}
+	usage := func(filter platform.UsageFilter) float64 {
+		u, err := engine.GetUsage(context.Background(), filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u[platform.UsageSeries].Value
+	}
+
+	// Both points of the fourth series of the bucket are dropped.
+	err := write(bucketA, "cpu,host=a v=1 1\ncpu,host=b v=1 1\ncpu,host=c v=1 1\ncpu,host=d v=1 1\ncpu,host=d v=2 2")
+	pwe, ok := err.(tsdb.PartialWriteError)
+	if !ok {
+		t.Fatalf("expected partial write error, got %v", err)
+	}
+	if pwe.Dropped != 1 || !bytes.Contains(pwe.DroppedKeys[0], []byte("host=d")) || !strings.HasPrefix(pwe.Reason, "max series per bucket exceeded") {
+		t.Fatalf("unexpected partial write error: %v", pwe)
+	}
+
+	// Existing series may still be written.
+	if err := write(bucketA, "cpu,host=a v=2 2\ncpu,host=c v=2 2"); err != nil {
+		t.Fatalf("unexpected error writing existing series: %v", err)
+	}
+
+	// The organization limit spans buckets.
+	err = write(bucketB, "cpu,host=a v=1 1\ncpu,host=b v=1 1")
+	pwe, ok = err.(tsdb.PartialWriteError)
+	if !ok {
+		t.Fatalf("expected partial write error, got %v", err)
+	}
+	if pwe.Dropped != 1 || !strings.HasPrefix(pwe.Reason, "max series per organization exceeded") {
+		t.Fatalf("unexpected partial write error: %v", pwe)
+	}
+
+	if got := usage(platform.UsageFilter{}); got != 4 {
+		t.Fatalf("got %v series in engine, exp 4", got)
+	}
+	if got := usage(platform.UsageFilter{OrgID: &org}); got != 4 {
+		t.Fatalf("got %v series in org, exp 4", got)
+	}
+	if got := usage(platform.UsageFilter{OrgID: &org, BucketID: &bucketA}); got != 3 {
+		t.Fatalf("got %v series in bucket, exp 3", got)
+	}
+	if got := usage(platform.UsageFilter{BucketID: &bucketB}); got != 1 {
+		t.Fatalf("got %v series in bucket, exp 1", got)
+	}
+
+	// Deleting series makes room for new series, but a deleted series counts as new.
+	if err := engine.DeleteBucket(org, bucketB); err != nil {
+		t.Fatal(err)
+	}
+	if err := write(bucketB, "cpu,host=b v=2 2"); err != nil {
+		t.Fatalf("unexpected error writing series after delete: %v", err)
+	}
+	err = write(bucketB, "cpu,host=a v=2 2")
+	if pwe, ok := err.(tsdb.PartialWriteError); !ok || !strings.HasPrefix(pwe.Reason, "max series per organization exceeded") {
+		t.Fatalf("expected deleted series to be limited, got %v", err)
+	}
+	if got := usage(platform.UsageFilter{OrgID: &org}); got != 4 {
+		t.Fatalf("got %v series in org, exp 4", got)
+	}
+}
+
+func TestEngine_BucketSchemas(t *testing.T) {
+	org := platform.ID(1)
+	explicit, implicit := platform.ID(2), platform.ID(3)
+	finder := &mock.BucketService{
+		FindBucketsFn: func(_ context.Context, filter platform.BucketFilter, _ ...platform.FindOptions) ([]*platform.Bucket, int, error) {
+			switch *filter.ID {
+			case explicit:
+				return []*platform.Bucket{{
+					ID:             explicit,
+					OrganizationID: org,
+					SchemaType:     platform.SchemaTypeExplicit,
+					MeasurementSchemas: []platform.MeasurementSchema{{
+						Name:   "cpu",
+						Tags:   []string{"host"},
+						Fields: []platform.FieldSchema{{Name: "usage", Type: platform.SchemaFieldTypeFloat}},
+					}},
+				}}, 1, nil
+			case implicit:
+				return []*platform.Bucket{{ID: implicit, OrganizationID: org}}, 1, nil
+			}
+			return nil, 0, &platform.Error{Code: platform.ENotFound, Msg: "bucket not found"}
+		},
+	}
+
+	path, _ := ioutil.TempDir("", "storage_engine_test")
+	engine := &Engine{path: path, Engine: storage.NewEngine(path, storage.NewConfig(), storage.WithBucketSchemas(finder))}
+	defer engine.Close()
+	engine.MustOpen()
+
+	write := func(bucket platform.ID, lines string) error {
+		pts, err := models.ParsePointsString(lines)
+		if err != nil {
+			t.Fatal(err)
+		}
+		points, err := tsdb.ExplodePoints(org, bucket, pts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return engine.WritePoints(points)
+	}
+
+	// Points that don't match the schema of an explicit bucket are dropped, whoever writes them.
+	err := write(explicit, "cpu,host=a usage=1 1\ncpu,host=a usage=1i 2\nmem,host=a used=1 1\ncpu,host=a,rogue=x usage=1 1")
+	pwe, ok := err.(tsdb.PartialWriteError)
+	if !ok {
+		t.Fatalf("expected partial write error, got %v", err)
+	}
+	if pwe.Dropped != 3 || !strings.Contains(pwe.Reason, "the schema requires float") {
+		t.Fatalf("unexpected partial write error: %v", pwe)
+	}
+	if got, exp := engine.SeriesCardinality(), int64(1); got != exp {
+		t.Fatalf("got %d series, exp %d series in index", got, exp)
+	}
+
+	// Buckets without an explicit schema, or unknown to the finder, accept any point.
+	if err := write(implicit, "mem,host=a used=1 1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := write(platform.ID(4), "mem,host=a used=1 1"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEngine_DeleteBucketRangePredicate(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+	engine.MustOpen()
+
+	org := platform.ID(1)
+	bucketA, bucketB := platform.ID(2), platform.ID(3)
+	write := func(bucket platform.ID, lines string) {
+		pts, err := models.ParsePointsString(lines)
+		if err != nil {
+			t.Fatal(err)
+		}
+		points, err := tsdb.ExplodePoints(org, bucket, pts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := engine.WritePoints(points); err != nil {
+			t.Fatal(err)
+		}
+	}
+	seriesN := func(bucket platform.ID) float64 {
+		u, err := engine.GetUsage(context.Background(), platform.UsageFilter{BucketID: &bucket})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return u[platform.UsageSeries].Value
+	}
+
+	write(bucketA, "cpu,customer=acme v=1 1\ncpu,customer=acme v=2 20\ncpu,customer=other v=1 1\nmem,customer=acme v=1 1")
+	write(bucketB, "cpu,customer=acme v=1 1")
+
+	// Deleting part of the data of a series keeps the series.
+	pred := influxql.MustParseExpr(`customer = 'acme' AND "_m" = 'cpu'`)
+	if err := engine.DeleteBucketRangePredicate(org, bucketA, 0, 10, pred); err != nil {
+		t.Fatal(err)
+	}
+	if got := seriesN(bucketA); got != 3 {
+		t.Fatalf("got %v series in bucket, exp 3", got)
+	}
+
+	// Deleting all of the data of the matching series removes them from the index.
+	pred = influxql.MustParseExpr(`customer = 'acme'`)
+	if err := engine.DeleteBucketRangePredicate(org, bucketA, math.MinInt64, math.MaxInt64, pred); err != nil {
+		t.Fatal(err)
+	}
+	if got := seriesN(bucketA); got != 1 {
+		t.Fatalf("got %v series in bucket, exp 1", got)
+	}
+	if got := seriesN(bucketB); got != 1 {
+		t.Fatalf("got %v series in other bucket, exp 1", got)
+	}
+
+	// Without a predicate every series of the bucket is deleted.
+	if err := engine.DeleteBucketRangePredicate(org, bucketA, math.MinInt64, math.MaxInt64, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := seriesN(bucketA); got != 0 {
+		t.Fatalf("got %v series in bucket, exp 0", got)
+	}
+	if got := seriesN(bucketB); got != 1 {
+		t.Fatalf("got %v series in other bucket, exp 1", got)
+	}
+
+	// Only equality and regular expression comparisons of tags are supported.
+	pred = influxql.MustParseExpr(`customer > 'acme'`)
+	if err := engine.DeleteBucketRangePredicate(org, bucketB, math.MinInt64, math.MaxInt64, pred); err == nil {
+		t.Fatal("expected error for unsupported operator")
+	}
+}
+
 // Ensures that when a shard is closed, it removes any series meta-data
 // from the index.
 func TestEngineClose_RemoveIndex(t *testing.T) {
@@ -201,6 +471,60 @@ func TestEngine_WALDisabled(t *testing.T) {
 	}
 }
 
+func TestEngine_SetMode(t *testing.T) {
+	engine := NewDefaultEngine()
+	defer engine.Close()
+
+	// The mode of a closed engine applies once it is opened.
+	if err := engine.SetMode(storage.ModeReadOnly); err != nil {
+		t.Fatal(err)
+	}
+	engine.MustOpen()
+
+	pt := models.MustNewPoint(
+		"cpu",
+		models.NewTags(map[string]string{"host": "server"}),
+		map[string]interface{}{"value": 1.0},
+		time.Unix(1, 2),
+	)
+	if got, exp := engine.Write1xPoints([]models.Point{pt}), storage.ErrEngineReadOnly; got != exp {
+		t.Fatalf("got %v, expected %v", got, exp)
+	}
+	if err := engine.DeleteBucket(1, 2); err != storage.ErrEngineReadOnly {
+		t.Fatalf("got %v, expected %v", err, storage.ErrEngineReadOnly)
+	}
+	if cur, err := engine.CreateSeriesCursor(context.Background(), storage.SeriesCursorRequest{}, nil); err != nil {
+		t.Fatal(err)
+	} else {
+		cur.Close()
+	}
+
+	// Queries are rejected in maintenance mode.
+	if err := engine.SetMode(storage.ModeMaintenance); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := engine.CreateSeriesCursor(context.Background(), storage.SeriesCursorRequest{}, nil); err != storage.ErrEngineMaintenance {
+		t.Fatalf("got %v, expected %v", err, storage.ErrEngineMaintenance)
+	}
+	if _, err := engine.CreateCursorIterator(context.Background()); err != storage.ErrEngineMaintenance {
+		t.Fatalf("got %v, expected %v", err, storage.ErrEngineMaintenance)
+	}
+
+	if err := engine.SetMode(storage.ModeReadWrite); err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.Write1xPoints([]models.Point{pt}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := engine.SetMode("offline"); err == nil {
+		t.Fatal("expected error setting invalid mode")
+	}
+	if got := engine.Mode(); got != storage.ModeReadWrite {
+		t.Fatalf("got mode %v, expected %v", got, storage.ModeReadWrite)
+	}
+}
+
 type Engine struct {
 	path string
 	*storage.Engine
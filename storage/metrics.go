@@ -18,6 +18,8 @@ type retentionMetrics struct {
 	CheckDuration *prometheus.HistogramVec
 	Unprocessable *prometheus.CounterVec
 	Series        *prometheus.CounterVec
+	Files         *prometheus.CounterVec
+	ColdFiles     *prometheus.CounterVec
 }
 
 func newRetentionMetrics(labels prometheus.Labels) *retentionMetrics {
@@ -59,6 +61,20 @@ func newRetentionMetrics(labels prometheus.Labels) *retentionMetrics {
 			Name:      "series_total",
 			Help:      "Number of series that a delete was applied to.",
 		}, names),
+
+		Files: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: retentionSubsystem,
+			Name:      "files_total",
+			Help:      "Number of TSM files of expired data that were removed.",
+		}, names),
+
+		ColdFiles: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: retentionSubsystem,
+			Name:      "cold_files_total",
+			Help:      "Number of TSM files of cold data that were offloaded to cold storage.",
+		}, names),
 	}
 }
 
@@ -78,5 +94,7 @@ func (rm *retentionMetrics) PrometheusCollectors() []prometheus.Collector {
 		rm.CheckDuration,
 		rm.Unprocessable,
 		rm.Series,
+		rm.Files,
+		rm.ColdFiles,
 	}
 }
@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/influxdata/flux/ast"
+	"github.com/influxdata/flux/parser"
 	"github.com/influxdata/flux/semantic"
 	"github.com/influxdata/influxql"
 	"github.com/influxdata/platform/storage/reads/datatypes"
@@ -169,6 +170,35 @@ func toStoragePredicate(f *semantic.FunctionExpression) (*datatypes.Predicate, e
 	}, nil
 }
 
+// ParsePredicate parses a Flux boolean expression over the record r, such as the body of the function
+// passed to filter, into a storage predicate. For example:
+//
+//	r._measurement == "cpu" and r.host =~ /^server/
+func ParsePredicate(s string) (*datatypes.Predicate, error) {
+	pkg := parser.ParseSource(s)
+	if ast.Check(pkg) > 0 {
+		return nil, ast.GetError(pkg)
+	}
+
+	sp, err := semantic.New(pkg)
+	if err != nil {
+		return nil, err
+	}
+	if len(sp.Files) != 1 || len(sp.Files[0].Body) != 1 {
+		return nil, errors.New("predicate must be a single expression")
+	}
+	stmt, ok := sp.Files[0].Body[0].(*semantic.ExpressionStatement)
+	if !ok {
+		return nil, errors.New("predicate must be a single expression")
+	}
+
+	root, err := toStoragePredicateHelper(stmt.Expression, "r")
+	if err != nil {
+		return nil, err
+	}
+	return &datatypes.Predicate{Root: root}, nil
+}
+
 func toStoragePredicateHelper(n semantic.Expression, objectName string) (*datatypes.Node, error) {
 	switch n := n.(type) {
 	case *semantic.LogicalExpression:
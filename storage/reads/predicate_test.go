@@ -56,3 +56,53 @@ func TestPredicateToExprString(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePredicate(t *testing.T) {
+	cases := []struct {
+		n   string
+		s   string
+		e   string
+		err bool
+	}{
+		{
+			n: "tag equality",
+			s: `r.host == "host1"`,
+			e: `'host' = "host1"`,
+		},
+		{
+			n: "measurement, field and regex",
+			s: `r._measurement == "cpu" and r._field != "usage" or r.region =~ /^us-west/`,
+			e: `'_m' = "cpu" AND '_f' != "usage" OR 'region' =~ /^us-west/`,
+		},
+		{
+			n:   "invalid syntax",
+			s:   `r.host ==`,
+			err: true,
+		},
+		{
+			n:   "other object",
+			s:   `x.host == "host1"`,
+			err: true,
+		},
+		{
+			n:   "multiple statements",
+			s:   "r.host == \"a\"\nr.host == \"b\"",
+			err: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.n, func(t *testing.T) {
+			p, err := reads.ParsePredicate(tc.s)
+			if (err != nil) != tc.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+			if got, wanted := reads.PredicateToExprString(p), tc.e; got != wanted {
+				t.Fatal("got:", got, "wanted:", wanted)
+			}
+		})
+	}
+}
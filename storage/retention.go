@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"math"
@@ -26,6 +27,9 @@ const (
 type Deleter interface {
 	CreateSeriesCursor(context.Context, SeriesCursorRequest, influxql.Expr) (SeriesCursor, error)
 	DeleteSeriesRangeWithPredicate(tsdb.SeriesIterator, func([]byte, models.Tags) (int64, int64, bool)) error
+	DeleteExpiredFiles(func(name []byte, maxTime int64) bool) (int, error)
+	OffloadColdFiles(func(name []byte, maxTime int64) bool) (int, error)
+	UnpartitionedNameRanges() [][2][]byte
 }
 
 // A BucketFinder is responsible for providing access to buckets via a filter.
@@ -46,6 +50,10 @@ type retentionEnforcer struct {
 	// organisations.
 	BucketService BucketFinder
 
+	// SystemBuckets maps the IDs of internal buckets, which BucketService does not know about,
+	// to their retention periods.
+	SystemBuckets map[platform.ID]time.Duration
+
 	logger *zap.Logger
 
 	metrics *retentionMetrics
@@ -92,18 +100,58 @@ func (s *retentionEnforcer) run() {
 		log.Error("Deletion not successful", zap.Error(err))
 		labels["status"] = "error"
 	}
+
+	coldByBucketID, err := s.getColdStorageAfterPerBucket()
+	if err != nil {
+		log.Error("Unable to determine bucket:cold storage mapping", zap.Error(err))
+		labels["status"] = "error"
+	} else if err := s.offloadColdData(coldByBucketID, now); err != nil {
+		log.Error("Offloading cold data not successful", zap.Error(err))
+		labels["status"] = "error"
+	}
 	s.metrics.CheckDuration.With(labels).Observe(time.Since(now).Seconds())
 	s.metrics.Checks.With(labels).Inc()
 }
 
 // expireData runs a delete operation on the storage engine.
 //
-// Any series data that (1) belongs to a bucket in the provided map and
-// (2) falls outside the bucket's indicated retention period will be deleted.
+// The data of each bucket in the provided map is partitioned by the bucket's shard
+// group duration, and a partition is removed as a whole once all of its data falls
+// outside the bucket's retention period. Only the series of buckets that still have
+// data in unpartitioned files, such as files written before the data was partitioned,
+// have their expired data deleted series by series.
 func (s *retentionEnforcer) expireData(rpByBucketID map[platform.ID]time.Duration, now time.Time) error {
 	_, logEnd := logger.NewOperation(s.logger, "Data deletion", "data_deletion")
 	defer logEnd()
 
+	files, err := s.Engine.DeleteExpiredFiles(func(name []byte, maxTime int64) bool {
+		if len(name) != platform.IDLength {
+			return false
+		}
+		var n [16]byte
+		copy(n[:], name)
+		_, bucketID := tsdb.DecodeName(n)
+
+		retentionPeriod, ok := rpByBucketID[bucketID]
+		return ok && retentionPeriod != 0 && maxTime < now.Add(-retentionPeriod).UnixNano()
+	})
+	if s.metrics != nil {
+		labels := s.metrics.Labels()
+		labels["status"] = "ok"
+		if err != nil {
+			labels["status"] = "error"
+		}
+		s.metrics.Files.With(labels).Add(float64(files))
+	}
+	if err != nil {
+		return err
+	}
+
+	unpartitioned := s.Engine.UnpartitionedNameRanges()
+	if len(unpartitioned) == 0 {
+		return nil
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), engineAPITimeout)
 	defer cancel()
 	cur, err := s.Engine.CreateSeriesCursor(ctx, SeriesCursorRequest{}, nil)
@@ -133,6 +181,10 @@ func (s *retentionEnforcer) expireData(rpByBucketID map[platform.ID]time.Duratio
 		copy(n[:], name)
 		_, bucketID := tsdb.DecodeName(n)
 
+		if !inNameRanges(name, unpartitioned) {
+			return 0, 0, false // Expired with the partitions of the bucket.
+		}
+
 		retentionPeriod, ok := rpByBucketID[bucketID]
 		if !ok {
 			mu.Lock()
@@ -171,6 +223,48 @@ func (s *retentionEnforcer) expireData(rpByBucketID map[platform.ID]time.Duratio
 	return s.Engine.DeleteSeriesRangeWithPredicate(newSeriesIteratorAdapter(cur), fn)
 }
 
+// inNameRanges returns true if name falls within any of the name ranges.
+func inNameRanges(name []byte, ranges [][2][]byte) bool {
+	for _, r := range ranges {
+		if bytes.Compare(name, r[0]) >= 0 && bytes.Compare(name, r[1]) <= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// offloadColdData offloads the files of data older than the cold storage threshold of
+// their bucket in the provided map to the cold storage of the engine.
+func (s *retentionEnforcer) offloadColdData(coldByBucketID map[platform.ID]time.Duration, now time.Time) error {
+	if len(coldByBucketID) == 0 {
+		return nil
+	}
+
+	_, logEnd := logger.NewOperation(s.logger, "Cold data offload", "cold_data_offload")
+	defer logEnd()
+
+	files, err := s.Engine.OffloadColdFiles(func(name []byte, maxTime int64) bool {
+		if len(name) != platform.IDLength {
+			return false
+		}
+		var n [16]byte
+		copy(n[:], name)
+		_, bucketID := tsdb.DecodeName(n)
+
+		after, ok := coldByBucketID[bucketID]
+		return ok && maxTime < now.Add(-after).UnixNano()
+	})
+	if s.metrics != nil {
+		labels := s.metrics.Labels()
+		labels["status"] = "ok"
+		if err != nil {
+			labels["status"] = "error"
+		}
+		s.metrics.ColdFiles.With(labels).Add(float64(files))
+	}
+	return err
+}
+
 // getRetentionPeriodPerBucket returns a map of (bucket ID -> retention period)
 // for all buckets.
 func (s *retentionEnforcer) getRetentionPeriodPerBucket() (map[platform.ID]time.Duration, error) {
@@ -180,13 +274,50 @@ func (s *retentionEnforcer) getRetentionPeriodPerBucket() (map[platform.ID]time.
 	if err != nil {
 		return nil, err
 	}
-	rpByBucketID := make(map[platform.ID]time.Duration, len(buckets))
+	rpByBucketID := make(map[platform.ID]time.Duration, len(buckets)+len(s.SystemBuckets))
+	for id, rp := range s.SystemBuckets {
+		rpByBucketID[id] = rp
+	}
 	for _, bucket := range buckets {
-		rpByBucketID[bucket.ID] = bucket.RetentionPeriod
+		rpByBucketID[bucket.ID] = retentionPeriod(bucket)
 	}
 	return rpByBucketID, nil
 }
 
+// getColdStorageAfterPerBucket returns a map of (bucket ID -> cold storage threshold)
+// for all buckets whose data is offloaded to cold storage.
+func (s *retentionEnforcer) getColdStorageAfterPerBucket() (map[platform.ID]time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), bucketAPITimeout)
+	defer cancel()
+	buckets, _, err := s.BucketService.FindBuckets(ctx, platform.BucketFilter{})
+	if err != nil {
+		return nil, err
+	}
+	coldByBucketID := make(map[platform.ID]time.Duration)
+	for _, bucket := range buckets {
+		if bucket.ColdStorageAfter > 0 {
+			coldByBucketID[bucket.ID] = bucket.ColdStorageAfter
+		}
+	}
+	return coldByBucketID, nil
+}
+
+// retentionPeriod returns the retention period enforced on the data of bucket b.
+// The data of a bucket with downsample tiers is kept for at least the window of each tier,
+// so that it is not expired before the tier's task has rolled it up.
+func retentionPeriod(b *platform.Bucket) time.Duration {
+	rp := b.RetentionPeriod
+	if rp == platform.InfiniteRetention {
+		return rp
+	}
+	for _, t := range b.DownsampleTiers {
+		if t.Every > rp {
+			rp = t.Every
+		}
+	}
+	return rp
+}
+
 // PrometheusCollectors satisfies the prom.PrometheusCollector interface.
 func (s *retentionEnforcer) PrometheusCollectors() []prometheus.Collector {
 	return s.metrics.PrometheusCollectors()
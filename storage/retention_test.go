@@ -1,6 +1,7 @@
 package storage
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"math"
@@ -65,6 +66,11 @@ func TestService_expireData(t *testing.T) {
 	}
 	expRejectedFrequencies["zyzwrong"] = 5
 
+	// All of the data is in unpartitioned files.
+	engine.UnpartitionedNameRangesFn = func() [][2][]byte {
+		return [][2][]byte{{nil, bytes.Repeat([]byte{0xff}, platform.IDLength)}}
+	}
+
 	gotMatchedFrequencies := map[string]int{}
 	gotRejectedFrequencies := map[string]int{}
 	engine.DeleteSeriesRangeWithPredicateFn = func(_ tsdb.SeriesIterator, fn func([]byte, models.Tags) (int64, int64, bool)) error {
@@ -110,6 +116,155 @@ func TestService_expireData(t *testing.T) {
 	})
 }
 
+func TestService_expireData_Partitioned(t *testing.T) {
+	engine := NewTestEngine()
+	service := newRetentionEnforcer(engine, NewTestBucketFinder())
+	now := time.Date(2018, 4, 10, 23, 12, 33, 0, time.UTC)
+
+	partitioned, unpartitioned := genMeasurementName(), genMeasurementName()
+	rpByBucketID := map[platform.ID]time.Duration{}
+	for _, name := range [][]byte{partitioned, unpartitioned} {
+		var n [16]byte
+		copy(n[:], name)
+		_, bucketID := tsdb.DecodeName(n)
+		rpByBucketID[bucketID] = time.Hour
+	}
+
+	var expired [][]byte
+	engine.DeleteExpiredFilesFn = func(fn func([]byte, int64) bool) (int, error) {
+		for _, name := range [][]byte{partitioned, unpartitioned} {
+			if !fn(name, now.Add(-2*time.Hour).UnixNano()) {
+				t.Fatalf("expected partition of %x to be expired", name)
+			}
+			if fn(name, now.Add(-30*time.Minute).UnixNano()) {
+				t.Fatalf("expected partition of %x straddling the retention period not to be expired", name)
+			}
+			expired = append(expired, name)
+		}
+		return len(expired), nil
+	}
+
+	var cursors int
+	engine.CreateSeriesCursorFn = func(context.Context, SeriesCursorRequest, influxql.Expr) (SeriesCursor, error) {
+		cursors++
+		return &TestSeriesCursor{
+			CloseFn: func() error { return nil },
+			NextFn:  func() (*SeriesCursorRow, error) { return nil, nil },
+		}, nil
+	}
+	var deleted [][]byte
+	engine.DeleteSeriesRangeWithPredicateFn = func(_ tsdb.SeriesIterator, fn func([]byte, models.Tags) (int64, int64, bool)) error {
+		for _, name := range [][]byte{partitioned, unpartitioned} {
+			if _, _, ok := fn(name, nil); ok {
+				deleted = append(deleted, name)
+			}
+		}
+		return nil
+	}
+
+	t.Run("all partitioned", func(t *testing.T) {
+		if err := service.expireData(rpByBucketID, now); err != nil {
+			t.Fatal(err)
+		}
+		if len(expired) != 2 {
+			t.Fatalf("expected 2 expired partitions, got %d", len(expired))
+		}
+		if cursors != 0 || len(deleted) != 0 {
+			t.Fatalf("expected no series to be deleted, got %d cursors and %d series", cursors, len(deleted))
+		}
+	})
+
+	t.Run("unpartitioned file", func(t *testing.T) {
+		engine.UnpartitionedNameRangesFn = func() [][2][]byte {
+			return [][2][]byte{{unpartitioned, unpartitioned}}
+		}
+		if err := service.expireData(rpByBucketID, now); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(deleted, [][]byte{unpartitioned}) {
+			t.Fatalf("expected only the series of %x to be deleted, got %x", unpartitioned, deleted)
+		}
+	})
+}
+
+func TestService_getRetentionPeriodPerBucket(t *testing.T) {
+	finder := NewTestBucketFinder()
+	finder.FindBucketsFn = func(context.Context, platform.BucketFilter, ...platform.FindOptions) ([]*platform.Bucket, int, error) {
+		return []*platform.Bucket{
+			{ID: 1, RetentionPeriod: time.Hour},
+			{ID: 2, RetentionPeriod: platform.InfiniteRetention},
+			{ID: 3, RetentionPeriod: time.Hour, DownsampleTiers: []platform.DownsampleTier{{Every: time.Minute}, {Every: 2 * time.Hour}}},
+			{ID: 4, RetentionPeriod: platform.InfiniteRetention, DownsampleTiers: []platform.DownsampleTier{{Every: time.Hour}}},
+		}, 4, nil
+	}
+	service := newRetentionEnforcer(NewTestEngine(), finder)
+	service.SystemBuckets = map[platform.ID]time.Duration{10: 24 * time.Hour}
+
+	got, err := service.getRetentionPeriodPerBucket()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := map[platform.ID]time.Duration{
+		1:  time.Hour,
+		2:  platform.InfiniteRetention,
+		3:  2 * time.Hour,
+		4:  platform.InfiniteRetention,
+		10: 24 * time.Hour,
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Fatalf("got\n%#v\nexpected\n%#v", got, exp)
+	}
+}
+
+func TestService_offloadColdData(t *testing.T) {
+	finder := NewTestBucketFinder()
+	finder.FindBucketsFn = func(context.Context, platform.BucketFilter, ...platform.FindOptions) ([]*platform.Bucket, int, error) {
+		return []*platform.Bucket{
+			{ID: 1, ColdStorageAfter: 24 * time.Hour},
+			{ID: 2},
+		}, 2, nil
+	}
+	engine := NewTestEngine()
+	service := newRetentionEnforcer(engine, finder)
+	now := time.Date(2018, 4, 10, 23, 12, 33, 0, time.UTC)
+
+	coldByBucketID, err := service.getColdStorageAfterPerBucket()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := map[platform.ID]time.Duration{1: 24 * time.Hour}; !reflect.DeepEqual(coldByBucketID, exp) {
+		t.Fatalf("got\n%#v\nexpected\n%#v", coldByBucketID, exp)
+	}
+
+	name := func(bucketID platform.ID) []byte {
+		n := tsdb.EncodeName(3, bucketID)
+		return n[:]
+	}
+	threshold := now.Add(-24 * time.Hour).UnixNano()
+	engine.OffloadColdFilesFn = func(fn func([]byte, int64) bool) (int, error) {
+		for _, tt := range []struct {
+			name    []byte
+			maxTime int64
+			exp     bool
+		}{
+			{name: name(1), maxTime: threshold - 1, exp: true},
+			{name: name(1), maxTime: threshold, exp: false},
+			{name: name(2), maxTime: threshold - 1, exp: false},
+			{name: []byte("zyzwrong"), maxTime: threshold - 1, exp: false},
+		} {
+			if got := fn(tt.name, tt.maxTime); got != tt.exp {
+				return 0, fmt.Errorf("got %v for %x at %d, expected %v", got, tt.name, tt.maxTime, tt.exp)
+			}
+		}
+		return 1, nil
+	}
+
+	if err := service.offloadColdData(coldByBucketID, now); err != nil {
+		t.Fatal(err)
+	}
+}
+
 // genMeasurementName generates a random measurement name or panics.
 func genMeasurementName() []byte {
 	b := make([]byte, 16)
@@ -131,6 +286,9 @@ func (f *TestSeriesCursor) Next() (*SeriesCursorRow, error) { return f.NextFn()
 type TestEngine struct {
 	CreateSeriesCursorFn             func(context.Context, SeriesCursorRequest, influxql.Expr) (SeriesCursor, error)
 	DeleteSeriesRangeWithPredicateFn func(tsdb.SeriesIterator, func([]byte, models.Tags) (int64, int64, bool)) error
+	DeleteExpiredFilesFn             func(func([]byte, int64) bool) (int, error)
+	OffloadColdFilesFn               func(func([]byte, int64) bool) (int, error)
+	UnpartitionedNameRangesFn        func() [][2][]byte
 
 	SeriesCursor *TestSeriesCursor
 }
@@ -145,6 +303,9 @@ func NewTestEngine() *TestEngine {
 		SeriesCursor:                     cursor,
 		CreateSeriesCursorFn:             func(context.Context, SeriesCursorRequest, influxql.Expr) (SeriesCursor, error) { return cursor, nil },
 		DeleteSeriesRangeWithPredicateFn: func(tsdb.SeriesIterator, func([]byte, models.Tags) (int64, int64, bool)) error { return nil },
+		DeleteExpiredFilesFn:             func(func([]byte, int64) bool) (int, error) { return 0, nil },
+		OffloadColdFilesFn:               func(func([]byte, int64) bool) (int, error) { return 0, nil },
+		UnpartitionedNameRangesFn:        func() [][2][]byte { return nil },
 	}
 }
 
@@ -156,6 +317,18 @@ func (e *TestEngine) DeleteSeriesRangeWithPredicate(itr tsdb.SeriesIterator, fn
 	return e.DeleteSeriesRangeWithPredicateFn(itr, fn)
 }
 
+func (e *TestEngine) DeleteExpiredFiles(fn func([]byte, int64) bool) (int, error) {
+	return e.DeleteExpiredFilesFn(fn)
+}
+
+func (e *TestEngine) OffloadColdFiles(fn func([]byte, int64) bool) (int, error) {
+	return e.OffloadColdFilesFn(fn)
+}
+
+func (e *TestEngine) UnpartitionedNameRanges() [][2][]byte {
+	return e.UnpartitionedNameRangesFn()
+}
+
 type TestBucketFinder struct {
 	FindBucketsFn func(context.Context, platform.BucketFilter, ...platform.FindOptions) ([]*platform.Bucket, int, error)
 }
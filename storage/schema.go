@@ -0,0 +1,196 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/tsdb"
+)
+
+// A SchemaValidator checks points against the explicit schema of a bucket before they are written.
+// The engine drops the points that do not match the schema of their bucket when created WithBucketSchemas,
+// and writers may validate points up front to reject a write as a whole.
+// A nil SchemaValidator accepts every point.
+type SchemaValidator struct {
+	measurements map[string]*measurementSchema
+}
+
+type measurementSchema struct {
+	tags   map[string]bool
+	fields map[string]models.FieldType
+}
+
+// NewSchemaValidator returns a SchemaValidator for the measurement schemas of b,
+// or nil if b does not have an explicit schema.
+func NewSchemaValidator(b *platform.Bucket) *SchemaValidator {
+	if b.SchemaType != platform.SchemaTypeExplicit {
+		return nil
+	}
+
+	v := &SchemaValidator{measurements: make(map[string]*measurementSchema, len(b.MeasurementSchemas))}
+	for _, ms := range b.MeasurementSchemas {
+		m := &measurementSchema{
+			tags:   make(map[string]bool, len(ms.Tags)),
+			fields: make(map[string]models.FieldType, len(ms.Fields)),
+		}
+		for _, k := range ms.Tags {
+			m.tags[k] = true
+		}
+		for _, f := range ms.Fields {
+			m.fields[f.Name] = schemaFieldType(f.Type)
+		}
+		v.measurements[ms.Name] = m
+	}
+	return v
+}
+
+// Validate returns an error describing why pt does not match the schema of its measurement.
+// pt must not have been exploded.
+func (v *SchemaValidator) Validate(pt models.Point) error {
+	if v == nil {
+		return nil
+	}
+
+	name := pt.Name()
+	m, err := v.measurement(name, pt.Tags())
+	if err != nil {
+		return err
+	}
+
+	iter := pt.FieldIterator()
+	for iter.Next() {
+		if err := m.validateField(name, iter.FieldKey(), iter.Type()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSeries returns an error describing why the series of an exploded point, with the given tags
+// and field type, does not match the schema of its measurement.
+// The measurement and field key of the point are read from the tags set by tsdb.ExplodePoints.
+func (v *SchemaValidator) validateSeries(tags models.Tags, typ models.FieldType) error {
+	if v == nil {
+		return nil
+	}
+
+	var name, field []byte
+	pointTags := make(models.Tags, 0, len(tags))
+	for _, t := range tags {
+		switch {
+		case bytes.Equal(t.Key, tsdb.MeasurementTagKeyBytes):
+			name = t.Value
+		case bytes.Equal(t.Key, tsdb.FieldKeyTagKeyBytes):
+			field = t.Value
+		default:
+			pointTags = append(pointTags, t)
+		}
+	}
+
+	m, err := v.measurement(name, pointTags)
+	if err != nil {
+		return err
+	}
+	return m.validateField(name, field, typ)
+}
+
+// measurement returns the schema of the measurement with the given name,
+// or an error if the measurement is not in the schema or tags do not match its tag keys.
+func (v *SchemaValidator) measurement(name []byte, tags models.Tags) (*measurementSchema, error) {
+	m, ok := v.measurements[string(name)]
+	if !ok {
+		return nil, fmt.Errorf("measurement %q is not in the bucket schema", name)
+	}
+
+	for _, t := range tags {
+		if !m.tags[string(t.Key)] {
+			return nil, fmt.Errorf("tag %q is not in the schema for measurement %q", t.Key, name)
+		}
+	}
+	if len(tags) != len(m.tags) {
+		for k := range m.tags {
+			if tags.Get([]byte(k)) == nil {
+				return nil, fmt.Errorf("missing tag %q required by the schema for measurement %q", k, name)
+			}
+		}
+	}
+	return m, nil
+}
+
+// validateField returns an error if the field with the given key and type does not match the schema of
+// the measurement with the given name.
+func (m *measurementSchema) validateField(name, key []byte, typ models.FieldType) error {
+	want, ok := m.fields[string(key)]
+	if !ok {
+		return fmt.Errorf("field %q is not in the schema for measurement %q", key, name)
+	}
+	if typ != want {
+		return fmt.Errorf("field %q of measurement %q has type %s; the schema requires %s", key, name, fieldTypeName(typ), fieldTypeName(want))
+	}
+	return nil
+}
+
+// bucketSchema returns the SchemaValidator of the bucket whose data has the measurement name,
+// or nil if the engine does not enforce bucket schemas or the bucket does not have an explicit schema.
+// Buckets that are not known to the engine's BucketFinder, such as internal buckets, have no schema.
+func (e *Engine) bucketSchema(name []byte) (*SchemaValidator, error) {
+	if e.schemaFinder == nil {
+		return nil, nil
+	}
+	_, bucketID, ok := decodeName(string(name))
+	if !ok {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), bucketAPITimeout)
+	defer cancel()
+	buckets, _, err := e.schemaFinder.FindBuckets(ctx, platform.BucketFilter{ID: &bucketID})
+	if platform.ErrorCode(err) == platform.ENotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if len(buckets) == 0 {
+		return nil, nil
+	}
+	return NewSchemaValidator(buckets[0]), nil
+}
+
+// schemaFieldType returns the point field type corresponding to t.
+func schemaFieldType(t platform.SchemaFieldType) models.FieldType {
+	switch t {
+	case platform.SchemaFieldTypeFloat:
+		return models.Float
+	case platform.SchemaFieldTypeInteger:
+		return models.Integer
+	case platform.SchemaFieldTypeUnsigned:
+		return models.Unsigned
+	case platform.SchemaFieldTypeString:
+		return models.String
+	case platform.SchemaFieldTypeBoolean:
+		return models.Boolean
+	default:
+		return models.Empty
+	}
+}
+
+// fieldTypeName returns the name used in measurement schemas for t.
+func fieldTypeName(t models.FieldType) string {
+	switch t {
+	case models.Float:
+		return string(platform.SchemaFieldTypeFloat)
+	case models.Integer:
+		return string(platform.SchemaFieldTypeInteger)
+	case models.Unsigned:
+		return string(platform.SchemaFieldTypeUnsigned)
+	case models.String:
+		return string(platform.SchemaFieldTypeString)
+	case models.Boolean:
+		return string(platform.SchemaFieldTypeBoolean)
+	default:
+		return t.String()
+	}
+}
@@ -0,0 +1,81 @@
+package storage_test
+
+import (
+	"testing"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/models"
+	"github.com/influxdata/platform/storage"
+)
+
+func TestSchemaValidator_Validate(t *testing.T) {
+	bucket := &platform.Bucket{
+		SchemaType: platform.SchemaTypeExplicit,
+		MeasurementSchemas: []platform.MeasurementSchema{
+			{
+				Name: "cpu",
+				Tags: []string{"host", "region"},
+				Fields: []platform.FieldSchema{
+					{Name: "usage", Type: platform.SchemaFieldTypeFloat},
+					{Name: "cores", Type: platform.SchemaFieldTypeInteger},
+				},
+			},
+			{
+				Name:   "event",
+				Fields: []platform.FieldSchema{{Name: "msg", Type: platform.SchemaFieldTypeString}},
+			},
+		},
+	}
+
+	tests := []struct {
+		point   string
+		wantErr string
+	}{
+		{point: "cpu,host=a,region=west usage=1,cores=4i"},
+		{point: "cpu,host=a,region=west cores=4i"},
+		{point: `event msg="hi"`},
+		{point: "mem,host=a used=1", wantErr: `measurement "mem" is not in the bucket schema`},
+		{point: "cpu,host=a usage=1", wantErr: `missing tag "region" required by the schema for measurement "cpu"`},
+		{point: "cpu,host=a,region=west,rogue=x usage=1", wantErr: `tag "rogue" is not in the schema for measurement "cpu"`},
+		{point: "cpu,host=a,region=west idle=1", wantErr: `field "idle" is not in the schema for measurement "cpu"`},
+		{point: "cpu,host=a,region=west cores=4", wantErr: `field "cores" of measurement "cpu" has type float; the schema requires integer`},
+		{point: "event msg=true", wantErr: `field "msg" of measurement "event" has type boolean; the schema requires string`},
+	}
+	v := storage.NewSchemaValidator(bucket)
+	for _, tt := range tests {
+		t.Run(tt.point, func(t *testing.T) {
+			pt, err := models.ParsePointsString(tt.point)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = v.Validate(pt[0])
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("unexpected error: got %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSchemaValidator_Implicit(t *testing.T) {
+	v := storage.NewSchemaValidator(&platform.Bucket{
+		MeasurementSchemas: []platform.MeasurementSchema{{Name: "cpu"}},
+	})
+	if v != nil {
+		t.Fatal("expected no validator for a bucket without an explicit schema")
+	}
+
+	pt, err := models.ParsePointsString("mem,host=a used=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Validate(pt[0]); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/tsdb"
+)
+
+// shardGroupRefreshInterval is how often the shard group durations of buckets are refreshed.
+const shardGroupRefreshInterval = time.Minute
+
+// shardGroups tracks the shard group duration of each bucket, which is the duration of
+// the time partitions of the TSM files holding the bucket's data.
+type shardGroups struct {
+	finder BucketFinder
+
+	// Retention periods of internal buckets that are not known to finder.
+	// It must not be modified once the engine is open.
+	system map[platform.ID]time.Duration
+
+	refreshMu sync.Mutex // Serializes refreshes.
+
+	mu        sync.RWMutex
+	durations map[platform.ID]time.Duration
+	refreshed time.Time
+}
+
+func newShardGroups(finder BucketFinder) *shardGroups {
+	return &shardGroups{finder: finder}
+}
+
+// Duration returns the shard group duration of the bucket whose data has the measurement name,
+// refreshing the durations of all buckets from the BucketFinder if they are stale.
+// Unknown buckets have the default shard group duration of infinite retention.
+// Duration is a tsm1.PartitionFunc.
+func (g *shardGroups) Duration(name []byte) time.Duration {
+	if len(name) != platform.IDLength {
+		return 0
+	}
+	var n [16]byte
+	copy(n[:], name)
+	_, bucketID := tsdb.DecodeName(n)
+
+	g.mu.RLock()
+	stale := time.Since(g.refreshed) > shardGroupRefreshInterval
+	g.mu.RUnlock()
+	if stale {
+		g.refresh()
+	}
+
+	g.mu.RLock()
+	d, ok := g.durations[bucketID]
+	g.mu.RUnlock()
+	if ok {
+		return d
+	}
+	if rp, ok := g.system[bucketID]; ok {
+		return platform.DefaultShardGroupDuration(rp)
+	}
+	return platform.DefaultShardGroupDuration(platform.InfiniteRetention)
+}
+
+// refresh reads the shard group durations of all buckets, unless another caller just did.
+// The previous durations are kept if the buckets cannot be read.
+func (g *shardGroups) refresh() {
+	g.refreshMu.Lock()
+	defer g.refreshMu.Unlock()
+
+	g.mu.RLock()
+	stale := time.Since(g.refreshed) > shardGroupRefreshInterval
+	g.mu.RUnlock()
+	if !stale {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), bucketAPITimeout)
+	defer cancel()
+	buckets, _, err := g.finder.FindBuckets(ctx, platform.BucketFilter{})
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.refreshed = time.Now()
+	if err != nil {
+		return
+	}
+
+	g.durations = make(map[platform.ID]time.Duration, len(buckets))
+	for _, b := range buckets {
+		d := b.ShardGroupDuration
+		if d == 0 {
+			d = platform.DefaultShardGroupDuration(b.RetentionPeriod)
+		}
+		g.durations[b.ID] = d
+	}
+}
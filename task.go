@@ -35,6 +35,17 @@ type Run struct {
 	Log          Log    `json:"log"`
 }
 
+// Backfill is a request to run a task for every schedule in a historical time range.
+type Backfill struct {
+	ID          ID     `json:"id,omitempty"`
+	TaskID      ID     `json:"taskID"`
+	Start       string `json:"start"`
+	Stop        string `json:"stop"`
+	RequestedAt string `json:"requestedAt,omitempty"`
+	Completed   int    `json:"completed"`
+	Remaining   int    `json:"remaining"`
+}
+
 // Log represents a link to a log resource
 type Log string
 
@@ -74,6 +85,16 @@ type TaskService interface {
 	// ForceRun forces a run to occur with unix timestamp scheduledFor, to be executed as soon as possible.
 	// The value of scheduledFor may or may not align with the task's schedule.
 	ForceRun(ctx context.Context, taskID ID, scheduledFor int64) (*Run, error)
+
+	// BackfillTask requests runs of the task for every schedule between the unix timestamps start and stop, inclusive.
+	// The runs are executed as soon as possible, alongside the task's regularly scheduled runs.
+	BackfillTask(ctx context.Context, taskID ID, start, stop int64) (*Backfill, error)
+
+	// FindBackfills returns the backfills of the task that have runs yet to be created.
+	FindBackfills(ctx context.Context, taskID ID) ([]*Backfill, error)
+
+	// CancelBackfill stops a backfill from creating any more runs, and cancels its runs that are in progress.
+	CancelBackfill(ctx context.Context, taskID, backfillID ID) error
 }
 
 // TaskUpdate represents updates to a task
@@ -98,6 +119,11 @@ type RunFilter struct {
 	Limit      int
 	AfterTime  string
 	BeforeTime string
+
+	// Status, if set, restricts the results to runs with that status.
+	Status string
+	// Match, if set, restricts the results to runs with a log line containing the text, ignoring case.
+	Match string
 }
 
 // LogFilter represents a set of filters that restrict the returned results
@@ -105,4 +131,12 @@ type LogFilter struct {
 	Org  *ID
 	Task *ID
 	Run  *ID
+
+	// AfterTime and BeforeTime, if set, restrict the results to log lines written within that window, RFC3339.
+	AfterTime  string
+	BeforeTime string
+	// Status, if set, restricts the results to logs of runs with that status.
+	Status string
+	// Match, if set, restricts the results to log lines containing the text, ignoring case.
+	Match string
 }
@@ -17,6 +17,7 @@
 package bolt
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -106,6 +107,11 @@ func (s *Store) CreateTask(ctx context.Context, req backend.CreateTaskRequest) (
 		// get the root bucket
 		b := tx.Bucket(s.bucket)
 		name := []byte(o.Name)
+
+		if err := backend.StoreValidator.Dependencies(id, req.Org, o.DependsOn, findTaskFunc(b)); err != nil {
+			return err
+		}
+
 		// Encode ID
 		encodedID, err := id.Encode()
 		if err != nil {
@@ -206,6 +212,15 @@ func (s *Store) UpdateTask(ctx context.Context, req backend.UpdateTaskRequest) (
 		}
 		res.OldScript = string(v)
 
+		var userID, orgID platform.ID
+		if err := userID.Decode(b.Bucket(userByTaskID).Get(encodedID)); err != nil {
+			return err
+		}
+
+		if err := orgID.Decode(b.Bucket(orgByTaskID).Get(encodedID)); err != nil {
+			return err
+		}
+
 		newScript := req.Script
 		if req.Script == "" {
 			// Need to build op from existing script.
@@ -215,6 +230,9 @@ func (s *Store) UpdateTask(ctx context.Context, req backend.UpdateTaskRequest) (
 			}
 			newScript = string(v)
 		} else {
+			if err := backend.StoreValidator.Dependencies(req.ID, orgID, op.DependsOn, findTaskFunc(b)); err != nil {
+				return err
+			}
 			if err := bt.Put(encodedID, []byte(req.Script)); err != nil {
 				return err
 			}
@@ -223,15 +241,6 @@ func (s *Store) UpdateTask(ctx context.Context, req backend.UpdateTaskRequest) (
 			}
 		}
 
-		var userID, orgID platform.ID
-		if err := userID.Decode(b.Bucket(userByTaskID).Get(encodedID)); err != nil {
-			return err
-		}
-
-		if err := orgID.Decode(b.Bucket(orgByTaskID).Get(encodedID)); err != nil {
-			return err
-		}
-
 		stmBytes := b.Bucket(taskMetaPath).Get(encodedID)
 		if stmBytes == nil {
 			return backend.ErrTaskNotFound
@@ -241,8 +250,14 @@ func (s *Store) UpdateTask(ctx context.Context, req backend.UpdateTaskRequest) (
 			return err
 		}
 		res.OldStatus = backend.TaskStatus(stm.Status)
+		if req.Script != "" {
+			stm.SetDependsOn(op.DependsOn)
+			stm.MaxRetry = int32(op.Retry)
+		}
 		if req.Status != "" {
 			stm.Status = string(req.Status)
+		}
+		if req.Script != "" || req.Status != "" {
 			stmBytes, err = stm.Marshal()
 			if err != nil {
 				return err
@@ -504,6 +519,30 @@ func (s *Store) FindTaskMetaByID(ctx context.Context, id platform.ID) (*backend.
 	return &stm, nil
 }
 
+// findTaskFunc returns a function to look up the organization IDs and metas of tasks in the root bucket b,
+// for use in backend.StoreValidator.Dependencies.
+func findTaskFunc(b *bolt.Bucket) func(platform.ID) (platform.ID, *backend.StoreTaskMeta, error) {
+	return func(id platform.ID) (platform.ID, *backend.StoreTaskMeta, error) {
+		encodedID, err := id.Encode()
+		if err != nil {
+			return platform.InvalidID(), nil, err
+		}
+		stmBytes := b.Bucket(taskMetaPath).Get(encodedID)
+		if stmBytes == nil {
+			return platform.InvalidID(), nil, backend.ErrTaskNotFound
+		}
+		var stm backend.StoreTaskMeta
+		if err := stm.Unmarshal(stmBytes); err != nil {
+			return platform.InvalidID(), nil, err
+		}
+		var orgID platform.ID
+		if err := orgID.Decode(b.Bucket(orgByTaskID).Get(encodedID)); err != nil {
+			return platform.InvalidID(), nil, err
+		}
+		return orgID, &stm, nil
+	}
+}
+
 func (s *Store) FindTaskByIDWithMeta(ctx context.Context, id platform.ID) (*backend.StoreTask, *backend.StoreTaskMeta, error) {
 	var stmBytes []byte
 	var userID, orgID platform.ID
@@ -699,6 +738,98 @@ func (s *Store) ManuallyRunTimeRange(_ context.Context, taskID platform.ID, star
 	return mRun, nil
 }
 
+// CancelManualRun removes a queued manual run range from a task, returning the IDs of its runs still in progress.
+func (s *Store) CancelManualRun(_ context.Context, taskID, manualRunID platform.ID) ([]platform.ID, error) {
+	encodedID, err := taskID.Encode()
+	if err != nil {
+		return nil, err
+	}
+	var running []platform.ID
+
+	if err = s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(s.bucket)
+		stmBytes := b.Bucket(taskMetaPath).Get(encodedID)
+		if stmBytes == nil {
+			return backend.ErrTaskNotFound
+		}
+		var stm backend.StoreTaskMeta
+		if err := stm.Unmarshal(stmBytes); err != nil {
+			return err
+		}
+		running, err = stm.CancelManualRun(manualRunID)
+		if err != nil {
+			return err
+		}
+
+		stmBytes, err := stm.Marshal()
+		if err != nil {
+			return err
+		}
+
+		return b.Bucket(taskMetaPath).Put(encodedID, stmBytes)
+	}); err != nil {
+		return nil, err
+	}
+	return running, nil
+}
+
+// QueueDependentRuns records the successful run of the task with the given ID in the meta of every task that depends on it,
+// queueing runs for the dependent tasks whose upstream tasks have all succeeded for now.
+func (s *Store) QueueDependentRuns(_ context.Context, taskID platform.ID, now, requestedAt int64) ([]platform.ID, error) {
+	var queued []platform.ID
+
+	if err := s.db.Update(func(tx *bolt.Tx) error {
+		mb := tx.Bucket(s.bucket).Bucket(taskMetaPath)
+
+		// Collect the modified metas first, as the bucket must not be modified while iterating over it.
+		updates := make(map[string][]byte)
+		err := mb.ForEach(func(k, v []byte) error {
+			var stm backend.StoreTaskMeta
+			if err := stm.Unmarshal(v); err != nil {
+				return err
+			}
+			if len(stm.DependsOn) == 0 {
+				return nil
+			}
+
+			makeID := func() (platform.ID, error) { return s.idGen.ID(), nil }
+			ok, err := stm.UpstreamSucceeded(taskID, now, requestedAt, makeID)
+			if err != nil {
+				return err
+			}
+			if ok {
+				var id platform.ID
+				if err := id.Decode(k); err != nil {
+					return err
+				}
+				queued = append(queued, id)
+			}
+
+			stmBytes, err := stm.Marshal()
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(stmBytes, v) {
+				updates[string(k)] = stmBytes
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for k, stmBytes := range updates {
+			if err := mb.Put([]byte(k), stmBytes); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return queued, nil
+}
+
 // Close closes the store
 func (s *Store) Close() error {
 	return s.db.Close()
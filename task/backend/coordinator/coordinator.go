@@ -3,6 +3,8 @@ package coordinator
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/influxdata/platform"
 	"github.com/influxdata/platform/task/backend"
@@ -16,6 +18,17 @@ type Coordinator struct {
 	sch    backend.Scheduler
 
 	limit int
+
+	// Lease settings; leases is nil when this coordinator owns every task.
+	leases   LeaseStore
+	nodeID   string
+	leaseTTL time.Duration
+
+	mu    sync.Mutex             // Serializes claiming and releasing tasks.
+	owned map[platform.ID]string // Script of each task this node holds a lease on.
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
 }
 
 type Option func(*Coordinator)
@@ -26,6 +39,20 @@ func WithLimit(i int) Option {
 	}
 }
 
+// WithLeases makes the coordinator share tasks with coordinators on other nodes, through ls.
+// The coordinator only schedules tasks it holds a lease on, renewing its leases every third of ttl.
+// It takes over tasks whose leases expired, along with their currently running runs,
+// and releases tasks when it holds more than its share.
+// ls and the task store of the coordinator must be shared by every node; with stores local to each
+// node, every node schedules every task.
+func WithLeases(ls LeaseStore, nodeID string, ttl time.Duration) Option {
+	return func(c *Coordinator) {
+		c.leases = ls
+		c.nodeID = nodeID
+		c.leaseTTL = ttl
+	}
+}
+
 func New(logger *zap.Logger, scheduler backend.Scheduler, st backend.Store, opts ...Option) *Coordinator {
 	c := &Coordinator{
 		logger: logger,
@@ -38,11 +65,40 @@ func New(logger *zap.Logger, scheduler backend.Scheduler, st backend.Store, opts
 		opt(c)
 	}
 
-	go c.claimExistingTasks()
+	if c.leases != nil {
+		c.owned = make(map[platform.ID]string)
+
+		var ctx context.Context
+		ctx, c.cancel = context.WithCancel(context.Background())
+		c.wg.Add(1)
+		go c.maintainLeases(ctx)
+	} else {
+		go c.claimExistingTasks()
+	}
 
 	return c
 }
 
+// Stop stops lease maintenance and releases the leases held by this coordinator,
+// so that other nodes may take over its tasks without waiting for the leases to expire.
+// Stop does nothing if the coordinator was not created with leases.
+func (c *Coordinator) Stop() {
+	if c.leases == nil {
+		return
+	}
+
+	c.cancel()
+	c.wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id := range c.owned {
+		if err := c.releaseTask(context.Background(), id); err != nil {
+			c.logger.Error("failed to release task lease", zap.String("task_id", id.String()), zap.Error(err))
+		}
+	}
+}
+
 // claimExistingTasks is called on startup to claim all tasks in the store.
 func (c *Coordinator) claimExistingTasks() {
 	tasks, err := c.Store.ListTasks(context.Background(), backend.TaskSearchParams{})
@@ -80,7 +136,9 @@ func (c *Coordinator) CreateTask(ctx context.Context, req backend.CreateTaskRequ
 		return id, err
 	}
 
-	if err := c.sch.ClaimTask(task, meta); err != nil {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.claimTask(ctx, task, meta); err != nil {
 		_, delErr := c.Store.DeleteTask(ctx, id)
 		if delErr != nil {
 			return id, fmt.Errorf("schedule task failed: %s\n\tcleanup also failed: %s", err, delErr)
@@ -102,9 +160,12 @@ func (c *Coordinator) UpdateTask(ctx context.Context, req backend.UpdateTaskRequ
 		return res, err
 	}
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	// If disabling the task, do so before modifying the script.
 	if req.Status == backend.TaskInactive && res.OldStatus != backend.TaskInactive {
-		if err := c.sch.ReleaseTask(req.ID); err != nil && err != backend.ErrTaskNotClaimed {
+		if err := c.releaseTask(ctx, req.ID); err != nil && err != backend.ErrTaskNotClaimed {
 			return res, err
 		}
 	}
@@ -112,10 +173,14 @@ func (c *Coordinator) UpdateTask(ctx context.Context, req backend.UpdateTaskRequ
 	if err := c.sch.UpdateTask(task, meta); err != nil && err != backend.ErrTaskNotClaimed {
 		return res, err
 	}
+	if _, ok := c.owned[req.ID]; ok {
+		c.owned[req.ID] = task.Script
+	}
 
 	// If enabling the task, claim it after modifying the script.
+	// With leases, a task held by another node is updated by that node when it next renews its leases.
 	if req.Status == backend.TaskActive {
-		if err := c.sch.ClaimTask(task, meta); err != nil && err != backend.ErrTaskAlreadyClaimed {
+		if err := c.claimTask(ctx, task, meta); err != nil && err != backend.ErrTaskAlreadyClaimed && err != ErrLeaseHeld {
 			return res, err
 		}
 	}
@@ -124,7 +189,9 @@ func (c *Coordinator) UpdateTask(ctx context.Context, req backend.UpdateTaskRequ
 }
 
 func (c *Coordinator) DeleteTask(ctx context.Context, id platform.ID) (deleted bool, err error) {
-	if err := c.sch.ReleaseTask(id); err != nil && err != backend.ErrTaskNotClaimed {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.releaseTask(ctx, id); err != nil && err != backend.ErrTaskNotClaimed {
 		return false, err
 	}
 
@@ -139,8 +206,10 @@ func (c *Coordinator) DeleteOrg(ctx context.Context, orgID platform.ID) error {
 		return err
 	}
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	for _, orgTask := range orgTasks {
-		if err := c.sch.ReleaseTask(orgTask.Task.ID); err != nil {
+		if err := c.releaseTask(ctx, orgTask.Task.ID); err != nil {
 			return err
 		}
 	}
@@ -156,8 +225,10 @@ func (c *Coordinator) DeleteUser(ctx context.Context, userID platform.ID) error
 		return err
 	}
 
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	for _, userTask := range userTasks {
-		if err := c.sch.ReleaseTask(userTask.Task.ID); err != nil {
+		if err := c.releaseTask(ctx, userTask.Task.ID); err != nil {
 			return err
 		}
 	}
@@ -168,3 +239,216 @@ func (c *Coordinator) DeleteUser(ctx context.Context, userID platform.ID) error
 func (c *Coordinator) CancelRun(ctx context.Context, taskID, runID platform.ID) error {
 	return c.sch.CancelRun(ctx, taskID, runID)
 }
+
+func (c *Coordinator) ManuallyRunTimeRange(ctx context.Context, taskID platform.ID, start, end, requestedAt int64) (*backend.StoreTaskMetaManualRun, error) {
+	mr, err := c.Store.ManuallyRunTimeRange(ctx, taskID, start, end, requestedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	c.sch.ManualRunsQueued(taskID)
+	return mr, nil
+}
+
+// claimTask begins scheduling the given task in this coordinator's scheduler.
+// With leases, the task is only claimed if this node acquires its lease, and ErrLeaseHeld is returned otherwise.
+// The caller must hold c.mu.
+func (c *Coordinator) claimTask(ctx context.Context, task *backend.StoreTask, meta *backend.StoreTaskMeta) error {
+	if c.leases == nil {
+		return c.sch.ClaimTask(task, meta)
+	}
+
+	if _, ok := c.owned[task.ID]; ok {
+		return backend.ErrTaskAlreadyClaimed
+	}
+	if _, err := c.leases.AcquireLease(ctx, task.ID, c.nodeID, c.leaseTTL); err != nil {
+		return err
+	}
+
+	if err := c.sch.ClaimTask(task, meta); err != nil {
+		if relErr := c.leases.ReleaseLease(ctx, task.ID, c.nodeID); relErr != nil {
+			c.logger.Error("failed to release task lease", zap.String("task_id", task.ID.String()), zap.Error(relErr))
+		}
+		return err
+	}
+	c.owned[task.ID] = task.Script
+	return nil
+}
+
+// releaseTask stops scheduling the task with the given ID in this coordinator's scheduler.
+// With leases, this node's lease on the task is dropped too,
+// and releasing a task this node does not hold is not an error.
+// The caller must hold c.mu.
+func (c *Coordinator) releaseTask(ctx context.Context, id platform.ID) error {
+	if c.leases == nil {
+		return c.sch.ReleaseTask(id)
+	}
+
+	if _, ok := c.owned[id]; !ok {
+		return nil
+	}
+	delete(c.owned, id)
+
+	if err := c.sch.ReleaseTask(id); err != nil && err != backend.ErrTaskNotClaimed {
+		return err
+	}
+	return c.leases.ReleaseLease(ctx, id, c.nodeID)
+}
+
+// maintainLeases syncs this node's leases every third of the lease TTL, until ctx is canceled.
+func (c *Coordinator) maintainLeases(ctx context.Context) {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.leaseTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		if err := c.syncLeases(ctx); err != nil {
+			c.logger.Error("failed to sync task leases", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// syncLeases marks this node as alive, renews the leases on the tasks it schedules,
+// and claims or releases tasks so that it holds its share of the active tasks across all live nodes.
+// Changes made to its tasks through other nodes are applied to the scheduler.
+func (c *Coordinator) syncLeases(ctx context.Context) error {
+	if err := c.leases.RegisterNode(ctx, c.nodeID, c.leaseTTL); err != nil {
+		return err
+	}
+	nodes, err := c.leases.ListNodes(ctx)
+	if err != nil {
+		return err
+	}
+	leases, err := c.leases.ListLeases(ctx)
+	if err != nil {
+		return err
+	}
+	// Tasks leased to this node but not scheduled here, such as after a restart, are treated as unheld.
+	held := make(map[platform.ID]bool, len(leases))
+	for _, l := range leases {
+		if l.NodeID != c.nodeID {
+			held[l.TaskID] = true
+		}
+	}
+
+	tasks, err := c.listActiveTasks(ctx)
+	if err != nil {
+		return err
+	}
+
+	share := len(tasks)
+	if n := len(nodes); n > 1 {
+		share = (len(tasks) + n - 1) / n
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	active := make(map[platform.ID]bool, len(tasks))
+	var unclaimed []backend.StoreTaskWithMeta
+	kept := 0
+	for _, t := range tasks {
+		id := t.Task.ID
+		active[id] = true
+
+		script, ok := c.owned[id]
+		if !ok {
+			if !held[id] {
+				unclaimed = append(unclaimed, t)
+			}
+			continue
+		}
+
+		if kept >= share {
+			// Leave the task for a node holding less than its share.
+			if err := c.releaseTask(ctx, id); err != nil {
+				c.logger.Error("failed to release task", zap.String("task_id", id.String()), zap.Error(err))
+			}
+			continue
+		}
+
+		if _, err := c.leases.AcquireLease(ctx, id, c.nodeID, c.leaseTTL); err != nil {
+			if err != ErrLeaseHeld {
+				c.logger.Error("failed to renew task lease", zap.String("task_id", id.String()), zap.Error(err))
+				kept++
+				continue
+			}
+
+			// Our lease expired and another node has taken over the task.
+			delete(c.owned, id)
+			if err := c.sch.ReleaseTask(id); err != nil && err != backend.ErrTaskNotClaimed {
+				c.logger.Error("failed to release task", zap.String("task_id", id.String()), zap.Error(err))
+			}
+			continue
+		}
+		kept++
+
+		if t.Meta.HasPendingManualRuns() {
+			// Runs may have been queued through another node, such as when an upstream task succeeded there.
+			c.sch.ManualRunsQueued(id)
+		}
+
+		if script != t.Task.Script {
+			if err := c.sch.UpdateTask(&t.Task, &t.Meta); err != nil {
+				c.logger.Error("failed to update task", zap.String("task_id", id.String()), zap.Error(err))
+				continue
+			}
+			c.owned[id] = t.Task.Script
+		}
+	}
+
+	// Release tasks that were deleted or disabled through another node.
+	for id := range c.owned {
+		if !active[id] {
+			if err := c.releaseTask(ctx, id); err != nil {
+				c.logger.Error("failed to release task", zap.String("task_id", id.String()), zap.Error(err))
+			}
+		}
+	}
+
+	// Claim tasks that no node holds, resuming any runs they had in progress.
+	for i := range unclaimed {
+		if kept >= share {
+			break
+		}
+
+		t := &unclaimed[i]
+		if err := c.claimTask(ctx, &t.Task, &t.Meta); err != nil {
+			if err != ErrLeaseHeld {
+				c.logger.Error("failed to claim task", zap.String("task_id", t.Task.ID.String()), zap.Error(err))
+			}
+			continue
+		}
+		kept++
+	}
+
+	return nil
+}
+
+// listActiveTasks returns every active task in the store.
+func (c *Coordinator) listActiveTasks(ctx context.Context) ([]backend.StoreTaskWithMeta, error) {
+	var active []backend.StoreTaskWithMeta
+
+	tasks, err := c.Store.ListTasks(ctx, backend.TaskSearchParams{})
+	for err == nil && len(tasks) > 0 {
+		for _, t := range tasks {
+			if t.Meta.Status == string(backend.TaskActive) {
+				active = append(active, t)
+			}
+		}
+		tasks, err = c.Store.ListTasks(ctx, backend.TaskSearchParams{
+			After: tasks[len(tasks)-1].Task.ID,
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return active, nil
+}
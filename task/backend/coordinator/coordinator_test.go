@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/inmem"
 	_ "github.com/influxdata/platform/query/builtin"
 	"github.com/influxdata/platform/task/backend"
 	"github.com/influxdata/platform/task/backend/coordinator"
@@ -192,3 +193,98 @@ func TestCoordinator_ClaimExistingTasks(t *testing.T) {
 		}
 	}
 }
+
+func TestCoordinator_Leases(t *testing.T) {
+	ctx := context.Background()
+	st := backend.NewInMemStore()
+
+	ls := coordinator.NewKVLeaseStore(inmem.NewKVStore())
+	if err := ls.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+
+	const numTasks = 4
+	taskIDs := make([]platform.ID, numTasks)
+	for i := range taskIDs {
+		id, err := st.CreateTask(ctx, backend.CreateTaskRequest{Org: 1, User: 2, Script: script, ScheduleAfter: 3000})
+		if err != nil {
+			t.Fatal(err)
+		}
+		taskIDs[i] = id
+	}
+
+	// The first task was being run by a node that has since gone away without releasing its lease.
+	const ttl = 100 * time.Millisecond
+	orphanID := taskIDs[0]
+	if _, err := ls.AcquireLease(ctx, orphanID, "gone", ttl); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := st.CreateNextRun(ctx, orphanID, 3060)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	schedA, schedB := mock.NewScheduler(), mock.NewScheduler()
+	coordA := coordinator.New(zaptest.NewLogger(t), schedA, st, coordinator.WithLeases(ls, "a", ttl))
+	defer coordA.Stop()
+	coordB := coordinator.New(zaptest.NewLogger(t), schedB, st, coordinator.WithLeases(ls, "b", ttl))
+
+	claimed := func(s *mock.Scheduler) int {
+		n := 0
+		for _, id := range taskIDs {
+			if s.TaskFor(id) != nil {
+				n++
+			}
+		}
+		return n
+	}
+
+	// Each node should settle on its share of the tasks, including the orphaned one.
+	waitFor(t, func() bool {
+		return claimed(schedA) == numTasks/2 && claimed(schedB) == numTasks/2
+	})
+	for _, id := range taskIDs {
+		if schedA.TaskFor(id) != nil && schedB.TaskFor(id) != nil {
+			t.Fatalf("task %s claimed by both nodes", id)
+		}
+	}
+
+	// Whichever node took over the orphaned task should resume its in-progress run.
+	meta := schedA.TaskMetaFor(orphanID)
+	if meta == nil {
+		meta = schedB.TaskMetaFor(orphanID)
+	}
+	if len(meta.CurrentlyRunning) != 1 || platform.ID(meta.CurrentlyRunning[0].RunID) != rc.Created.RunID {
+		t.Fatalf("expected orphaned task to be claimed with its running run %s, got %+v", rc.Created.RunID, meta.CurrentlyRunning)
+	}
+
+	// When a node stops, the remaining node picks up all of its tasks.
+	coordB.Stop()
+	if n := claimed(schedB); n != 0 {
+		t.Fatalf("expected stopped node to release its tasks, still holds %d", n)
+	}
+	waitFor(t, func() bool {
+		return claimed(schedA) == numTasks
+	})
+
+	// Tasks disabled through any node are released by the node that holds them.
+	if _, err := st.UpdateTask(ctx, backend.UpdateTaskRequest{ID: taskIDs[1], Status: backend.TaskInactive}); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool {
+		return schedA.TaskFor(taskIDs[1]) == nil
+	})
+}
+
+// waitFor fails the test if cond does not become true within a few seconds.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for condition")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,232 @@
+package coordinator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/kv"
+)
+
+var (
+	leaseBucket = []byte("taskleasesv1")
+	nodeBucket  = []byte("tasknodesv1")
+)
+
+// ErrLeaseHeld is returned when acquiring a lease on a task whose unexpired lease belongs to another node.
+var ErrLeaseHeld = errors.New("task lease is held by another node")
+
+// Lease records which node is responsible for scheduling a task, and until when.
+type Lease struct {
+	TaskID  platform.ID `json:"taskID"`
+	NodeID  string      `json:"nodeID"`
+	Expires time.Time   `json:"expires"`
+}
+
+// LeaseStore persists task leases and node liveness,
+// so that coordinators on several nodes can divide tasks between themselves.
+type LeaseStore interface {
+	// AcquireLease grants nodeID a lease on taskID lasting ttl, or extends the lease if nodeID already holds it.
+	// It returns ErrLeaseHeld if another node holds an unexpired lease on the task.
+	AcquireLease(ctx context.Context, taskID platform.ID, nodeID string, ttl time.Duration) (*Lease, error)
+
+	// ReleaseLease drops nodeID's lease on taskID.
+	// Releasing a lease that is not held by nodeID is not an error.
+	ReleaseLease(ctx context.Context, taskID platform.ID, nodeID string) error
+
+	// ListLeases returns all unexpired leases.
+	ListLeases(ctx context.Context) ([]Lease, error)
+
+	// RegisterNode records that nodeID is alive for the next ttl.
+	RegisterNode(ctx context.Context, nodeID string, ttl time.Duration) error
+
+	// ListNodes returns the IDs of all nodes whose registration has not expired.
+	ListNodes(ctx context.Context) ([]string, error)
+}
+
+// KVLeaseStore is a LeaseStore built on a kv.Store.
+type KVLeaseStore struct {
+	kv kv.Store
+
+	// Now returns the current time, used to determine lease expiration.
+	Now func() time.Time
+}
+
+var _ LeaseStore = (*KVLeaseStore)(nil)
+
+// NewKVLeaseStore returns a KVLeaseStore that persists leases in s.
+func NewKVLeaseStore(s kv.Store) *KVLeaseStore {
+	return &KVLeaseStore{
+		kv:  s,
+		Now: time.Now,
+	}
+}
+
+// Initialize creates the buckets for the lease store.
+func (s *KVLeaseStore) Initialize() error {
+	return s.kv.Update(func(tx kv.Tx) error {
+		if _, err := tx.Bucket(leaseBucket); err != nil {
+			return err
+		}
+		if _, err := tx.Bucket(nodeBucket); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// AcquireLease grants or renews nodeID's lease on taskID.
+func (s *KVLeaseStore) AcquireLease(ctx context.Context, taskID platform.ID, nodeID string, ttl time.Duration) (*Lease, error) {
+	key, err := taskID.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	var l *Lease
+	err = s.kv.Update(func(tx kv.Tx) error {
+		b, err := tx.Bucket(leaseBucket)
+		if err != nil {
+			return err
+		}
+
+		now := s.Now()
+		cur, err := getLease(b, key)
+		if err != nil {
+			return err
+		}
+		if cur != nil && cur.NodeID != nodeID && now.Before(cur.Expires) {
+			return ErrLeaseHeld
+		}
+
+		l = &Lease{TaskID: taskID, NodeID: nodeID, Expires: now.Add(ttl)}
+		v, err := json.Marshal(l)
+		if err != nil {
+			return err
+		}
+		return b.Put(key, v)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// ReleaseLease deletes nodeID's lease on taskID, if nodeID holds it.
+func (s *KVLeaseStore) ReleaseLease(ctx context.Context, taskID platform.ID, nodeID string) error {
+	key, err := taskID.Encode()
+	if err != nil {
+		return err
+	}
+
+	return s.kv.Update(func(tx kv.Tx) error {
+		b, err := tx.Bucket(leaseBucket)
+		if err != nil {
+			return err
+		}
+
+		cur, err := getLease(b, key)
+		if err != nil {
+			return err
+		}
+		if cur == nil || cur.NodeID != nodeID {
+			return nil
+		}
+		return b.Delete(key)
+	})
+}
+
+// ListLeases returns every unexpired lease.
+func (s *KVLeaseStore) ListLeases(ctx context.Context) ([]Lease, error) {
+	var ls []Lease
+	err := s.kv.View(func(tx kv.Tx) error {
+		b, err := tx.Bucket(leaseBucket)
+		if err != nil {
+			return err
+		}
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+
+		now := s.Now()
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			var l Lease
+			if err := json.Unmarshal(v, &l); err != nil {
+				return err
+			}
+			if now.Before(l.Expires) {
+				ls = append(ls, l)
+			}
+		}
+		return nil
+	})
+	return ls, err
+}
+
+type nodeRegistration struct {
+	NodeID  string    `json:"nodeID"`
+	Expires time.Time `json:"expires"`
+}
+
+// RegisterNode marks nodeID as alive until ttl from now.
+func (s *KVLeaseStore) RegisterNode(ctx context.Context, nodeID string, ttl time.Duration) error {
+	v, err := json.Marshal(nodeRegistration{NodeID: nodeID, Expires: s.Now().Add(ttl)})
+	if err != nil {
+		return err
+	}
+
+	return s.kv.Update(func(tx kv.Tx) error {
+		b, err := tx.Bucket(nodeBucket)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(nodeID), v)
+	})
+}
+
+// ListNodes returns the IDs of the nodes whose registrations have not expired.
+func (s *KVLeaseStore) ListNodes(ctx context.Context) ([]string, error) {
+	var nodes []string
+	err := s.kv.View(func(tx kv.Tx) error {
+		b, err := tx.Bucket(nodeBucket)
+		if err != nil {
+			return err
+		}
+		cur, err := b.Cursor()
+		if err != nil {
+			return err
+		}
+
+		now := s.Now()
+		for k, v := cur.First(); k != nil; k, v = cur.Next() {
+			var n nodeRegistration
+			if err := json.Unmarshal(v, &n); err != nil {
+				return err
+			}
+			if now.Before(n.Expires) {
+				nodes = append(nodes, n.NodeID)
+			}
+		}
+		return nil
+	})
+	return nodes, err
+}
+
+// getLease returns the lease stored under key in b, or nil if there is none.
+func getLease(b kv.Bucket, key []byte) (*Lease, error) {
+	v, err := b.Get(key)
+	if err == kv.ErrKeyNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var l Lease
+	if err := json.Unmarshal(v, &l); err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
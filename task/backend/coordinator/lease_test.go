@@ -0,0 +1,127 @@
+package coordinator_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/bolt"
+	"github.com/influxdata/platform/inmem"
+	"github.com/influxdata/platform/kv"
+	"github.com/influxdata/platform/task/backend/coordinator"
+)
+
+func TestKVLeaseStore(t *testing.T) {
+	t.Run("inmem", func(t *testing.T) {
+		testLeaseStore(t, inmem.NewKVStore())
+	})
+
+	t.Run("bolt", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "task-lease")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		s := bolt.NewKVStore(filepath.Join(dir, "leases.bolt"))
+		if err := s.Open(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+
+		testLeaseStore(t, s)
+	})
+}
+
+func testLeaseStore(t *testing.T, s kv.Store) {
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+
+	ls := coordinator.NewKVLeaseStore(s)
+	ls.Now = func() time.Time { return now }
+	if err := ls.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+
+	taskID := platform.ID(1)
+	l, err := ls.AcquireLease(ctx, taskID, "node-a", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l.NodeID != "node-a" || !l.Expires.Equal(now.Add(time.Minute)) {
+		t.Fatalf("unexpected lease: %+v", l)
+	}
+
+	if _, err := ls.AcquireLease(ctx, taskID, "node-b", time.Minute); err != coordinator.ErrLeaseHeld {
+		t.Fatalf("expected ErrLeaseHeld acquiring a held lease, got %v", err)
+	}
+
+	// Renewing extends the lease past the original expiration.
+	now = now.Add(30 * time.Second)
+	if _, err := ls.AcquireLease(ctx, taskID, "node-a", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(45 * time.Second)
+	if _, err := ls.AcquireLease(ctx, taskID, "node-b", time.Minute); err != coordinator.ErrLeaseHeld {
+		t.Fatalf("expected ErrLeaseHeld acquiring a renewed lease, got %v", err)
+	}
+
+	// Once expired, another node may take the lease.
+	now = now.Add(time.Minute)
+	leases, err := ls.ListLeases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leases) != 0 {
+		t.Fatalf("expected expired lease to be omitted, got %+v", leases)
+	}
+	if _, err := ls.AcquireLease(ctx, taskID, "node-b", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	// Releasing someone else's lease does nothing.
+	if err := ls.ReleaseLease(ctx, taskID, "node-a"); err != nil {
+		t.Fatal(err)
+	}
+	leases, err = ls.ListLeases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leases) != 1 || leases[0].TaskID != taskID || leases[0].NodeID != "node-b" {
+		t.Fatalf("unexpected leases: %+v", leases)
+	}
+
+	if err := ls.ReleaseLease(ctx, taskID, "node-b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ls.AcquireLease(ctx, taskID, "node-a", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ls.RegisterNode(ctx, "node-a", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := ls.RegisterNode(ctx, "node-b", 2*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	nodes, err := ls.ListNodes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 2 {
+		t.Fatalf("expected 2 live nodes, got %v", nodes)
+	}
+
+	now = now.Add(90 * time.Second)
+	nodes, err = ls.ListNodes(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 1 || nodes[0] != "node-b" {
+		t.Fatalf("expected only node-b to be live, got %v", nodes)
+	}
+}
@@ -9,9 +9,12 @@ import (
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/lang"
+	"github.com/influxdata/platform"
 	"github.com/influxdata/platform/logger"
 	"github.com/influxdata/platform/query"
+	"github.com/influxdata/platform/storage"
 	"github.com/influxdata/platform/task/backend"
+	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
@@ -142,6 +145,11 @@ func (p *syncRunPromise) doQuery(wg *sync.WaitGroup) {
 	}
 	it, err := p.svc.Query(p.ctx, req)
 	if err != nil {
+		if p.ctx.Err() == nil && isRetryable(err) {
+			// A transient error is part of the runResult, so that the run can be retried.
+			p.finish(&runResult{err: err, retryable: true}, nil)
+			return
+		}
 		// Assume the error should not be part of the runResult.
 		p.finish(nil, err)
 		return
@@ -158,7 +166,8 @@ func (p *syncRunPromise) doQuery(wg *sync.WaitGroup) {
 	}
 
 	// Is it okay to assume it.Err will be set if the query context is canceled?
-	p.finish(&runResult{err: it.Err()}, nil)
+	err = it.Err()
+	p.finish(&runResult{err: err, retryable: isRetryable(err)}, nil)
 }
 
 func (p *syncRunPromise) cancelOnContextDone(wg *sync.WaitGroup) {
@@ -287,7 +296,8 @@ func (p *asyncRunPromise) followQuery(wg *sync.WaitGroup) {
 	case results, ok := <-p.q.Ready():
 		if !ok {
 			// Something went wrong with the flux. Set the error in the run result.
-			rr := &runResult{err: p.q.Err()}
+			err := p.q.Err()
+			rr := &runResult{err: err, retryable: isRetryable(err)}
 			p.finish(rr, nil)
 			return
 		}
@@ -339,6 +349,19 @@ var _ backend.RunResult = (*runResult)(nil)
 func (rr *runResult) Err() error        { return rr.err }
 func (rr *runResult) IsRetryable() bool { return rr.retryable }
 
+// isRetryable returns true if err is a transient query or storage error,
+// after which executing the run again may succeed.
+func isRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch errors.Cause(err) {
+	case context.DeadlineExceeded, storage.ErrEngineClosed, storage.ErrEngineMaintenance:
+		return true
+	}
+	return platform.ErrorCode(err) == platform.EUnavailable
+}
+
 // exhaustResultIterators drains all the iterators from a flux query Result.
 func exhaustResultIterators(res flux.Result) error {
 	return res.Tables().Do(func(tbl flux.Table) error {
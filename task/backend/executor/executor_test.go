@@ -18,9 +18,11 @@ import (
 	"github.com/influxdata/platform"
 	"github.com/influxdata/platform/query"
 	_ "github.com/influxdata/platform/query/builtin"
+	"github.com/influxdata/platform/storage"
 	"github.com/influxdata/platform/task/backend"
 	"github.com/influxdata/platform/task/backend/executor"
 	platformtesting "github.com/influxdata/platform/testing"
+	pkgerrors "github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
@@ -262,6 +264,7 @@ func TestExecutor(t *testing.T) {
 	for _, fn := range []createSysFn{createAsyncSystem, createSyncSystem} {
 		testExecutorQuerySuccess(t, fn)
 		testExecutorQueryFailure(t, fn)
+		testExecutorQueryRetryableFailure(t, fn)
 		testExecutorPromiseCancel(t, fn)
 		testExecutorServiceError(t, fn)
 		testExecutorWait(t, fn)
@@ -358,9 +361,50 @@ func testExecutorQueryFailure(t *testing.T, fn createSysFn) {
 		if got := res.Err(); got != expErr {
 			t.Fatalf("expected error %v; got %v", expErr, got)
 		}
+		if res.IsRetryable() {
+			t.Fatal("expected query failure not to be retryable")
+		}
 	})
 }
 
+func testExecutorQueryRetryableFailure(t *testing.T, fn createSysFn) {
+	var orgID = platformtesting.MustIDBase16("aaaaaaaaaaaaaaaa")
+	var userID = platformtesting.MustIDBase16("baaaaaaaaaaaaaab")
+	for _, expErr := range []error{
+		&platform.Error{Code: platform.EUnavailable, Msg: "forced unavailable"},
+		pkgerrors.Wrap(storage.ErrEngineClosed, "forced"),
+	} {
+		expErr := expErr
+		sys := fn()
+		t.Run(sys.name+"/QueryRetryableFail/"+expErr.Error(), func(t *testing.T) {
+			t.Parallel()
+			script := fmt.Sprintf(fmtTestScript, t.Name())
+			tid, err := sys.st.CreateTask(context.Background(), backend.CreateTaskRequest{Org: orgID, User: userID, Script: script})
+			if err != nil {
+				t.Fatal(err)
+			}
+			qr := backend.QueuedRun{TaskID: tid, RunID: platform.ID(1), Now: 123}
+			rp, err := sys.ex.Execute(context.Background(), qr)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sys.svc.WaitForQueryLive(t, script)
+			sys.svc.FailQuery(script, expErr)
+			res, err := rp.Wait()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := res.Err(); got != expErr {
+				t.Fatalf("expected error %v; got %v", expErr, got)
+			}
+			if !res.IsRetryable() {
+				t.Fatal("expected transient query failure to be retryable")
+			}
+		})
+	}
+}
+
 func testExecutorPromiseCancel(t *testing.T, fn createSysFn) {
 	var orgID = platformtesting.MustIDBase16("aaaaaaaaaaaaaaaa")
 	var userID = platformtesting.MustIDBase16("baaaaaaaaaaaaaab")
@@ -104,6 +104,9 @@ func (r *runReaderWriter) ListRuns(ctx context.Context, runFilter platform.RunFi
 		if r.ID.String() <= afterID {
 			continue
 		}
+		if !runMatchesFilter(r, runFilter) {
+			continue
+		}
 
 		// Copy the element, to avoid a data race if the original Run is modified in UpdateRunState or AddRunLog.
 		r := *r
@@ -142,12 +145,8 @@ func (r *runReaderWriter) ListLogs(ctx context.Context, logFilter platform.LogFi
 		if !ok {
 			return nil, ErrRunNotFound
 		}
-		return []platform.Log{run.Log}, nil
+		return filterRunLogs([]*platform.Run{run}, logFilter)
 	}
 
-	logs := []platform.Log{}
-	for _, run := range r.byTaskID[logFilter.Task.String()] {
-		logs = append(logs, run.Log)
-	}
-	return logs, nil
+	return filterRunLogs(r.byTaskID[logFilter.Task.String()], logFilter)
 }
@@ -57,6 +57,10 @@ func (s *inmem) CreateTask(_ context.Context, req CreateTaskRequest) (platform.I
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if err := StoreValidator.Dependencies(id, req.Org, o.DependsOn, s.findTask); err != nil {
+		return platform.InvalidID(), err
+	}
+
 	s.tasks = append(s.tasks, task)
 	s.meta[id] = NewStoreTaskMeta(req, o)
 
@@ -89,6 +93,9 @@ func (s *inmem) UpdateTask(_ context.Context, req UpdateTaskRequest) (UpdateTask
 				return res, err
 			}
 		} else {
+			if err := StoreValidator.Dependencies(req.ID, t.Org, op.DependsOn, s.findTask); err != nil {
+				return res, err
+			}
 			t.Script = req.Script
 		}
 		t.Name = op.Name
@@ -107,6 +114,12 @@ func (s *inmem) UpdateTask(_ context.Context, req UpdateTaskRequest) (UpdateTask
 	}
 	res.OldStatus = TaskStatus(stm.Status)
 
+	if req.Script != "" {
+		stm.SetDependsOn(op.DependsOn)
+		stm.MaxRetry = int32(op.Retry)
+		s.meta[req.ID] = stm
+	}
+
 	if req.Status != "" {
 		// Changing the status.
 		stm.Status = string(req.Status)
@@ -117,6 +130,18 @@ func (s *inmem) UpdateTask(_ context.Context, req UpdateTaskRequest) (UpdateTask
 	return res, nil
 }
 
+// findTask returns the organization ID and the meta of the task with the given ID.
+// s.mu must be held when calling findTask.
+func (s *inmem) findTask(id platform.ID) (platform.ID, *StoreTaskMeta, error) {
+	for _, t := range s.tasks {
+		if t.ID == id {
+			stm := s.meta[id]
+			return t.Org, &stm, nil
+		}
+	}
+	return platform.InvalidID(), nil, ErrTaskNotFound
+}
+
 func (s *inmem) ListTasks(_ context.Context, params TaskSearchParams) ([]StoreTaskWithMeta, error) {
 	if params.Org.Valid() && params.User.Valid() {
 		return nil, errors.New("ListTasks: org and user filters are mutually exclusive")
@@ -314,6 +339,56 @@ func (s *inmem) ManuallyRunTimeRange(_ context.Context, taskID platform.ID, star
 	return mr, nil
 }
 
+func (s *inmem) CancelManualRun(_ context.Context, taskID, manualRunID platform.ID) ([]platform.ID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stm, ok := s.meta[taskID]
+	if !ok {
+		return nil, ErrTaskNotFound
+	}
+
+	// Copy the queue so the stored meta isn't modified if cancellation fails.
+	stm.ManualRuns = append([]*StoreTaskMetaManualRun(nil), stm.ManualRuns...)
+	running, err := stm.CancelManualRun(manualRunID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.meta[taskID] = stm
+	return running, nil
+}
+
+func (s *inmem) QueueDependentRuns(_ context.Context, taskID platform.ID, now, requestedAt int64) ([]platform.ID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var queued []platform.ID
+	for _, t := range s.tasks {
+		stm := s.meta[t.ID]
+		if len(stm.DependsOn) == 0 {
+			continue
+		}
+
+		// Copy the tracked runs so the stored meta isn't modified if queueing fails.
+		runs := make([]*StoreTaskMetaDependencyRun, len(stm.DependencyRuns))
+		for i, r := range stm.DependencyRuns {
+			runs[i] = &StoreTaskMetaDependencyRun{Now: r.Now, Succeeded: append([]uint64(nil), r.Succeeded...)}
+		}
+		stm.DependencyRuns = runs
+
+		ok, err := stm.UpstreamSucceeded(taskID, now, requestedAt, func() (platform.ID, error) { return s.idgen.ID(), nil })
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			queued = append(queued, t.ID)
+		}
+		s.meta[t.ID] = stm
+	}
+	return queued, nil
+}
+
 func (s *inmem) delete(ctx context.Context, id platform.ID, f func(StoreTask) platform.ID) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -20,7 +20,9 @@ func NewStoreTaskMeta(req CreateTaskRequest, o options.Options) StoreTaskMeta {
 		LatestCompleted: req.ScheduleAfter,
 		EffectiveCron:   o.EffectiveCronString(),
 		Offset:          int32(o.Offset / time.Second),
+		MaxRetry:        int32(o.Retry),
 	}
+	stm.SetDependsOn(o.DependsOn)
 
 	if stm.Status == "" {
 		stm.Status = string(DefaultTaskStatus)
@@ -61,12 +63,16 @@ func (stm *StoreTaskMeta) FinishRun(runID platform.ID) bool {
 			}
 		} else {
 			// It was a requested run. Check if we need to update a latest completed.
-			for _, q := range stm.ManualRuns {
+			for j, q := range stm.ManualRuns {
 				if q.Start == rs && q.End == re && q.RequestedAt == ra {
 					// Match.
 					if runner.Now > q.LatestCompleted {
 						q.LatestCompleted = runner.Now
 					}
+					if done, running := stm.manualRunState(q); done && running == 0 {
+						// All the runs of the range have finished; drop the range.
+						stm.ManualRuns = append(stm.ManualRuns[:j], stm.ManualRuns[j+1:]...)
+					}
 					break
 				}
 			}
@@ -77,6 +83,94 @@ func (stm *StoreTaskMeta) FinishRun(runID platform.ID) bool {
 	return false
 }
 
+// SetDependsOn replaces the upstream task IDs of stm,
+// discarding the upstream runs recorded for the previous dependencies.
+func (stm *StoreTaskMeta) SetDependsOn(ids []platform.ID) {
+	stm.DependsOn = nil
+	stm.DependencyRuns = nil
+	for _, id := range ids {
+		stm.DependsOn = append(stm.DependsOn, uint64(id))
+	}
+}
+
+// DependsOnIDs returns the upstream task IDs of stm.
+func (stm *StoreTaskMeta) DependsOnIDs() []platform.ID {
+	if len(stm.DependsOn) == 0 {
+		return nil
+	}
+	ids := make([]platform.ID, len(stm.DependsOn))
+	for i, id := range stm.DependsOn {
+		ids[i] = platform.ID(id)
+	}
+	return ids
+}
+
+// maxPendingDependencyRuns is the maximum number of schedules tracked in a task's DependencyRuns,
+// for which some but not all upstream tasks have succeeded.
+const maxPendingDependencyRuns = 100
+
+// UpstreamSucceeded records that the run of the upstream task with the given ID succeeded for the Unix timestamp now.
+// Once every upstream task of stm has succeeded for now, a run for now is queued through ManuallyRunTimeRange,
+// and UpstreamSucceeded returns true.
+// If upstreamID is not one of stm's upstream tasks, UpstreamSucceeded does nothing and returns false.
+func (stm *StoreTaskMeta) UpstreamSucceeded(upstreamID platform.ID, now, requestedAt int64, makeID func() (platform.ID, error)) (bool, error) {
+	found := false
+	for _, id := range stm.DependsOn {
+		if platform.ID(id) == upstreamID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return false, nil
+	}
+
+	var dr *StoreTaskMetaDependencyRun
+	idx := -1
+	for i, r := range stm.DependencyRuns {
+		if r.Now == now {
+			dr, idx = r, i
+			break
+		}
+	}
+	if dr == nil {
+		dr = &StoreTaskMetaDependencyRun{Now: now}
+		idx = len(stm.DependencyRuns)
+		stm.DependencyRuns = append(stm.DependencyRuns, dr)
+	}
+
+	recorded := false
+	for _, id := range dr.Succeeded {
+		if platform.ID(id) == upstreamID {
+			recorded = true
+			break
+		}
+	}
+	if !recorded {
+		dr.Succeeded = append(dr.Succeeded, uint64(upstreamID))
+	}
+
+	if len(dr.Succeeded) < len(stm.DependsOn) {
+		if len(stm.DependencyRuns) > maxPendingDependencyRuns {
+			// Some upstream task probably failed for an earlier now; stop waiting on the oldest one.
+			oldest := 0
+			for i, r := range stm.DependencyRuns {
+				if r.Now < stm.DependencyRuns[oldest].Now {
+					oldest = i
+				}
+			}
+			stm.DependencyRuns = append(stm.DependencyRuns[:oldest], stm.DependencyRuns[oldest+1:]...)
+		}
+		return false, nil
+	}
+
+	if err := stm.ManuallyRunTimeRange(now, now, requestedAt, makeID); err != nil {
+		return false, err
+	}
+	stm.DependencyRuns = append(stm.DependencyRuns[:idx], stm.DependencyRuns[idx+1:]...)
+	return true, nil
+}
+
 // CreateNextRun attempts to update stm's CurrentlyRunning slice with a new run.
 // The new run's now is assigned the earliest possible time according to stm.EffectiveCron,
 // that is later than any in-progress run and stm's LatestCompleted timestamp.
@@ -89,6 +183,14 @@ func (stm *StoreTaskMeta) CreateNextRun(now int64, makeID func() (platform.ID, e
 		return RunCreation{}, errors.New("cannot create next run when max concurrency already reached")
 	}
 
+	if len(stm.DependsOn) > 0 {
+		// Tasks with dependencies are never due on a schedule; they only run from their queue.
+		if stm.HasPendingManualRuns() {
+			return stm.createNextRunFromQueue(now, math.MaxInt64, nil, makeID)
+		}
+		return RunCreation{}, RunNotYetDueError{DueAt: math.MaxInt64}
+	}
+
 	// Not calling stm.DueAt here because we reuse sch.
 	// We can definitely optimize (minimize) cron parsing at a later point in time.
 	sch, err := cron.Parse(stm.EffectiveCron)
@@ -107,7 +209,7 @@ func (stm *StoreTaskMeta) CreateNextRun(now int64, makeID func() (platform.ID, e
 	nextScheduledUnix := nextScheduled.Unix()
 	if dueAt := nextScheduledUnix + int64(stm.Offset); dueAt > now {
 		// Can't schedule yet.
-		if len(stm.ManualRuns) > 0 {
+		if stm.HasPendingManualRuns() {
 			return stm.createNextRunFromQueue(now, dueAt, sch, makeID)
 		}
 		return RunCreation{}, RunNotYetDueError{DueAt: dueAt}
@@ -130,18 +232,53 @@ func (stm *StoreTaskMeta) CreateNextRun(now int64, makeID func() (platform.ID, e
 			Now:   nextScheduledUnix,
 		},
 		NextDue:  sch.Next(nextScheduled).Unix() + int64(stm.Offset),
-		HasQueue: len(stm.ManualRuns) > 0,
+		HasQueue: stm.HasPendingManualRuns(),
 	}, nil
 }
 
+// HasPendingManualRuns reports whether any of stm's manual run ranges still has runs to be created.
+func (stm *StoreTaskMeta) HasPendingManualRuns() bool {
+	return stm.pendingManualRun() != nil
+}
+
+// pendingManualRun returns the first of stm's manual run ranges that still has runs to be created, or nil if there is none.
+// Ranges whose runs have all been created are kept until those runs finish,
+// so that their progress can be reported and they can be canceled.
+func (stm *StoreTaskMeta) pendingManualRun() *StoreTaskMetaManualRun {
+	for _, q := range stm.ManualRuns {
+		if done, _ := stm.manualRunState(q); !done {
+			return q
+		}
+	}
+	return nil
+}
+
+// manualRunState reports whether all the runs of the manual run range q have been created,
+// and how many of them are in progress.
+func (stm *StoreTaskMeta) manualRunState(q *StoreTaskMetaManualRun) (done bool, running int) {
+	latest := q.LatestCompleted
+	for _, r := range stm.CurrentlyRunning {
+		if r.RangeStart != q.Start || r.RangeEnd != q.End || r.RequestedAt != q.RequestedAt {
+			// Doesn't match our queue.
+			continue
+		}
+		running++
+		if r.Now > latest {
+			latest = r.Now
+		}
+	}
+	return latest >= q.End, running
+}
+
 // createNextRunFromQueue creates the next run from a queue.
-// This should only be called when the queue is not empty.
+// This should only be called when the queue has a pending range.
+// If sch is nil, the task has no schedule, and the run is created for the end of the queued range.
 func (stm *StoreTaskMeta) createNextRunFromQueue(now, nextDue int64, sch cron.Schedule, makeID func() (platform.ID, error)) (RunCreation, error) {
-	if len(stm.ManualRuns) == 0 {
+	q := stm.pendingManualRun()
+	if q == nil {
 		return RunCreation{}, errors.New("cannot create run from empty queue")
 	}
 
-	q := stm.ManualRuns[0]
 	latest := q.LatestCompleted
 	for _, r := range stm.CurrentlyRunning {
 		if r.RangeStart != q.Start || r.RangeEnd != q.End || r.RequestedAt != q.RequestedAt {
@@ -153,7 +290,10 @@ func (stm *StoreTaskMeta) createNextRunFromQueue(now, nextDue int64, sch cron.Sc
 		}
 	}
 
-	runNow := sch.Next(time.Unix(latest, 0)).Unix()
+	runNow := q.End
+	if sch != nil {
+		runNow = sch.Next(time.Unix(latest, 0)).Unix()
+	}
 
 	// Already validated that we have room to create another run, in CreateNextRun.
 	id := platform.ID(q.RunID)
@@ -174,13 +314,9 @@ func (stm *StoreTaskMeta) createNextRunFromQueue(now, nextDue int64, sch cron.Sc
 		RangeStart:  q.Start,
 		RangeEnd:    q.End,
 		RequestedAt: q.RequestedAt,
+		ManualRunID: q.ID,
 	})
 
-	if runNow >= q.End {
-		// Drop the queue.
-		stm.ManualRuns = append(stm.ManualRuns[:0], stm.ManualRuns[1:]...)
-	}
-
 	return RunCreation{
 		Created: QueuedRun{
 			RunID:       id,
@@ -188,13 +324,19 @@ func (stm *StoreTaskMeta) createNextRunFromQueue(now, nextDue int64, sch cron.Sc
 			RequestedAt: q.RequestedAt,
 		},
 		NextDue:  nextDue,
-		HasQueue: len(stm.ManualRuns) > 0,
+		HasQueue: stm.HasPendingManualRuns(),
 	}, nil
 }
 
 // NextDueRun returns the Unix timestamp of when the next call to CreateNextRun will be ready.
 // The returned timestamp reflects the task's delay, so it does not necessarily exactly match the schedule time.
+// For a task with dependencies, the returned timestamp is math.MaxInt64.
 func (stm *StoreTaskMeta) NextDueRun() (int64, error) {
+	if len(stm.DependsOn) > 0 {
+		// Never due on a schedule.
+		return math.MaxInt64, nil
+	}
+
 	sch, err := cron.Parse(stm.EffectiveCron)
 	if err != nil {
 		return 0, err
@@ -244,17 +386,122 @@ func (stm *StoreTaskMeta) ManuallyRunTimeRange(start, end, requestedAt int64, ma
 		LatestCompleted: lc,
 		RequestedAt:     requestedAt,
 	}
-	if start == end && makeID != nil {
+	if makeID != nil {
 		id, err := makeID()
 		if err != nil {
 			return err
 		}
-		run.RunID = uint64(id)
+		run.ID = uint64(id)
+		if start == end {
+			run.RunID = uint64(id)
+		}
 	}
 	stm.ManualRuns = append(stm.ManualRuns, run)
 	return nil
 }
 
+// ManualRunProgress describes how far along a queued manual run range is.
+type ManualRunProgress struct {
+	ID          platform.ID
+	Start, End  int64
+	RequestedAt int64
+
+	// Completed is the number of runs from the range that have finished.
+	Completed int
+	// Remaining is the number of runs from the range that are in progress or not yet created.
+	Remaining int
+}
+
+// ManualRunProgress reports the progress of each of stm's queued manual run ranges.
+// Ranges are reported until all of their runs have finished.
+func (stm *StoreTaskMeta) ManualRunProgress() ([]ManualRunProgress, error) {
+	if len(stm.ManualRuns) == 0 {
+		return nil, nil
+	}
+
+	var sch cron.Schedule
+	if len(stm.DependsOn) == 0 {
+		var err error
+		sch, err = cron.Parse(stm.EffectiveCron)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]ManualRunProgress, 0, len(stm.ManualRuns))
+	for _, q := range stm.ManualRuns {
+		p := ManualRunProgress{
+			ID:          platform.ID(q.ID),
+			Start:       q.Start,
+			End:         q.End,
+			RequestedAt: q.RequestedAt,
+		}
+
+		latest := q.LatestCompleted
+		running := 0
+		for _, r := range stm.CurrentlyRunning {
+			if r.ManualRunID != q.ID || r.RangeStart != q.Start || r.RangeEnd != q.End || r.RequestedAt != q.RequestedAt {
+				continue
+			}
+			running++
+			if r.Now > latest {
+				latest = r.Now
+			}
+		}
+
+		total := countManualSchedules(sch, q.Start-1, q.End)
+		p.Remaining = countManualSchedules(sch, latest, q.End) + running
+		p.Completed = total - p.Remaining
+		if p.Completed < 0 {
+			p.Completed = 0
+		}
+		out = append(out, p)
+	}
+	return out, nil
+}
+
+// CancelManualRun removes the queued manual run range identified by id,
+// and returns the IDs of the runs created from that range that are still in progress.
+// If no queued range matches id, CancelManualRun returns ErrManualRunNotFound.
+func (stm *StoreTaskMeta) CancelManualRun(id platform.ID) ([]platform.ID, error) {
+	idx := -1
+	for i, q := range stm.ManualRuns {
+		if platform.ID(q.ID) == id {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return nil, ErrManualRunNotFound
+	}
+	stm.ManualRuns = append(stm.ManualRuns[:idx], stm.ManualRuns[idx+1:]...)
+
+	var running []platform.ID
+	for _, r := range stm.CurrentlyRunning {
+		if platform.ID(r.ManualRunID) == id {
+			running = append(running, platform.ID(r.RunID))
+		}
+	}
+	return running, nil
+}
+
+// countManualSchedules returns the number of times in sch after the Unix timestamp after, up to and including end.
+// If sch is nil, the range is run exactly once, at its end.
+func countManualSchedules(sch cron.Schedule, after, end int64) int {
+	if sch == nil {
+		if after < end {
+			return 1
+		}
+		return 0
+	}
+
+	n := 0
+	for t := sch.Next(time.Unix(after, 0)); !t.IsZero() && t.Unix() <= end; t = sch.Next(t) {
+		n++
+	}
+	return n
+}
+
 // Equal returns true if all of stm's fields compare equal to other.
 // Note that this method operates on values, unlike the other methods which operate on pointers.
 //
@@ -265,11 +512,20 @@ func (stm StoreTaskMeta) Equal(other StoreTaskMeta) bool {
 		stm.Status != other.Status ||
 		stm.EffectiveCron != other.EffectiveCron ||
 		stm.Offset != other.Offset ||
+		stm.MaxRetry != other.MaxRetry ||
+		len(stm.DependsOn) != len(other.DependsOn) ||
 		len(stm.CurrentlyRunning) != len(other.CurrentlyRunning) ||
-		len(stm.ManualRuns) != len(other.ManualRuns) {
+		len(stm.ManualRuns) != len(other.ManualRuns) ||
+		len(stm.DependencyRuns) != len(other.DependencyRuns) {
 		return false
 	}
 
+	for i, o := range other.DependsOn {
+		if stm.DependsOn[i] != o {
+			return false
+		}
+	}
+
 	for i, o := range other.CurrentlyRunning {
 		s := stm.CurrentlyRunning[i]
 
@@ -278,7 +534,8 @@ func (stm StoreTaskMeta) Equal(other StoreTaskMeta) bool {
 			s.RunID != o.RunID ||
 			s.RangeStart != o.RangeStart ||
 			s.RangeEnd != o.RangeEnd ||
-			s.RequestedAt != o.RequestedAt {
+			s.RequestedAt != o.RequestedAt ||
+			s.ManualRunID != o.ManualRunID {
 			return false
 		}
 	}
@@ -289,10 +546,24 @@ func (stm StoreTaskMeta) Equal(other StoreTaskMeta) bool {
 		if s.Start != o.Start ||
 			s.End != o.End ||
 			s.LatestCompleted != o.LatestCompleted ||
-			s.RequestedAt != o.RequestedAt {
+			s.RequestedAt != o.RequestedAt ||
+			s.ID != o.ID {
 			return false
 		}
 	}
 
+	for i, o := range other.DependencyRuns {
+		s := stm.DependencyRuns[i]
+
+		if s.Now != o.Now || len(s.Succeeded) != len(o.Succeeded) {
+			return false
+		}
+		for j, id := range o.Succeeded {
+			if s.Succeeded[j] != id {
+				return false
+			}
+		}
+	}
+
 	return true
 }
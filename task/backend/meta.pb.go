@@ -35,17 +35,24 @@ type StoreTaskMeta struct {
 	// effective_cron is the effective cron string as reported by the task's options.
 	EffectiveCron string `protobuf:"bytes,5,opt,name=effective_cron,json=effectiveCron,proto3" json:"effective_cron,omitempty"`
 	// Task's configured delay, in seconds.
-	Offset               int32                     `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
-	ManualRuns           []*StoreTaskMetaManualRun `protobuf:"bytes,16,rep,name=manual_runs,json=manualRuns" json:"manual_runs,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
-	XXX_sizecache        int32                     `json:"-"`
+	Offset int32 `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
+	// max_retry is the maximum number of attempts for a single run, as reported by the task's retry option.
+	MaxRetry int32 `protobuf:"varint,7,opt,name=max_retry,json=maxRetry,proto3" json:"max_retry,omitempty"`
+	// depends_on is the list of upstream task IDs, as reported by the task's dependsOn option.
+	// A task with dependencies is never run on a schedule; it only runs when its upstream tasks succeed.
+	DependsOn  []uint64                  `protobuf:"varint,8,rep,packed,name=depends_on,json=dependsOn" json:"depends_on,omitempty"`
+	ManualRuns []*StoreTaskMetaManualRun `protobuf:"bytes,16,rep,name=manual_runs,json=manualRuns" json:"manual_runs,omitempty"`
+	// dependency_runs tracks the schedules for which some, but not all, of the task's upstream tasks have succeeded.
+	DependencyRuns       []*StoreTaskMetaDependencyRun `protobuf:"bytes,17,rep,name=dependency_runs,json=dependencyRuns" json:"dependency_runs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                      `json:"-"`
+	XXX_sizecache        int32                         `json:"-"`
 }
 
 func (m *StoreTaskMeta) Reset()         { *m = StoreTaskMeta{} }
 func (m *StoreTaskMeta) String() string { return proto.CompactTextString(m) }
 func (*StoreTaskMeta) ProtoMessage()    {}
 func (*StoreTaskMeta) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meta_d42b29c328506298, []int{0}
+	return fileDescriptor_meta_d1754233ef260615, []int{0}
 }
 func (m *StoreTaskMeta) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -116,6 +123,20 @@ func (m *StoreTaskMeta) GetOffset() int32 {
 	return 0
 }
 
+func (m *StoreTaskMeta) GetMaxRetry() int32 {
+	if m != nil {
+		return m.MaxRetry
+	}
+	return 0
+}
+
+func (m *StoreTaskMeta) GetDependsOn() []uint64 {
+	if m != nil {
+		return m.DependsOn
+	}
+	return nil
+}
+
 func (m *StoreTaskMeta) GetManualRuns() []*StoreTaskMetaManualRun {
 	if m != nil {
 		return m.ManualRuns
@@ -123,6 +144,13 @@ func (m *StoreTaskMeta) GetManualRuns() []*StoreTaskMetaManualRun {
 	return nil
 }
 
+func (m *StoreTaskMeta) GetDependencyRuns() []*StoreTaskMetaDependencyRun {
+	if m != nil {
+		return m.DependencyRuns
+	}
+	return nil
+}
+
 type StoreTaskMetaRun struct {
 	// now is the unix timestamp of the "now" value for the run.
 	Now   int64  `protobuf:"varint,1,opt,name=now,proto3" json:"now,omitempty"`
@@ -134,7 +162,9 @@ type StoreTaskMetaRun struct {
 	RangeEnd int64 `protobuf:"varint,5,opt,name=range_end,json=rangeEnd,proto3" json:"range_end,omitempty"`
 	// requested_at is the unix timestamp indicating when this run was requested.
 	// It is the same value as the "parent" StoreTaskMetaManualRun, if this run was the result of a manual request.
-	RequestedAt          int64    `protobuf:"varint,6,opt,name=requested_at,json=requestedAt,proto3" json:"requested_at,omitempty"`
+	RequestedAt int64 `protobuf:"varint,6,opt,name=requested_at,json=requestedAt,proto3" json:"requested_at,omitempty"`
+	// manual_run_id is the ID of the "parent" StoreTaskMetaManualRun, if this run was the result of a manual request.
+	ManualRunID          uint64   `protobuf:"varint,7,opt,name=manual_run_id,json=manualRunId,proto3" json:"manual_run_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
@@ -143,7 +173,7 @@ func (m *StoreTaskMetaRun) Reset()         { *m = StoreTaskMetaRun{} }
 func (m *StoreTaskMetaRun) String() string { return proto.CompactTextString(m) }
 func (*StoreTaskMetaRun) ProtoMessage()    {}
 func (*StoreTaskMetaRun) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meta_d42b29c328506298, []int{1}
+	return fileDescriptor_meta_d1754233ef260615, []int{1}
 }
 func (m *StoreTaskMetaRun) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -214,6 +244,13 @@ func (m *StoreTaskMetaRun) GetRequestedAt() int64 {
 	return 0
 }
 
+func (m *StoreTaskMetaRun) GetManualRunID() uint64 {
+	if m != nil {
+		return m.ManualRunID
+	}
+	return 0
+}
+
 // StoreTaskMetaManualRun indicates a manually requested run for a time range.
 // It has a start and end pair of unix timestamps indicating the time range covered by the request.
 type StoreTaskMetaManualRun struct {
@@ -226,7 +263,9 @@ type StoreTaskMetaManualRun struct {
 	// requested_at is the unix timestamp indicating when this run was requested.
 	RequestedAt int64 `protobuf:"varint,4,opt,name=requested_at,json=requestedAt,proto3" json:"requested_at,omitempty"`
 	// run_id is set ahead of time for retries of individual runs. Manually run time ranges do not receive an ID.
-	RunID                uint64   `protobuf:"varint,5,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	RunID uint64 `protobuf:"varint,5,opt,name=run_id,json=runId,proto3" json:"run_id,omitempty"`
+	// id identifies the request as a whole, so that its progress can be reported and it can be canceled as a unit.
+	ID                   uint64   `protobuf:"varint,6,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
@@ -235,7 +274,7 @@ func (m *StoreTaskMetaManualRun) Reset()         { *m = StoreTaskMetaManualRun{}
 func (m *StoreTaskMetaManualRun) String() string { return proto.CompactTextString(m) }
 func (*StoreTaskMetaManualRun) ProtoMessage()    {}
 func (*StoreTaskMetaManualRun) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meta_d42b29c328506298, []int{2}
+	return fileDescriptor_meta_d1754233ef260615, []int{2}
 }
 func (m *StoreTaskMetaManualRun) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -299,10 +338,75 @@ func (m *StoreTaskMetaManualRun) GetRunID() uint64 {
 	return 0
 }
 
+func (m *StoreTaskMetaManualRun) GetID() uint64 {
+	if m != nil {
+		return m.ID
+	}
+	return 0
+}
+
+// StoreTaskMetaDependencyRun records which upstream tasks have succeeded for a single schedule of a task with dependencies.
+type StoreTaskMetaDependencyRun struct {
+	// now is the unix timestamp of the "now" value of the upstream runs.
+	Now int64 `protobuf:"varint,1,opt,name=now,proto3" json:"now,omitempty"`
+	// succeeded is the list of upstream task IDs whose run for now succeeded.
+	Succeeded            []uint64 `protobuf:"varint,2,rep,packed,name=succeeded" json:"succeeded,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StoreTaskMetaDependencyRun) Reset()         { *m = StoreTaskMetaDependencyRun{} }
+func (m *StoreTaskMetaDependencyRun) String() string { return proto.CompactTextString(m) }
+func (*StoreTaskMetaDependencyRun) ProtoMessage()    {}
+func (*StoreTaskMetaDependencyRun) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meta_d1754233ef260615, []int{3}
+}
+func (m *StoreTaskMetaDependencyRun) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *StoreTaskMetaDependencyRun) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_StoreTaskMetaDependencyRun.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalTo(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (dst *StoreTaskMetaDependencyRun) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StoreTaskMetaDependencyRun.Merge(dst, src)
+}
+func (m *StoreTaskMetaDependencyRun) XXX_Size() int {
+	return m.Size()
+}
+func (m *StoreTaskMetaDependencyRun) XXX_DiscardUnknown() {
+	xxx_messageInfo_StoreTaskMetaDependencyRun.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StoreTaskMetaDependencyRun proto.InternalMessageInfo
+
+func (m *StoreTaskMetaDependencyRun) GetNow() int64 {
+	if m != nil {
+		return m.Now
+	}
+	return 0
+}
+
+func (m *StoreTaskMetaDependencyRun) GetSucceeded() []uint64 {
+	if m != nil {
+		return m.Succeeded
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*StoreTaskMeta)(nil), "com.influxdata.platform.task.backend.StoreTaskMeta")
 	proto.RegisterType((*StoreTaskMetaRun)(nil), "com.influxdata.platform.task.backend.StoreTaskMetaRun")
 	proto.RegisterType((*StoreTaskMetaManualRun)(nil), "com.influxdata.platform.task.backend.StoreTaskMetaManualRun")
+	proto.RegisterType((*StoreTaskMetaDependencyRun)(nil), "com.influxdata.platform.task.backend.StoreTaskMetaDependencyRun")
 }
 func (m *StoreTaskMeta) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
@@ -358,6 +462,28 @@ func (m *StoreTaskMeta) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintMeta(dAtA, i, uint64(m.Offset))
 	}
+	if m.MaxRetry != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(m.MaxRetry))
+	}
+	if len(m.DependsOn) > 0 {
+		dAtA2 := make([]byte, len(m.DependsOn)*10)
+		var j1 int
+		for _, num := range m.DependsOn {
+			for num >= 1<<7 {
+				dAtA2[j1] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j1++
+			}
+			dAtA2[j1] = uint8(num)
+			j1++
+		}
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(j1))
+		i += copy(dAtA[i:], dAtA2[:j1])
+	}
 	if len(m.ManualRuns) > 0 {
 		for _, msg := range m.ManualRuns {
 			dAtA[i] = 0x82
@@ -372,6 +498,20 @@ func (m *StoreTaskMeta) MarshalTo(dAtA []byte) (int, error) {
 			i += n
 		}
 	}
+	if len(m.DependencyRuns) > 0 {
+		for _, msg := range m.DependencyRuns {
+			dAtA[i] = 0x8a
+			i++
+			dAtA[i] = 0x1
+			i++
+			i = encodeVarintMeta(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
 	return i, nil
 }
 
@@ -420,6 +560,11 @@ func (m *StoreTaskMetaRun) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintMeta(dAtA, i, uint64(m.RequestedAt))
 	}
+	if m.ManualRunID != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(m.ManualRunID))
+	}
 	return i, nil
 }
 
@@ -463,6 +608,51 @@ func (m *StoreTaskMetaManualRun) MarshalTo(dAtA []byte) (int, error) {
 		i++
 		i = encodeVarintMeta(dAtA, i, uint64(m.RunID))
 	}
+	if m.ID != 0 {
+		dAtA[i] = 0x30
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(m.ID))
+	}
+	return i, nil
+}
+
+func (m *StoreTaskMetaDependencyRun) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StoreTaskMetaDependencyRun) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Now != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(m.Now))
+	}
+	if len(m.Succeeded) > 0 {
+		dAtA4 := make([]byte, len(m.Succeeded)*10)
+		var j3 int
+		for _, num := range m.Succeeded {
+			for num >= 1<<7 {
+				dAtA4[j3] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j3++
+			}
+			dAtA4[j3] = uint8(num)
+			j3++
+		}
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintMeta(dAtA, i, uint64(j3))
+		i += copy(dAtA[i:], dAtA4[:j3])
+	}
 	return i, nil
 }
 
@@ -501,12 +691,28 @@ func (m *StoreTaskMeta) Size() (n int) {
 	if m.Offset != 0 {
 		n += 1 + sovMeta(uint64(m.Offset))
 	}
+	if m.MaxRetry != 0 {
+		n += 1 + sovMeta(uint64(m.MaxRetry))
+	}
+	if len(m.DependsOn) > 0 {
+		l = 0
+		for _, e := range m.DependsOn {
+			l += sovMeta(uint64(e))
+		}
+		n += 1 + sovMeta(uint64(l)) + l
+	}
 	if len(m.ManualRuns) > 0 {
 		for _, e := range m.ManualRuns {
 			l = e.Size()
 			n += 2 + l + sovMeta(uint64(l))
 		}
 	}
+	if len(m.DependencyRuns) > 0 {
+		for _, e := range m.DependencyRuns {
+			l = e.Size()
+			n += 2 + l + sovMeta(uint64(l))
+		}
+	}
 	return n
 }
 
@@ -531,6 +737,9 @@ func (m *StoreTaskMetaRun) Size() (n int) {
 	if m.RequestedAt != 0 {
 		n += 1 + sovMeta(uint64(m.RequestedAt))
 	}
+	if m.ManualRunID != 0 {
+		n += 1 + sovMeta(uint64(m.ManualRunID))
+	}
 	return n
 }
 
@@ -552,6 +761,25 @@ func (m *StoreTaskMetaManualRun) Size() (n int) {
 	if m.RunID != 0 {
 		n += 1 + sovMeta(uint64(m.RunID))
 	}
+	if m.ID != 0 {
+		n += 1 + sovMeta(uint64(m.ID))
+	}
+	return n
+}
+
+func (m *StoreTaskMetaDependencyRun) Size() (n int) {
+	var l int
+	_ = l
+	if m.Now != 0 {
+		n += 1 + sovMeta(uint64(m.Now))
+	}
+	if len(m.Succeeded) > 0 {
+		l = 0
+		for _, e := range m.Succeeded {
+			l += sovMeta(uint64(e))
+		}
+		n += 1 + sovMeta(uint64(l)) + l
+	}
 	return n
 }
 
@@ -743,6 +971,87 @@ func (m *StoreTaskMeta) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxRetry", wireType)
+			}
+			m.MaxRetry = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxRetry |= (int32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMeta
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.DependsOn = append(m.DependsOn, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMeta
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMeta
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMeta
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.DependsOn = append(m.DependsOn, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field DependsOn", wireType)
+			}
 		case 16:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field ManualRuns", wireType)
@@ -774,6 +1083,37 @@ func (m *StoreTaskMeta) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DependencyRuns", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthMeta
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.DependencyRuns = append(m.DependencyRuns, &StoreTaskMetaDependencyRun{})
+			if err := m.DependencyRuns[len(m.DependencyRuns)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMeta(dAtA[iNdEx:])
@@ -938,6 +1278,25 @@ func (m *StoreTaskMetaRun) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ManualRunID", wireType)
+			}
+			m.ManualRunID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ManualRunID |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMeta(dAtA[iNdEx:])
@@ -1083,6 +1442,156 @@ func (m *StoreTaskMetaManualRun) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ID", wireType)
+			}
+			m.ID = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ID |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipMeta(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthMeta
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StoreTaskMetaDependencyRun) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowMeta
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StoreTaskMetaDependencyRun: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StoreTaskMetaDependencyRun: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Now", wireType)
+			}
+			m.Now = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowMeta
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Now |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType == 0 {
+				var v uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMeta
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Succeeded = append(m.Succeeded, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowMeta
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= (int(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthMeta
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				for iNdEx < postIndex {
+					var v uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowMeta
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Succeeded = append(m.Succeeded, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Succeeded", wireType)
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipMeta(dAtA[iNdEx:])
@@ -1209,38 +1718,46 @@ var (
 	ErrIntOverflowMeta   = fmt.Errorf("proto: integer overflow")
 )
 
-func init() { proto.RegisterFile("meta.proto", fileDescriptor_meta_d42b29c328506298) }
+func init() { proto.RegisterFile("meta.proto", fileDescriptor_meta_d1754233ef260615) }
 
-var fileDescriptor_meta_d42b29c328506298 = []byte{
-	// 474 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x93, 0xc1, 0x6e, 0x13, 0x31,
-	0x10, 0x86, 0xd9, 0x6e, 0x36, 0x90, 0x09, 0x69, 0x17, 0xab, 0xaa, 0x16, 0x90, 0xd2, 0x25, 0x02,
-	0x11, 0x2e, 0x8b, 0x04, 0x12, 0x27, 0x2e, 0x34, 0x70, 0xe8, 0xa1, 0x17, 0x97, 0x13, 0x12, 0x5a,
-	0xb9, 0x5e, 0x6f, 0x14, 0x65, 0xd7, 0x2e, 0xf6, 0x18, 0x92, 0xb7, 0xe0, 0x51, 0xb8, 0xf2, 0x06,
-	0x1c, 0x79, 0x02, 0x84, 0xc2, 0x7b, 0x20, 0x64, 0x3b, 0x0d, 0x50, 0x72, 0x40, 0xbd, 0xcd, 0x7c,
-	0x5a, 0x8f, 0xff, 0xff, 0xdf, 0x31, 0x40, 0x2b, 0x90, 0x15, 0xe7, 0x5a, 0xa1, 0x22, 0xf7, 0xb9,
-	0x6a, 0x8b, 0x99, 0xac, 0x1b, 0xbb, 0xa8, 0x98, 0xa3, 0x0d, 0xc3, 0x5a, 0xe9, 0xb6, 0x40, 0x66,
-	0xe6, 0xc5, 0x19, 0xe3, 0x73, 0x21, 0xab, 0x3b, 0xfb, 0x53, 0x35, 0x55, 0xfe, 0xc0, 0x63, 0x57,
-	0x85, 0xb3, 0xa3, 0x9f, 0x3b, 0x30, 0x38, 0x45, 0xa5, 0xc5, 0x6b, 0x66, 0xe6, 0x27, 0x02, 0x19,
-	0x79, 0x08, 0x7b, 0x2d, 0x5b, 0x94, 0x5c, 0x49, 0x6e, 0xb5, 0x16, 0x92, 0x2f, 0xb3, 0x28, 0x8f,
-	0xc6, 0x09, 0xdd, 0x6d, 0xd9, 0x62, 0xf2, 0x9b, 0x92, 0x47, 0x90, 0x36, 0x0c, 0x85, 0xc1, 0x92,
-	0xab, 0xf6, 0xbc, 0x11, 0x28, 0xaa, 0x6c, 0x27, 0x8f, 0xc6, 0x31, 0xdd, 0x0b, 0x7c, 0x72, 0x81,
-	0xc9, 0x01, 0x74, 0x0d, 0x32, 0xb4, 0x26, 0x8b, 0xf3, 0x68, 0xdc, 0xa3, 0xeb, 0x8e, 0x70, 0xb8,
-	0x15, 0xc6, 0x61, 0xb3, 0x2c, 0xb5, 0x95, 0x72, 0x26, 0xa7, 0x59, 0x27, 0x8f, 0xc7, 0xfd, 0x27,
-	0xcf, 0x8a, 0xff, 0x71, 0x55, 0xfc, 0xa5, 0x9d, 0x5a, 0x49, 0xd3, 0xcd, 0x40, 0x1a, 0xe6, 0x91,
-	0x07, 0xb0, 0x2b, 0xea, 0x5a, 0x70, 0x9c, 0xbd, 0x17, 0x25, 0xd7, 0x4a, 0x66, 0x89, 0x17, 0x31,
-	0xd8, 0xd0, 0x89, 0x56, 0xd2, 0x69, 0x54, 0x75, 0x6d, 0x04, 0x66, 0x5d, 0x6f, 0x77, 0xdd, 0x91,
-	0xb7, 0xd0, 0x6f, 0x99, 0xb4, 0xac, 0x71, 0x02, 0x4d, 0x96, 0x7a, 0x75, 0xcf, 0xaf, 0xa0, 0xee,
-	0xc4, 0x4f, 0x71, 0x1a, 0xa1, 0xbd, 0x28, 0xcd, 0xe8, 0x73, 0x04, 0xe9, 0x65, 0x13, 0x24, 0x85,
-	0x58, 0xaa, 0x0f, 0x3e, 0xf7, 0x98, 0xba, 0xd2, 0x11, 0xd4, 0x4b, 0x9f, 0xef, 0x80, 0xba, 0x92,
-	0xe4, 0xd0, 0xd5, 0x56, 0x96, 0xb3, 0xca, 0x67, 0xda, 0x39, 0xea, 0xad, 0xbe, 0x1d, 0x26, 0xd4,
-	0xca, 0xe3, 0x97, 0x34, 0xd1, 0x56, 0x1e, 0x57, 0xe4, 0x10, 0xfa, 0x9a, 0xc9, 0xa9, 0x28, 0x0d,
-	0x32, 0x8d, 0x59, 0xc7, 0x4f, 0x03, 0x8f, 0x4e, 0x1d, 0x21, 0x77, 0xa1, 0x17, 0x3e, 0x10, 0xb2,
-	0xf2, 0xa1, 0xc4, 0xf4, 0x86, 0x07, 0xaf, 0x64, 0x45, 0xee, 0xc1, 0x4d, 0x2d, 0xde, 0x59, 0x61,
-	0x50, 0x54, 0x25, 0x0b, 0xa9, 0xc4, 0xb4, 0xbf, 0x61, 0x2f, 0x70, 0xf4, 0x29, 0x82, 0x83, 0xed,
-	0x16, 0xc9, 0x3e, 0x24, 0xe1, 0xd6, 0xe0, 0x21, 0x34, 0xce, 0x85, 0xbb, 0x2a, 0x6c, 0x89, 0x2b,
-	0xb7, 0x2e, 0x51, 0xbc, 0x7d, 0x89, 0x2e, 0x0b, 0xea, 0xfc, 0x23, 0xe8, 0x8f, 0x4c, 0x92, 0xed,
-	0x99, 0x1c, 0xdd, 0xfe, 0xb2, 0x1a, 0x46, 0x5f, 0x57, 0xc3, 0xe8, 0xfb, 0x6a, 0x18, 0x7d, 0xfc,
-	0x31, 0xbc, 0xf6, 0xe6, 0xfa, 0xfa, 0x67, 0x9d, 0x75, 0xfd, 0x8b, 0x78, 0xfa, 0x2b, 0x00, 0x00,
-	0xff, 0xff, 0x93, 0x87, 0xe2, 0xd0, 0x5b, 0x03, 0x00, 0x00,
+var fileDescriptor_meta_d1754233ef260615 = []byte{
+	// 598 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0xc1, 0x6e, 0xd3, 0x4c,
+	0x10, 0xfe, 0x1d, 0x3b, 0x69, 0x33, 0xf9, 0xd3, 0xa4, 0xab, 0xaa, 0x32, 0x05, 0x92, 0x10, 0x81,
+	0x08, 0x17, 0x23, 0x51, 0x89, 0x13, 0x07, 0x68, 0xc3, 0x21, 0x12, 0x15, 0xd2, 0x96, 0x13, 0x12,
+	0xb2, 0xb6, 0xde, 0x75, 0x64, 0xd5, 0xde, 0x2d, 0xbb, 0x6b, 0x48, 0xdf, 0x82, 0x97, 0xe0, 0x5d,
+	0x7a, 0xe4, 0x09, 0x2a, 0x64, 0xee, 0x3c, 0x03, 0xda, 0xdd, 0xd4, 0x81, 0x92, 0x4a, 0xa8, 0xb7,
+	0x99, 0x2f, 0xd9, 0x99, 0xf9, 0xbe, 0x6f, 0xc6, 0x00, 0x05, 0xd3, 0x24, 0x3a, 0x93, 0x42, 0x0b,
+	0xf4, 0x30, 0x11, 0x45, 0x94, 0xf1, 0x34, 0x2f, 0x17, 0x94, 0x18, 0x34, 0x27, 0x3a, 0x15, 0xb2,
+	0x88, 0x34, 0x51, 0xa7, 0xd1, 0x09, 0x49, 0x4e, 0x19, 0xa7, 0x7b, 0x3b, 0x73, 0x31, 0x17, 0xf6,
+	0xc1, 0x53, 0x13, 0xb9, 0xb7, 0xe3, 0xaf, 0x01, 0x74, 0x8f, 0xb5, 0x90, 0xec, 0x1d, 0x51, 0xa7,
+	0x47, 0x4c, 0x13, 0xf4, 0x18, 0x7a, 0x05, 0x59, 0xc4, 0x89, 0xe0, 0x49, 0x29, 0x25, 0xe3, 0xc9,
+	0x79, 0xe8, 0x8d, 0xbc, 0x49, 0x13, 0x6f, 0x15, 0x64, 0x71, 0xb8, 0x42, 0xd1, 0x13, 0xe8, 0xe7,
+	0x44, 0x33, 0xa5, 0xe3, 0x44, 0x14, 0x67, 0x39, 0xd3, 0x8c, 0x86, 0x8d, 0x91, 0x37, 0xf1, 0x71,
+	0xcf, 0xe1, 0x87, 0x57, 0x30, 0xda, 0x85, 0x96, 0xd2, 0x44, 0x97, 0x2a, 0xf4, 0x47, 0xde, 0xa4,
+	0x8d, 0x97, 0x19, 0x4a, 0x60, 0xdb, 0x95, 0xd3, 0xf9, 0x79, 0x2c, 0x4b, 0xce, 0x33, 0x3e, 0x0f,
+	0x83, 0x91, 0x3f, 0xe9, 0x3c, 0x7b, 0x1e, 0xfd, 0x0b, 0xab, 0xe8, 0x8f, 0xd9, 0x71, 0xc9, 0x71,
+	0xbf, 0x2e, 0x88, 0x5d, 0x3d, 0xf4, 0x08, 0xb6, 0x58, 0x9a, 0xb2, 0x44, 0x67, 0x9f, 0x58, 0x9c,
+	0x48, 0xc1, 0xc3, 0xa6, 0x1d, 0xa2, 0x5b, 0xa3, 0x87, 0x52, 0x70, 0x33, 0xa3, 0x48, 0x53, 0xc5,
+	0x74, 0xd8, 0xb2, 0x74, 0x97, 0x19, 0xba, 0x0b, 0x6d, 0xa3, 0x87, 0x64, 0x5a, 0x9e, 0x87, 0x1b,
+	0xf6, 0xa7, 0xcd, 0x82, 0x2c, 0xb0, 0xc9, 0xd1, 0x7d, 0x00, 0xca, 0xce, 0x18, 0xa7, 0x2a, 0x16,
+	0x3c, 0xdc, 0x1c, 0xf9, 0x93, 0x00, 0xb7, 0x97, 0xc8, 0x5b, 0x8e, 0x3e, 0x40, 0xa7, 0x20, 0xbc,
+	0x24, 0xb9, 0x21, 0xa7, 0xc2, 0xbe, 0x65, 0xf6, 0xe2, 0x16, 0xcc, 0x8e, 0x6c, 0x15, 0xc3, 0x0f,
+	0x8a, 0xab, 0x50, 0xa1, 0x0c, 0x7a, 0xae, 0x97, 0xf1, 0xc3, 0xb5, 0xd8, 0xb6, 0x2d, 0x5e, 0xde,
+	0xa2, 0xc5, 0xb4, 0xae, 0x64, 0xda, 0x6c, 0xd1, 0xdf, 0x53, 0x35, 0xfe, 0xe9, 0x41, 0xff, 0xba,
+	0xd6, 0xa8, 0x0f, 0x3e, 0x17, 0x9f, 0xed, 0x7a, 0xf8, 0xd8, 0x84, 0x06, 0x31, 0x32, 0x99, 0x35,
+	0xe8, 0x62, 0x13, 0xa2, 0x11, 0xb4, 0x64, 0xc9, 0xe3, 0x8c, 0x5a, 0xeb, 0x83, 0x83, 0x76, 0x75,
+	0x39, 0x6c, 0xe2, 0x92, 0xcf, 0xa6, 0xb8, 0x29, 0x4b, 0x3e, 0xa3, 0x68, 0x08, 0x1d, 0x49, 0xf8,
+	0x9c, 0xc5, 0x4a, 0x13, 0xa9, 0xc3, 0xc0, 0x56, 0x03, 0x0b, 0x1d, 0x1b, 0xc4, 0x38, 0xe0, 0xfe,
+	0xc0, 0x38, 0xb5, 0xde, 0xf9, 0x78, 0xd3, 0x02, 0xaf, 0x39, 0x45, 0x0f, 0xe0, 0x7f, 0xc9, 0x3e,
+	0x96, 0x4c, 0x69, 0x46, 0x63, 0xe2, 0xcc, 0xf3, 0x71, 0xa7, 0xc6, 0x5e, 0x69, 0xb4, 0x0f, 0xdd,
+	0x95, 0x0b, 0x66, 0x92, 0x0d, 0x3b, 0x49, 0xaf, 0xba, 0x1c, 0x76, 0x6a, 0x61, 0x67, 0x53, 0xdc,
+	0xa9, 0xa5, 0x9d, 0xd1, 0xf1, 0x85, 0x07, 0xbb, 0xeb, 0x2d, 0x40, 0x3b, 0xd0, 0x74, 0xa3, 0x3a,
+	0xe2, 0x2e, 0x31, 0xd4, 0xcd, 0x7c, 0xee, 0x02, 0x4c, 0xb8, 0xf6, 0x40, 0xfc, 0xf5, 0x07, 0x72,
+	0x9d, 0x45, 0xf0, 0x37, 0x8b, 0x95, 0x90, 0xcd, 0x1b, 0x84, 0xdc, 0x85, 0x46, 0x46, 0xad, 0x00,
+	0xc1, 0x41, 0xab, 0xba, 0x1c, 0x36, 0x66, 0x53, 0xdc, 0xc8, 0xe8, 0xf8, 0x0d, 0xec, 0xdd, 0xec,
+	0xf4, 0x1a, 0x13, 0xef, 0x41, 0x5b, 0x95, 0x49, 0xc2, 0x18, 0xb5, 0x17, 0x6d, 0x77, 0xba, 0x06,
+	0x0e, 0xee, 0x5c, 0x54, 0x03, 0xef, 0x5b, 0x35, 0xf0, 0xbe, 0x57, 0x03, 0xef, 0xcb, 0x8f, 0xc1,
+	0x7f, 0xef, 0x37, 0x96, 0xfb, 0x74, 0xd2, 0xb2, 0xdf, 0x94, 0xfd, 0x5f, 0x03, 0x00, 0x41, 0xd5,
+	0x31, 0xc9, 0x9d, 0x04, 0x00, 0x00,
 }
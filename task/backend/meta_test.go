@@ -2,6 +2,7 @@ package backend_test
 
 import (
 	"errors"
+	"math"
 	"strings"
 	"testing"
 	"time"
@@ -213,6 +214,44 @@ func TestMeta_CreateNextRun_Delay(t *testing.T) {
 	}
 }
 
+func TestMeta_CreateNextRun_DependsOn(t *testing.T) {
+	stm := backend.StoreTaskMeta{
+		MaxConcurrency:  2,
+		Status:          "enabled",
+		LatestCompleted: 30,
+	}
+	stm.SetDependsOn([]platform.ID{1})
+
+	if due, err := stm.NextDueRun(); err != nil {
+		t.Fatal(err)
+	} else if due != math.MaxInt64 {
+		t.Fatalf("expected task with dependencies to never be due, got %d", due)
+	}
+
+	// Without a queued run, no run is created regardless of the time.
+	if _, err := stm.CreateNextRun(1000, makeID); err == nil {
+		t.Fatal("expected error creating run for task with dependencies and no queue")
+	}
+
+	// A queued run is created for exactly the requested time.
+	if err := stm.ManuallyRunTimeRange(65, 65, 100, makeID); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := stm.CreateNextRun(1000, makeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.Created.Now != 65 {
+		t.Fatalf("expected created run to have time 65, got %d", rc.Created.Now)
+	}
+	if rc.HasQueue {
+		t.Fatal("expected queue to be empty after creating run")
+	}
+	if rc.NextDue != math.MaxInt64 {
+		t.Fatalf("unexpected next run time: %d", rc.NextDue)
+	}
+}
+
 func TestMeta_ManuallyRunTimeRange(t *testing.T) {
 	now := time.Now().Unix()
 	stm := backend.StoreTaskMeta{
@@ -279,3 +318,158 @@ func TestMeta_ManuallyRunTimeRange(t *testing.T) {
 
 	// Not currently enforcing one way or another when a newly requested time range overlaps with an existing one.
 }
+
+func TestMeta_ManualRunProgress(t *testing.T) {
+	stm := backend.StoreTaskMeta{
+		MaxConcurrency:  2,
+		Status:          "enabled",
+		EffectiveCron:   "* * * * *", // Every minute.
+		LatestCompleted: 3000,        // Far enough ahead that no natural run is due.
+	}
+
+	// Schedules at 60, 120, 180, 240, and 300.
+	if err := stm.ManuallyRunTimeRange(60, 300, 1, makeID); err != nil {
+		t.Fatal(err)
+	}
+	backfillID := platform.ID(stm.ManualRuns[0].ID)
+	if !backfillID.Valid() {
+		t.Fatal("expected manual run range to be assigned an ID")
+	}
+
+	assertProgress := func(t *testing.T, completed, remaining int) {
+		t.Helper()
+		p, err := stm.ManualRunProgress()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(p) != 1 {
+			t.Fatalf("expected progress for 1 range, got %d", len(p))
+		}
+		if p[0].ID != backfillID {
+			t.Fatalf("expected ID %s, got %s", backfillID, p[0].ID)
+		}
+		if p[0].Completed != completed || p[0].Remaining != remaining {
+			t.Fatalf("expected %d completed and %d remaining, got %d and %d", completed, remaining, p[0].Completed, p[0].Remaining)
+		}
+	}
+
+	assertProgress(t, 0, 5)
+
+	rc, err := stm.CreateNextRun(100, makeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rc.Created.Now != 60 {
+		t.Fatalf("expected run for 60, got %d", rc.Created.Now)
+	}
+	if got := platform.ID(stm.CurrentlyRunning[0].ManualRunID); got != backfillID {
+		t.Fatalf("expected created run to reference manual run %s, got %s", backfillID, got)
+	}
+	// The in-progress run is still remaining.
+	assertProgress(t, 0, 5)
+
+	if !stm.FinishRun(rc.Created.RunID) {
+		t.Fatal("expected to finish run")
+	}
+	assertProgress(t, 1, 4)
+
+	var running []platform.ID
+	for i := 0; i < 2; i++ {
+		rc, err := stm.CreateNextRun(100, makeID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		running = append(running, rc.Created.RunID)
+	}
+	assertProgress(t, 1, 4)
+
+	canceled, err := stm.CancelManualRun(backfillID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(canceled) != 2 || canceled[0] != running[0] || canceled[1] != running[1] {
+		t.Fatalf("expected in-progress runs %v, got %v", running, canceled)
+	}
+	if len(stm.ManualRuns) != 0 {
+		t.Fatalf("expected manual run to be removed from queue, got %d queued", len(stm.ManualRuns))
+	}
+	if p, err := stm.ManualRunProgress(); err != nil || len(p) != 0 {
+		t.Fatalf("expected no progress after cancel, got %v, %v", p, err)
+	}
+
+	if _, err := stm.CancelManualRun(backfillID); err != backend.ErrManualRunNotFound {
+		t.Fatalf("expected ErrManualRunNotFound, got %v", err)
+	}
+}
+
+func TestMeta_ManualRunProgress_UntilRunsFinish(t *testing.T) {
+	stm := backend.StoreTaskMeta{
+		MaxConcurrency:  2,
+		Status:          "enabled",
+		EffectiveCron:   "* * * * *", // Every minute.
+		LatestCompleted: 3000,        // Far enough ahead that no natural run is due.
+	}
+
+	// Schedules at 60 and 120.
+	if err := stm.ManuallyRunTimeRange(60, 120, 1, makeID); err != nil {
+		t.Fatal(err)
+	}
+	backfillID := platform.ID(stm.ManualRuns[0].ID)
+
+	var running []platform.ID
+	for i := 0; i < 2; i++ {
+		rc, err := stm.CreateNextRun(100, makeID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		running = append(running, rc.Created.RunID)
+		if i == 1 && rc.HasQueue {
+			t.Fatal("expected no queue once all runs of the range were created")
+		}
+	}
+	if stm.HasPendingManualRuns() {
+		t.Fatal("expected no pending manual runs once all runs of the range were created")
+	}
+
+	// The range is still reported while its runs are in progress.
+	p, err := stm.ManualRunProgress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p) != 1 || p[0].ID != backfillID || p[0].Completed != 0 || p[0].Remaining != 2 {
+		t.Fatalf("expected 0 completed and 2 remaining for %s, got %+v", backfillID, p)
+	}
+
+	if !stm.FinishRun(running[0]) {
+		t.Fatal("expected to finish run")
+	}
+	p, err = stm.ManualRunProgress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p) != 1 || p[0].Completed != 1 || p[0].Remaining != 1 {
+		t.Fatalf("expected 1 completed and 1 remaining, got %+v", p)
+	}
+
+	// Creating another run doesn't come from the exhausted range.
+	if _, err := stm.CreateNextRun(100, makeID); err == nil {
+		t.Fatal("expected no run to be due")
+	}
+
+	// The range can still be canceled, reporting its last run.
+	stmCopy := stm
+	stmCopy.ManualRuns = append([]*backend.StoreTaskMetaManualRun(nil), stm.ManualRuns...)
+	if canceled, err := stmCopy.CancelManualRun(backfillID); err != nil {
+		t.Fatal(err)
+	} else if len(canceled) != 1 || canceled[0] != running[1] {
+		t.Fatalf("expected in-progress run %s, got %v", running[1], canceled)
+	}
+
+	// Once the last run finishes, the range is dropped.
+	if !stm.FinishRun(running[1]) {
+		t.Fatal("expected to finish run")
+	}
+	if len(stm.ManualRuns) != 0 {
+		t.Fatalf("expected manual run to be removed once its runs finished, got %d queued", len(stm.ManualRuns))
+	}
+}
@@ -19,7 +19,10 @@ const (
 	statusTag = "status"
 
 	// Fixed system bucket ID for task and run logs.
-	taskSystemBucketID platform.ID = 10
+	taskSystemBucketID = platform.ID(platform.BucketTypeLogs)
+
+	// DefaultRunLogRetention is how long run records and logs are kept in the task system bucket by default.
+	DefaultRunLogRetention = 7 * 24 * time.Hour
 )
 
 // Copy of storage.PointsWriter interface.
@@ -11,6 +11,7 @@ import (
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/lang"
 	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
 	"github.com/influxdata/platform"
 	pctx "github.com/influxdata/platform/context"
 	"github.com/influxdata/platform/query"
@@ -18,12 +19,37 @@ import (
 
 type QueryLogReader struct {
 	queryService query.QueryService
+
+	// How far back to look for run records and logs.
+	lookback time.Duration
 }
 
-func NewQueryLogReader(qs query.QueryService) *QueryLogReader {
-	return &QueryLogReader{
+// QueryLogReaderOption is an option you can use to modify a QueryLogReader's behavior.
+type QueryLogReaderOption func(*QueryLogReader)
+
+// WithLookback sets how far back the QueryLogReader looks for run records and logs.
+// It should match the retention period of the task system bucket.
+// If not set, the reader uses DefaultRunLogRetention.
+func WithLookback(d time.Duration) QueryLogReaderOption {
+	return func(qlr *QueryLogReader) {
+		qlr.lookback = d
+	}
+}
+
+func NewQueryLogReader(qs query.QueryService, opts ...QueryLogReaderOption) *QueryLogReader {
+	qlr := &QueryLogReader{
 		queryService: qs,
+		lookback:     DefaultRunLogRetention,
+	}
+	for _, opt := range opts {
+		opt(qlr)
 	}
+	return qlr
+}
+
+// rangeStart returns the flux duration literal to use as the start of a range over the task system bucket.
+func (qlr *QueryLogReader) rangeStart() string {
+	return fmt.Sprintf("-%ds", int64(qlr.lookback/time.Second))
 }
 
 func (qlr *QueryLogReader) ListLogs(ctx context.Context, logFilter platform.LogFilter) ([]platform.Log, error) {
@@ -36,18 +62,35 @@ func (qlr *QueryLogReader) ListLogs(ctx context.Context, logFilter platform.LogF
 
 	filterPart := ""
 	if logFilter.Run != nil {
-		filterPart = fmt.Sprintf(`|> filter(fn: (r) => r._measurement == "logs" and r.runID == %q)`, logFilter.Run.String())
+		filterPart = fmt.Sprintf(`|> filter(fn: (r) => r.runID == %q)`, logFilter.Run.String())
 	} else {
-		filterPart = fmt.Sprintf(`|> filter(fn: (r) => r._measurement == "logs" and r.taskID == %q)`, logFilter.Task.String())
+		filterPart = fmt.Sprintf(`|> filter(fn: (r) => r.taskID == %q)`, logFilter.Task.String())
 	}
 
-	// TODO(lh): Change the range to something more reasonable. Not sure what that range will be.
-	listScript := fmt.Sprintf(`from(bucketID: "000000000000000a")
-  |> range(start: -100h)
+	listScript := fmt.Sprintf(`logs = from(bucketID: %q)
+  |> range(start: %s)
+  |> filter(fn: (r) => r._measurement == "logs")
+  |> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
+  %s
+  |> group(columns: ["taskID", "runID", "_measurement"])
+
+logs |> yield(name: "logs")
+  `, taskSystemBucketID.String(), qlr.rangeStart(), filterPart)
+
+	if logFilter.Status != "" {
+		// The status of each run is the status of its latest record.
+		listScript += fmt.Sprintf(`
+records = from(bucketID: %q)
+  |> range(start: %s)
+  |> filter(fn: (r) => r._measurement == "records")
   |> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
   %s
   |> group(columns: ["taskID", "runID", "_measurement"])
-  `, filterPart)
+  |> sort(columns: ["_time"])
+
+records |> yield(name: "records")
+  `, taskSystemBucketID.String(), qlr.rangeStart(), filterPart)
+	}
 
 	auth, err := pctx.GetAuthorizer(ctx)
 	if err != nil {
@@ -75,12 +118,15 @@ func (qlr *QueryLogReader) ListLogs(ctx context.Context, logFilter platform.LogF
 		return nil, err
 	}
 
-	runs := re.Runs()
-	logs := make([]platform.Log, len(runs))
-	for i, r := range runs {
-		logs[i] = r.Log
+	var runs []*platform.Run
+	for _, r := range re.Runs() {
+		if r.Log == "" {
+			// Only a record was found for this run.
+			continue
+		}
+		runs = append(runs, r)
 	}
-	return logs, nil
+	return filterRunLogs(runs, logFilter)
 }
 
 func (qlr *QueryLogReader) ListRuns(ctx context.Context, runFilter platform.RunFilter) ([]*platform.Run, error) {
@@ -91,9 +137,16 @@ func (qlr *QueryLogReader) ListRuns(ctx context.Context, runFilter platform.RunF
 		return nil, errors.New("org required")
 	}
 
-	limit := "|> limit(n: 100)\n"
+	n := 100
 	if runFilter.Limit > 0 {
-		limit = fmt.Sprintf("|> limit(n: %d)\n", runFilter.Limit)
+		n = runFilter.Limit
+	}
+	limit := fmt.Sprintf("|> limit(n: %d)\n", n)
+
+	// Status and message filters are applied after the query, so the limit must be too.
+	filtered := runFilter.Status != "" || runFilter.Match != ""
+	if filtered {
+		limit = ""
 	}
 
 	afterID := ""
@@ -106,26 +159,39 @@ func (qlr *QueryLogReader) ListRuns(ctx context.Context, runFilter platform.RunF
 		scheduledBefore = runFilter.BeforeTime
 	}
 
-	listScript := fmt.Sprintf(`supl = from(bucketID: "000000000000000a")
-  |> range(start: -24h)
-  |> filter(fn: (r) => r._measurement == "records" and r.taskID == %q)
+	listScript := fmt.Sprintf(`supl = from(bucketID: %[1]q)
+  |> range(start: %[2]s)
+  |> filter(fn: (r) => r._measurement == "records" and r.taskID == %[3]q)
   |> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
   |> group(columns: ["scheduledFor"])
-  |> filter(fn: (r) => r.scheduledFor < %q and r.scheduledFor > %q)
+  |> filter(fn: (r) => r.scheduledFor < %[4]q and r.scheduledFor > %[5]q)
   |> sort(desc: true, columns: ["_start"]) |> limit(n: 1)
 
-main = from(bucketID: "000000000000000a")
-  |> range(start: -24h)
-  |> filter(fn: (r) => r._measurement == "records" and r.taskID == %q)
+main = from(bucketID: %[1]q)
+  |> range(start: %[2]s)
+  |> filter(fn: (r) => r._measurement == "records" and r.taskID == %[3]q)
   |> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
   |> pivot(rowKey:["runID"], columnKey: ["status"], valueColumn: "_time")
-  |> filter(fn: (r) => r.runID > %q)
+  |> filter(fn: (r) => r.runID > %[6]q)
 
 join(tables: {main: main, supl: supl}, on: ["_start", "_stop", "orgID", "taskID", "runID", "_measurement"])
   |> group(columns: ["_measurement"])
-  %s
+  %[7]s
   |> yield(name: "result")
-  `, runFilter.Task.String(), scheduledBefore, scheduledAfter, runFilter.Task.String(), afterID, limit)
+  `, taskSystemBucketID.String(), qlr.rangeStart(), runFilter.Task.String(), scheduledBefore, scheduledAfter, afterID, limit)
+
+	if runFilter.Match != "" {
+		listScript += fmt.Sprintf(`
+logs = from(bucketID: %q)
+  |> range(start: %s)
+  |> filter(fn: (r) => r._measurement == "logs")
+  |> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
+  |> filter(fn: (r) => r.taskID == %q)
+  |> group(columns: ["taskID", "runID", "_measurement"])
+
+logs |> yield(name: "logs")
+  `, taskSystemBucketID.String(), qlr.rangeStart(), runFilter.Task.String())
+	}
 
 	auth, err := pctx.GetAuthorizer(ctx)
 	if err != nil {
@@ -141,30 +207,53 @@ join(tables: {main: main, supl: supl}, on: ["_start", "_stop", "orgID", "taskID"
 		return nil, err
 	}
 
-	return queryIttrToRuns(ittr)
+	re, err := queryIttrToRunExtractor(ittr)
+	if err != nil {
+		return nil, err
+	}
+	runs := re.Runs()
+	if !filtered {
+		return runs, nil
+	}
+
+	matched := make([]*platform.Run, 0, len(runs))
+	for _, r := range runs {
+		if !re.Recorded(r.ID) {
+			// Logs were found for a run that did not match the other filters.
+			continue
+		}
+		if !runMatchesFilter(r, runFilter) {
+			continue
+		}
+		matched = append(matched, r)
+		if len(matched) >= n {
+			break
+		}
+	}
+	return matched, nil
 }
 
 func (qlr *QueryLogReader) FindRunByID(ctx context.Context, orgID, runID platform.ID) (*platform.Run, error) {
 	// TODO: sort |> limit will be replaced with last once last is working.
-	showScript := fmt.Sprintf(`supl = from(bucketID: "000000000000000a")
-  |> range(start: -24h)
+	showScript := fmt.Sprintf(`supl = from(bucketID: %[1]q)
+  |> range(start: %[2]s)
   |> filter(fn: (r) => r._measurement == "records")
   |> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
-  |> filter(fn: (r) => r.runID == %q)
+  |> filter(fn: (r) => r.runID == %[3]q)
   |> group(columns: ["scheduledFor"])
   |> sort(desc: true, columns: ["_start"]) |> limit(n: 1)
 
-logs = from(bucketID: "000000000000000a")
-  |> range(start: -24h)
+logs = from(bucketID: %[1]q)
+  |> range(start: %[2]s)
   |> filter(fn: (r) => r._measurement == "logs")
   |> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
-	|> filter(fn: (r) => r.runID == %q)
+	|> filter(fn: (r) => r.runID == %[3]q)
 
-main = from(bucketID: "000000000000000a")
-  |> range(start: -24h)
+main = from(bucketID: %[1]q)
+  |> range(start: %[2]s)
   |> filter(fn: (r) => r._measurement == "records")
   |> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
-  |> filter(fn: (r) => r.runID == %q)
+  |> filter(fn: (r) => r.runID == %[3]q)
   |> pivot(rowKey:["runID"], columnKey: ["status"], valueColumn: "_time")
 
 join(
@@ -173,7 +262,7 @@ join(
 ) |> yield(name: "result")
 
 logs |> yield(name: "logs")
-  `, runID.String(), runID.String(), runID.String())
+  `, taskSystemBucketID.String(), qlr.rangeStart(), runID.String())
 
 	auth, err := pctx.GetAuthorizer(ctx)
 	if err != nil {
@@ -200,6 +289,14 @@ logs |> yield(name: "logs")
 }
 
 func queryIttrToRuns(results flux.ResultIterator) ([]*platform.Run, error) {
+	re, err := queryIttrToRunExtractor(results)
+	if err != nil {
+		return nil, err
+	}
+	return re.Runs(), nil
+}
+
+func queryIttrToRunExtractor(results flux.ResultIterator) (*runExtractor, error) {
 	defer results.Release()
 
 	re := newRunExtractor()
@@ -214,16 +311,28 @@ func queryIttrToRuns(results flux.ResultIterator) ([]*platform.Run, error) {
 		return nil, err
 	}
 
-	return re.Runs(), nil
+	return re, nil
 }
 
 // runExtractor is used to decode query results to runs.
 type runExtractor struct {
 	runs map[platform.ID]platform.Run
+
+	// recorded holds the IDs of the runs that records were found for.
+	recorded map[platform.ID]struct{}
 }
 
 func newRunExtractor() *runExtractor {
-	return &runExtractor{runs: make(map[platform.ID]platform.Run)}
+	return &runExtractor{
+		runs:     make(map[platform.ID]platform.Run),
+		recorded: make(map[platform.ID]struct{}),
+	}
+}
+
+// Recorded returns true if a record was found for the run.
+func (re *runExtractor) Recorded(id platform.ID) bool {
+	_, ok := re.recorded[id]
+	return ok
 }
 
 // Runs returns the runExtractor's stored runs as a slice.
@@ -262,6 +371,9 @@ func (re *runExtractor) Extract(tbl flux.Table) error {
 func (re *runExtractor) extractRecord(cr flux.ColReader) error {
 	for i := 0; i < cr.Len(); i++ {
 		var r platform.Run
+		// When the records are pivoted by status, the status of the run is the one recorded last.
+		var latestStatus string
+		var latestStatusTime values.Time
 		for j, col := range cr.Cols() {
 			switch col.Label {
 			case requestedAtField:
@@ -284,10 +396,24 @@ func (re *runExtractor) extractRecord(cr flux.ColReader) error {
 				r.TaskID = *id
 			case RunStarted.String():
 				r.StartedAt = cr.Times(j)[i].Time().Format(time.RFC3339Nano)
+				if t := cr.Times(j)[i]; t > latestStatusTime {
+					latestStatus, latestStatusTime = col.Label, t
+				}
 			case RunSuccess.String(), RunFail.String(), RunCanceled.String():
-				r.FinishedAt = cr.Times(j)[i].Time().Format(time.RFC3339Nano)
+				t := cr.Times(j)[i]
+				if t == 0 {
+					// This run did not finish with this status.
+					continue
+				}
+				r.FinishedAt = t.Time().Format(time.RFC3339Nano)
+				if t > latestStatusTime {
+					latestStatus, latestStatusTime = col.Label, t
+				}
 			}
 		}
+		if latestStatus != "" {
+			r.Status = latestStatus
+		}
 
 		if !r.ID.Valid() {
 			return errors.New("extractRecord: did not find valid run ID in table")
@@ -298,6 +424,7 @@ func (re *runExtractor) extractRecord(cr flux.ColReader) error {
 		}
 
 		re.runs[r.ID] = r
+		re.recorded[r.ID] = struct{}{}
 	}
 
 	return nil
@@ -332,6 +459,7 @@ func (re *runExtractor) extractLog(cr flux.ColReader) error {
 
 	for id, lines := range entries {
 		run := re.runs[id]
+		run.ID = id
 		run.Log = platform.Log(strings.Join(lines, "\n"))
 		re.runs[id] = run
 	}
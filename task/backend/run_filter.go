@@ -0,0 +1,100 @@
+package backend
+
+import (
+	"strings"
+	"time"
+
+	"github.com/influxdata/platform"
+)
+
+// runMatchesFilter reports whether run satisfies the status and message filters of f.
+func runMatchesFilter(run *platform.Run, f platform.RunFilter) bool {
+	if f.Status != "" && run.Status != f.Status {
+		return false
+	}
+	if f.Match != "" {
+		return filterLogLines(run.Log, time.Time{}, time.Time{}, f.Match) != ""
+	}
+	return true
+}
+
+// filterRunLogs returns the logs of runs which satisfy the status, time window and message filters of f.
+// If f restricts which log lines are returned, runs without any matching lines are omitted.
+func filterRunLogs(runs []*platform.Run, f platform.LogFilter) ([]platform.Log, error) {
+	restricted := f.AfterTime != "" || f.BeforeTime != "" || f.Match != ""
+
+	logs := make([]platform.Log, 0, len(runs))
+	for _, run := range runs {
+		if f.Status != "" && run.Status != f.Status {
+			continue
+		}
+
+		log, err := filterRunLog(run.Log, f)
+		if err != nil {
+			return nil, err
+		}
+		if restricted && log == "" {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
+
+// filterRunLog returns the lines of log which satisfy the time window and message filters of f.
+func filterRunLog(log platform.Log, f platform.LogFilter) (platform.Log, error) {
+	var after, before time.Time
+	if f.AfterTime != "" {
+		t, err := time.Parse(time.RFC3339, f.AfterTime)
+		if err != nil {
+			return "", err
+		}
+		after = t
+	}
+	if f.BeforeTime != "" {
+		t, err := time.Parse(time.RFC3339, f.BeforeTime)
+		if err != nil {
+			return "", err
+		}
+		before = t
+	}
+	return filterLogLines(log, after, before, f.Match), nil
+}
+
+// filterLogLines returns the lines of log written after after and before before, which contain match ignoring case.
+// Zero times and an empty match do not filter.
+// Each line of log is expected to be formatted as "<RFC3339Nano time>: <message>".
+func filterLogLines(log platform.Log, after, before time.Time, match string) platform.Log {
+	if after.IsZero() && before.IsZero() && match == "" {
+		return log
+	}
+
+	match = strings.ToLower(match)
+	var kept []string
+	for _, line := range strings.Split(string(log), "\n") {
+		i := strings.Index(line, ": ")
+		if i < 0 {
+			continue
+		}
+
+		if !after.IsZero() || !before.IsZero() {
+			when, err := time.Parse(time.RFC3339Nano, line[:i])
+			if err != nil {
+				continue
+			}
+			if !after.IsZero() && !when.After(after) {
+				continue
+			}
+			if !before.IsZero() && !when.Before(before) {
+				continue
+			}
+		}
+
+		if match != "" && !strings.Contains(strings.ToLower(line[i+2:]), match) {
+			continue
+		}
+
+		kept = append(kept, line)
+	}
+	return platform.Log(strings.Join(kept, "\n"))
+}
@@ -0,0 +1,168 @@
+package backend
+
+import (
+	"sync"
+
+	"github.com/influxdata/platform"
+)
+
+// runLimiter bounds the number of runs executing at once, both per organization and in total.
+// Runs that cannot execute immediately wait in a queue per organization.
+// Freed slots go to the waiting organization that least recently started a run,
+// so organizations take turns and one organization with many runs cannot starve the others.
+type runLimiter struct {
+	orgLimit    int // Maximum executing runs per organization, or 0 for no limit.
+	globalLimit int // Maximum executing runs overall, or 0 for no limit.
+
+	metrics *schedulerMetrics
+
+	mu      sync.Mutex
+	active  map[platform.ID]int        // Executing runs per organization.
+	total   int                        // Executing runs overall.
+	waiting map[platform.ID][]*runSlot // Queued runs per organization, oldest first.
+	served  map[platform.ID]uint64     // Value of seq when each organization last started a run.
+	seq     uint64
+}
+
+// runSlot is a request for permission to execute a run.
+type runSlot struct {
+	org     platform.ID
+	ready   chan struct{} // Closed when the slot is granted.
+	granted bool
+}
+
+func newRunLimiter(orgLimit, globalLimit int, metrics *schedulerMetrics) *runLimiter {
+	return &runLimiter{
+		orgLimit:    orgLimit,
+		globalLimit: globalLimit,
+		metrics:     metrics,
+		active:      make(map[platform.ID]int),
+		waiting:     make(map[platform.ID][]*runSlot),
+		served:      make(map[platform.ID]uint64),
+	}
+}
+
+// enqueue requests a slot to execute a run for the given organization.
+// The returned slot's ready channel is closed once the run may execute,
+// after which the caller must call release.
+// If the caller stops waiting before then, it must call cancel instead.
+func (l *runLimiter) enqueue(org platform.ID) *runSlot {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s := &runSlot{org: org, ready: make(chan struct{})}
+	if len(l.waiting) == 0 && l.canRun(org) {
+		// Nobody is waiting, so there is no need to queue.
+		l.grant(s)
+		return s
+	}
+
+	l.waiting[org] = append(l.waiting[org], s)
+	l.metrics.QueueRun(org.String())
+
+	l.dispatch()
+	return s
+}
+
+// cancel gives up on s, whether or not it has been granted.
+func (l *runLimiter) cancel(s *runSlot) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if s.granted {
+		l.finish(s.org)
+		return
+	}
+
+	q := l.waiting[s.org]
+	for i, w := range q {
+		if w == s {
+			q = append(q[:i:i], q[i+1:]...)
+			l.metrics.DequeueRun(s.org.String(), len(q) == 0)
+			break
+		}
+	}
+	if len(q) == 0 {
+		delete(l.waiting, s.org)
+		l.forget(s.org)
+	} else {
+		l.waiting[s.org] = q
+	}
+	l.dispatch()
+}
+
+// release returns the slot held by a finished run for the given organization.
+func (l *runLimiter) release(org platform.ID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.finish(org)
+}
+
+// finish frees an executing slot for org and hands out any slots that became available.
+// l.mu must be held.
+func (l *runLimiter) finish(org platform.ID) {
+	l.total--
+	if l.active[org]--; l.active[org] <= 0 {
+		delete(l.active, org)
+		l.forget(org)
+	}
+	l.dispatch()
+}
+
+// forget drops the turn-taking history of org, once it has no executing or waiting runs.
+// l.mu must be held.
+func (l *runLimiter) forget(org platform.ID) {
+	if l.active[org] == 0 && len(l.waiting[org]) == 0 {
+		delete(l.served, org)
+	}
+}
+
+// canRun reports whether another run for org may begin executing. l.mu must be held.
+func (l *runLimiter) canRun(org platform.ID) bool {
+	if l.globalLimit > 0 && l.total >= l.globalLimit {
+		return false
+	}
+	return l.orgLimit <= 0 || l.active[org] < l.orgLimit
+}
+
+// grant marks s as executing. l.mu must be held.
+func (l *runLimiter) grant(s *runSlot) {
+	s.granted = true
+	l.active[s.org]++
+	l.total++
+	l.seq++
+	l.served[s.org] = l.seq
+	close(s.ready)
+}
+
+// dispatch starts the oldest waiting run of the eligible organization that least recently started a run,
+// until no waiting run may execute. l.mu must be held.
+func (l *runLimiter) dispatch() {
+	for {
+		var (
+			next  platform.ID
+			found bool
+		)
+		for org := range l.waiting {
+			if !l.canRun(org) {
+				continue
+			}
+			if !found || l.served[org] < l.served[next] || (l.served[org] == l.served[next] && org < next) {
+				next, found = org, true
+			}
+		}
+		if !found {
+			return
+		}
+
+		q := l.waiting[next]
+		if len(q) == 1 {
+			delete(l.waiting, next)
+		} else {
+			l.waiting[next] = q[1:]
+		}
+		l.metrics.DequeueRun(next.String(), len(q) == 1)
+		l.grant(q[0])
+	}
+}
@@ -39,6 +39,10 @@ type DesiredState interface {
 	// FinishRun indicates that the given run is no longer intended to be executed.
 	// This may be called after a successful or failed execution, or upon cancellation.
 	FinishRun(ctx context.Context, taskID, runID platform.ID) error
+
+	// QueueDependentRuns records that the run of the task with the given ID succeeded for the Unix timestamp now.
+	// The scheduler uses this to queue runs of tasks whose dependencies have completed.
+	QueueDependentRuns(ctx context.Context, taskID platform.ID, now, requestedAt int64) ([]platform.ID, error)
 }
 
 // Executor handles execution of a run.
@@ -120,6 +124,10 @@ type Scheduler interface {
 
 	// Cancel stops an executing run.
 	CancelRun(ctx context.Context, taskID, runID platform.ID) error
+
+	// ManualRunsQueued notifies the scheduler that manual runs were queued for the given task ID,
+	// so that they may begin on the next tick rather than waiting for the task's next scheduled run.
+	ManualRunsQueued(taskID platform.ID)
 }
 
 // TickSchedulerOption is a option you can use to modify the schedulers behavior.
@@ -160,6 +168,36 @@ func WithLogger(logger *zap.Logger) TickSchedulerOption {
 	}
 }
 
+// WithRetryBackoff sets the delay before the first retry of a failed, retryable run,
+// and the upper bound on the delay between subsequent retries.
+// The delay doubles after each failed attempt.
+// If not set, the scheduler uses DefaultRetryBackoff and DefaultMaxRetryBackoff.
+func WithRetryBackoff(initial, max time.Duration) TickSchedulerOption {
+	return func(s *TickScheduler) {
+		s.retryBackoff = initial
+		s.maxRetryBackoff = max
+	}
+}
+
+// WithConcurrencyLimits bounds how many runs may execute at once through the scheduler's Executor,
+// both per organization and across all organizations, in addition to each task's own concurrency limit.
+// Runs beyond either limit wait for a slot, and waiting runs are started round-robin across organizations.
+// A limit of 0 means no limit.
+func WithConcurrencyLimits(perOrg, global int) TickSchedulerOption {
+	return func(s *TickScheduler) {
+		s.orgConcurrency = perOrg
+		s.globalConcurrency = global
+	}
+}
+
+const (
+	// DefaultRetryBackoff is the default delay before retrying a failed, retryable run.
+	DefaultRetryBackoff = time.Second
+
+	// DefaultMaxRetryBackoff is the default upper bound on the delay between retries of a run.
+	DefaultMaxRetryBackoff = time.Minute
+)
+
 // NewScheduler returns a new scheduler with the given desired state and the given now UTC timestamp.
 func NewScheduler(desiredState DesiredState, executor Executor, lw LogWriter, now int64, opts ...TickSchedulerOption) *TickScheduler {
 	o := &TickScheduler{
@@ -171,12 +209,21 @@ func NewScheduler(desiredState DesiredState, executor Executor, lw LogWriter, no
 		logger:         zap.NewNop(),
 		wg:             &sync.WaitGroup{},
 		metrics:        newSchedulerMetrics(),
+
+		retryBackoff:    DefaultRetryBackoff,
+		maxRetryBackoff: DefaultMaxRetryBackoff,
+
+		queued: make(map[platform.ID]struct{}),
 	}
 
 	for _, opt := range opts {
 		opt(o)
 	}
 
+	if o.orgConcurrency > 0 || o.globalConcurrency > 0 {
+		o.limiter = newRunLimiter(o.orgConcurrency, o.globalConcurrency, o.metrics)
+	}
+
 	return o
 }
 
@@ -190,12 +237,23 @@ type TickScheduler struct {
 
 	metrics *schedulerMetrics
 
+	// Delay before the first retry of a run, and the upper bound for subsequent retries.
+	retryBackoff, maxRetryBackoff time.Duration
+
+	// Limits on runs executing at once per organization and overall, enforced by limiter if either is set.
+	orgConcurrency, globalConcurrency int
+	limiter                           *runLimiter
+
 	ctx    context.Context
 	cancel context.CancelFunc
 	wg     *sync.WaitGroup
 
 	schedulerMu    sync.Mutex                     // Protects access and modification of taskSchedulers map.
 	taskSchedulers map[platform.ID]*taskScheduler // task ID -> task scheduler.
+
+	// queuedMu may be acquired while holding schedulerMu, but never the other way around.
+	queuedMu sync.Mutex
+	queued   map[platform.ID]struct{} // task IDs with manual runs queued since the last tick.
 }
 
 // CancelRun cancels a run, it has the unused Context argument so that it can implement a task.RunController
@@ -219,6 +277,12 @@ func (s *TickScheduler) CancelRun(_ context.Context, taskID, runID platform.ID)
 	return nil
 }
 
+func (s *TickScheduler) ManualRunsQueued(taskID platform.ID) {
+	s.queuedMu.Lock()
+	s.queued[taskID] = struct{}{}
+	s.queuedMu.Unlock()
+}
+
 // Tick updates the time of the scheduler.
 // Any owned tasks who are due to execute and who have a free concurrency slot,
 // will begin a new execution.
@@ -239,6 +303,15 @@ func (s *TickScheduler) Tick(now int64) {
 
 	atomic.StoreInt64(&s.now, now)
 
+	s.queuedMu.Lock()
+	for id := range s.queued {
+		if ts, ok := s.taskSchedulers[id]; ok {
+			ts.SetHasQueue()
+		}
+		delete(s.queued, id)
+	}
+	s.queuedMu.Unlock()
+
 	affected := 0
 	for _, ts := range s.taskSchedulers {
 		if nextDue, hasQueue := ts.NextDue(); now >= nextDue || hasQueue {
@@ -365,6 +438,20 @@ func (s *TickScheduler) ReleaseTask(taskID platform.ID) error {
 	return nil
 }
 
+// runSucceeded records the success of qr in the desired state, which queues a run for every task that depends on qr's task,
+// once all of the dependent task's upstream tasks have succeeded for qr.Now.
+// The queued runs of tasks claimed by this scheduler are started on the next tick;
+// other schedulers pick up the runs queued for their tasks from the desired state.
+func (s *TickScheduler) runSucceeded(ctx context.Context, qr QueuedRun, logger *zap.Logger) {
+	ids, err := s.desiredState.QueueDependentRuns(ctx, qr.TaskID, qr.Now, time.Now().Unix())
+	if err != nil {
+		logger.Info("Failed to queue runs for dependent tasks", zap.Error(err))
+	}
+	for _, id := range ids {
+		s.ManualRunsQueued(id)
+	}
+}
+
 func (s *TickScheduler) PrometheusCollectors() []prometheus.Collector {
 	return s.metrics.PrometheusCollectors()
 }
@@ -379,6 +466,9 @@ type taskScheduler struct {
 	// Reference to outerScheduler.now. Must be accessed atomically.
 	now *int64
 
+	// The TickScheduler that owns this taskScheduler.
+	scheduler *TickScheduler
+
 	// Task we are scheduling for.
 	task *StoreTask
 
@@ -395,6 +485,10 @@ type taskScheduler struct {
 
 	metrics *schedulerMetrics
 
+	// Maximum number of attempts for a single run, and the backoff settings inherited from the TickScheduler.
+	maxRetry                      int
+	retryBackoff, maxRetryBackoff time.Duration
+
 	nextDueMu     sync.RWMutex // Protects following fields.
 	nextDue       int64        // Unix timestamp of next due.
 	nextDueSource int64        // Run time that produced nextDue.
@@ -414,9 +508,16 @@ func newTaskScheduler(
 		return nil, err
 	}
 
+	maxRetry := int(meta.MaxRetry)
+	if maxRetry < 1 {
+		// Tasks created before the retry option was stored in the meta get a single attempt.
+		maxRetry = 1
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	ts := &taskScheduler{
 		now:           &s.now,
+		scheduler:     s,
 		task:          task,
 		cancel:        cancel,
 		wg:            wg,
@@ -426,7 +527,11 @@ func newTaskScheduler(
 		metrics:       s.metrics,
 		nextDue:       firstDue,
 		nextDueSource: math.MinInt64,
-		hasQueue:      len(meta.ManualRuns) > 0,
+		hasQueue:      meta.HasPendingManualRuns(),
+
+		maxRetry:        maxRetry,
+		retryBackoff:    s.retryBackoff,
+		maxRetryBackoff: s.maxRetryBackoff,
 	}
 
 	for i := range ts.runners {
@@ -491,6 +596,25 @@ func (ts *taskScheduler) SetNextDue(nextDue int64, hasQueue bool, source int64)
 	ts.hasQueue = hasQueue
 }
 
+// SetHasQueue indicates that the task has manual runs queued, so that the next tick will start them.
+func (ts *taskScheduler) SetHasQueue() {
+	ts.nextDueMu.Lock()
+	defer ts.nextDueMu.Unlock()
+	ts.hasQueue = true
+}
+
+// RetryBackoff returns how long to wait before the next attempt of a run, after the given failed attempt.
+func (ts *taskScheduler) RetryBackoff(try int) time.Duration {
+	d := ts.retryBackoff
+	for i := 1; i < try && d < ts.maxRetryBackoff; i++ {
+		d *= 2
+	}
+	if d > ts.maxRetryBackoff {
+		d = ts.maxRetryBackoff
+	}
+	return d
+}
+
 // A runner is one eligible "concurrency slot" for a given task.
 type runner struct {
 	state *uint32
@@ -633,35 +757,31 @@ func (r *runner) executeAndWait(ctx context.Context, qr QueuedRun, runLogger *za
 	sp, spCtx := opentracing.StartSpanFromContext(ctx, "task.run.execution")
 	defer sp.Finish()
 
-	rp, err := r.executor.Execute(spCtx, qr)
+	var res RunResult
+	var err error
+	for try := 1; ; try++ {
+		res, err = r.executeAttempt(spCtx, qr)
+		if err != nil || res == nil || res.Err() == nil || !res.IsRetryable() || try >= r.ts.maxRetry {
+			break
+		}
 
-	if err != nil {
-		// TODO(mr): retry? and log error.
-		atomic.StoreUint32(r.state, runnerIdle)
-		r.updateRunState(qr, RunFail, runLogger)
-		return
-	}
+		backoff := r.ts.RetryBackoff(try)
+		runLogger.Info("Execution failed; retrying", zap.Int("try", try), zap.Duration("backoff", backoff), zap.Error(res.Err()))
+		r.addRunLog(qr, fmt.Sprintf("Attempt %d of %d failed: %v; retrying in %s", try, r.ts.maxRetry, res.Err(), backoff))
 
-	ready := make(chan struct{})
-	go func() {
-		// If the runner's context is canceled, cancel the RunPromise.
 		select {
+		case <-time.After(backoff):
 		case <-ctx.Done():
-			r.clearRunning(qr.RunID)
-			rp.Cancel()
-		// Canceled context.
+			err = ErrRunCanceled
 		case <-r.ctx.Done():
-			r.clearRunning(qr.RunID)
-			rp.Cancel()
-		// Wait finished.
-		case <-ready:
-			r.clearRunning(qr.RunID)
+			err = ErrRunCanceled
 		}
-	}()
+		if err != nil {
+			break
+		}
+	}
+	r.clearRunning(qr.RunID)
 
-	// TODO(mr): handle res.IsRetryable().
-	_, err = rp.Wait()
-	close(ready)
 	if err != nil {
 		if err == ErrRunCanceled {
 			_ = r.desiredState.FinishRun(r.ctx, qr.TaskID, qr.RunID)
@@ -687,13 +807,76 @@ func (r *runner) executeAndWait(ctx context.Context, qr QueuedRun, runLogger *za
 		r.updateRunState(qr, RunFail, runLogger)
 		return
 	}
-	r.updateRunState(qr, RunSuccess, runLogger)
-	runLogger.Info("Execution succeeded")
+
+	if res != nil && res.Err() != nil {
+		runLogger.Info("Execution failed", zap.Error(res.Err()))
+		r.addRunLog(qr, fmt.Sprintf("Execution failed: %v", res.Err()))
+		r.updateRunState(qr, RunFail, runLogger)
+	} else {
+		r.updateRunState(qr, RunSuccess, runLogger)
+		runLogger.Info("Execution succeeded")
+		r.ts.scheduler.runSucceeded(r.ctx, qr, runLogger)
+	}
 
 	// Check again if there is a new run available, without returning to idle state.
 	r.startFromWorking(atomic.LoadInt64(r.ts.now))
 }
 
+// executeAttempt executes a single attempt of qr and blocks until the attempt finishes.
+// If the scheduler limits concurrent executions, the attempt first waits for an execution slot.
+// If ctx or the runner's context is canceled while the attempt is executing, the attempt is canceled.
+func (r *runner) executeAttempt(ctx context.Context, qr QueuedRun) (RunResult, error) {
+	if l := r.ts.scheduler.limiter; l != nil {
+		// Wait for the task's organization to be allowed another executing run.
+		slot := l.enqueue(r.task.Org)
+		select {
+		case <-slot.ready:
+		case <-ctx.Done():
+			l.cancel(slot)
+			return nil, ErrRunCanceled
+		case <-r.ctx.Done():
+			l.cancel(slot)
+			return nil, ErrRunCanceled
+		}
+		defer l.release(r.task.Org)
+	}
+
+	rp, err := r.executor.Execute(ctx, qr)
+	if err != nil {
+		return nil, err
+	}
+
+	ready := make(chan struct{})
+	defer close(ready)
+	go func() {
+		select {
+		// Canceled run.
+		case <-ctx.Done():
+			rp.Cancel()
+		// Canceled runner.
+		case <-r.ctx.Done():
+			rp.Cancel()
+		// Wait finished.
+		case <-ready:
+		}
+	}()
+
+	return rp.Wait()
+}
+
+// addRunLog adds a log line to the run, logging rather than returning any error from the LogWriter.
+func (r *runner) addRunLog(qr QueuedRun, msg string) {
+	rlb := RunLogBase{
+		Task:            r.task,
+		RunID:           qr.RunID,
+		RunScheduledFor: qr.Now,
+		RequestedAt:     qr.RequestedAt,
+	}
+	if err := r.logWriter.AddRunLog(r.ctx, rlb, time.Now(), msg); err != nil {
+		r.logger.Info("Error adding run log", zap.String("run_id", qr.RunID.String()), zap.Error(err))
+	}
+}
+
 func (r *runner) updateRunState(qr QueuedRun, s RunStatus, runLogger *zap.Logger) {
 	rlb := RunLogBase{
 		Task:            r.task,
@@ -14,6 +14,9 @@ type schedulerMetrics struct {
 
 	claimsComplete *prometheus.CounterVec
 	claimsActive   prometheus.Gauge
+
+	totalRunsQueued prometheus.Gauge
+	runsQueued      *prometheus.GaugeVec
 }
 
 func newSchedulerMetrics() *schedulerMetrics {
@@ -59,6 +62,19 @@ func newSchedulerMetrics() *schedulerMetrics {
 			Name:      "claims_active",
 			Help:      "Total number of claims currently held.",
 		}),
+
+		totalRunsQueued: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "total_runs_queued",
+			Help:      "Total number of runs waiting for an execution slot, across all organizations.",
+		}),
+		runsQueued: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: subsystem,
+			Name:      "runs_queued",
+			Help:      "Number of runs waiting for an execution slot, split out by organization ID.",
+		}, []string{"org_id"}),
 	}
 }
 
@@ -71,6 +87,8 @@ func (sm *schedulerMetrics) PrometheusCollectors() []prometheus.Collector {
 		sm.runsActive,
 		sm.claimsComplete,
 		sm.claimsActive,
+		sm.totalRunsQueued,
+		sm.runsQueued,
 	}
 }
 
@@ -110,6 +128,23 @@ func (sm *schedulerMetrics) ReleaseTask(tid string) {
 	sm.runsComplete.DeleteLabelValues(tid, statusString(false))
 }
 
+// QueueRun adjusts the metrics to indicate a run for the given organization ID is waiting for an execution slot.
+func (sm *schedulerMetrics) QueueRun(oid string) {
+	sm.totalRunsQueued.Inc()
+	sm.runsQueued.WithLabelValues(oid).Inc()
+}
+
+// DequeueRun adjusts the metrics to indicate a run for the given organization ID is no longer waiting for an execution slot.
+// If drained is true, no runs of the organization are waiting anymore and its label is removed.
+func (sm *schedulerMetrics) DequeueRun(oid string, drained bool) {
+	sm.totalRunsQueued.Dec()
+	if drained {
+		sm.runsQueued.DeleteLabelValues(oid)
+	} else {
+		sm.runsQueued.WithLabelValues(oid).Dec()
+	}
+}
+
 func statusString(succeeded bool) string {
 	if succeeded {
 		return "success"
@@ -509,6 +509,231 @@ func TestScheduler_RunLog(t *testing.T) {
 	pollForRunStatus(t, rl, task.ID, 3, 2, backend.RunCanceled.String())
 }
 
+func TestScheduler_Retry(t *testing.T) {
+	d := mock.NewDesiredState()
+	e := mock.NewExecutor()
+	rl := backend.NewInMemRunReaderWriter()
+	s := backend.NewScheduler(d, e, rl, 5, backend.WithLogger(zaptest.NewLogger(t)), backend.WithRetryBackoff(10*time.Millisecond, 20*time.Millisecond))
+	s.Start(context.Background())
+	defer s.Stop()
+
+	task := &backend.StoreTask{
+		ID: platform.ID(1),
+	}
+	meta := &backend.StoreTaskMeta{
+		MaxConcurrency:  1,
+		MaxRetry:        3,
+		EffectiveCron:   "@every 1s",
+		LatestCompleted: 5,
+	}
+
+	d.SetTaskMeta(task.ID, *meta)
+	if err := s.ClaimTask(task, meta); err != nil {
+		t.Fatal(err)
+	}
+
+	// waitForNewPromise returns the running promise for the task, once it is different from prev.
+	waitForNewPromise := func(prev *mock.RunPromise) *mock.RunPromise {
+		t.Helper()
+		for i := 0; i < 50; i++ {
+			if running := e.RunningFor(task.ID); len(running) == 1 && running[0] != prev {
+				return running[0]
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatal("did not see new run attempt in time")
+		return nil
+	}
+
+	// A retryable failure is retried with the same run ID and now.
+	s.Tick(6)
+	rp := waitForNewPromise(nil)
+	first := rp.Run()
+	rp.Finish(mock.NewRunResult(errors.New("transient failure"), true), nil)
+
+	rp = waitForNewPromise(rp)
+	if got := rp.Run(); got != first {
+		t.Fatalf("expected retry of run %+v, got %+v", first, got)
+	}
+	pollForRunStatus(t, rl, task.ID, 1, 0, backend.RunStarted.String())
+
+	// The second failure is retried too, and the run succeeds on the last attempt.
+	rp.Finish(mock.NewRunResult(errors.New("transient failure"), true), nil)
+	rp = waitForNewPromise(rp)
+	rp.Finish(mock.NewRunResult(nil, false), nil)
+	pollForRunStatus(t, rl, task.ID, 1, 0, backend.RunSuccess.String())
+
+	runs, err := rl.ListRuns(context.Background(), platform.RunFilter{Task: &task.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, exp := range []string{"Attempt 1 of 3 failed", "Attempt 2 of 3 failed"} {
+		if !strings.Contains(string(runs[0].Log), exp) {
+			t.Fatalf("expected run log to contain %q, got %q", exp, runs[0].Log)
+		}
+	}
+
+	// A run that keeps failing is marked failed after its last attempt.
+	s.Tick(7)
+	rp = waitForNewPromise(rp)
+	for i := 0; i < 3; i++ {
+		if i > 0 {
+			rp = waitForNewPromise(rp)
+		}
+		rp.Finish(mock.NewRunResult(errors.New("transient failure"), true), nil)
+	}
+	pollForRunStatus(t, rl, task.ID, 2, 1, backend.RunFail.String())
+	if _, err := e.PollForNumberRunning(task.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// A non-retryable failure is not retried.
+	s.Tick(8)
+	rp = waitForNewPromise(rp)
+	rp.Finish(mock.NewRunResult(errors.New("bad script"), false), nil)
+	pollForRunStatus(t, rl, task.ID, 3, 2, backend.RunFail.String())
+	if _, err := e.PollForNumberRunning(task.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScheduler_DependsOn(t *testing.T) {
+	d := mock.NewDesiredState()
+	e := mock.NewExecutor()
+	s := backend.NewScheduler(d, e, backend.NopLogWriter{}, 5, backend.WithLogger(zaptest.NewLogger(t)))
+	s.Start(context.Background())
+	defer s.Stop()
+
+	up1 := &backend.StoreTask{ID: platform.ID(1)}
+	up2 := &backend.StoreTask{ID: platform.ID(2)}
+	down := &backend.StoreTask{ID: platform.ID(3)}
+
+	upMeta := &backend.StoreTaskMeta{
+		MaxConcurrency:  1,
+		EffectiveCron:   "@every 1s",
+		LatestCompleted: 5,
+	}
+	downMeta := &backend.StoreTaskMeta{
+		MaxConcurrency:  1,
+		LatestCompleted: 5,
+	}
+	downMeta.SetDependsOn([]platform.ID{up1.ID, up2.ID})
+
+	for _, tm := range []struct {
+		task *backend.StoreTask
+		meta *backend.StoreTaskMeta
+	}{{up1, upMeta}, {up2, upMeta}, {down, downMeta}} {
+		d.SetTaskMeta(tm.task.ID, *tm.meta)
+		if err := s.ClaimTask(tm.task, tm.meta); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s.Tick(6)
+	up1Runs, err := e.PollForNumberRunning(up1.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	up2Runs, err := e.PollForNumberRunning(up2.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The downstream task is not scheduled on its own.
+	if _, err := e.PollForNumberRunning(down.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only one upstream task succeeding does not trigger the downstream task.
+	up1Runs[0].Finish(mock.NewRunResult(nil, false), nil)
+	if _, err := e.PollForNumberRunning(up1.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+	s.Tick(6)
+	if _, err := e.PollForNumberRunning(down.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Once both succeed, the downstream task runs on the next tick, with the same now.
+	up2Runs[0].Finish(mock.NewRunResult(nil, false), nil)
+	if _, err := e.PollForNumberRunning(up2.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	var running []*mock.RunPromise
+	for i := 0; i < 50 && len(running) == 0; i++ {
+		s.Tick(6)
+		running = e.RunningFor(down.ID)
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(running) != 1 {
+		t.Fatalf("expected 1 run of downstream task, got %d", len(running))
+	}
+	if now := running[0].Run().Now; now != 6 {
+		t.Fatalf("expected downstream run to have now 6, got %d", now)
+	}
+}
+
+func TestScheduler_DependsOnAcrossSchedulers(t *testing.T) {
+	d := mock.NewDesiredState()
+	e := mock.NewExecutor()
+	upSched := backend.NewScheduler(d, e, backend.NopLogWriter{}, 5, backend.WithLogger(zaptest.NewLogger(t)))
+	upSched.Start(context.Background())
+	defer upSched.Stop()
+	downSched := backend.NewScheduler(d, e, backend.NopLogWriter{}, 5, backend.WithLogger(zaptest.NewLogger(t)))
+	downSched.Start(context.Background())
+	defer downSched.Stop()
+
+	up := &backend.StoreTask{ID: platform.ID(1)}
+	down := &backend.StoreTask{ID: platform.ID(2)}
+
+	upMeta := &backend.StoreTaskMeta{
+		MaxConcurrency:  1,
+		EffectiveCron:   "@every 1s",
+		LatestCompleted: 5,
+	}
+	downMeta := &backend.StoreTaskMeta{
+		MaxConcurrency:  1,
+		LatestCompleted: 5,
+	}
+	downMeta.SetDependsOn([]platform.ID{up.ID})
+
+	d.SetTaskMeta(up.ID, *upMeta)
+	d.SetTaskMeta(down.ID, *downMeta)
+	if err := upSched.ClaimTask(up, upMeta); err != nil {
+		t.Fatal(err)
+	}
+	if err := downSched.ClaimTask(down, downMeta); err != nil {
+		t.Fatal(err)
+	}
+
+	upSched.Tick(6)
+	upRuns, err := e.PollForNumberRunning(up.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	upRuns[0].Finish(mock.NewRunResult(nil, false), nil)
+	if _, err := e.PollForNumberRunning(up.ID, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// The run is queued in the desired state, where the scheduler claiming the downstream task is told about it,
+	// as the coordinator does when syncing its leases.
+	var running []*mock.RunPromise
+	for i := 0; i < 50 && len(running) == 0; i++ {
+		downSched.ManualRunsQueued(down.ID)
+		downSched.Tick(6)
+		running = e.RunningFor(down.ID)
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(running) != 1 {
+		t.Fatalf("expected 1 run of downstream task, got %d", len(running))
+	}
+	if now := running[0].Run().Now; now != 6 {
+		t.Fatalf("expected downstream run to have now 6, got %d", now)
+	}
+}
+
 func TestScheduler_Metrics(t *testing.T) {
 	d := mock.NewDesiredState()
 	e := mock.NewExecutor()
@@ -636,6 +861,130 @@ func TestScheduler_Metrics(t *testing.T) {
 	}
 }
 
+func TestScheduler_ConcurrencyLimits(t *testing.T) {
+	orgA, orgB := platform.ID(100), platform.ID(200)
+	meta := backend.StoreTaskMeta{
+		MaxConcurrency:  1,
+		EffectiveCron:   "@every 1s",
+		LatestCompleted: 5,
+	}
+
+	// claim claims a task with the given ID belonging to the given org.
+	claim := func(t *testing.T, s backend.Scheduler, d *mock.DesiredState, id, org platform.ID) {
+		t.Helper()
+		m := meta
+		d.SetTaskMeta(id, m)
+		if err := s.ClaimTask(&backend.StoreTask{ID: id, Org: org}, &m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// pollForQueued waits until the number of queued runs for org matches exp.
+	pollForQueued := func(t *testing.T, reg *prom.Registry, org platform.ID, exp float64) {
+		t.Helper()
+		var got float64
+		for i := 0; i < 50; i++ {
+			mfs := promtest.MustGather(t, reg)
+			if m := promtest.FindMetric(mfs, "task_scheduler_runs_queued", map[string]string{"org_id": org.String()}); m != nil {
+				got = *m.Gauge.Value
+			}
+			if got == exp {
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		t.Fatalf("expected %v runs queued for org %s, got %v", exp, org, got)
+	}
+
+	t.Run("per org", func(t *testing.T) {
+		d := mock.NewDesiredState()
+		e := mock.NewExecutor()
+		s := backend.NewScheduler(d, e, backend.NopLogWriter{}, 5, backend.WithConcurrencyLimits(1, 0))
+		s.Start(context.Background())
+		defer s.Stop()
+
+		reg := prom.NewRegistry()
+		reg.MustRegister(s.PrometheusCollectors()...)
+
+		claim(t, s, d, 1, orgA)
+		claim(t, s, d, 2, orgA)
+		claim(t, s, d, 3, orgB)
+		s.Tick(6)
+
+		// One run per org executes; the second run for org A waits.
+		if _, err := e.PollForNumberRunning(3, 1); err != nil {
+			t.Fatal(err)
+		}
+		pollForQueued(t, reg, orgA, 1)
+		running := len(e.RunningFor(1)) + len(e.RunningFor(2))
+		if running != 1 {
+			t.Fatalf("expected 1 run executing for org A, got %d", running)
+		}
+
+		// Finishing org A's run lets its other run execute.
+		first, second := platform.ID(1), platform.ID(2)
+		if len(e.RunningFor(first)) == 0 {
+			first, second = second, first
+		}
+		e.RunningFor(first)[0].Finish(mock.NewRunResult(nil, false), nil)
+		if _, err := e.PollForNumberRunning(second, 1); err != nil {
+			t.Fatal(err)
+		}
+		pollForQueued(t, reg, orgA, 0)
+
+		// The label of org A is removed once it has no queued runs.
+		mfs := promtest.MustGather(t, reg)
+		if m := promtest.FindMetric(mfs, "task_scheduler_runs_queued", map[string]string{"org_id": orgA.String()}); m != nil {
+			t.Fatalf("expected metric to be removed after the queue drained, got %v", m)
+		}
+	})
+
+	t.Run("global round robin", func(t *testing.T) {
+		d := mock.NewDesiredState()
+		e := mock.NewExecutor()
+		s := backend.NewScheduler(d, e, backend.NopLogWriter{}, 5, backend.WithConcurrencyLimits(0, 1))
+		s.Start(context.Background())
+		defer s.Stop()
+
+		reg := prom.NewRegistry()
+		reg.MustRegister(s.PrometheusCollectors()...)
+
+		// Org A has many runs due; only one executes at a time.
+		aTasks := []platform.ID{1, 2, 3}
+		for _, id := range aTasks {
+			claim(t, s, d, id, orgA)
+		}
+		s.Tick(6)
+		pollForQueued(t, reg, orgA, 2)
+
+		// Org B's run arrives after org A's runs are queued.
+		claim(t, s, d, 4, orgB)
+		pollForQueued(t, reg, orgB, 1)
+
+		var executing *mock.RunPromise
+		for _, id := range aTasks {
+			if r := e.RunningFor(id); len(r) == 1 {
+				executing = r[0]
+			}
+		}
+		if executing == nil {
+			t.Fatal("expected a run executing for org A")
+		}
+
+		// Org B gets the freed slot before org A's remaining runs.
+		executing.Finish(mock.NewRunResult(nil, false), nil)
+		rs, err := e.PollForNumberRunning(4, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pollForQueued(t, reg, orgB, 0)
+		pollForQueued(t, reg, orgA, 2)
+
+		rs[0].Finish(mock.NewRunResult(nil, false), nil)
+		pollForQueued(t, reg, orgA, 1)
+	})
+}
+
 type fakeWaitExecutor struct {
 	wait chan struct{}
 }
@@ -28,11 +28,17 @@ var (
 	// ErrManualQueueFull is returned when a manual run request cannot be completed.
 	ErrManualQueueFull = errors.New("manual queue at capacity")
 
+	// ErrManualRunNotFound is returned when a queued manual run range doesn't exist.
+	ErrManualRunNotFound = errors.New("manual run not found")
+
 	// ErrRunNotFound is returned when searching for a run that doesn't exist.
 	ErrRunNotFound = errors.New("run not found")
 
 	// ErrRunNotFinished is returned when a retry is invalid due to the run not being finished yet.
 	ErrRunNotFinished = errors.New("run is still in progress")
+
+	// ErrTaskDependencyCycle is returned when a task would directly or indirectly depend on itself.
+	ErrTaskDependencyCycle = errors.New("task dependency cycle")
 )
 
 type TaskStatus string
@@ -228,6 +234,18 @@ type Store interface {
 	// ManuallyRunTimeRange must delegate to an underlying StoreTaskMeta's ManuallyRunTimeRange method.
 	ManuallyRunTimeRange(ctx context.Context, taskID platform.ID, start, end, requestedAt int64) (*StoreTaskMetaManualRun, error)
 
+	// CancelManualRun removes the queued manual run range with the given ID from the task with the given ID.
+	// It returns the IDs of runs created from that range which are still in progress, so that the caller may cancel them.
+	// CancelManualRun must delegate to an underlying StoreTaskMeta's CancelManualRun method.
+	CancelManualRun(ctx context.Context, taskID, manualRunID platform.ID) ([]platform.ID, error)
+
+	// QueueDependentRuns records that the run of the task with the given ID succeeded for the Unix timestamp now,
+	// in the meta of every task that depends on it.
+	// A run for now is queued for each dependent task whose upstream tasks have now all succeeded for now,
+	// and the IDs of those tasks are returned.
+	// QueueDependentRuns must delegate to an underlying StoreTaskMeta's UpstreamSucceeded method.
+	QueueDependentRuns(ctx context.Context, taskID platform.ID, now, requestedAt int64) ([]platform.ID, error)
+
 	// DeleteOrg deletes the org.
 	DeleteOrg(ctx context.Context, orgID platform.ID) error
 
@@ -381,6 +399,61 @@ func (StoreValidation) CreateArgs(req CreateTaskRequest) (options.Options, error
 	return o, nil
 }
 
+// Dependencies returns an error if the task with the given ID, belonging to the organization with the given ID,
+// cannot depend on the given upstream tasks, either because an upstream task does not exist or belongs to another organization,
+// or because the task would then directly or indirectly depend on itself.
+//
+// findTask is called to look up the organization and meta of upstream tasks, and must return ErrTaskNotFound for a missing task.
+func (StoreValidation) Dependencies(taskID, orgID platform.ID, dependsOn []platform.ID, findTask func(platform.ID) (platform.ID, *StoreTaskMeta, error)) error {
+	visited := make(map[platform.ID]bool)
+
+	var visit func(id platform.ID) error
+	visit = func(id platform.ID) error {
+		if id == taskID {
+			return ErrTaskDependencyCycle
+		}
+		if visited[id] {
+			return nil
+		}
+		visited[id] = true
+
+		_, stm, err := findTask(id)
+		if err == ErrTaskNotFound {
+			// An upstream task further up the chain was deleted, so it can't be part of a cycle.
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, upID := range stm.DependsOnIDs() {
+			if err := visit(upID); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, id := range dependsOn {
+		if id != taskID {
+			upOrgID, _, err := findTask(id)
+			if err == ErrTaskNotFound {
+				return fmt.Errorf("upstream task %s not found", id.String())
+			} else if err != nil {
+				return err
+			}
+			if upOrgID != orgID {
+				return fmt.Errorf("upstream task %s belongs to another organization", id.String())
+			}
+		}
+		if err := visit(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // UpdateArgs validates the UpdateTaskRequest.
 // If the update only includes a new status (i.e. req.Script is empty), the returned options are zero.
 // If the update contains neither a new script nor a new status, or if the script is invalid, an error is returned.
@@ -44,6 +44,10 @@ func NewRunStoreTest(name string, crf CreateRunStoreFunc, drf DestroyRunStoreFun
 				t.Parallel()
 				listLogsTest(t, crf, drf)
 			})
+			t.Run("Filters", func(t *testing.T) {
+				t.Parallel()
+				filtersTest(t, crf, drf)
+			})
 		})
 	}
 }
@@ -180,6 +184,7 @@ func listRunsTest(t *testing.T, crf CreateRunStoreFunc, drf DestroyRunStoreFunc)
 	now := time.Now().UTC()
 	const nRuns = 150
 	runs := make([]platform.Run, nRuns)
+	rlbs := make([]backend.RunLogBase, nRuns)
 	for i := 0; i < len(runs); i++ {
 		// Scheduled for times ascending with IDs.
 		scheduledFor := now.Add(time.Duration(-2*(nRuns-i)) * time.Second)
@@ -194,6 +199,7 @@ func listRunsTest(t *testing.T, crf CreateRunStoreFunc, drf DestroyRunStoreFunc)
 			RunID:           runs[i].ID,
 			RunScheduledFor: scheduledFor.Unix(),
 		}
+		rlbs[i] = rlb
 
 		err := writer.UpdateRunState(ctx, rlb, scheduledFor.Add(time.Second), backend.RunStarted)
 		if err != nil {
@@ -276,6 +282,32 @@ func listRunsTest(t *testing.T, crf CreateRunStoreFunc, drf DestroyRunStoreFunc)
 	if len(listRuns) != beforeTimeIdx {
 		t.Fatalf("retrieved: %d, expected: %d", len(listRuns), beforeTimeIdx)
 	}
+
+	// The limit applies to the runs matching the status filter, not to the runs before filtering.
+	for i := 0; i < nRuns/2; i++ {
+		scheduledFor, _ := time.Parse(time.RFC3339, runs[i].ScheduledFor)
+		if err := writer.UpdateRunState(ctx, rlbs[i], scheduledFor.Add(2*time.Second), backend.RunSuccess); err != nil {
+			t.Fatal(err)
+		}
+	}
+	listRuns, err = reader.ListRuns(ctx, platform.RunFilter{
+		Task:   &task.ID,
+		Org:    &task.Org,
+		Status: backend.RunStarted.String(),
+		Limit:  30,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(listRuns) != 30 {
+		t.Fatalf("retrieved: %d, expected: %d", len(listRuns), 30)
+	}
+	for _, r := range listRuns {
+		if r.Status != backend.RunStarted.String() {
+			t.Fatalf("retrieved run %s with status %q, expected %q", r.ID, r.Status, backend.RunStarted.String())
+		}
+	}
 }
 
 func findRunByIDTest(t *testing.T, crf CreateRunStoreFunc, drf DestroyRunStoreFunc) {
@@ -399,6 +431,127 @@ func listLogsTest(t *testing.T, crf CreateRunStoreFunc, drf DestroyRunStoreFunc)
 	}
 }
 
+func filtersTest(t *testing.T, crf CreateRunStoreFunc, drf DestroyRunStoreFunc) {
+	writer, reader := crf(t)
+	defer drf(t, writer, reader)
+
+	task := &backend.StoreTask{
+		ID:  platformtesting.MustIDBase16("ab01ab01ab01ab01"),
+		Org: platformtesting.MustIDBase16("ab01ab01ab01ab05"),
+	}
+
+	ctx := pcontext.SetAuthorizer(context.Background(), makeNewAuthorization())
+
+	base := time.Now().UTC().Add(-time.Minute).Truncate(time.Second)
+	type logLine struct {
+		offset time.Duration
+		line   string
+	}
+	for i, r := range []struct {
+		status backend.RunStatus
+		logs   []logLine
+	}{
+		{status: backend.RunSuccess, logs: []logLine{{time.Second, "Started"}, {5 * time.Second, "Wrote 10 points"}}},
+		{status: backend.RunFail, logs: []logLine{{time.Second, "Started"}, {2 * time.Second, "Error: bucket not found"}}},
+		{status: backend.RunStarted, logs: []logLine{{time.Second, "Started"}}},
+	} {
+		sf := base.Add(time.Duration(i) * 10 * time.Second)
+		rlb := backend.RunLogBase{
+			Task:            task,
+			RunID:           platform.ID(i + 1),
+			RunScheduledFor: sf.Unix(),
+		}
+		if err := writer.UpdateRunState(ctx, rlb, sf, backend.RunStarted); err != nil {
+			t.Fatal(err)
+		}
+		for _, l := range r.logs {
+			if err := writer.AddRunLog(ctx, rlb, sf.Add(l.offset), l.line); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if r.status != backend.RunStarted {
+			if err := writer.UpdateRunState(ctx, rlb, sf.Add(9*time.Second), r.status); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	runIDs := func(runs []*platform.Run) []platform.ID {
+		ids := make([]platform.ID, len(runs))
+		for i, r := range runs {
+			ids[i] = r.ID
+		}
+		return ids
+	}
+
+	for _, tc := range []struct {
+		name   string
+		filter platform.RunFilter
+		exp    []platform.ID
+	}{
+		{name: "status", filter: platform.RunFilter{Status: backend.RunFail.String()}, exp: []platform.ID{2}},
+		{name: "match", filter: platform.RunFilter{Match: "NOT FOUND"}, exp: []platform.ID{2}},
+		{name: "status and match", filter: platform.RunFilter{Status: backend.RunSuccess.String(), Match: "error"}, exp: []platform.ID{}},
+	} {
+		t.Run("ListRuns "+tc.name, func(t *testing.T) {
+			tc.filter.Task = &task.ID
+			tc.filter.Org = &task.Org
+			runs, err := reader.ListRuns(ctx, tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.exp, runIDs(runs)); diff != "" {
+				t.Fatalf("unexpected runs: -want/+got: %s", diff)
+			}
+		})
+	}
+
+	format := func(offset time.Duration, line string) string {
+		return base.Add(offset).Format(time.RFC3339Nano) + ": " + line
+	}
+	for _, tc := range []struct {
+		name   string
+		filter platform.LogFilter
+		exp    []platform.Log
+	}{
+		{
+			name:   "status",
+			filter: platform.LogFilter{Status: backend.RunFail.String()},
+			exp: []platform.Log{platform.Log(
+				format(11*time.Second, "Started") + "\n" + format(12*time.Second, "Error: bucket not found"),
+			)},
+		},
+		{
+			name:   "match",
+			filter: platform.LogFilter{Match: "points"},
+			exp:    []platform.Log{platform.Log(format(5*time.Second, "Wrote 10 points"))},
+		},
+		{
+			name: "time window",
+			filter: platform.LogFilter{
+				AfterTime:  base.Add(3 * time.Second).Format(time.RFC3339),
+				BeforeTime: base.Add(15 * time.Second).Format(time.RFC3339),
+			},
+			exp: []platform.Log{
+				platform.Log(format(5*time.Second, "Wrote 10 points")),
+				platform.Log(format(11*time.Second, "Started") + "\n" + format(12*time.Second, "Error: bucket not found")),
+			},
+		},
+	} {
+		t.Run("ListLogs "+tc.name, func(t *testing.T) {
+			tc.filter.Task = &task.ID
+			tc.filter.Org = &task.Org
+			logs, err := reader.ListLogs(ctx, tc.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.exp, logs); diff != "" {
+				t.Fatalf("unexpected logs: -want/+got: %s", diff)
+			}
+		})
+	}
+}
+
 func makeNewAuthorization() *platform.Authorization {
 	return &platform.Authorization{
 		ID:          platformtesting.MustIDBase16("ab01ab01ab01ab01"),
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -46,6 +47,7 @@ func NewStoreTest(name string, cf CreateStoreFunc, df DestroyStoreFunc, funcName
 		"CreateNextRun":        testStoreCreateNextRun,
 		"FinishRun":            testStoreFinishRun,
 		"ManuallyRunTimeRange": testStoreManuallyRunTimeRange,
+		"CancelManualRun":      testStoreCancelManualRun,
 		"DeleteOrg":            testStoreDeleteOrg,
 		"DeleteUser":           testStoreDeleteUser,
 	}
@@ -126,6 +128,7 @@ from(bucket:"x") |> range(start:-1h)`
 	const script2 = `option task = {
 		name: "a task2",
 		cron: "* * * * *",
+		retry: 3,
 	}
 
 from(bucket:"y") |> range(start:-1h)`
@@ -158,6 +161,9 @@ from(bucket:"y") |> range(start:-1h)`
 		if task.Name != "a task2" {
 			t.Fatalf("Task didn't update name, expected 'a task2' but got '%s' for task %v", task.Name, task)
 		}
+		if meta.MaxRetry != 3 {
+			t.Fatalf("Task didn't update max retry, expected 3 but got %d", meta.MaxRetry)
+		}
 		if meta.Status != string(backend.TaskActive) {
 			// Other tests explicitly check the initial status against DefaultTaskStatus,
 			// but in this case we need to be certain of the initial status so we can toggle it correctly.
@@ -237,6 +243,109 @@ from(bucket:"y") |> range(start:-1h)`
 			}
 		})
 	}
+	t.Run("dependencies", func(t *testing.T) {
+		s := create(t)
+		defer destroy(t, s)
+
+		dependentScript := func(ids ...platform.ID) string {
+			quoted := make([]string, len(ids))
+			for i, id := range ids {
+				quoted[i] = fmt.Sprintf("%q", id.String())
+			}
+			return fmt.Sprintf(`option task = {
+		name: "dependent",
+		dependsOn: [%s],
+	}
+
+from(bucket:"x") |> range(start:-1h)`, strings.Join(quoted, ", "))
+		}
+
+		if _, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, User: 2, Script: dependentScript(platform.ID(7123))}); err == nil {
+			t.Fatal("expected error when depending on a task that does not exist")
+		}
+
+		upID, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, User: 2, Script: script})
+		if err != nil {
+			t.Fatal(err)
+		}
+		downID, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, User: 2, Script: dependentScript(upID)})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		meta, err := s.FindTaskMetaByID(context.Background(), downID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ids := meta.DependsOnIDs(); len(ids) != 1 || ids[0] != upID {
+			t.Fatalf("expected meta to depend on %s, got %v", upID, ids)
+		}
+
+		// Tasks cannot depend on tasks of another organization.
+		if _, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 3, User: 2, Script: dependentScript(upID)}); err == nil || !strings.Contains(err.Error(), "another organization") {
+			t.Fatalf("expected error when depending on a task of another organization, got %v", err)
+		}
+		otherID, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 3, User: 2, Script: script})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.UpdateTask(context.Background(), backend.UpdateTaskRequest{ID: otherID, Script: dependentScript(upID)}); err == nil || !strings.Contains(err.Error(), "another organization") {
+			t.Fatalf("expected error when updating a task to depend on a task of another organization, got %v", err)
+		}
+
+		// A successful upstream run queues a run of the downstream task once all of its upstream tasks succeeded.
+		up2ID, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, User: 2, Script: script})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := s.UpdateTask(context.Background(), backend.UpdateTaskRequest{ID: downID, Script: dependentScript(upID, up2ID)}); err != nil {
+			t.Fatal(err)
+		}
+		if queued, err := s.QueueDependentRuns(context.Background(), upID, 60, 100); err != nil {
+			t.Fatal(err)
+		} else if len(queued) != 0 {
+			t.Fatalf("expected no runs queued before all upstream tasks succeeded, got %v", queued)
+		}
+		meta, err = s.FindTaskMetaByID(context.Background(), downID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(meta.DependencyRuns) != 1 || meta.DependencyRuns[0].Now != 60 || len(meta.ManualRuns) != 0 {
+			t.Fatalf("expected the upstream success to be recorded without queueing a run, got %+v", meta)
+		}
+		if queued, err := s.QueueDependentRuns(context.Background(), up2ID, 60, 100); err != nil {
+			t.Fatal(err)
+		} else if len(queued) != 1 || queued[0] != downID {
+			t.Fatalf("expected a run of %s to be queued, got %v", downID, queued)
+		}
+		meta, err = s.FindTaskMetaByID(context.Background(), downID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(meta.DependencyRuns) != 0 || len(meta.ManualRuns) != 1 || meta.ManualRuns[0].Start != 60 || meta.ManualRuns[0].End != 60 {
+			t.Fatalf("expected a run queued for 60, got %+v", meta)
+		}
+
+		// Making the upstream task depend on the downstream task would create a cycle.
+		if _, err := s.UpdateTask(context.Background(), backend.UpdateTaskRequest{ID: upID, Script: dependentScript(downID)}); err == nil || !strings.Contains(err.Error(), backend.ErrTaskDependencyCycle.Error()) {
+			t.Fatalf("expected dependency cycle error, got %v", err)
+		}
+
+		// So would a task depending on itself.
+		if _, err := s.UpdateTask(context.Background(), backend.UpdateTaskRequest{ID: downID, Script: dependentScript(upID, downID)}); err == nil || !strings.Contains(err.Error(), backend.ErrTaskDependencyCycle.Error()) {
+			t.Fatalf("expected dependency cycle error, got %v", err)
+		}
+
+		// Removing the dependency through an update is reflected in the meta.
+		res, err := s.UpdateTask(context.Background(), backend.UpdateTaskRequest{ID: downID, Script: script})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ids := res.NewMeta.DependsOnIDs(); len(ids) != 0 {
+			t.Fatalf("expected no dependencies after update, got %v", ids)
+		}
+	})
+
 	t.Run("name repetition", func(t *testing.T) {
 		s := create(t)
 		defer destroy(t, s)
@@ -502,6 +611,7 @@ func testStoreFindMeta(t *testing.T, create CreateStoreFunc, destroy DestroyStor
 		cron: "* * * * *",
 		concurrency: 3,
 		offset: 5s,
+		retry: 4,
 	}
 
 from(bucket:"test") |> range(start:-1h)`
@@ -527,6 +637,10 @@ from(bucket:"test") |> range(start:-1h)`
 			t.Fatal("failed to set max concurrency")
 		}
 
+		if meta.MaxRetry != 4 {
+			t.Fatalf("unexpected max retry stored in meta: %d", meta.MaxRetry)
+		}
+
 		if meta.LatestCompleted != 6000 {
 			t.Fatalf("LatestCompleted should have been set to 6000, got %d", meta.LatestCompleted)
 		}
@@ -984,6 +1098,56 @@ from(bucket:"test") |> range(start:-1h)`
 	}
 }
 
+func testStoreCancelManualRun(t *testing.T, create CreateStoreFunc, destroy DestroyStoreFunc) {
+	const script = `option task = {
+		name: "a task",
+		cron: "* * * * *",
+	}
+
+from(bucket:"test") |> range(start:-1h)`
+	s := create(t)
+	defer destroy(t, s)
+
+	taskID, err := s.CreateTask(context.Background(), backend.CreateTaskRequest{Org: 1, User: 2, Script: script, ScheduleAfter: 6000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mr, err := s.ManuallyRunTimeRange(context.Background(), taskID, 60, 600, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !platform.ID(mr.ID).Valid() {
+		t.Fatal("expected manual run to be assigned an ID")
+	}
+
+	// The task's next natural run is well after 100, so this run must come from the queue.
+	rc, err := s.CreateNextRun(context.Background(), taskID, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	running, err := s.CancelManualRun(context.Background(), taskID, platform.ID(mr.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(running) != 1 || running[0] != rc.Created.RunID {
+		t.Fatalf("expected canceling to report run %s in progress, got %v", rc.Created.RunID, running)
+	}
+
+	meta, err := s.FindTaskMetaByID(context.Background(), taskID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(meta.ManualRuns) != 0 {
+		t.Fatalf("expected manual run to be removed, got %d", len(meta.ManualRuns))
+	}
+
+	if _, err := s.CancelManualRun(context.Background(), taskID, platform.ID(mr.ID)); err != backend.ErrManualRunNotFound {
+		t.Fatalf("expected ErrManualRunNotFound, got %v", err)
+	}
+}
+
 func testStoreDeleteUser(t *testing.T, create CreateStoreFunc, destroy DestroyStoreFunc) {
 	s := create(t)
 	defer destroy(t, s)
@@ -134,6 +134,18 @@ func (s *Scheduler) TaskFor(id platform.ID) *Task {
 	return s.claims[id.String()]
 }
 
+// TaskMetaFor returns the meta that the task with the given ID was last claimed or updated with,
+// or nil if the task is not claimed.
+func (s *Scheduler) TaskMetaFor(id platform.ID) *backend.StoreTaskMeta {
+	s.Lock()
+	defer s.Unlock()
+	meta, ok := s.meta[id.String()]
+	if !ok {
+		return nil
+	}
+	return &meta
+}
+
 func (s *Scheduler) TaskCreateChan() <-chan *Task {
 	s.createChan = make(chan *Task, 10)
 	return s.createChan
@@ -161,6 +173,8 @@ func (s *Scheduler) CancelRun(_ context.Context, taskID, runID platform.ID) erro
 	return nil
 }
 
+func (s *Scheduler) ManualRunsQueued(taskID platform.ID) {}
+
 // DesiredState is a mock implementation of DesiredState (used by NewScheduler).
 type DesiredState struct {
 	mu sync.Mutex
@@ -208,13 +222,7 @@ func (d *DesiredState) CreateNextRun(_ context.Context, taskID platform.ID, now
 		panic(fmt.Sprintf("meta not set for task with ID %s", tid))
 	}
 
-	makeID := func() (platform.ID, error) {
-		d.runIDs[tid]++
-		runID := platform.ID(d.runIDs[tid])
-		return runID, nil
-	}
-
-	rc, err := meta.CreateNextRun(now, makeID)
+	rc, err := meta.CreateNextRun(now, d.makeIDFunc(tid))
 	if err != nil {
 		return backend.RunCreation{}, err
 	}
@@ -224,6 +232,40 @@ func (d *DesiredState) CreateNextRun(_ context.Context, taskID platform.ID, now
 	return rc, nil
 }
 
+// QueueDependentRuns records the successful run of the given task in every task meta that depends on it,
+// delegating to the task meta's UpstreamSucceeded.
+func (d *DesiredState) QueueDependentRuns(_ context.Context, taskID platform.ID, now, requestedAt int64) ([]platform.ID, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var queued []platform.ID
+	for tid, meta := range d.meta {
+		ok, err := meta.UpstreamSucceeded(taskID, now, requestedAt, d.makeIDFunc(tid))
+		if err != nil {
+			return nil, err
+		}
+		d.meta[tid] = meta
+		if ok {
+			id, err := platform.IDFromString(tid)
+			if err != nil {
+				return nil, err
+			}
+			queued = append(queued, *id)
+		}
+	}
+	return queued, nil
+}
+
+// makeIDFunc returns a function that creates the next run ID for the given task.
+// d.mu must be held when calling the returned function.
+func (d *DesiredState) makeIDFunc(tid string) func() (platform.ID, error) {
+	return func() (platform.ID, error) {
+		d.runIDs[tid]++
+		runID := platform.ID(d.runIDs[tid])
+		return runID, nil
+	}
+}
+
 func (d *DesiredState) FinishRun(_ context.Context, taskID, runID platform.ID) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
@@ -10,6 +10,8 @@ import (
 
 	"github.com/influxdata/flux"
 	"github.com/influxdata/flux/semantic"
+	"github.com/influxdata/flux/values"
+	"github.com/influxdata/platform"
 	cron "gopkg.in/robfig/cron.v2"
 )
 
@@ -45,6 +47,11 @@ type Options struct {
 	Concurrency int64
 
 	Retry int64
+
+	// DependsOn lists the IDs of upstream tasks, in place of Cron or Every.
+	// A task with dependencies runs with the same now as its upstream tasks,
+	// once every upstream task has successfully completed a run for that now.
+	DependsOn []platform.ID
 }
 
 // FromScript extracts Options from a Flux script.
@@ -85,11 +92,15 @@ func FromScript(script string) (Options, error) {
 
 	crVal, cronOK := optObject.Get("cron")
 	everyVal, everyOK := optObject.Get("every")
+	dependsOnVal, dependsOnOK := optObject.Get("dependsOn")
 	if cronOK && everyOK {
 		return opt, errors.New("cannot use both cron and every in task options")
 	}
-	if !cronOK && !everyOK {
-		return opt, errors.New("cron or every is required")
+	if dependsOnOK && (cronOK || everyOK) {
+		return opt, errors.New("cannot use dependsOn with cron or every in task options")
+	}
+	if !cronOK && !everyOK && !dependsOnOK {
+		return opt, errors.New("cron, every or dependsOn is required")
 	}
 
 	if cronOK {
@@ -106,6 +117,30 @@ func FromScript(script string) (Options, error) {
 		opt.Every = everyVal.Duration().Duration()
 	}
 
+	if dependsOnOK {
+		if err := checkNature(dependsOnVal.PolyType().Nature(), semantic.Array); err != nil {
+			return opt, err
+		}
+		var err error
+		dependsOnVal.Array().Range(func(i int, v values.Value) {
+			if err != nil {
+				return
+			}
+			if err = checkNature(v.PolyType().Nature(), semantic.String); err != nil {
+				return
+			}
+			var id *platform.ID
+			if id, err = platform.IDFromString(v.Str()); err != nil {
+				err = fmt.Errorf("invalid task ID in dependsOn: %q", v.Str())
+				return
+			}
+			opt.DependsOn = append(opt.DependsOn, *id)
+		})
+		if err != nil {
+			return opt, err
+		}
+	}
+
 	if offsetVal, ok := optObject.Get("offset"); ok {
 		if err := checkNature(offsetVal.PolyType().Nature(), semantic.Duration); err != nil {
 			return opt, err
@@ -149,7 +184,22 @@ func (o *Options) Validate() error {
 
 	cronPresent := o.Cron != ""
 	everyPresent := o.Every != 0
-	if cronPresent == everyPresent {
+	if len(o.DependsOn) > 0 {
+		if cronPresent || everyPresent {
+			errs = append(errs, "cannot specify dependsOn with cron or every")
+		}
+		for i, id := range o.DependsOn {
+			if !id.Valid() {
+				errs = append(errs, "dependsOn contains an invalid task ID")
+				break
+			}
+			for _, other := range o.DependsOn[:i] {
+				if id == other {
+					errs = append(errs, "dependsOn contains duplicate task ID "+id.String())
+				}
+			}
+		}
+	} else if cronPresent == everyPresent {
 		// They're both present or both missing.
 		errs = append(errs, "must specify exactly one of either cron or every")
 	} else if cronPresent {
@@ -192,7 +242,7 @@ func (o *Options) Validate() error {
 // EffectiveCronString returns the effective cron string of the options.
 // If the cron option was specified, it is returned.
 // If the every option was specified, it is converted into a cron string using "@every".
-// Otherwise, the empty string is returned.
+// Otherwise, for example when the task only runs after its dependencies, the empty string is returned.
 // The value of the offset option is not considered.
 func (o *Options) EffectiveCronString() string {
 	if o.Cron != "" {
@@ -220,6 +220,88 @@ func (p pAdapter) ForceRun(ctx context.Context, taskID platform.ID, scheduledFor
 	}, nil
 }
 
+func (p pAdapter) BackfillTask(ctx context.Context, taskID platform.ID, start, stop int64) (*platform.Backfill, error) {
+	if start > stop {
+		return nil, errors.New("backfill start must not be after stop")
+	}
+
+	requestedAt := time.Now().Unix()
+	m, err := p.s.ManuallyRunTimeRange(ctx, taskID, start, stop, requestedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	stm, err := p.s.FindTaskMetaByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := stm.ManualRunProgress()
+	if err != nil {
+		return nil, err
+	}
+	for _, mp := range progress {
+		if mp.ID == platform.ID(m.ID) {
+			return toPlatformBackfill(taskID, mp), nil
+		}
+	}
+
+	// All the runs of the range finished before we could look at it.
+	return toPlatformBackfill(taskID, backend.ManualRunProgress{
+		ID:          platform.ID(m.ID),
+		Start:       m.Start,
+		End:         m.End,
+		RequestedAt: m.RequestedAt,
+	}), nil
+}
+
+func (p pAdapter) FindBackfills(ctx context.Context, taskID platform.ID) ([]*platform.Backfill, error) {
+	stm, err := p.s.FindTaskMetaByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := stm.ManualRunProgress()
+	if err != nil {
+		return nil, err
+	}
+
+	bs := make([]*platform.Backfill, 0, len(progress))
+	for _, mp := range progress {
+		if mp.Start == mp.End {
+			// Single forced runs and retries are reported as runs, not backfills.
+			continue
+		}
+		bs = append(bs, toPlatformBackfill(taskID, mp))
+	}
+	return bs, nil
+}
+
+func (p pAdapter) CancelBackfill(ctx context.Context, taskID, backfillID platform.ID) error {
+	running, err := p.s.CancelManualRun(ctx, taskID, backfillID)
+	if err != nil {
+		return err
+	}
+
+	for _, runID := range running {
+		// The run may have finished in the meantime, or be executing on another scheduler.
+		if err := p.rc.CancelRun(ctx, taskID, runID); err != nil && err != backend.ErrRunNotFound && err != backend.ErrTaskNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+func toPlatformBackfill(taskID platform.ID, mp backend.ManualRunProgress) *platform.Backfill {
+	return &platform.Backfill{
+		ID:          mp.ID,
+		TaskID:      taskID,
+		Start:       time.Unix(mp.Start, 0).UTC().Format(time.RFC3339),
+		Stop:        time.Unix(mp.End, 0).UTC().Format(time.RFC3339),
+		RequestedAt: time.Unix(mp.RequestedAt, 0).UTC().Format(time.RFC3339),
+		Completed:   mp.Completed,
+		Remaining:   mp.Remaining,
+	}
+}
+
 func (p pAdapter) CancelRun(ctx context.Context, taskID, runID platform.ID) error {
 	return p.rc.CancelRun(ctx, taskID, runID)
 }
@@ -537,6 +537,67 @@ func testTaskRuns(t *testing.T, sys *System) {
 		}
 	})
 
+	t.Run("Backfill", func(t *testing.T) {
+		t.Parallel()
+
+		task := &platform.Task{Organization: orgID, Owner: platform.User{ID: userID}, Flux: fmt.Sprintf(scriptFmt, 0)}
+		if err := sys.ts.CreateTask(sys.Ctx, task); err != nil {
+			t.Fatal(err)
+		}
+
+		// The task runs every minute, so there are 10 schedules in this range.
+		const start, stop = 60, 600
+		b, err := sys.ts.BackfillTask(sys.Ctx, task.ID, start, stop)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !b.ID.Valid() {
+			t.Fatal("expected backfill to have a valid ID")
+		}
+		if b.TaskID != task.ID {
+			t.Fatalf("expected task ID %s, got %s", task.ID, b.TaskID)
+		}
+		if exp := time.Unix(start, 0).UTC().Format(time.RFC3339); b.Start != exp {
+			t.Fatalf("expected start %s, got %s", exp, b.Start)
+		}
+		if exp := time.Unix(stop, 0).UTC().Format(time.RFC3339); b.Stop != exp {
+			t.Fatalf("expected stop %s, got %s", exp, b.Stop)
+		}
+		if b.Completed != 0 || b.Remaining != 10 {
+			t.Fatalf("expected 0 completed and 10 remaining, got %d and %d", b.Completed, b.Remaining)
+		}
+
+		// Requesting the same range before it's executed should be rejected.
+		exp := backend.RequestStillQueuedError{Start: start, End: stop}
+		if _, err := sys.ts.BackfillTask(sys.Ctx, task.ID, start, stop); err != exp {
+			t.Fatalf("subsequent backfill should have been rejected with %v; got %v", exp, err)
+		}
+
+		bs, err := sys.ts.FindBackfills(sys.Ctx, task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(bs) != 1 || bs[0].ID != b.ID {
+			t.Fatalf("expected to find backfill %s, got %#v", b.ID, bs)
+		}
+
+		if err := sys.ts.CancelBackfill(sys.Ctx, task.ID, b.ID); err != nil {
+			t.Fatal(err)
+		}
+
+		bs, err = sys.ts.FindBackfills(sys.Ctx, task.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(bs) != 0 {
+			t.Fatalf("expected no backfills after cancel, got %#v", bs)
+		}
+
+		if err := sys.ts.CancelBackfill(sys.Ctx, task.ID, b.ID); err != backend.ErrManualRunNotFound {
+			t.Fatalf("expected ErrManualRunNotFound canceling twice, got %v", err)
+		}
+	})
+
 	t.Run("FindLogs", func(t *testing.T) {
 		t.Parallel()
 
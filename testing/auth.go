@@ -5,12 +5,18 @@ import (
 	"context"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/influxdata/platform"
 	"github.com/influxdata/platform/mock"
+	"github.com/influxdata/platform/rand"
 )
 
+// authTokenHasher hashes the tokens of authorizations in tests that need their hashes.
+var authTokenHasher = rand.NewTokenHasher([]byte("token hash key"))
+
 const (
 	authZeroID  = "020f755c3c081000"
 	authOneID   = "020f755c3c082000"
@@ -19,6 +25,8 @@ const (
 )
 
 var authorizationCmpOptions = cmp.Options{
+	// Token hashes depend on the key of the service, and are not returned by every service.
+	cmpopts.IgnoreFields(platform.Authorization{}, "TokenHash", "PreviousTokenHash", "PreviousTokenExpiresAt"),
 	cmp.Comparer(func(x, y []byte) bool {
 		return bytes.Equal(x, y)
 	}),
@@ -38,6 +46,8 @@ type AuthorizationFields struct {
 	Authorizations []*platform.Authorization
 	Users          []*platform.User
 	Orgs           []*platform.Organization
+	TokenHasher    platform.TokenHasher
+	NowFn          func() time.Time
 }
 
 // AuthorizationService tests all the service functions.
@@ -69,6 +79,10 @@ func AuthorizationService(
 			name: "FindAuthorizations",
 			fn:   FindAuthorizations,
 		},
+		{
+			name: "RotateAuthorization",
+			fn:   RotateAuthorization,
+		},
 		{
 			name: "DeleteAuthorization",
 			fn:   DeleteAuthorization,
@@ -103,6 +117,7 @@ func CreateAuthorization(
 		{
 			name: "basic create authorization",
 			fields: AuthorizationFields{
+				NowFn:       func() time.Time { return time.Date(2009, time.November, 10, 24, 0, 0, 0, time.UTC) },
 				IDGenerator: mock.NewIDGenerator(authTwoID, t),
 				TokenGenerator: &mock.TokenGenerator{
 					TokenFn: func() (string, error) {
@@ -147,7 +162,6 @@ func CreateAuthorization(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "supersecret",
 						Permissions: allUsersPermission(),
 						Description: "already existing auth",
 					},
@@ -155,10 +169,10 @@ func CreateAuthorization(
 						ID:          MustIDBase16(authTwoID),
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(),
 						Description: "new auth",
+						CreatedAt:   time.Date(2009, time.November, 10, 24, 0, 0, 0, time.UTC),
 					},
 				},
 			},
@@ -166,6 +180,7 @@ func CreateAuthorization(
 		{
 			name: "if auth ID supplied it is ignored",
 			fields: AuthorizationFields{
+				NowFn:       func() time.Time { return time.Date(2009, time.November, 10, 24, 0, 0, 0, time.UTC) },
 				IDGenerator: mock.NewIDGenerator(authTwoID, t),
 				TokenGenerator: &mock.TokenGenerator{
 					TokenFn: func() (string, error) {
@@ -209,16 +224,15 @@ func CreateAuthorization(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "supersecret",
 						Permissions: allUsersPermission(),
 					},
 					{
 						ID:          MustIDBase16(authTwoID),
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(),
+						CreatedAt:   time.Date(2009, time.November, 10, 24, 0, 0, 0, time.UTC),
 					},
 				},
 			},
@@ -270,7 +284,6 @@ func CreateAuthorization(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "supersecret",
 						Permissions: allUsersPermission(),
 						Description: "already existing auth",
 					},
@@ -325,7 +338,6 @@ func CreateAuthorization(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "supersecret",
 						Permissions: allUsersPermission(),
 						Description: "already existing auth",
 					},
@@ -424,7 +436,6 @@ func FindAuthorizationByID(
 					UserID:      MustIDBase16(userTwoID),
 					OrgID:       MustIDBase16(orgOneID),
 					Status:      platform.Active,
-					Token:       "rand2",
 					Permissions: createUsersPermission(),
 				},
 			},
@@ -530,7 +541,6 @@ func UpdateAuthorizationStatus(
 					ID:          MustIDBase16(authTwoID),
 					UserID:      MustIDBase16(userTwoID),
 					OrgID:       MustIDBase16(orgOneID),
-					Token:       "rand2",
 					Permissions: createUsersPermission(),
 					Status:      platform.Inactive,
 				},
@@ -769,11 +779,92 @@ func FindAuthorizationByToken(
 					UserID:      MustIDBase16(userOneID),
 					OrgID:       MustIDBase16(orgTwoID),
 					Status:      platform.Inactive,
-					Token:       "rand1",
 					Permissions: allUsersPermission(),
 				},
 			},
 		},
+		{
+			name: "find authorization by previous token within its grace period",
+			fields: AuthorizationFields{
+				NowFn:       func() time.Time { return time.Date(2009, time.November, 10, 24, 0, 0, 0, time.UTC) },
+				TokenHasher: authTokenHasher,
+				Users: []*platform.User{
+					{
+						Name: "cooluser",
+						ID:   MustIDBase16(userOneID),
+					},
+				},
+				Orgs: []*platform.Organization{
+					{
+						Name: "o1",
+						ID:   MustIDBase16(orgOneID),
+					},
+				},
+				Authorizations: []*platform.Authorization{
+					{
+						ID:                     MustIDBase16(authOneID),
+						UserID:                 MustIDBase16(userOneID),
+						OrgID:                  MustIDBase16(orgOneID),
+						Token:                  "rand2",
+						PreviousTokenHash:      authTokenHasher.HashToken("rand1"),
+						PreviousTokenExpiresAt: timePtr(time.Date(2009, time.November, 11, 1, 0, 0, 0, time.UTC)),
+						Permissions:            allUsersPermission(),
+					},
+				},
+			},
+			args: args{
+				token: "rand1",
+			},
+			wants: wants{
+				authorization: &platform.Authorization{
+					ID:          MustIDBase16(authOneID),
+					UserID:      MustIDBase16(userOneID),
+					OrgID:       MustIDBase16(orgOneID),
+					Status:      platform.Active,
+					Permissions: allUsersPermission(),
+				},
+			},
+		},
+		{
+			name: "previous token is not found after its grace period",
+			fields: AuthorizationFields{
+				NowFn:       func() time.Time { return time.Date(2009, time.November, 11, 2, 0, 0, 0, time.UTC) },
+				TokenHasher: authTokenHasher,
+				Users: []*platform.User{
+					{
+						Name: "cooluser",
+						ID:   MustIDBase16(userOneID),
+					},
+				},
+				Orgs: []*platform.Organization{
+					{
+						Name: "o1",
+						ID:   MustIDBase16(orgOneID),
+					},
+				},
+				Authorizations: []*platform.Authorization{
+					{
+						ID:                     MustIDBase16(authOneID),
+						UserID:                 MustIDBase16(userOneID),
+						OrgID:                  MustIDBase16(orgOneID),
+						Token:                  "rand2",
+						PreviousTokenHash:      authTokenHasher.HashToken("rand1"),
+						PreviousTokenExpiresAt: timePtr(time.Date(2009, time.November, 11, 1, 0, 0, 0, time.UTC)),
+						Permissions:            allUsersPermission(),
+					},
+				},
+			},
+			args: args{
+				token: "rand1",
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Msg:  "authorization not found",
+					Op:   platform.OpFindAuthorizationByToken,
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -856,7 +947,6 @@ func FindAuthorizations(
 						ID:          MustIDBase16(authOneID),
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand1",
 						Status:      platform.Active,
 						Permissions: allUsersPermission(),
 					},
@@ -864,7 +954,6 @@ func FindAuthorizations(
 						ID:          MustIDBase16(authTwoID),
 						UserID:      MustIDBase16(userTwoID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand2",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(),
 					},
@@ -925,7 +1014,6 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand1",
 						Permissions: allUsersPermission(),
 					},
 					{
@@ -933,7 +1021,6 @@ func FindAuthorizations(
 						UserID:      MustIDBase16(userOneID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand3",
 						Permissions: deleteUsersPermission(),
 					},
 				},
@@ -998,7 +1085,6 @@ func FindAuthorizations(
 						ID:          MustIDBase16(authTwoID),
 						UserID:      MustIDBase16(userTwoID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand2",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(),
 					},
@@ -1098,7 +1184,6 @@ func DeleteAuthorization(
 						UserID:      MustIDBase16(userTwoID),
 						OrgID:       MustIDBase16(orgOneID),
 						Status:      platform.Active,
-						Token:       "rand2",
 						Permissions: createUsersPermission(),
 					},
 				},
@@ -1153,7 +1238,6 @@ func DeleteAuthorization(
 					{
 						ID:          MustIDBase16(authOneID),
 						UserID:      MustIDBase16(userOneID),
-						Token:       "rand1",
 						Status:      platform.Active,
 						OrgID:       MustIDBase16(orgOneID),
 						Permissions: allUsersPermission(),
@@ -1162,7 +1246,6 @@ func DeleteAuthorization(
 						ID:          MustIDBase16(authTwoID),
 						UserID:      MustIDBase16(userTwoID),
 						OrgID:       MustIDBase16(orgOneID),
-						Token:       "rand2",
 						Status:      platform.Active,
 						Permissions: createUsersPermission(),
 					},
@@ -1191,6 +1274,174 @@ func DeleteAuthorization(
 	}
 }
 
+// RotateAuthorization testing
+func RotateAuthorization(
+	init func(AuthorizationFields, *testing.T) (platform.AuthorizationService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id          platform.ID
+		gracePeriod time.Duration
+	}
+	type wants struct {
+		err            error
+		authorization  *platform.Authorization
+		authorizations []*platform.Authorization
+	}
+
+	tests := []struct {
+		name   string
+		fields AuthorizationFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "rotate authorization token",
+			fields: AuthorizationFields{
+				NowFn: func() time.Time { return time.Date(2009, time.November, 10, 24, 0, 0, 0, time.UTC) },
+				TokenGenerator: &mock.TokenGenerator{
+					TokenFn: func() (string, error) {
+						return "rand3", nil
+					},
+				},
+				Users: []*platform.User{
+					{
+						Name: "cooluser",
+						ID:   MustIDBase16(userOneID),
+					},
+				},
+				Orgs: []*platform.Organization{
+					{
+						Name: "o1",
+						ID:   MustIDBase16(orgOneID),
+					},
+				},
+				Authorizations: []*platform.Authorization{
+					{
+						ID:          MustIDBase16(authOneID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Token:       "rand1",
+						Permissions: allUsersPermission(),
+					},
+					{
+						ID:          MustIDBase16(authTwoID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Token:       "rand2",
+						Permissions: createUsersPermission(),
+					},
+				},
+			},
+			args: args{
+				id:          MustIDBase16(authOneID),
+				gracePeriod: time.Hour,
+			},
+			wants: wants{
+				authorization: &platform.Authorization{
+					ID:          MustIDBase16(authOneID),
+					UserID:      MustIDBase16(userOneID),
+					OrgID:       MustIDBase16(orgOneID),
+					Status:      platform.Active,
+					Token:       "rand3",
+					Permissions: allUsersPermission(),
+				},
+				authorizations: []*platform.Authorization{
+					{
+						ID:          MustIDBase16(authOneID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Status:      platform.Active,
+						Permissions: allUsersPermission(),
+					},
+					{
+						ID:          MustIDBase16(authTwoID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Status:      platform.Active,
+						Permissions: createUsersPermission(),
+					},
+				},
+			},
+		},
+		{
+			name: "rotate authorization that does not exist",
+			fields: AuthorizationFields{
+				NowFn: func() time.Time { return time.Date(2009, time.November, 10, 24, 0, 0, 0, time.UTC) },
+				TokenGenerator: &mock.TokenGenerator{
+					TokenFn: func() (string, error) {
+						return "rand3", nil
+					},
+				},
+				Users: []*platform.User{
+					{
+						Name: "cooluser",
+						ID:   MustIDBase16(userOneID),
+					},
+				},
+				Orgs: []*platform.Organization{
+					{
+						Name: "o1",
+						ID:   MustIDBase16(orgOneID),
+					},
+				},
+				Authorizations: []*platform.Authorization{
+					{
+						ID:          MustIDBase16(authOneID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Token:       "rand1",
+						Permissions: allUsersPermission(),
+					},
+				},
+			},
+			args: args{
+				id:          MustIDBase16(authThreeID),
+				gracePeriod: time.Hour,
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Msg:  "authorization not found",
+					Op:   platform.OpRotateAuthorization,
+				},
+				authorizations: []*platform.Authorization{
+					{
+						ID:          MustIDBase16(authOneID),
+						UserID:      MustIDBase16(userOneID),
+						OrgID:       MustIDBase16(orgOneID),
+						Status:      platform.Active,
+						Permissions: allUsersPermission(),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			authorization, err := s.RotateAuthorization(ctx, tt.args.id, tt.args.gracePeriod)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			if diff := cmp.Diff(authorization, tt.wants.authorization, authorizationCmpOptions...); diff != "" {
+				t.Errorf("authorization is different -got/+want\ndiff %s", diff)
+			}
+
+			authorizations, _, err := s.FindAuthorizations(ctx, platform.AuthorizationFilter{})
+			if err != nil {
+				t.Fatalf("failed to retrieve authorizations: %v", err)
+			}
+			if diff := cmp.Diff(authorizations, tt.wants.authorizations, authorizationCmpOptions...); diff != "" {
+				t.Errorf("authorizations are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
 func allUsersPermission() []platform.Permission {
 	return []platform.Permission{
 		{Action: platform.WriteAction, Resource: platform.UsersResource},
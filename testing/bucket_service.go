@@ -988,9 +988,11 @@ func UpdateBucket(
 	t *testing.T,
 ) {
 	type args struct {
-		name      string
-		id        platform.ID
-		retention int
+		name               string
+		id                 platform.ID
+		retention          int
+		schemaType         platform.SchemaType
+		measurementSchemas []platform.MeasurementSchema
 	}
 	type wants struct {
 		err    error
@@ -1111,6 +1113,51 @@ func UpdateBucket(
 				},
 			},
 		},
+		{
+			name: "update schema",
+			fields: BucketFields{
+				Organizations: []*platform.Organization{
+					{
+						Name: "theorg",
+						ID:   MustIDBase16(orgOneID),
+					},
+				},
+				Buckets: []*platform.Bucket{
+					{
+						ID:             MustIDBase16(bucketOneID),
+						OrganizationID: MustIDBase16(orgOneID),
+						Name:           "bucket1",
+					},
+				},
+			},
+			args: args{
+				id:         MustIDBase16(bucketOneID),
+				schemaType: platform.SchemaTypeExplicit,
+				measurementSchemas: []platform.MeasurementSchema{
+					{
+						Name:   "cpu",
+						Tags:   []string{"host"},
+						Fields: []platform.FieldSchema{{Name: "usage", Type: platform.SchemaFieldTypeFloat}},
+					},
+				},
+			},
+			wants: wants{
+				bucket: &platform.Bucket{
+					ID:             MustIDBase16(bucketOneID),
+					OrganizationID: MustIDBase16(orgOneID),
+					Organization:   "theorg",
+					Name:           "bucket1",
+					SchemaType:     platform.SchemaTypeExplicit,
+					MeasurementSchemas: []platform.MeasurementSchema{
+						{
+							Name:   "cpu",
+							Tags:   []string{"host"},
+							Fields: []platform.FieldSchema{{Name: "usage", Type: platform.SchemaFieldTypeFloat}},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1127,6 +1174,10 @@ func UpdateBucket(
 				d := time.Duration(tt.args.retention) * time.Minute
 				upd.RetentionPeriod = &d
 			}
+			if tt.args.schemaType != "" {
+				upd.SchemaType = &tt.args.schemaType
+			}
+			upd.MeasurementSchemas = tt.args.measurementSchemas
 
 			bucket, err := s.UpdateBucket(ctx, tt.args.id, upd)
 			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
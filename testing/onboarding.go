@@ -16,6 +16,7 @@ type OnboardingFields struct {
 	IDGenerator    platform.IDGenerator
 	TokenGenerator platform.TokenGenerator
 	IsOnboarding   bool
+	NowFn          func() time.Time
 }
 
 // Generate testing
@@ -135,6 +136,7 @@ func Generate(
 				},
 				TokenGenerator: mock.NewTokenGenerator(oneToken, nil),
 				IsOnboarding:   true,
+				NowFn:          func() time.Time { return time.Date(2009, time.November, 10, 24, 0, 0, 0, time.UTC) },
 			},
 			args: args{
 				request: &platform.OnboardingRequest{
@@ -171,6 +173,7 @@ func Generate(
 						Description: "admin's Token",
 						OrgID:       MustIDBase16(twoID),
 						Permissions: mustGeneratePermissions(MustIDBase16(twoID), MustIDBase16(threeID)),
+						CreatedAt:   time.Date(2009, time.November, 10, 24, 0, 0, 0, time.UTC),
 					},
 				},
 			},
@@ -190,7 +193,7 @@ func Generate(
 					t.Fatalf("expected error code to match '%s' got '%v'", tt.wants.errCode, code)
 				}
 			}
-			if diff := cmp.Diff(results, tt.wants.results); diff != "" {
+			if diff := cmp.Diff(results, tt.wants.results, authorizationCmpOptions...); diff != "" {
 				t.Errorf("onboarding results are different -got/+want\ndiff %s", diff)
 			}
 			if results != nil {
@@ -0,0 +1,581 @@
+package testing
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/platform"
+	"github.com/influxdata/platform/mock"
+)
+
+const (
+	roleOneID   = "020f755c3c085000"
+	roleTwoID   = "020f755c3c085001"
+	roleThreeID = "020f755c3c085002"
+)
+
+var roleCmpOptions = cmp.Options{
+	cmp.Transformer("Sort", func(in []*platform.Role) []*platform.Role {
+		out := append([]*platform.Role(nil), in...)
+		sort.Slice(out, func(i, j int) bool {
+			return out[i].ID.String() > out[j].ID.String()
+		})
+		return out
+	}),
+}
+
+// RoleFields will include the IDGenerator, and roles
+type RoleFields struct {
+	IDGenerator   platform.IDGenerator
+	Organizations []*platform.Organization
+	Roles         []*platform.Role
+}
+
+// readBucketsIn returns the permission to read every bucket of the organization id.
+func readBucketsIn(id string) []platform.Permission {
+	orgID := MustIDBase16(id)
+	return []platform.Permission{{Action: platform.ReadAction, Resource: platform.BucketsResource, OrgID: &orgID}}
+}
+
+// CreateRole testing
+func CreateRole(
+	init func(RoleFields, *testing.T) (platform.RoleService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		role *platform.Role
+	}
+	type wants struct {
+		err   error
+		roles []*platform.Role
+	}
+
+	tests := []struct {
+		name   string
+		fields RoleFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "create roles with empty set",
+			fields: RoleFields{
+				IDGenerator: mock.NewIDGenerator(roleOneID, t),
+				Organizations: []*platform.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+			},
+			args: args{
+				role: &platform.Role{
+					OrganizationID: MustIDBase16(orgOneID),
+					Name:           "viewer",
+					Permissions:    readBucketsIn(orgOneID),
+				},
+			},
+			wants: wants{
+				roles: []*platform.Role{
+					{
+						ID:             MustIDBase16(roleOneID),
+						OrganizationID: MustIDBase16(orgOneID),
+						Name:           "viewer",
+						Permissions:    readBucketsIn(orgOneID),
+					},
+				},
+			},
+		},
+		{
+			name: "names are unique within an organization",
+			fields: RoleFields{
+				IDGenerator: mock.NewIDGenerator(roleTwoID, t),
+				Organizations: []*platform.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+					{ID: MustIDBase16(orgTwoID), Name: "otherorg"},
+				},
+				Roles: []*platform.Role{
+					{
+						ID:             MustIDBase16(roleOneID),
+						OrganizationID: MustIDBase16(orgTwoID),
+						Name:           "viewer",
+						Permissions:    readBucketsIn(orgTwoID),
+					},
+				},
+			},
+			args: args{
+				role: &platform.Role{
+					OrganizationID: MustIDBase16(orgOneID),
+					Name:           "viewer",
+					Permissions:    readBucketsIn(orgOneID),
+				},
+			},
+			wants: wants{
+				roles: []*platform.Role{
+					{
+						ID:             MustIDBase16(roleOneID),
+						OrganizationID: MustIDBase16(orgTwoID),
+						Name:           "viewer",
+						Permissions:    readBucketsIn(orgTwoID),
+					},
+					{
+						ID:             MustIDBase16(roleTwoID),
+						OrganizationID: MustIDBase16(orgOneID),
+						Name:           "viewer",
+						Permissions:    readBucketsIn(orgOneID),
+					},
+				},
+			},
+		},
+		{
+			name: "names must be unique within an organization",
+			fields: RoleFields{
+				IDGenerator: mock.NewIDGenerator(roleTwoID, t),
+				Organizations: []*platform.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+				Roles: []*platform.Role{
+					{
+						ID:             MustIDBase16(roleOneID),
+						OrganizationID: MustIDBase16(orgOneID),
+						Name:           "viewer",
+						Permissions:    readBucketsIn(orgOneID),
+					},
+				},
+			},
+			args: args{
+				role: &platform.Role{
+					OrganizationID: MustIDBase16(orgOneID),
+					Name:           "viewer",
+				},
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.EConflict,
+					Op:   platform.OpCreateRole,
+					Msg:  "role with name viewer already exists",
+				},
+				roles: []*platform.Role{
+					{
+						ID:             MustIDBase16(roleOneID),
+						OrganizationID: MustIDBase16(orgOneID),
+						Name:           "viewer",
+						Permissions:    readBucketsIn(orgOneID),
+					},
+				},
+			},
+		},
+		{
+			name: "permissions must be restricted to the organization of the role",
+			fields: RoleFields{
+				IDGenerator: mock.NewIDGenerator(roleOneID, t),
+				Organizations: []*platform.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+			},
+			args: args{
+				role: &platform.Role{
+					OrganizationID: MustIDBase16(orgOneID),
+					Name:           "viewer",
+					Permissions: []platform.Permission{
+						{Action: platform.ReadAction, Resource: platform.BucketsResource},
+					},
+				},
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.EInvalid,
+					Op:   platform.OpCreateRole,
+					Msg:  "role permission read:buckets is not restricted to organization 020f755c3c083000",
+				},
+				roles: []*platform.Role{},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.CreateRole(ctx, tt.args.role)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			var roles []*platform.Role
+			for _, o := range tt.fields.Organizations {
+				rs, _, err := s.FindRoles(ctx, platform.RoleFilter{OrganizationID: &o.ID})
+				if err != nil {
+					t.Fatalf("failed to retrieve roles: %v", err)
+				}
+				roles = append(roles, rs...)
+			}
+			if diff := cmp.Diff(roles, tt.wants.roles, roleCmpOptions...); diff != "" {
+				t.Errorf("roles are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindRoleByID testing
+func FindRoleByID(
+	init func(RoleFields, *testing.T) (platform.RoleService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id platform.ID
+	}
+	type wants struct {
+		err  error
+		role *platform.Role
+	}
+
+	tests := []struct {
+		name   string
+		fields RoleFields
+		args   args
+		wants  wants
+	}{
+		{
+			name: "basic find role by id",
+			fields: RoleFields{
+				Organizations: []*platform.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+				Roles: []*platform.Role{
+					{
+						ID:             MustIDBase16(roleOneID),
+						OrganizationID: MustIDBase16(orgOneID),
+						Name:           "viewer",
+						Permissions:    readBucketsIn(orgOneID),
+					},
+					{
+						ID:             MustIDBase16(roleTwoID),
+						OrganizationID: MustIDBase16(orgOneID),
+						Name:           "editor",
+						Description:    "changes the dashboards",
+					},
+				},
+			},
+			args: args{
+				id: MustIDBase16(roleTwoID),
+			},
+			wants: wants{
+				role: &platform.Role{
+					ID:             MustIDBase16(roleTwoID),
+					OrganizationID: MustIDBase16(orgOneID),
+					Name:           "editor",
+					Description:    "changes the dashboards",
+				},
+			},
+		},
+		{
+			name: "find role by id not exists",
+			fields: RoleFields{
+				Organizations: []*platform.Organization{
+					{ID: MustIDBase16(orgOneID), Name: "theorg"},
+				},
+				Roles: []*platform.Role{
+					{
+						ID:             MustIDBase16(roleOneID),
+						OrganizationID: MustIDBase16(orgOneID),
+						Name:           "viewer",
+					},
+				},
+			},
+			args: args{
+				id: MustIDBase16(roleThreeID),
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Op:   platform.OpFindRoleByID,
+					Msg:  platform.ErrRoleNotFound,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			role, err := s.FindRoleByID(ctx, tt.args.id)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			if diff := cmp.Diff(role, tt.wants.role); diff != "" {
+				t.Errorf("role is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// FindRoles testing
+func FindRoles(
+	init func(RoleFields, *testing.T) (platform.RoleService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		organizationID *platform.ID
+		name           *string
+	}
+	type wants struct {
+		roles []*platform.Role
+		err   error
+	}
+
+	roles := []*platform.Role{
+		{
+			ID:             MustIDBase16(roleOneID),
+			OrganizationID: MustIDBase16(orgOneID),
+			Name:           "viewer",
+		},
+		{
+			ID:             MustIDBase16(roleTwoID),
+			OrganizationID: MustIDBase16(orgOneID),
+			Name:           "editor",
+		},
+		{
+			ID:             MustIDBase16(roleThreeID),
+			OrganizationID: MustIDBase16(orgTwoID),
+			Name:           "viewer",
+		},
+	}
+	fields := RoleFields{
+		Organizations: []*platform.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "theorg"},
+			{ID: MustIDBase16(orgTwoID), Name: "otherorg"},
+		},
+		Roles: roles,
+	}
+	name := "viewer"
+
+	tests := []struct {
+		name   string
+		fields RoleFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "find roles of organization",
+			fields: fields,
+			args: args{
+				organizationID: idPtr(MustIDBase16(orgOneID)),
+			},
+			wants: wants{
+				roles: roles[:2],
+			},
+		},
+		{
+			name:   "find role by name in organization",
+			fields: fields,
+			args: args{
+				organizationID: idPtr(MustIDBase16(orgTwoID)),
+				name:           &name,
+			},
+			wants: wants{
+				roles: roles[2:],
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			filter := platform.RoleFilter{
+				OrganizationID: tt.args.organizationID,
+				Name:           tt.args.name,
+			}
+			roles, _, err := s.FindRoles(ctx, filter)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			if diff := cmp.Diff(roles, tt.wants.roles, roleCmpOptions...); diff != "" {
+				t.Errorf("roles are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// UpdateRole testing
+func UpdateRole(
+	init func(RoleFields, *testing.T) (platform.RoleService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id  platform.ID
+		upd platform.RoleUpdate
+	}
+	type wants struct {
+		err  error
+		role *platform.Role
+	}
+
+	fields := RoleFields{
+		Organizations: []*platform.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "theorg"},
+		},
+		Roles: []*platform.Role{
+			{
+				ID:             MustIDBase16(roleOneID),
+				OrganizationID: MustIDBase16(orgOneID),
+				Name:           "viewer",
+			},
+			{
+				ID:             MustIDBase16(roleTwoID),
+				OrganizationID: MustIDBase16(orgOneID),
+				Name:           "editor",
+			},
+		},
+	}
+	reader, editor := "reader", "editor"
+	permissions := readBucketsIn(orgOneID)
+
+	tests := []struct {
+		name   string
+		fields RoleFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "update name and permissions",
+			fields: fields,
+			args: args{
+				id: MustIDBase16(roleOneID),
+				upd: platform.RoleUpdate{
+					Name:        &reader,
+					Permissions: &permissions,
+				},
+			},
+			wants: wants{
+				role: &platform.Role{
+					ID:             MustIDBase16(roleOneID),
+					OrganizationID: MustIDBase16(orgOneID),
+					Name:           "reader",
+					Permissions:    readBucketsIn(orgOneID),
+				},
+			},
+		},
+		{
+			name:   "update name to the name of another role",
+			fields: fields,
+			args: args{
+				id: MustIDBase16(roleOneID),
+				upd: platform.RoleUpdate{
+					Name: &editor,
+				},
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.EConflict,
+					Op:   platform.OpUpdateRole,
+					Msg:  "role with name editor already exists",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			role, err := s.UpdateRole(ctx, tt.args.id, tt.args.upd)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			if diff := cmp.Diff(role, tt.wants.role); diff != "" {
+				t.Errorf("role is different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
+
+// DeleteRole testing
+func DeleteRole(
+	init func(RoleFields, *testing.T) (platform.RoleService, string, func()),
+	t *testing.T,
+) {
+	type args struct {
+		id platform.ID
+	}
+	type wants struct {
+		err   error
+		roles []*platform.Role
+	}
+
+	roles := []*platform.Role{
+		{
+			ID:             MustIDBase16(roleOneID),
+			OrganizationID: MustIDBase16(orgOneID),
+			Name:           "viewer",
+		},
+		{
+			ID:             MustIDBase16(roleTwoID),
+			OrganizationID: MustIDBase16(orgOneID),
+			Name:           "editor",
+		},
+	}
+	fields := RoleFields{
+		Organizations: []*platform.Organization{
+			{ID: MustIDBase16(orgOneID), Name: "theorg"},
+		},
+		Roles: roles,
+	}
+
+	tests := []struct {
+		name   string
+		fields RoleFields
+		args   args
+		wants  wants
+	}{
+		{
+			name:   "delete roles using exist id",
+			fields: fields,
+			args: args{
+				id: MustIDBase16(roleOneID),
+			},
+			wants: wants{
+				roles: roles[1:],
+			},
+		},
+		{
+			name:   "delete roles using id that does not exist",
+			fields: fields,
+			args: args{
+				id: MustIDBase16(roleThreeID),
+			},
+			wants: wants{
+				err: &platform.Error{
+					Code: platform.ENotFound,
+					Op:   platform.OpDeleteRole,
+					Msg:  platform.ErrRoleNotFound,
+				},
+				roles: roles,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, opPrefix, done := init(tt.fields, t)
+			defer done()
+			ctx := context.Background()
+
+			err := s.DeleteRole(ctx, tt.args.id)
+			diffPlatformErrors(tt.name, err, tt.wants.err, opPrefix, t)
+
+			roles, _, err := s.FindRoles(ctx, platform.RoleFilter{
+				OrganizationID: idPtr(MustIDBase16(orgOneID)),
+			})
+			if err != nil {
+				t.Fatalf("failed to retrieve roles: %v", err)
+			}
+			if diff := cmp.Diff(roles, tt.wants.roles, roleCmpOptions...); diff != "" {
+				t.Errorf("roles are different -got/+want\ndiff %s", diff)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package testing
 
 import (
 	"testing"
+	"time"
 
 	"github.com/influxdata/platform"
 )
@@ -33,6 +34,10 @@ func idPtr(id platform.ID) *platform.ID {
 	return &id
 }
 
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
 // MustIDBase16 is an helper to ensure a correct ID is built during testing.
 func MustIDBase16(s string) platform.ID {
 	id, err := platform.IDFromString(s)
@@ -5,3 +5,10 @@ type TokenGenerator interface {
 	// Token generates a new API token.
 	Token() (string, error)
 }
+
+// TokenHasher represents the keyed hash API tokens are stored as, so that stored
+// authorizations do not reveal their tokens.
+type TokenHasher interface {
+	// HashToken returns the hash of an API token.
+	HashToken(token string) string
+}
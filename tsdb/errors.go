@@ -45,6 +45,9 @@ type PartialWriteError struct {
 
 	// A sorted slice of series keys that were dropped.
 	DroppedKeys [][]byte
+
+	// The reason each key in DroppedKeys was dropped for, by key. Reason is the first of them.
+	DroppedReasons map[string]string
 }
 
 func (e PartialWriteError) Error() string {
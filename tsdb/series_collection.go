@@ -21,9 +21,10 @@ type SeriesCollection struct {
 	SeriesIDs  []SeriesID
 
 	// Keeps track of invalid entries.
-	Dropped     uint64
-	DroppedKeys [][]byte
-	Reason      string
+	Dropped        uint64
+	DroppedKeys    [][]byte
+	DroppedReasons map[string]string // The first reason each key was dropped for, by key.
+	Reason         string
 
 	// Used by the concurrent iterators to stage drops. Inefficient, but should be
 	// very infrequently used.
@@ -34,7 +35,7 @@ type SeriesCollection struct {
 type seriesCollectionState struct {
 	mu     sync.Mutex
 	reason string
-	index  map[int]struct{}
+	index  map[int]string // The first reason each entry was dropped for, by index.
 }
 
 // NewSeriesCollection builds a SeriesCollection from a slice of points. It does some filtering
@@ -204,10 +205,33 @@ func (s *SeriesCollection) InvalidateAll(reason string) {
 		s.Reason = reason
 	}
 	s.Dropped += uint64(len(s.Keys))
-	s.DroppedKeys = append(s.DroppedKeys, s.Keys...)
+	for _, key := range s.Keys {
+		s.dropKey(key, reason)
+	}
 	s.Truncate(0)
 }
 
+// Drop records the key of an entry that is invalid for the given reason. Unlike Invalid, the caller
+// removes the entry itself, such as with Copy and Truncate. Only the first reason of each key is kept.
+func (s *SeriesCollection) Drop(key []byte, reason string) {
+	if s.Reason == "" {
+		s.Reason = reason
+	}
+	s.Dropped++
+	s.dropKey(key, reason)
+}
+
+// dropKey adds key to the dropped keys, keeping the first reason it was dropped for.
+func (s *SeriesCollection) dropKey(key []byte, reason string) {
+	s.DroppedKeys = append(s.DroppedKeys, key)
+	if s.DroppedReasons == nil {
+		s.DroppedReasons = make(map[string]string)
+	}
+	if _, ok := s.DroppedReasons[string(key)]; !ok {
+		s.DroppedReasons[string(key)] = reason
+	}
+}
+
 // ApplyConcurrentDrops will remove all of the dropped values during concurrent iteration. It should
 // not be called concurrently with any calls to Invalid.
 func (s *SeriesCollection) ApplyConcurrentDrops() {
@@ -218,11 +242,11 @@ func (s *SeriesCollection) ApplyConcurrentDrops() {
 
 	length, j := s.Length(), 0
 	for i := 0; i < length; i++ {
-		if _, ok := state.index[i]; ok {
+		if reason, ok := state.index[i]; ok {
 			s.Dropped++
 
 			if i < len(s.Keys) {
-				s.DroppedKeys = append(s.DroppedKeys, s.Keys[i])
+				s.dropKey(s.Keys[i], reason)
 			}
 
 			continue
@@ -266,9 +290,11 @@ func (s *SeriesCollection) invalidIndex(index int, reason string) {
 
 	state.mu.Lock()
 	if state.index == nil {
-		state.index = make(map[int]struct{})
+		state.index = make(map[int]string)
+	}
+	if _, ok := state.index[index]; !ok {
+		state.index[index] = reason
 	}
-	state.index[index] = struct{}{}
 	if state.reason == "" {
 		state.reason = reason
 	}
@@ -283,9 +309,10 @@ func (s *SeriesCollection) PartialWriteError() error {
 	}
 	droppedKeys := bytesutil.SortDedup(s.DroppedKeys)
 	return PartialWriteError{
-		Reason:      s.Reason,
-		Dropped:     len(droppedKeys),
-		DroppedKeys: droppedKeys,
+		Reason:         s.Reason,
+		Dropped:        len(droppedKeys),
+		DroppedKeys:    droppedKeys,
+		DroppedReasons: s.DroppedReasons,
 	}
 }
 
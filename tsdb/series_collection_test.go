@@ -118,9 +118,10 @@ func TestSeriesCollection(t *testing.T) {
 		collection.InvalidateAll("test reason")
 		assertEqual(t, "length", collection.Length(), 0)
 		assertEqual(t, "error", collection.PartialWriteError(), PartialWriteError{
-			Reason:      "test reason",
-			Dropped:     3,
-			DroppedKeys: bs("ka", "kb", "kc"),
+			Reason:         "test reason",
+			Dropped:        3,
+			DroppedKeys:    bs("ka", "kb", "kc"),
+			DroppedReasons: map[string]string{"ka": "test reason", "kb": "test reason", "kc": "test reason"},
 		})
 	})
 
@@ -133,6 +134,11 @@ func TestSeriesCollection(t *testing.T) {
 				iter.Invalid("test reason")
 			}
 		}
+		for iter := collection.Iterator(); iter.Next(); {
+			if iter.Index() == 2 {
+				iter.Invalid("other reason")
+			}
+		}
 
 		// nothing happens yet: all values are staged
 		assertEqual(t, "length", collection.Length(), 3)
@@ -141,9 +147,37 @@ func TestSeriesCollection(t *testing.T) {
 		collection.ApplyConcurrentDrops()
 		assertEqual(t, "length", collection.Length(), 1)
 		assertEqual(t, "error", collection.PartialWriteError(), PartialWriteError{
-			Reason:      "test reason",
-			Dropped:     2,
-			DroppedKeys: bs("ka", "kc"),
+			Reason:         "test reason",
+			Dropped:        2,
+			DroppedKeys:    bs("ka", "kc"),
+			DroppedReasons: map[string]string{"ka": "test reason", "kc": "test reason"},
+		})
+	})
+
+	t.Run("Drop", func(t *testing.T) {
+		collection := &SeriesCollection{Keys: bs("ka", "kb", "kc")}
+
+		// drop the entries the way a filtering loop does
+		j := 0
+		for iter := collection.Iterator(); iter.Next(); {
+			switch iter.Index() {
+			case 0:
+				collection.Drop(iter.Key(), "reason a")
+			case 2:
+				collection.Drop(iter.Key(), "reason c")
+			default:
+				collection.Copy(j, iter.Index())
+				j++
+			}
+		}
+		collection.Truncate(j)
+
+		assertEqual(t, "keys", collection.Keys, bs("kb"))
+		assertEqual(t, "error", collection.PartialWriteError(), PartialWriteError{
+			Reason:         "reason a",
+			Dropped:        2,
+			DroppedKeys:    bs("ka", "kc"),
+			DroppedReasons: map[string]string{"ka": "reason a", "kc": "reason c"},
 		})
 	})
 }
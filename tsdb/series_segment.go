@@ -398,6 +398,82 @@ func AppendSeriesEntry(dst []byte, flag uint8, id SeriesIDTyped, key []byte) []b
 	return dst
 }
 
+// VerifySeriesSegment returns an error if data, the contents of a series segment file, has an invalid
+// header, contains an entry that is truncated or malformed, or contains data after its last entry.
+func VerifySeriesSegment(data []byte) error {
+	hdr, err := ReadSeriesSegmentHeader(data)
+	if err != nil {
+		return err
+	} else if hdr.Version != SeriesSegmentVersion {
+		return ErrInvalidSeriesSegmentVersion
+	}
+
+	pos := SeriesSegmentHeaderSize
+	for pos < len(data) {
+		flag := data[pos]
+		if flag == 0 {
+			// Segments are preallocated, so everything after the last entry must be zero.
+			for i := pos; i < len(data); i++ {
+				if data[i] != 0 {
+					return fmt.Errorf("unexpected data after last entry at position %d", i)
+				}
+			}
+			return nil
+		} else if !IsValidSeriesEntryFlag(flag) {
+			return fmt.Errorf("invalid entry flag %d at position %d", flag, pos)
+		} else if pos+SeriesEntryHeaderSize > len(data) {
+			return fmt.Errorf("truncated entry at position %d", pos)
+		}
+
+		if binary.BigEndian.Uint64(data[pos+SeriesEntryFlagSize:]) == 0 {
+			return fmt.Errorf("invalid series id 0 at position %d", pos)
+		}
+
+		sz := SeriesEntryHeaderSize
+		if flag == SeriesEntryInsertFlag {
+			n, err := verifySeriesKey(data[pos+SeriesEntryHeaderSize:])
+			if err != nil {
+				return fmt.Errorf("invalid series key at position %d: %v", pos, err)
+			}
+			sz += n
+		}
+		pos += sz
+	}
+	return nil
+}
+
+// verifySeriesKey returns the size of the series key at the start of data, or an error if it is malformed.
+func verifySeriesKey(data []byte) (int, error) {
+	sz, n := binary.Uvarint(data)
+	if n <= 0 || sz == 0 || uint64(len(data)-n) < sz {
+		return 0, errors.New("truncated key")
+	}
+	key := data[n : n+int(sz)]
+
+	if len(key) < 2 || len(key)-2 < int(binary.BigEndian.Uint16(key)) {
+		return 0, errors.New("truncated measurement")
+	}
+	key = key[2+int(binary.BigEndian.Uint16(key)):]
+
+	tagN, i := binary.Uvarint(key)
+	if i <= 0 {
+		return 0, errors.New("truncated tag count")
+	}
+	key = key[i:]
+	for j := uint64(0); j < tagN; j++ {
+		for k := 0; k < 2; k++ { // tag key, then tag value
+			if len(key) < 2 || len(key)-2 < int(binary.BigEndian.Uint16(key)) {
+				return 0, errors.New("truncated tag")
+			}
+			key = key[2+int(binary.BigEndian.Uint16(key)):]
+		}
+	}
+	if len(key) != 0 {
+		return 0, errors.New("unexpected data after tags")
+	}
+	return n + int(sz), nil
+}
+
 // IsValidSeriesEntryFlag returns true if flag is valid.
 func IsValidSeriesEntryFlag(flag byte) bool {
 	switch flag {
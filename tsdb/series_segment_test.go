@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/influxdata/platform/models"
 	"github.com/influxdata/platform/tsdb"
 )
 
@@ -266,3 +267,43 @@ func TestSeriesEntry(t *testing.T) {
 		t.Fatalf("unexpected size: %d", sz)
 	}
 }
+
+func TestVerifySeriesSegment(t *testing.T) {
+	var hdr bytes.Buffer
+	h := tsdb.NewSeriesSegmentHeader()
+	if _, err := h.WriteTo(&hdr); err != nil {
+		t.Fatal(err)
+	}
+	insert := tsdb.AppendSeriesEntry(nil, tsdb.SeriesEntryInsertFlag, toTypedSeriesID(1), tsdb.AppendSeriesKey(nil, []byte("m0"), models.NewTags(map[string]string{"k": "v"})))
+	tombstone := tsdb.AppendSeriesEntry(nil, tsdb.SeriesEntryTombstoneFlag, toTypedSeriesID(1), nil)
+
+	segment := func(parts ...[]byte) []byte {
+		data := append([]byte{}, hdr.Bytes()...)
+		for _, p := range parts {
+			data = append(data, p...)
+		}
+		return data
+	}
+
+	for _, tt := range []struct {
+		name  string
+		data  []byte
+		valid bool
+	}{
+		{name: "empty", data: segment(make([]byte, 16)), valid: true},
+		{name: "entries", data: segment(insert, tombstone, make([]byte, 16)), valid: true},
+		{name: "full", data: segment(insert, tombstone), valid: true},
+		{name: "bad magic", data: append([]byte("XXXX"), segment(insert)[4:]...)},
+		{name: "bad flag", data: segment(insert, []byte{0x07}, make([]byte, 16))},
+		{name: "truncated entry", data: segment(insert[:len(insert)-1])},
+		{name: "truncated key", data: segment(insert[:len(insert)-2], make([]byte, 16))},
+		{name: "trailing data", data: segment(insert, []byte{0, 0, 1})},
+		{name: "zero id", data: segment(tsdb.AppendSeriesEntry(nil, tsdb.SeriesEntryTombstoneFlag, tsdb.SeriesIDTyped{}, nil))},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tsdb.VerifySeriesSegment(tt.data); (err == nil) != tt.valid {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -742,6 +742,12 @@ func (i *Index) DropSeries(seriesID tsdb.SeriesID, key []byte, cascade bool) err
 	return nil
 }
 
+// HasSeries returns true if the series with the id seriesID and the key is in the index.
+// The series file may still have the ids of series that were dropped from the index.
+func (i *Index) HasSeries(seriesID tsdb.SeriesID, key []byte) bool {
+	return i.partition(key).HasSeries(seriesID)
+}
+
 // DropSeriesGlobal is a no-op on the tsi1 index.
 func (i *Index) DropSeriesGlobal(key []byte) error { return nil }
 
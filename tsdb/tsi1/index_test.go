@@ -133,6 +133,38 @@ func TestIndex_MeasurementExists(t *testing.T) {
 	})
 }
 
+func TestIndex_HasSeries(t *testing.T) {
+	idx := MustOpenIndex(1, tsi1.NewConfig())
+	defer idx.Close()
+
+	name, tags := []byte("cpu"), models.NewTags(map[string]string{"region": "east"})
+	if err := idx.CreateSeriesSliceIfNotExists([]Series{{Name: name, Tags: tags}}); err != nil {
+		t.Fatal(err)
+	}
+	sid := idx.Index.SeriesFile().SeriesID(name, tags, nil)
+	if sid.IsZero() {
+		t.Fatalf("got 0 series id for %s/%v", name, tags)
+	}
+
+	idx.Run(t, func(t *testing.T) {
+		if !idx.HasSeries(sid, models.MakeKey(name, tags)) {
+			t.Fatal("expected series to exist")
+		}
+	})
+
+	// The series file keeps the id of a series dropped from the index.
+	if err := idx.DropSeries(sid, models.MakeKey(name, tags), true); err != nil {
+		t.Fatal(err)
+	}
+	idx.Run(t, func(t *testing.T) {
+		if idx.HasSeries(sid, models.MakeKey(name, tags)) {
+			t.Fatal("expected series to be dropped")
+		} else if idx.Index.SeriesFile().SeriesID(name, tags, nil).IsZero() {
+			t.Fatal("expected series id to remain in series file")
+		}
+	})
+}
+
 // Ensure index can return a list of matching measurements.
 func TestIndex_MeasurementNamesByRegex(t *testing.T) {
 	idx := MustOpenIndex(1, tsi1.NewConfig())
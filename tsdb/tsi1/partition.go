@@ -711,6 +711,11 @@ func (p *Partition) createSeriesListIfNotExists(collection *tsdb.SeriesCollectio
 	return ids, nil
 }
 
+// HasSeries returns true if the series id is in the partition.
+func (p *Partition) HasSeries(seriesID tsdb.SeriesID) bool {
+	return p.seriesIDSet.Contains(seriesID)
+}
+
 // DropSeries removes the provided series id from the index.
 //
 // TODO(edd): We should support a bulk drop here.
@@ -0,0 +1,244 @@
+package tsm1
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/influxdata/platform/pkg/file"
+	"go.uber.org/zap"
+)
+
+// WithObjectStore enables offloading cold TSM files to store with OffloadColdFiles,
+// caching up to cacheSize bytes of the blocks read back from it in memory.
+var WithObjectStore = func(store ObjectStore, cacheSize int64) EngineOption {
+	return func(e *Engine) {
+		e.FileStore.WithObjectStore(store, NewBlockCache(cacheSize))
+	}
+}
+
+// OffloadColdFiles offloads the fully compacted TSM files holding only the data of a single
+// measurement for which cold returns true, given the maximum time of the data in the file,
+// to the object store of the engine. A file is fully compacted if no other generation of
+// files holds data of its partition. It returns the number of files offloaded, which is
+// always zero if the engine has no object store.
+//
+// The blocks of offloaded files are fetched from the object store when they are read.
+// Offloaded files are never compacted again.
+func (e *Engine) OffloadColdFiles(cold func(name []byte, maxTime int64) bool) (int, error) {
+	if e.FileStore.objectStore == nil {
+		return 0, nil
+	}
+
+	find := func() []string {
+		stats := e.FileStore.Stats()
+		generations := make(map[string]map[int]struct{})
+		for _, f := range stats {
+			if f.Cold {
+				continue
+			}
+			partition := fileStatPartition(e.Compactor.Partition, f)
+			gen, _, _ := e.FileStore.ParseFileName(f.Path)
+			if generations[partition] == nil {
+				generations[partition] = make(map[int]struct{})
+			}
+			generations[partition][gen] = struct{}{}
+		}
+
+		var paths []string
+		for _, f := range stats {
+			if f.Cold {
+				continue
+			}
+			partition := fileStatPartition(e.Compactor.Partition, f)
+			if partition == "" || len(generations[partition]) != 1 {
+				continue
+			}
+			if cold(compositeKeyName(f.MinKey), f.MaxTime) {
+				paths = append(paths, f.Path)
+			}
+		}
+		return paths
+	}
+
+	if len(find()) == 0 {
+		return 0, nil
+	}
+
+	// Level compactions are stopped so that none of the cold files are being compacted.
+	e.disableLevelCompactions(true)
+	defer e.enableLevelCompactions(true)
+
+	paths := find()
+	for i, path := range paths {
+		if err := e.FileStore.offload(path); err != nil {
+			return i, err
+		}
+	}
+	e.logger.Info("Offloaded cold TSM files", zap.Int("files", len(paths)))
+	return len(paths), nil
+}
+
+// offload stores the TSM file at path in the object store, and replaces it with the stub
+// of a cold TSM file. Queries reading the file while it is offloaded keep reading the
+// local file until they complete.
+func (f *FileStore) offload(path string) error {
+	key := filepath.Base(path)
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	err = f.objectStore.Put(key, src)
+	if e := src.Close(); e != nil && err == nil {
+		err = e
+	}
+	if err != nil {
+		return err
+	}
+
+	tmp := fmt.Sprintf("%s.%s", path, TmpTSMFileExtension)
+	if err := writeColdStub(path, tmp, key); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	old, err := f.replaceWithStub(path, tmp)
+	f.mu.Unlock()
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	} else if old == nil {
+		// The file was removed while it was being offloaded.
+		os.Remove(tmp)
+		return f.objectStore.Delete(key)
+	}
+
+	// Close waits for any queries reading the local file.
+	return old.Close()
+}
+
+// replaceWithStub replaces the TSM file at path with the stub at tmp, returning the reader of
+// the replaced file, or nil if there is no file at path. f.mu must be held.
+func (f *FileStore) replaceWithStub(path, tmp string) (TSMFile, error) {
+	i := -1
+	for j, r := range f.files {
+		if r.Path() == path {
+			i = j
+			break
+		}
+	}
+	if i < 0 {
+		return nil, nil
+	}
+
+	if err := f.obs.FileFinishing(tmp); err != nil {
+		return nil, err
+	}
+	if err := file.RenameFile(tmp, path); err != nil {
+		return nil, err
+	}
+	if err := file.SyncDir(f.dir); err != nil {
+		return nil, err
+	}
+
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewTSMReader(fd,
+		WithMadviseWillNeed(f.tsmMMAPWillNeed),
+		WithTSMReaderLogger(f.logger),
+		WithTSMReaderObjectStore(f.objectStore, f.blockCache))
+	if err != nil {
+		fd.Close()
+		return nil, err
+	}
+	r.WithObserver(f.obs)
+
+	old := f.files[i]
+	f.files[i] = r
+
+	// The data of the file store is unchanged, but the stats of its files are not.
+	f.lastFileStats = nil
+	if lm := time.Unix(0, r.LastModified()).UTC(); lm.After(f.lastModified) {
+		f.lastModified = lm
+	} else {
+		f.lastModified = f.lastModified.UTC().Add(1)
+	}
+
+	var totalSize uint64
+	for _, file := range f.files {
+		totalSize += uint64(file.Size())
+		for _, ts := range file.TombstoneFiles() {
+			totalSize += uint64(ts.Size)
+		}
+	}
+	f.tracker.SetBytes(totalSize)
+
+	return old, nil
+}
+
+// FetchColdFile replaces the stub of the cold TSM file at path with the whole TSM file, fetched
+// from store, such as when copying the file to a backup. The stub is replaced by renaming the
+// fetched file over it, so that other hard links to the stub are left as they are. It returns
+// false and leaves the file as it is if the file at path is not the stub of a cold TSM file.
+func FetchColdFile(store ObjectStore, path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	if cold, err := isColdFile(f); err != nil || !cold {
+		return false, err
+	}
+
+	// Read the key and size of the object from the header of the stub.
+	var header [7]byte
+	if _, err := f.ReadAt(header[:], 0); err != nil {
+		return false, err
+	}
+	b := make([]byte, int(binary.BigEndian.Uint16(header[5:7]))+8)
+	if _, err := f.ReadAt(b, int64(len(header))); err != nil {
+		return false, err
+	}
+	key := string(b[:len(b)-8])
+	size := int64(binary.BigEndian.Uint64(b[len(b)-8:]))
+
+	tmp := fmt.Sprintf("%s.%s", path, TmpTSMFileExtension)
+	out, err := os.OpenFile(tmp, os.O_CREATE|os.O_RDWR|os.O_EXCL, 0666)
+	if err != nil {
+		return false, err
+	}
+	if err := func() error {
+		if _, err := io.Copy(out, io.NewSectionReader(objectReaderAt{store: store, key: key}, 0, size)); err != nil {
+			return err
+		}
+		if err := verifyVersion(out); err != nil {
+			return err
+		}
+		return out.Sync()
+	}(); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return false, err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return false, err
+	}
+	return true, file.RenameFile(tmp, path)
+}
+
+// objectReaderAt is an io.ReaderAt of the object key of an ObjectStore.
+type objectReaderAt struct {
+	store ObjectStore
+	key   string
+}
+
+func (r objectReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	return r.store.ReadAt(r.key, p, off)
+}
@@ -0,0 +1,190 @@
+package tsm1_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform/tsdb/tsm1"
+)
+
+// Ensure cold files are offloaded to the object store and read back from it, also after reopening.
+func TestEngine_OffloadColdFiles(t *testing.T) {
+	objects, err := ioutil.TempDir("", "tsm1-objects-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(objects)
+	store := tsm1.NewDirObjectStore(objects)
+
+	e, err := NewEngine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tsm1.WithPartitionFunc(func(name []byte) time.Duration { return time.Hour })(e.Engine)
+	tsm1.WithObjectStore(store, 1<<20)(e.Engine)
+	if err := e.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	hour := int64(time.Hour)
+	if err := e.WritePointsString(
+		"cpu,host=a value=1 10",
+		"cpu,host=a value=2 20",
+		"cpu,host=a value=3 3600000000010",
+	); err != nil {
+		t.Fatal(err)
+	}
+	e.MustWriteSnapshot()
+
+	cold := func(name []byte, maxTime int64) bool {
+		return bytes.Equal(name, []byte("cpu")) && maxTime < hour
+	}
+	if n, err := e.OffloadColdFiles(cold); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Fatalf("expected 1 file offloaded, got %d", n)
+	}
+	if n, err := e.OffloadColdFiles(cold); err != nil {
+		t.Fatal(err)
+	} else if n != 0 {
+		t.Fatalf("expected cold file not to be offloaded again, got %d", n)
+	}
+
+	var coldPath string
+	for _, f := range e.FileStore.Stats() {
+		if f.Cold != (f.MaxTime < hour) {
+			t.Fatalf("unexpected cold state of file %s: %v", f.Path, f.Cold)
+		}
+		if f.Cold {
+			coldPath = f.Path
+		}
+	}
+	if _, err := os.Stat(filepath.Join(objects, filepath.Base(coldPath))); err != nil {
+		t.Fatalf("expected object of cold file: %v", err)
+	}
+
+	key := tsm1.SeriesFieldKeyBytes("cpu,host=a", "value")
+	read := func(fs *tsm1.FileStore) {
+		t.Helper()
+		values, err := fs.Read(key, 10)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(values) != 2 || values[0].Value() != 1.0 || values[1].Value() != 2.0 {
+			t.Fatalf("unexpected values of cold file: %v", values)
+		}
+		values, err = fs.Read(key, 3600000000010)
+		if err != nil {
+			t.Fatal(err)
+		} else if len(values) != 1 || values[0].Value() != 3.0 {
+			t.Fatalf("unexpected values of local file: %v", values)
+		}
+	}
+	read(e.FileStore)
+
+	// The stub is opened as a cold file by a file store with the object store, and cannot
+	// be opened without one.
+	dir := filepath.Dir(coldPath)
+	fs := tsm1.NewFileStore(dir)
+	fs.WithObjectStore(store, nil)
+	if err := fs.Open(); err != nil {
+		t.Fatal(err)
+	}
+	read(fs)
+	if err := fs.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	fs = tsm1.NewFileStore(dir)
+	if err := fs.Open(); err == nil || !strings.Contains(err.Error(), tsm1.ErrNoObjectStore.Error()) {
+		t.Fatalf("expected error opening cold file without object store, got %v", err)
+	}
+	if _, err := os.Stat(coldPath); err != nil {
+		t.Fatalf("expected cold file to be kept: %v", err)
+	}
+
+	// Fetching the cold file through a hard link replaces the link with the whole file,
+	// which can be read without the object store, and leaves the stub as it is.
+	fetched, err := ioutil.TempDir("", "tsm1-fetched-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(fetched)
+	link := filepath.Join(fetched, filepath.Base(coldPath))
+	if err := os.Link(coldPath, link); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := tsm1.FetchColdFile(store, link); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Fatal("expected cold file to be fetched")
+	}
+	if ok, err := tsm1.FetchColdFile(store, link); err != nil || ok {
+		t.Fatalf("expected fetched file not to be cold, got %v, %v", ok, err)
+	}
+	fs = tsm1.NewFileStore(fetched)
+	if err := fs.Open(); err != nil {
+		t.Fatal(err)
+	}
+	if values, err := fs.Read(key, 10); err != nil {
+		t.Fatal(err)
+	} else if len(values) != 2 || values[0].Value() != 1.0 || values[1].Value() != 2.0 {
+		t.Fatalf("unexpected values of fetched file: %v", values)
+	}
+	if err := fs.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range e.FileStore.Stats() {
+		if f.Path == coldPath && !f.Cold {
+			t.Fatal("expected stub of cold file to be kept")
+		}
+	}
+
+	// Removing the cold file removes its object.
+	if n, err := e.DeleteExpiredFiles(cold); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Fatalf("expected 1 file deleted, got %d", n)
+	}
+	if _, err := os.Stat(filepath.Join(objects, filepath.Base(coldPath))); !os.IsNotExist(err) {
+		t.Fatalf("expected object of deleted cold file to be removed, got %v", err)
+	}
+}
+
+// Ensure a partition with more than one generation of files is not offloaded.
+func TestEngine_OffloadColdFiles_NotFullyCompacted(t *testing.T) {
+	objects, err := ioutil.TempDir("", "tsm1-objects-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(objects)
+
+	e, err := NewEngine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tsm1.WithPartitionFunc(func(name []byte) time.Duration { return time.Hour })(e.Engine)
+	tsm1.WithObjectStore(tsm1.NewDirObjectStore(objects), 1<<20)(e.Engine)
+	if err := e.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	for _, p := range []string{"cpu,host=a value=1 10", "cpu,host=a value=2 20"} {
+		if err := e.WritePointsString(p); err != nil {
+			t.Fatal(err)
+		}
+		e.MustWriteSnapshot()
+	}
+
+	if n, err := e.OffloadColdFiles(func(name []byte, maxTime int64) bool { return true }); err != nil {
+		t.Fatal(err)
+	} else if n != 0 {
+		t.Fatalf("expected no files offloaded, got %d", n)
+	}
+}
@@ -121,6 +121,10 @@ type DefaultPlanner struct {
 	// filesInUse is the set of files that have been returned as part of a plan and might
 	// be being compacted.  Two plans should not return the same file at any given time.
 	filesInUse map[string]struct{}
+
+	// Partition, if set, determines the time partitions of the files. Files in different
+	// partitions are never planned to be compacted together.
+	Partition PartitionFunc
 }
 
 type fileStore interface {
@@ -202,8 +206,12 @@ func (c *DefaultPlanner) ParseFileName(path string) (int, int, error) {
 
 // FullyCompacted returns true if the shard is fully compacted.
 func (c *DefaultPlanner) FullyCompacted() bool {
-	gens := c.findGenerations(false)
-	return len(gens) <= 1 && !gens.hasTombstones()
+	for _, gens := range c.findPartitions(false) {
+		if len(gens) > 1 || gens.hasTombstones() {
+			return false
+		}
+	}
+	return true
 }
 
 // ForceFull causes the planner to return a full compaction plan the next time
@@ -229,8 +237,20 @@ func (c *DefaultPlanner) PlanLevel(level int) []CompactionGroup {
 	// Determine the generations from all files on disk.  We need to treat
 	// a generation conceptually as a single file even though it may be
 	// split across several files in sequence.
-	generations := c.findGenerations(true)
+	var cGroups []CompactionGroup
+	for _, generations := range c.findPartitions(true) {
+		cGroups = append(cGroups, c.planLevel(generations, level)...)
+	}
+
+	if !c.acquire(cGroups) {
+		return nil
+	}
 
+	return cGroups
+}
+
+// planLevel returns the groups of files of the generations of a partition to rewrite for a specific level.
+func (c *DefaultPlanner) planLevel(generations tsmGenerations, level int) []CompactionGroup {
 	// If there is only one generation and no tombstones, then there's nothing to
 	// do.
 	if len(generations) <= 1 && !generations.hasTombstones() {
@@ -302,10 +322,6 @@ func (c *DefaultPlanner) PlanLevel(level int) []CompactionGroup {
 		}
 	}
 
-	if !c.acquire(cGroups) {
-		return nil
-	}
-
 	return cGroups
 }
 
@@ -325,8 +341,20 @@ func (c *DefaultPlanner) PlanOptimize() []CompactionGroup {
 	// Determine the generations from all files on disk.  We need to treat
 	// a generation conceptually as a single file even though it may be
 	// split across several files in sequence.
-	generations := c.findGenerations(true)
+	var cGroups []CompactionGroup
+	for _, generations := range c.findPartitions(true) {
+		cGroups = append(cGroups, c.planOptimize(generations)...)
+	}
 
+	if !c.acquire(cGroups) {
+		return nil
+	}
+
+	return cGroups
+}
+
+// planOptimize returns the groups of level 4 files of the generations of a partition to optimize.
+func (c *DefaultPlanner) planOptimize(generations tsmGenerations) []CompactionGroup {
 	// If there is only one generation and no tombstones, then there's nothing to
 	// do.
 	if len(generations) <= 1 && !generations.hasTombstones() {
@@ -394,24 +422,26 @@ func (c *DefaultPlanner) PlanOptimize() []CompactionGroup {
 		cGroups = append(cGroups, cGroup)
 	}
 
-	if !c.acquire(cGroups) {
-		return nil
-	}
-
 	return cGroups
 }
 
 // Plan returns a set of TSM files to rewrite for level 4 or higher.  The planning returns
 // multiple groups if possible to allow compactions to run concurrently.
 func (c *DefaultPlanner) Plan(lastWrite time.Time) []CompactionGroup {
-	generations := c.findGenerations(true)
+	partitions := c.findPartitions(true)
 
 	c.mu.RLock()
 	forceFull := c.forceFull
 	c.mu.RUnlock()
 
+	var multipleGenerations, hasTombstones bool
+	for _, generations := range partitions {
+		multipleGenerations = multipleGenerations || len(generations) > 1
+		hasTombstones = hasTombstones || generations.hasTombstones()
+	}
+
 	// first check if we should be doing a full compaction because nothing has been written in a long time
-	if forceFull || c.compactFullWriteColdDuration > 0 && time.Since(lastWrite) > c.compactFullWriteColdDuration && len(generations) > 1 {
+	if forceFull || c.compactFullWriteColdDuration > 0 && time.Since(lastWrite) > c.compactFullWriteColdDuration && multipleGenerations {
 
 		// Reset the full schedule if we planned because of it.
 		if forceFull {
@@ -420,56 +450,86 @@ func (c *DefaultPlanner) Plan(lastWrite time.Time) []CompactionGroup {
 			c.mu.Unlock()
 		}
 
-		var tsmFiles []string
-		var genCount int
-		for i, group := range generations {
-			var skip bool
-
-			// Skip the file if it's over the max size and contains a full block and it does not have any tombstones
-			if len(generations) > 2 && group.size() > uint64(maxTSMFileSize) && c.FileStore.BlockCount(group.files[0].Path, 1) == MaxPointsPerBlock && !group.hasTombstones() {
-				skip = true
+		var groups []CompactionGroup
+		for _, generations := range partitions {
+			if group := c.planFull(generations); group != nil {
+				groups = append(groups, group)
 			}
-
-			// We need to look at the level of the next file because it may need to be combined with this generation
-			// but won't get picked up on it's own if this generation is skipped.  This allows the most recently
-			// created files to get picked up by the full compaction planner and avoids having a few less optimally
-			// compressed files.
-			if i < len(generations)-1 {
-				if generations[i+1].level() <= 3 {
-					skip = false
-				}
-			}
-
-			if skip {
-				continue
-			}
-
-			for _, f := range group.files {
-				tsmFiles = append(tsmFiles, f.Path)
-			}
-			genCount += 1
 		}
-		sort.Strings(tsmFiles)
-
-		// Make sure we have more than 1 file and more than 1 generation
-		if len(tsmFiles) <= 1 || genCount <= 1 {
+		if len(groups) == 0 {
 			return nil
 		}
 
-		group := []CompactionGroup{tsmFiles}
-		if !c.acquire(group) {
+		if !c.acquire(groups) {
 			return nil
 		}
-		return group
+		return groups
 	}
 
 	// don't plan if nothing has changed in the filestore
-	if c.lastPlanCheck.After(c.FileStore.LastModified()) && !generations.hasTombstones() {
+	if c.lastPlanCheck.After(c.FileStore.LastModified()) && !hasTombstones {
 		return nil
 	}
 
 	c.lastPlanCheck = time.Now()
 
+	var tsmFiles []CompactionGroup
+	for _, generations := range partitions {
+		tsmFiles = append(tsmFiles, c.planGenerations(generations)...)
+	}
+	if len(tsmFiles) == 0 {
+		return nil
+	}
+
+	if !c.acquire(tsmFiles) {
+		return nil
+	}
+	return tsmFiles
+}
+
+// planFull returns all the files of the generations of a partition, other than those that
+// are already fully compacted, if there is more than one generation to compact.
+func (c *DefaultPlanner) planFull(generations tsmGenerations) CompactionGroup {
+	var tsmFiles []string
+	var genCount int
+	for i, group := range generations {
+		var skip bool
+
+		// Skip the file if it's over the max size and contains a full block and it does not have any tombstones
+		if len(generations) > 2 && group.size() > uint64(maxTSMFileSize) && c.FileStore.BlockCount(group.files[0].Path, 1) == MaxPointsPerBlock && !group.hasTombstones() {
+			skip = true
+		}
+
+		// We need to look at the level of the next file because it may need to be combined with this generation
+		// but won't get picked up on it's own if this generation is skipped.  This allows the most recently
+		// created files to get picked up by the full compaction planner and avoids having a few less optimally
+		// compressed files.
+		if i < len(generations)-1 {
+			if generations[i+1].level() <= 3 {
+				skip = false
+			}
+		}
+
+		if skip {
+			continue
+		}
+
+		for _, f := range group.files {
+			tsmFiles = append(tsmFiles, f.Path)
+		}
+		genCount += 1
+	}
+	sort.Strings(tsmFiles)
+
+	// Make sure we have more than 1 file and more than 1 generation
+	if len(tsmFiles) <= 1 || genCount <= 1 {
+		return nil
+	}
+	return tsmFiles
+}
+
+// planGenerations returns groups of level 4 or higher files of the generations of a partition to rewrite.
+func (c *DefaultPlanner) planGenerations(generations tsmGenerations) []CompactionGroup {
 	// If there is only one generation, return early to avoid re-compacting the same file
 	// over and over again.
 	if len(generations) <= 1 && !generations.hasTombstones() {
@@ -592,10 +652,6 @@ func (c *DefaultPlanner) Plan(lastWrite time.Time) []CompactionGroup {
 		sort.Strings(cGroup)
 		tsmFiles = append(tsmFiles, cGroup)
 	}
-
-	if !c.acquire(tsmFiles) {
-		return nil
-	}
 	return tsmFiles
 }
 
@@ -625,6 +681,11 @@ func (c *DefaultPlanner) findGenerations(skipInUse bool) tsmGenerations {
 			continue
 		}
 
+		// Cold files are never compacted, which would fetch all of their blocks again.
+		if f.Cold {
+			continue
+		}
+
 		group := generations[gen]
 		if group == nil {
 			group = newTsmGeneration(gen, c.ParseFileName)
@@ -695,6 +756,10 @@ type Compactor struct {
 	// RateLimit is the limit for disk writes for all concurrent compactions.
 	RateLimit limiter.Rate
 
+	// Partition, if set, determines the time partitions of the files written by snapshots.
+	// A snapshot writes separate files for each partition.
+	Partition PartitionFunc
+
 	formatFileName FormatFileNameFunc
 	parseFileName  ParseFileNameFunc
 
@@ -836,16 +901,25 @@ func (c *Compactor) WriteSnapshot(cache *Cache) ([]string, error) {
 		throttle = false
 	}
 
-	splits := cache.Split(concurrency)
+	var splits []*Cache
+	if c.Partition != nil {
+		splits = cache.Partition(c.Partition)
+	} else {
+		splits = cache.Split(concurrency)
+	}
 
 	type res struct {
 		files []string
 		err   error
 	}
 
-	resC := make(chan res, concurrency)
-	for i := 0; i < concurrency; i++ {
+	// At most concurrency splits are written at once.
+	sem := make(chan struct{}, concurrency)
+	resC := make(chan res, len(splits))
+	for i := range splits {
 		go func(sp *Cache) {
+			sem <- struct{}{}
+			defer func() { <-sem }()
 			iter := NewCacheKeyIterator(sp, MaxPointsPerBlock, intC)
 			files, err := c.writeNewFiles(c.FileStore.NextGeneration(), 0, nil, iter, throttle)
 			resC <- res{files: files, err: err}
@@ -854,8 +928,8 @@ func (c *Compactor) WriteSnapshot(cache *Cache) ([]string, error) {
 	}
 
 	var err error
-	files := make([]string, 0, concurrency)
-	for i := 0; i < concurrency; i++ {
+	files := make([]string, 0, len(splits))
+	for range splits {
 		result := <-resC
 		if result.err != nil {
 			err = result.err
@@ -1344,17 +1418,22 @@ func NewTSMKeyIterator(size int, fast bool, interrupt chan struct{}, readers ...
 	for _, r := range readers {
 		iter = append(iter, r.BlockIterator())
 	}
+	return newTSMKeyIterator(size, fast, interrupt, readers, iter), nil
+}
 
+// newTSMKeyIterator returns a tsmKeyIterator of the blocks of iterators, which iterate over
+// the blocks of the readers at the same positions.
+func newTSMKeyIterator(size int, fast bool, interrupt chan struct{}, readers []*TSMReader, iterators []*BlockIterator) *tsmKeyIterator {
 	return &tsmKeyIterator{
 		readers:   readers,
 		values:    map[string][]Value{},
 		pos:       make([]int, len(readers)),
 		size:      size,
-		iterators: iter,
+		iterators: iterators,
 		fast:      fast,
-		buf:       make([]blocks, len(iter)),
+		buf:       make([]blocks, len(iterators)),
 		interrupt: interrupt,
-	}, nil
+	}
 }
 
 func (k *tsmKeyIterator) hasMergedValues() bool {
@@ -0,0 +1,147 @@
+package tsm1
+
+import (
+	"bytes"
+	"context"
+	"os"
+)
+
+// copyPrefixBatchSize is the number of new keys passed at a time to the function given to CopyPrefix.
+const copyPrefixBatchSize = 10000
+
+// CopyPrefix copies the data of the keys starting with src to the same keys with src replaced by dst.
+// The blocks of the keys are written to new TSM files, merged and without any deleted values,
+// and the data of other keys is not read or rewritten. Points written to the keys while they
+// are copied may or may not be copied.
+//
+// fn is called with batches of the new keys, their block types and the number of points copied
+// since the previous call, before the TSM files holding them are added to the engine. If fn
+// returns an error, the copy stops and the new files are removed.
+func (e *Engine) CopyPrefix(ctx context.Context, src, dst []byte, fn func(keys [][]byte, types []byte, points int64) error) error {
+	// Write the cached points to TSM files so that they are copied too.
+	if err := e.WriteSnapshot(); err != nil {
+		return err
+	}
+
+	// Ensure that compactions do not remove the files before we're done with them.
+	var readers []*TSMReader
+	e.FileStore.mu.RLock()
+	for _, f := range e.FileStore.files {
+		if r, ok := f.(*TSMReader); ok && hasKeyPrefix(r, src) {
+			r.Ref()
+			readers = append(readers, r)
+		}
+	}
+	e.FileStore.mu.RUnlock()
+	defer func() {
+		for _, r := range readers {
+			r.Unref()
+		}
+	}()
+	if len(readers) == 0 {
+		return nil
+	}
+
+	iters := make([]*BlockIterator, 0, len(readers))
+	for _, r := range readers {
+		iters = append(iters, r.prefixBlockIterator(src))
+	}
+	iter := &copyPrefixKeyIterator{
+		KeyIterator: newTSMKeyIterator(MaxPointsPerBlock, true, nil, readers, iters),
+		ctx:         ctx,
+		src:         src,
+		dst:         dst,
+		fn:          fn,
+	}
+
+	files, err := e.Compactor.writeNewFiles(e.FileStore.NextGeneration(), 0, nil, iter, true)
+	if err != nil {
+		return err
+	}
+	if err := iter.flush(); err != nil {
+		for _, f := range files {
+			os.RemoveAll(f)
+			os.RemoveAll(StatsFilename(f))
+		}
+		return err
+	}
+
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.FileStore.Replace(nil, files)
+}
+
+// hasKeyPrefix returns true if the TSM file r has a key starting with prefix.
+func hasKeyPrefix(r TSMFile, prefix []byte) bool {
+	iter := r.Iterator(prefix)
+	return iter.Next() && bytes.HasPrefix(iter.Key(), prefix)
+}
+
+// copyPrefixKeyIterator is a KeyIterator replacing the prefix src of the keys of another
+// KeyIterator with dst, and passing the new keys to fn in batches.
+type copyPrefixKeyIterator struct {
+	KeyIterator
+	ctx      context.Context
+	src, dst []byte
+	fn       func(keys [][]byte, types []byte, points int64) error
+
+	key    []byte
+	keys   [][]byte
+	types  []byte
+	points int64
+	err    error
+}
+
+func (k *copyPrefixKeyIterator) Next() bool {
+	if k.err != nil {
+		return false
+	}
+	if k.err = k.ctx.Err(); k.err != nil {
+		return false
+	}
+	return k.KeyIterator.Next()
+}
+
+func (k *copyPrefixKeyIterator) Read() ([]byte, int64, int64, []byte, error) {
+	key, minTime, maxTime, block, err := k.KeyIterator.Read()
+	if err != nil {
+		return nil, 0, 0, nil, err
+	}
+
+	if len(k.key) == 0 || !bytes.Equal(key[len(k.src):], k.key[len(k.dst):]) {
+		typ, err := BlockType(block)
+		if err != nil {
+			return nil, 0, 0, nil, err
+		}
+
+		// The TSM writer keeps the keys of its index, so each new key is allocated.
+		k.key = make([]byte, 0, len(k.dst)+len(key)-len(k.src))
+		k.key = append(append(k.key, k.dst...), key[len(k.src):]...)
+		k.keys = append(k.keys, k.key)
+		k.types = append(k.types, typ)
+		if len(k.keys) == copyPrefixBatchSize {
+			if k.err = k.flush(); k.err != nil {
+				return nil, 0, 0, nil, k.err
+			}
+		}
+	}
+	k.points += int64(BlockCount(block))
+	return k.key, minTime, maxTime, block, nil
+}
+
+func (k *copyPrefixKeyIterator) Err() error {
+	if k.err != nil {
+		return k.err
+	}
+	return k.KeyIterator.Err()
+}
+
+// flush passes the new keys read since the previous flush to fn.
+func (k *copyPrefixKeyIterator) flush() error {
+	if len(k.keys) == 0 && k.points == 0 {
+		return nil
+	}
+	err := k.fn(k.keys, k.types, k.points)
+	k.keys, k.types, k.points = nil, nil, 0
+	return err
+}
@@ -0,0 +1,85 @@
+package tsm1_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestEngine_CopyPrefix(t *testing.T) {
+	e, err := NewEngine()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// mock the planner so compactions don't run during the test
+	e.CompactionPlan = &mockPlanner{}
+	if err := e.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	// Write the points of cpu to two TSM files and the cache.
+	if err := e.writePoints(
+		MustParsePointString("cpu,host=A value=1.1 1"),
+		MustParsePointString("cpu,host=B value=1.2 1"),
+		MustParsePointString("mem,host=A value=1.3 1"),
+	); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+	if err := e.WriteSnapshot(); err != nil {
+		t.Fatalf("failed to snapshot: %s", err.Error())
+	}
+	if err := e.writePoints(
+		MustParsePointString("cpu,host=A value=2.1 2"),
+		MustParsePointString("cpu,host=B value=2.2 2"),
+	); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+	if err := e.WriteSnapshot(); err != nil {
+		t.Fatalf("failed to snapshot: %s", err.Error())
+	}
+	if err := e.writePoints(MustParsePointString("cpu,host=A value=3.1 3")); err != nil {
+		t.Fatalf("failed to write points: %s", err.Error())
+	}
+
+	// Deleted values of cpu are not copied.
+	if err := e.DeletePrefix([]byte("cpu,host=B"), 0, 9); err != nil {
+		t.Fatalf("failed to delete series: %v", err)
+	}
+
+	var keys []string
+	var points int64
+	if err := e.CopyPrefix(context.Background(), []byte("cpu"), []byte("gpu"), func(ks [][]byte, types []byte, n int64) error {
+		for _, k := range ks {
+			keys = append(keys, string(k))
+		}
+		points += n
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to copy prefix: %v", err)
+	}
+
+	if exp := []string{"gpu,host=A#!~#value"}; !reflect.DeepEqual(keys, exp) {
+		t.Fatalf("unexpected copied keys: %v != %v", keys, exp)
+	}
+	if points != 3 {
+		t.Fatalf("unexpected number of points copied: %d", points)
+	}
+
+	values, err := e.FileStore.Read([]byte("gpu,host=A#!~#value"), 2)
+	if err != nil {
+		t.Fatal(err)
+	} else if len(values) != 1 || values[0].Value() != 2.1 {
+		t.Fatalf("unexpected copied values: %v", values)
+	}
+
+	exp := map[string]byte{
+		"cpu,host=A#!~#value": 0,
+		"gpu,host=A#!~#value": 0,
+		"mem,host=A#!~#value": 0,
+	}
+	if got := e.FileStore.Keys(); !reflect.DeepEqual(got, exp) {
+		t.Fatalf("unexpected series in file store: %v != %v", got, exp)
+	}
+}
@@ -206,6 +206,10 @@ type FileStore struct {
 	parseFileName ParseFileNameFunc
 
 	obs FileStoreObserver
+
+	// objectStore holds the blocks of cold TSM files, which are cached in blockCache.
+	objectStore ObjectStore
+	blockCache  *BlockCache
 }
 
 // FileStat holds information about a TSM file on disk.
@@ -216,6 +220,9 @@ type FileStat struct {
 	LastModified     int64
 	MinTime, MaxTime int64
 	MinKey, MaxKey   []byte
+
+	// Cold is true if the blocks of the file have been offloaded to an object store.
+	Cold bool
 }
 
 // OverlapsTimeRange returns true if the time range of the file intersect min and max.
@@ -262,6 +269,13 @@ func (f *FileStore) WithObserver(obs FileStoreObserver) {
 	f.obs = obs
 }
 
+// WithObjectStore sets the object store that TSM files are offloaded to, and the cache of
+// the blocks read from it. It must be called before the FileStore is opened.
+func (f *FileStore) WithObjectStore(store ObjectStore, cache *BlockCache) {
+	f.objectStore = store
+	f.blockCache = cache
+}
+
 func (f *FileStore) WithParseFileNameFunc(parseFileNameFunc ParseFileNameFunc) {
 	f.parseFileName = parseFileNameFunc
 }
@@ -588,12 +602,20 @@ func (f *FileStore) Open() error {
 			start := time.Now()
 			df, err := NewTSMReader(file,
 				WithMadviseWillNeed(f.tsmMMAPWillNeed),
-				WithTSMReaderLogger(f.logger))
+				WithTSMReaderLogger(f.logger),
+				WithTSMReaderObjectStore(f.objectStore, f.blockCache))
 			f.logger.Info("Opened file",
 				zap.String("path", file.Name()),
 				zap.Int("id", idx),
 				zap.Duration("duration", time.Since(start)))
 
+			// A cold TSM file is not corrupt, but cannot be read without its object store.
+			if err == ErrNoObjectStore {
+				file.Close()
+				readerC <- &res{err: fmt.Errorf("cannot open cold tsm file %s: %v", file.Name(), err)}
+				return
+			}
+
 			// If we are unable to read a TSM file then log the error, rename
 			// the file, and continue loading the shard without it.
 			if err != nil {
@@ -816,7 +838,8 @@ func (f *FileStore) replace(oldFiles, newFiles []string, updatedFn func(r []TSMF
 
 		tsm, err := NewTSMReader(fd,
 			WithMadviseWillNeed(f.tsmMMAPWillNeed),
-			WithTSMReaderLogger(f.logger))
+			WithTSMReaderLogger(f.logger),
+			WithTSMReaderObjectStore(f.objectStore, f.blockCache))
 		if err != nil {
 			return err
 		}
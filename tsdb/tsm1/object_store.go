@@ -0,0 +1,105 @@
+package tsm1
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/influxdata/platform/pkg/file"
+)
+
+// ObjectStore stores the TSM files that have been offloaded to cold storage.
+// Each file is stored as an object, which is only ever read in ranges.
+type ObjectStore interface {
+	// Put stores the contents of r as the object key, replacing any existing object.
+	Put(key string, r io.Reader) error
+
+	// ReadAt reads len(p) bytes of the object key, starting at offset off.
+	ReadAt(key string, p []byte, off int64) (int, error)
+
+	// Delete removes the object key. Deleting an object that does not exist is not an error.
+	Delete(key string) error
+}
+
+// DirObjectStore is an ObjectStore keeping each object as a file in a directory,
+// such as a mount point of slower, cheaper storage than that of the engine.
+type DirObjectStore struct {
+	dir string
+}
+
+// NewDirObjectStore returns an ObjectStore of the files in dir, which is created if it does not exist.
+func NewDirObjectStore(dir string) *DirObjectStore {
+	return &DirObjectStore{dir: dir}
+}
+
+func (s *DirObjectStore) path(key string) (string, error) {
+	if key == "" || filepath.Base(key) != key {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return filepath.Join(s.dir, key), nil
+}
+
+// Put stores the contents of r as the object key, replacing any existing object.
+// The object is written to a temporary file and renamed once synced, so that a
+// partially written object is never read.
+func (s *DirObjectStore) Put(key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0777); err != nil {
+		return err
+	}
+
+	tmp := path + "." + TmpTSMFileExtension
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if err := file.RenameFile(tmp, path); err != nil {
+		return err
+	}
+	return file.SyncDir(s.dir)
+}
+
+// ReadAt reads len(p) bytes of the object key, starting at offset off.
+func (s *DirObjectStore) ReadAt(key string, p []byte, off int64) (int, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return f.ReadAt(p, off)
+}
+
+// Delete removes the object key.
+func (s *DirObjectStore) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
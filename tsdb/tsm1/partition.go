@@ -0,0 +1,198 @@
+package tsm1
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/influxdata/platform/models"
+	"go.uber.org/zap"
+)
+
+// A PartitionFunc returns the duration of the time partitions of the series with the
+// measurement name, or zero if the series are not partitioned by time.
+//
+// The TSM files of an engine with a PartitionFunc each hold the data of a single
+// measurement in a single time partition. Snapshots write a file for each partition,
+// and compactions never merge files of different partitions, so that expired data can
+// be removed by deleting whole files instead of writing tombstones.
+type PartitionFunc func(name []byte) time.Duration
+
+// WithPartitionFunc partitions the TSM files of the engine by measurement and time,
+// as determined by fn. It must be given after any WithCompactionPlanner option.
+var WithPartitionFunc = func(fn PartitionFunc) EngineOption {
+	return func(e *Engine) {
+		e.Compactor.Partition = fn
+		if p, ok := e.CompactionPlan.(*DefaultPlanner); ok {
+			p.Partition = fn
+		}
+	}
+}
+
+// partitionWindow returns the start of the partition of duration d containing the time t.
+func partitionWindow(t int64, d time.Duration) int64 {
+	w := t - t%int64(d)
+	if t < 0 && w != t {
+		w -= int64(d)
+	}
+	return w
+}
+
+// compositeKeyName returns the measurement name of the series of the composite key.
+func compositeKeyName(key []byte) []byte {
+	seriesKey, _ := SeriesAndFieldFromCompositeKey(key)
+	return models.ParseName(seriesKey)
+}
+
+// Partition splits the cache into one cache for each measurement and time partition, as
+// determined by fn. The values of each key must already be sorted and deduplicated.
+func (c *Cache) Partition(fn PartitionFunc) []*Cache {
+	type partition struct {
+		name   string
+		window int64
+	}
+	caches := make(map[partition]*Cache)
+
+	c.mu.RLock()
+	store := c.store
+	c.mu.RUnlock()
+
+	// applySerial cannot return an error in this invocation.
+	_ = store.applySerial(func(key []byte, e *entry) error {
+		name := compositeKeyName(key)
+		d := fn(name)
+
+		e.mu.RLock()
+		values := e.values
+		e.mu.RUnlock()
+
+		for len(values) > 0 {
+			p, n := partition{name: string(name)}, len(values)
+			if d > 0 {
+				p.window = partitionWindow(values[0].UnixNano(), d)
+				end := p.window + int64(d)
+				n = sort.Search(len(values), func(i int) bool { return values[i].UnixNano() >= end })
+			}
+
+			pc := caches[p]
+			if pc == nil {
+				pc = NewCache(0)
+				pc.init()
+				caches[p] = pc
+			}
+			pc.store.add(key, &entry{values: values[:n], vtype: e.vtype})
+			values = values[n:]
+		}
+		return nil
+	})
+
+	partitions := make([]*Cache, 0, len(caches))
+	for _, pc := range caches {
+		partitions = append(partitions, pc)
+	}
+	return partitions
+}
+
+// fileStatPartition returns the partition of the file f, as determined by fn, or the empty
+// partition if the file holds the data of more than one measurement or time partition,
+// such as files written before the data was partitioned. Files are only partitioned by
+// measurement if fn is nil.
+func fileStatPartition(fn PartitionFunc, f FileStat) string {
+	name := compositeKeyName(f.MinKey)
+	if !bytes.Equal(name, compositeKeyName(f.MaxKey)) {
+		return ""
+	}
+	if fn == nil {
+		return string(name)
+	}
+
+	d := fn(name)
+	if d <= 0 {
+		return string(name)
+	}
+	w := partitionWindow(f.MinTime, d)
+	if partitionWindow(f.MaxTime, d) != w {
+		return ""
+	}
+	return fmt.Sprintf("%s/%d", name, w)
+}
+
+// findPartitions returns the generations of each partition, as ordered by findGenerations.
+// All generations are in a single partition if the planner does not partition files.
+func (c *DefaultPlanner) findPartitions(skipInUse bool) []tsmGenerations {
+	generations := c.findGenerations(skipInUse)
+	if c.Partition == nil {
+		return []tsmGenerations{generations}
+	}
+
+	byPartition := make(map[string]tsmGenerations)
+	var keys []string
+	for _, g := range generations {
+		key := fileStatPartition(c.Partition, g.files[0])
+		for _, f := range g.files[1:] {
+			if fileStatPartition(c.Partition, f) != key {
+				key = ""
+			}
+		}
+
+		if _, ok := byPartition[key]; !ok {
+			keys = append(keys, key)
+		}
+		byPartition[key] = append(byPartition[key], g)
+	}
+	sort.Strings(keys)
+
+	partitions := make([]tsmGenerations, 0, len(keys))
+	for _, key := range keys {
+		partitions = append(partitions, byPartition[key])
+	}
+	return partitions
+}
+
+// UnpartitionedNameRanges returns the first and last measurement names of each TSM file that does
+// not hold the data of a single partition, such as files written before the data was partitioned.
+// The data of these files can only be expired by deleting series ranges. Every file is returned if
+// the engine does not partition its files.
+func (e *Engine) UnpartitionedNameRanges() [][2][]byte {
+	var ranges [][2][]byte
+	for _, f := range e.FileStore.Stats() {
+		if e.Compactor.Partition != nil && fileStatPartition(e.Compactor.Partition, f) != "" {
+			continue
+		}
+		ranges = append(ranges, [2][]byte{compositeKeyName(f.MinKey), compositeKeyName(f.MaxKey)})
+	}
+	return ranges
+}
+
+// DeleteExpiredFiles removes the TSM files holding only the data of a single measurement
+// for which expired returns true, given the maximum time of the data in the file.
+// It returns the number of files removed. Files are only expected to hold a single
+// measurement if the engine partitions its files with WithPartitionFunc.
+func (e *Engine) DeleteExpiredFiles(expired func(name []byte, maxTime int64) bool) (int, error) {
+	find := func() []string {
+		var paths []string
+		for _, f := range e.FileStore.Stats() {
+			name := compositeKeyName(f.MinKey)
+			if bytes.Equal(name, compositeKeyName(f.MaxKey)) && expired(name, f.MaxTime) {
+				paths = append(paths, f.Path)
+			}
+		}
+		return paths
+	}
+
+	if len(find()) == 0 {
+		return 0, nil
+	}
+
+	// Level compactions are stopped so that none of the expired files are being compacted.
+	e.disableLevelCompactions(true)
+	defer e.enableLevelCompactions(true)
+
+	paths := find()
+	if err := e.FileStore.Replace(paths, nil); err != nil {
+		return 0, err
+	}
+	e.logger.Info("Deleted expired TSM files", zap.Int("files", len(paths)))
+	return len(paths), nil
+}
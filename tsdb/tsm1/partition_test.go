@@ -0,0 +1,138 @@
+package tsm1_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/influxdata/platform/tsdb/tsm1"
+)
+
+// Ensure the planner never compacts files of different partitions together.
+func TestDefaultPlanner_Plan_Partitioned(t *testing.T) {
+	hour := int64(time.Hour)
+	key := func(name string) []byte { return tsm1.SeriesFieldKeyBytes(name+",host=a", "value") }
+	data := []tsm1.FileStat{
+		{Path: "01-01.tsm1", MinKey: key("cpu"), MaxKey: key("cpu"), MinTime: 0, MaxTime: hour - 1},
+		{Path: "02-01.tsm1", MinKey: key("cpu"), MaxKey: key("cpu"), MinTime: hour, MaxTime: 2*hour - 1},
+		{Path: "03-01.tsm1", MinKey: key("cpu"), MaxKey: key("cpu"), MinTime: 10, MaxTime: 20},
+		{Path: "04-01.tsm1", MinKey: key("cpu"), MaxKey: key("cpu"), MinTime: hour + 10, MaxTime: hour + 20},
+		{Path: "05-01.tsm1", MinKey: key("mem"), MaxKey: key("mem"), MinTime: 10, MaxTime: 20},
+	}
+
+	newPlanner := func(fn tsm1.PartitionFunc) *tsm1.DefaultPlanner {
+		cp := tsm1.NewDefaultPlanner(&fakeFileStore{
+			PathsFn: func() []tsm1.FileStat { return data },
+		}, tsm1.DefaultCompactFullWriteColdDuration)
+		cp.Partition = fn
+		cp.ForceFull()
+		return cp
+	}
+
+	if groups := newPlanner(nil).Plan(time.Now()); len(groups) != 1 || len(groups[0]) != len(data) {
+		t.Fatalf("expected a single group of all files without partitions, got %v", groups)
+	}
+
+	cp := newPlanner(func(name []byte) time.Duration { return time.Hour })
+	groups := cp.Plan(time.Now())
+	exp := []tsm1.CompactionGroup{
+		{"01-01.tsm1", "03-01.tsm1"},
+		{"02-01.tsm1", "04-01.tsm1"},
+	}
+	if len(groups) != len(exp) {
+		t.Fatalf("unexpected groups: got %v, exp %v", groups, exp)
+	}
+	for i := range exp {
+		if len(groups[i]) != len(exp[i]) {
+			t.Fatalf("unexpected groups: got %v, exp %v", groups, exp)
+		}
+		for j := range exp[i] {
+			if groups[i][j] != exp[i][j] {
+				t.Fatalf("unexpected groups: got %v, exp %v", groups, exp)
+			}
+		}
+	}
+	cp.Release(groups)
+
+	if cp.FullyCompacted() {
+		t.Fatal("expected partitions with multiple generations not to be fully compacted")
+	}
+}
+
+// Ensure snapshots write a file per partition, and that expired partitions can be deleted.
+func TestEngine_DeleteExpiredFiles(t *testing.T) {
+	e, err := NewEngine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tsm1.WithPartitionFunc(func(name []byte) time.Duration { return time.Hour })(e.Engine)
+	if err := e.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	hour := int64(time.Hour)
+	if err := e.WritePointsString(
+		"cpu,host=a value=1 10",
+		"cpu,host=a value=2 3600000000010",
+		"mem,host=a value=3 20",
+	); err != nil {
+		t.Fatal(err)
+	}
+	e.MustWriteSnapshot()
+
+	if got := len(e.FileStore.Stats()); got != 3 {
+		t.Fatalf("expected 3 files after snapshot, got %d", got)
+	}
+
+	n, err := e.DeleteExpiredFiles(func(name []byte, maxTime int64) bool {
+		return bytes.Equal(name, []byte("cpu")) && maxTime < hour
+	})
+	if err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Fatalf("expected 1 file deleted, got %d", n)
+	}
+
+	stats := e.FileStore.Stats()
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 files remaining, got %d", len(stats))
+	}
+	for _, f := range stats {
+		if f.MaxTime < hour && bytes.HasPrefix(f.MinKey, []byte("cpu")) {
+			t.Fatalf("expired file %s not deleted", f.Path)
+		}
+	}
+}
+
+// Ensure only the files not holding a single partition are reported as unpartitioned.
+func TestEngine_UnpartitionedNameRanges(t *testing.T) {
+	e, err := NewEngine()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Open(); err != nil {
+		t.Fatal(err)
+	}
+	defer e.Close()
+
+	// A file written before the data is partitioned holds both measurements.
+	if err := e.WritePointsString("cpu,host=a value=1 10", "mem,host=a value=2 20"); err != nil {
+		t.Fatal(err)
+	}
+	e.MustWriteSnapshot()
+
+	tsm1.WithPartitionFunc(func(name []byte) time.Duration { return time.Hour })(e.Engine)
+	if err := e.WritePointsString("cpu,host=a value=3 30"); err != nil {
+		t.Fatal(err)
+	}
+	e.MustWriteSnapshot()
+
+	ranges := e.UnpartitionedNameRanges()
+	if len(ranges) != 1 {
+		t.Fatalf("expected 1 unpartitioned file, got %d", len(ranges))
+	}
+	if got := ranges[0]; !bytes.Equal(got[0], []byte("cpu")) || !bytes.Equal(got[1], []byte("mem")) {
+		t.Fatalf("unexpected name range %q", got)
+	}
+}
@@ -283,3 +283,83 @@ func (m *mmapAccessor) readBooleanArrayBlock(entry *IndexEntry, values *tsdb.Boo
 
 	return err
 }
+
+func (o *objectAccessor) readFloatBlock(entry *IndexEntry, values *[]FloatValue) ([]FloatValue, error) {
+	b, err := o.block(entry)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeFloatBlock(b[4:], values)
+}
+
+func (o *objectAccessor) readFloatArrayBlock(entry *IndexEntry, values *tsdb.FloatArray) error {
+	b, err := o.block(entry)
+	if err != nil {
+		return err
+	}
+	return DecodeFloatArrayBlock(b[4:], values)
+}
+
+func (o *objectAccessor) readIntegerBlock(entry *IndexEntry, values *[]IntegerValue) ([]IntegerValue, error) {
+	b, err := o.block(entry)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeIntegerBlock(b[4:], values)
+}
+
+func (o *objectAccessor) readIntegerArrayBlock(entry *IndexEntry, values *tsdb.IntegerArray) error {
+	b, err := o.block(entry)
+	if err != nil {
+		return err
+	}
+	return DecodeIntegerArrayBlock(b[4:], values)
+}
+
+func (o *objectAccessor) readUnsignedBlock(entry *IndexEntry, values *[]UnsignedValue) ([]UnsignedValue, error) {
+	b, err := o.block(entry)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeUnsignedBlock(b[4:], values)
+}
+
+func (o *objectAccessor) readUnsignedArrayBlock(entry *IndexEntry, values *tsdb.UnsignedArray) error {
+	b, err := o.block(entry)
+	if err != nil {
+		return err
+	}
+	return DecodeUnsignedArrayBlock(b[4:], values)
+}
+
+func (o *objectAccessor) readStringBlock(entry *IndexEntry, values *[]StringValue) ([]StringValue, error) {
+	b, err := o.block(entry)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeStringBlock(b[4:], values)
+}
+
+func (o *objectAccessor) readStringArrayBlock(entry *IndexEntry, values *tsdb.StringArray) error {
+	b, err := o.block(entry)
+	if err != nil {
+		return err
+	}
+	return DecodeStringArrayBlock(b[4:], values)
+}
+
+func (o *objectAccessor) readBooleanBlock(entry *IndexEntry, values *[]BooleanValue) ([]BooleanValue, error) {
+	b, err := o.block(entry)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeBooleanBlock(b[4:], values)
+}
+
+func (o *objectAccessor) readBooleanArrayBlock(entry *IndexEntry, values *tsdb.BooleanArray) error {
+	b, err := o.block(entry)
+	if err != nil {
+		return err
+	}
+	return DecodeBooleanArrayBlock(b[4:], values)
+}
@@ -23,6 +23,10 @@ type TSMReader struct {
 	madviseWillNeed bool // Hint to the kernel with MADV_WILLNEED.
 	mu              sync.RWMutex
 
+	// objectStore and blockCache provide access to the blocks of a cold TSM file.
+	objectStore ObjectStore
+	blockCache  *BlockCache
+
 	// accessor provides access and decoding of blocks for the reader.
 	accessor blockAccessor
 
@@ -57,6 +61,15 @@ var WithTSMReaderLogger = func(logger *zap.Logger) tsmReaderOption {
 	}
 }
 
+// WithTSMReaderObjectStore is an option for reading the blocks of a cold TSM file from store,
+// caching them in cache. The cache may be nil.
+var WithTSMReaderObjectStore = func(store ObjectStore, cache *BlockCache) tsmReaderOption {
+	return func(r *TSMReader) {
+		r.objectStore = store
+		r.blockCache = cache
+	}
+}
+
 // NewTSMReader returns a new TSMReader from the given file.
 func NewTSMReader(f *os.File, options ...tsmReaderOption) (*TSMReader, error) {
 	t := &TSMReader{
@@ -72,10 +85,27 @@ func NewTSMReader(f *os.File, options ...tsmReaderOption) (*TSMReader, error) {
 	}
 	t.size = stat.Size()
 	t.lastModified = stat.ModTime().UnixNano()
-	t.accessor = &mmapAccessor{
-		logger:       t.logger,
-		f:            f,
-		mmapWillNeed: t.madviseWillNeed,
+
+	cold, err := isColdFile(f)
+	if err != nil {
+		return nil, err
+	}
+	if cold {
+		if t.objectStore == nil {
+			return nil, ErrNoObjectStore
+		}
+		t.accessor = &objectAccessor{
+			logger: t.logger,
+			store:  t.objectStore,
+			cache:  t.blockCache,
+			f:      f,
+		}
+	} else {
+		t.accessor = &mmapAccessor{
+			logger:       t.logger,
+			f:            f,
+			mmapWillNeed: t.madviseWillNeed,
+		}
 	}
 
 	index, err := t.accessor.init()
@@ -283,9 +313,19 @@ func (t *TSMReader) remove() error {
 	if err := t.tombstoner.Delete(); err != nil {
 		return err
 	}
+
+	if o, ok := t.accessor.(*objectAccessor); ok {
+		return o.removeObject()
+	}
 	return nil
 }
 
+// Cold returns true if the blocks of the file have been offloaded to an object store.
+func (t *TSMReader) Cold() bool {
+	_, ok := t.accessor.(*objectAccessor)
+	return ok
+}
+
 // Contains returns whether the given key is present in the index.
 func (t *TSMReader) Contains(key []byte) bool {
 	return t.index.Contains(key)
@@ -441,6 +481,7 @@ func (t *TSMReader) Stats() FileStat {
 		MinKey:       minKey,
 		MaxKey:       maxKey,
 		HasTombstone: t.tombstoner.HasTombstones(),
+		Cold:         t.Cold(),
 	}
 }
 
@@ -456,6 +497,19 @@ func (t *TSMReader) BlockIterator() *BlockIterator {
 	}
 }
 
+// prefixBlockIterator returns a BlockIterator of the blocks of the keys starting with prefix.
+func (t *TSMReader) prefixBlockIterator(prefix []byte) *BlockIterator {
+	t.mu.RLock()
+	iter := t.index.Iterator(prefix)
+	t.mu.RUnlock()
+
+	return &BlockIterator{
+		r:      t,
+		iter:   iter,
+		prefix: prefix,
+	}
+}
+
 type BatchDeleter interface {
 	DeleteRange(keys [][]byte, min, max int64) error
 	Commit() error
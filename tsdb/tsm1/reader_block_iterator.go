@@ -1,11 +1,16 @@
 package tsm1
 
+import "bytes"
+
 // BlockIterator allows iterating over each block in a TSM file in order.  It provides
 // raw access to the block bytes without decoding them.
 type BlockIterator struct {
 	r       *TSMReader
 	iter    *TSMIndexIterator
 	entries []IndexEntry
+
+	// prefix limits iteration to the keys starting with it.
+	prefix []byte
 }
 
 // PeekNext returns the next key to be iterated or an empty string.
@@ -26,7 +31,7 @@ func (b *BlockIterator) Next() bool {
 		}
 	}
 
-	if !b.iter.Next() {
+	if !b.iter.Next() || !bytes.HasPrefix(b.iter.Key(), b.prefix) {
 		return false
 	}
 	b.entries = b.iter.Entries()
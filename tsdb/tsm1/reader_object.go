@@ -0,0 +1,429 @@
+package tsm1
+
+/*
+A cold TSM file is a TSM file whose blocks have been offloaded to an ObjectStore.
+The file on disk is replaced by a stub keeping only the index of the file, so that
+the index can be searched without fetching anything from the object store. The
+offsets of the index entries remain those of the blocks in the stored object.
+
+┌────────┬─────────┬─────────┬─────────┬─────────┬─────────────┬──────────────┐
+│ Magic  │ Version │ Key Len │   Key   │  Size   │    Index    │    Footer    │
+│4 bytes │ 1 byte  │ 2 bytes │ N bytes │ 8 bytes │   N bytes   │   8 bytes    │
+└────────┴─────────┴─────────┴─────────┴─────────┴─────────────┴──────────────┘
+
+The key is the key of the object holding the whole TSM file, whose size follows.
+The footer stores the offset of the start of the index in the stub.
+*/
+
+import (
+	"container/list"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/influxdata/platform/pkg/file"
+	"go.uber.org/zap"
+)
+
+// ColdMagicNumber is written as the first 4 bytes of the stub of a cold TSM file
+// to identify the file as such.
+const ColdMagicNumber uint32 = 0x16D116D2
+
+// ErrNoObjectStore is returned when opening a cold TSM file without an ObjectStore.
+var ErrNoObjectStore = errors.New("cold tsm file requires an object store")
+
+// isColdFile returns true if f is the stub of a cold TSM file.
+func isColdFile(f *os.File) (bool, error) {
+	var b [4]byte
+	if _, err := f.ReadAt(b[:], 0); err == io.EOF {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return binary.BigEndian.Uint32(b[:]) == ColdMagicNumber, nil
+}
+
+// writeColdStub writes the stub of the TSM file at src, stored as the object key, to dst.
+func writeColdStub(src, dst, key string) error {
+	if len(key) > math.MaxUint16 {
+		return fmt.Errorf("object key too long: %d", len(key))
+	}
+
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := verifyVersion(f); err != nil {
+		return err
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	size := stat.Size()
+	if size < 8 {
+		return fmt.Errorf("writeColdStub: file too small for index offset")
+	}
+
+	var b [8]byte
+	if _, err := f.ReadAt(b[:], size-8); err != nil {
+		return err
+	}
+	indexStart := int64(binary.BigEndian.Uint64(b[:]))
+	if indexStart >= size-8 {
+		return fmt.Errorf("writeColdStub: invalid indexStart")
+	}
+
+	header := make([]byte, 0, 4+1+2+len(key)+8)
+	header = append(header, 0, 0, 0, 0, Version, 0, 0)
+	binary.BigEndian.PutUint32(header[0:4], ColdMagicNumber)
+	binary.BigEndian.PutUint16(header[5:7], uint16(len(key)))
+	header = append(header, key...)
+	header = append(header, 0, 0, 0, 0, 0, 0, 0, 0)
+	binary.BigEndian.PutUint64(header[len(header)-8:], uint64(size))
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	if err := func() error {
+		if _, err := out.Write(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, io.NewSectionReader(f, indexStart, size-8-indexStart)); err != nil {
+			return err
+		}
+		binary.BigEndian.PutUint64(b[:], uint64(len(header)))
+		if _, err := out.Write(b[:]); err != nil {
+			return err
+		}
+		return out.Sync()
+	}(); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	return out.Close()
+}
+
+// BlockCache caches the blocks of cold TSM files fetched from an ObjectStore, evicting
+// the least recently used blocks once the cache holds more than its maximum size.
+type BlockCache struct {
+	maxSize int64
+
+	mu     sync.Mutex
+	size   int64
+	lru    *list.List
+	blocks map[blockCacheKey]*list.Element
+}
+
+type blockCacheKey struct {
+	key    string
+	offset int64
+}
+
+type blockCacheEntry struct {
+	k blockCacheKey
+	b []byte
+}
+
+// NewBlockCache returns a BlockCache holding up to maxSize bytes of blocks.
+func NewBlockCache(maxSize int64) *BlockCache {
+	return &BlockCache{
+		maxSize: maxSize,
+		lru:     list.New(),
+		blocks:  make(map[blockCacheKey]*list.Element),
+	}
+}
+
+func (c *BlockCache) get(k blockCacheKey) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.blocks[k]
+	if !ok {
+		return nil, false
+	}
+	c.lru.MoveToFront(e)
+	return e.Value.(*blockCacheEntry).b, true
+}
+
+func (c *BlockCache) add(k blockCacheKey, b []byte) {
+	if int64(len(b)) > c.maxSize {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.blocks[k]; ok {
+		return
+	}
+	c.blocks[k] = c.lru.PushFront(&blockCacheEntry{k: k, b: b})
+	c.size += int64(len(b))
+	for c.size > c.maxSize {
+		c.removeElement(c.lru.Back())
+	}
+}
+
+// removeObject removes the blocks of the object key.
+func (c *BlockCache) removeObject(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, e := range c.blocks {
+		if k.key == key {
+			c.removeElement(e)
+		}
+	}
+}
+
+func (c *BlockCache) removeElement(e *list.Element) {
+	entry := c.lru.Remove(e).(*blockCacheEntry)
+	delete(c.blocks, entry.k)
+	c.size -= int64(len(entry.b))
+}
+
+// Size returns the number of bytes of blocks in the cache.
+func (c *BlockCache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// objectAccessor is a block accessor for cold TSM files. It reads the index from the
+// mmapped stub, and fetches blocks from the object store through the block cache.
+type objectAccessor struct {
+	logger *zap.Logger
+	store  ObjectStore
+	cache  *BlockCache
+
+	mu   sync.RWMutex
+	b    []byte
+	f    *os.File
+	key  string
+	size int64
+
+	index *indirectIndex
+}
+
+func (o *objectAccessor) init() (*indirectIndex, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	stat, err := o.f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	o.b, err = mmap(o.f, 0, int(stat.Size()))
+	if err != nil {
+		return nil, err
+	}
+	if len(o.b) < 4+1+2+8+8 {
+		return nil, fmt.Errorf("objectAccessor: byte slice too small for stub")
+	}
+	if binary.BigEndian.Uint32(o.b[0:4]) != ColdMagicNumber {
+		return nil, fmt.Errorf("can only read from cold tsm file")
+	}
+	if o.b[4] != Version {
+		return nil, fmt.Errorf("init: file is version %b. expected %b", o.b[4], Version)
+	}
+
+	n := int(binary.BigEndian.Uint16(o.b[5:7]))
+	headerSize := 7 + n + 8
+	if len(o.b) < headerSize+8 {
+		return nil, fmt.Errorf("objectAccessor: byte slice too small for stub")
+	}
+	o.key = string(o.b[7 : 7+n])
+	o.size = int64(binary.BigEndian.Uint64(o.b[7+n : headerSize]))
+
+	indexOfsPos := len(o.b) - 8
+	indexStart := binary.BigEndian.Uint64(o.b[indexOfsPos : indexOfsPos+8])
+	if indexStart != uint64(headerSize) {
+		return nil, fmt.Errorf("objectAccessor: invalid indexStart")
+	}
+
+	o.index = NewIndirectIndex()
+	if err := o.index.UnmarshalBinary(o.b[indexStart:indexOfsPos]); err != nil {
+		return nil, err
+	}
+	o.index.logger = o.logger
+
+	return o.index, nil
+}
+
+// block returns the checksum and data of the block of the entry, fetching it from
+// the object store unless it is cached.
+func (o *objectAccessor) block(entry *IndexEntry) ([]byte, error) {
+	o.mu.RLock()
+	closed, key, size := o.b == nil, o.key, o.size
+	o.mu.RUnlock()
+	if closed {
+		return nil, ErrTSMClosed
+	}
+	if entry.Size < 4 || entry.Offset+int64(entry.Size) > size {
+		return nil, fmt.Errorf("objectAccessor: block out of range of object %s", key)
+	}
+
+	k := blockCacheKey{key: key, offset: entry.Offset}
+	if o.cache != nil {
+		if b, ok := o.cache.get(k); ok {
+			return b, nil
+		}
+	}
+
+	b := make([]byte, entry.Size)
+	if _, err := o.store.ReadAt(key, b, entry.Offset); err != nil {
+		return nil, fmt.Errorf("objectAccessor: error reading block of object %s: %v", key, err)
+	}
+	if crc32.ChecksumIEEE(b[4:]) != binary.BigEndian.Uint32(b[:4]) {
+		return nil, fmt.Errorf("objectAccessor: checksum mismatch of block of object %s at offset %d", key, entry.Offset)
+	}
+
+	if o.cache != nil {
+		o.cache.add(k, b)
+	}
+	return b, nil
+}
+
+func (o *objectAccessor) read(key []byte, timestamp int64) ([]Value, error) {
+	entry := o.index.Entry(key, timestamp)
+	if entry == nil {
+		return nil, nil
+	}
+
+	return o.readBlock(entry, nil)
+}
+
+func (o *objectAccessor) readBlock(entry *IndexEntry, values []Value) ([]Value, error) {
+	b, err := o.block(entry)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeBlock(b[4:], values)
+}
+
+func (o *objectAccessor) readBytes(entry *IndexEntry, buf []byte) (uint32, []byte, error) {
+	b, err := o.block(entry)
+	if err != nil {
+		return 0, nil, err
+	}
+	return binary.BigEndian.Uint32(b[:4]), b[4:], nil
+}
+
+// readAll returns all values for a key in all blocks.
+func (o *objectAccessor) readAll(key []byte) ([]Value, error) {
+	blocks, err := o.index.ReadEntries(key, nil)
+	if len(blocks) == 0 || err != nil {
+		return nil, err
+	}
+
+	tombstones := o.index.TombstoneRange(key, nil)
+
+	var temp []Value
+	var values []Value
+	for i := range blocks {
+		block := &blocks[i]
+		var skip bool
+		for _, t := range tombstones {
+			// Should we skip this block because it contains points that have been deleted
+			if t.Min <= block.MinTime && t.Max >= block.MaxTime {
+				skip = true
+				break
+			}
+		}
+
+		if skip {
+			continue
+		}
+
+		temp, err = o.readBlock(block, temp[:0])
+		if err != nil {
+			return nil, err
+		}
+
+		// Filter out any values that were deleted
+		for _, t := range tombstones {
+			temp = Values(temp).Exclude(t.Min, t.Max)
+		}
+
+		values = append(values, temp...)
+	}
+
+	return values, nil
+}
+
+func (o *objectAccessor) rename(path string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if err := munmap(o.b); err != nil {
+		return err
+	}
+
+	if err := o.f.Close(); err != nil {
+		return err
+	}
+
+	if err := file.RenameFile(o.f.Name(), path); err != nil {
+		return err
+	}
+
+	var err error
+	o.f, err = os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	stat, err := o.f.Stat()
+	if err != nil {
+		return err
+	}
+
+	o.b, err = mmap(o.f, 0, int(stat.Size()))
+	return err
+}
+
+func (o *objectAccessor) path() string {
+	o.mu.RLock()
+	path := o.f.Name()
+	o.mu.RUnlock()
+	return path
+}
+
+// free is a no-op; the stub only holds the index, which is needed for all reads.
+func (o *objectAccessor) free() error { return nil }
+
+func (o *objectAccessor) close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.b == nil {
+		return nil
+	}
+
+	err := munmap(o.b)
+	if err != nil {
+		return err
+	}
+
+	o.b = nil
+	return o.f.Close()
+}
+
+// removeObject deletes the object of the file from the object store, along with its cached blocks.
+func (o *objectAccessor) removeObject() error {
+	o.mu.RLock()
+	key := o.key
+	o.mu.RUnlock()
+
+	if o.cache != nil {
+		o.cache.removeObject(key)
+	}
+	return o.store.Delete(key)
+}
@@ -85,7 +85,7 @@ type UserResourceMappingFilter struct {
 	UserType   UserType
 }
 
-var ownerActions = []Action{WriteAction, ReadAction}
+var ownerActions = []Action{WriteAction, ReadAction, DeleteAction}
 var memberActions = []Action{ReadAction}
 
 func (m *UserResourceMapping) ownerPerms() ([]Permission, error) {
@@ -104,6 +104,10 @@ func (m *UserResourceMapping) ownerPerms() ([]Permission, error) {
 
 	}
 
+	if m.Resource == OrgsResource {
+		ps = append(ps, SharedMemberPermissions()...)
+	}
+
 	return ps, nil
 }
 
@@ -122,6 +126,10 @@ func (m *UserResourceMapping) memberPerms() ([]Permission, error) {
 		}
 	}
 
+	if m.Resource == OrgsResource {
+		ps = append(ps, SharedMemberPermissions()...)
+	}
+
 	return ps, nil
 }
 
@@ -2,6 +2,7 @@ package zap
 
 import (
 	"context"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -81,3 +82,14 @@ func (s *AuthorizationService) SetAuthorizationStatus(ctx context.Context, id pl
 
 	return s.AuthorizationService.SetAuthorizationStatus(ctx, id, status)
 }
+
+// RotateAuthorization replaces the token of an authorization, and logs any errors.
+func (s *AuthorizationService) RotateAuthorization(ctx context.Context, id platform.ID, gracePeriod time.Duration) (a *platform.Authorization, err error) {
+	defer func() {
+		if err != nil {
+			s.Logger.Info("error rotating authorization", zap.Error(err))
+		}
+	}()
+
+	return s.AuthorizationService.RotateAuthorization(ctx, id, gracePeriod)
+}